@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/backup"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+)
+
+func backupDatabase(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlags("backup")
+	output := flags.String("output", "", "write a single uncompressed copy to this file rather than to the backup directory")
+	list := flags.Bool("list", false, "list the backups kept in the backup directory")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		backups, err := backup.NewManager(nil, settings).List(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tSIZE\tCREATED")
+		for _, backup := range backups {
+			fmt.Fprintf(table, "%s\t%d\t%s\n", backup.Name, backup.Size, backup.CreatedAt.Format(time.RFC3339))
+		}
+		return table.Flush()
+	}
+
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *output != "" {
+		if err := db.Backup(ctx, database, *output); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "backed up %s to %s\n", settings.DatabaseURL, *output)
+		return nil
+	}
+
+	created, err := backup.NewManager(database, settings).Create(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "backed up %s to %s\n", settings.DatabaseURL, created.Path)
+	return nil
+}
@@ -1,66 +1,255 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
 	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
-	"github.com/juliocnsouzadev/go-videos-api/internal/api"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/mail"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/oidc"
 )
 
-var database *sql.DB
+const binary = "videos-api"
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, out io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{"serve", "run the HTTP and gRPC servers, the default command", serve},
+		{"migrate", "up|down|status: apply, revert or list the schema migrations", migrate},
+		{"user", "create|disable|set-role|reset-password|unlock|reset-mfa: manage users", user},
+		{"video", "export|import: copy videos and their annotations as JSON lines", video},
+		{"backup", "back up the database while it is in use, or list the backups", backupDatabase},
+		{"restore", "replace the database with a verified backup, the server must be stopped", restoreDatabase},
+		{"seed", "create a demo user with a few annotated videos", seed},
+	}
+}
 
 func main() {
-	defer Cleanup()
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		slog.Error("Command failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command named by the first argument, serve when there is none
+// so the server still starts with flags only.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	for _, command := range commands() {
+		if command.name == name {
+			return command.run(ctx, args, out)
+		}
+	}
 
-	log.Println("Starting server...")
+	usage(os.Stderr)
+	if name == "help" {
+		return flag.ErrHelp
+	}
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [command] [flags]\n\nCommands:\n", binary)
+	for _, command := range commands() {
+		fmt.Fprintf(w, "  %-8s %s\n", command.name, command.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command, every command takes the server settings.\n", binary)
+}
 
-	var err error
-	var settings *config.Settings
+type subcommandFunc func(ctx context.Context, name string, args []string, out io.Writer) error
 
-	log.Println("Loading settings...")
-	if settings, err = config.Load(); err != nil {
-		log.Fatal(err)
+// subcommand runs the subcommand of group named by the first argument.
+func subcommand(ctx context.Context, group string, args []string, out io.Writer, subcommands map[string]subcommandFunc) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s needs a subcommand, see '%s help'", group, binary)
+	}
+	run, ok := subcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown %s subcommand %q, see '%s help'", group, args[0], binary)
 	}
+	return run(ctx, group+" "+args[0], args[1:], out)
+}
+
+// newFlags returns the flag set of a command, config.Load adds the settings
+// to it.
+func newFlags(name string) *flag.FlagSet {
+	return flag.NewFlagSet(binary+" "+name, flag.ContinueOnError)
+}
 
-	log.Println("Connecting to database...")
-	if database, err = db.Connect(settings.DatabaseURL); err != nil {
-		log.Fatal(err)
+// loadSettings parses args, loads the settings and logs the way they say.
+func loadSettings(flags *flag.FlagSet, args []string) (*config.Settings, error) {
+	settings, err := config.Load(flags, args)
+	if err != nil {
+		return nil, err
 	}
+	slog.SetDefault(logging.New(os.Stderr, settings))
+	return settings, nil
+}
+
+// app holds the repositories and services the administration commands use.
+type app struct {
+	database             *sql.DB
+	userRepository       ports.UserRepository
+	videoRepository      ports.VideoRepository
+	annotationRepository ports.AnnotationRepository
+	workspaceRepository  ports.WorkspaceRepository
+	userService          ports.UserService
+	mfaService           ports.MfaService
+	videoService         ports.VideoService
+}
 
-	log.Println("Creating tables...")
-	if err = createTables(database); err != nil {
-		log.Fatal(err)
+// openApp connects to the database, which must have every migration applied.
+// Events of the changes wait in the outbox until the server dispatches them.
+func openApp(ctx context.Context, settings *config.Settings) (*app, error) {
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.NewMigrator(database).Verify(ctx); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("%w, run '%s migrate up' first", err, binary)
 	}
 
-	authService := auth.NewAuthService(settings.JwtKey)
+	authService, err := auth.New(settings)
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
 	userRepository := repository.NewUserRepository(database)
-	userService := service.NewUserService(userRepository, authService)
+	mfaRepository := repository.NewMfaRepository(database)
+	videoRepository := repository.NewVideoRepository(database)
+	annotationRepository := repository.NewAnnotationRepository(database)
+	workspaceRepository := repository.NewWorkspaceRepository(database)
+	transactor := repository.NewTransactor(database)
+	throttle := newLoginThrottle(database, settings)
+	mfaService := service.NewMfaService(userRepository, mfaRepository, repository.NewAuditRepository(database),
+		authService, throttle, settings.MfaIssuer)
+	accountService, err := newAccountService(database, settings, authService, throttle)
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
+
+	return &app{
+		database:             database,
+		userRepository:       userRepository,
+		videoRepository:      videoRepository,
+		annotationRepository: annotationRepository,
+		workspaceRepository:  workspaceRepository,
+		userService:          service.NewUserService(userRepository, mfaRepository, accountService, authService, throttle),
+		mfaService:           mfaService,
+		videoService: service.NewVideoService(videoRepository, annotationRepository, userRepository,
+			workspaceRepository, transactor),
+	}, nil
+}
+
+func newLoginThrottle(database *sql.DB, settings *config.Settings) *service.LoginThrottle {
+	throttle := service.NewLoginThrottle(repository.NewLoginAttemptRepository(database), repository.NewAuditRepository(database))
+	throttle.MaxFailures = settings.LoginMaxFailures
+	throttle.MaxAddressFailures = settings.LoginMaxAddressFailures
+	throttle.FailureDelay = settings.LoginFailureDelay
+	throttle.Lockout = settings.LoginLockout
+	return throttle
+}
+
+func newAccountService(database *sql.DB, settings *config.Settings, authService auth.AuthService, throttle *service.LoginThrottle) (ports.AccountService, error) {
+	mailer, err := mail.New(settings)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewAccountService(repository.NewUserRepository(database), repository.NewUserTokenRepository(database),
+		repository.NewAuditRepository(database), authService, mailer, throttle, service.AccountSettings{
+			AppURL:               settings.AppURL,
+			VerificationTTL:      settings.EmailVerificationTTL,
+			ResetTTL:             settings.PasswordResetTTL,
+			RequireVerifiedEmail: settings.RequireVerifiedEmail,
+		}), nil
+}
 
-	videoRepo := repository.NewVideoRepository(database)
-	annotationRepo := repository.NewAnnotationRepository(database)
-	videoService := service.NewVideoService(videoRepo, annotationRepo, userRepository)
+func newProfileService(database *sql.DB, settings *config.Settings, authService auth.AuthService, accountService ports.AccountService,
+	throttle *service.LoginThrottle, transactor ports.Transactor) (ports.ProfileService, error) {
+	mailer, err := mail.New(settings)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewProfileService(repository.NewUserRepository(database), repository.NewWorkspaceRepository(database),
+		repository.NewAuditRepository(database), transactor, authService, accountService, mailer, throttle), nil
+}
+
+func newWorkspaceService(database *sql.DB, settings *config.Settings, authService auth.AuthService) (ports.WorkspaceService, error) {
+	mailer, err := mail.New(settings)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewWorkspaceService(repository.NewUserRepository(database), repository.NewWorkspaceRepository(database),
+		repository.NewAuditRepository(database), authService, mailer, service.WorkspaceSettings{
+			AppURL:        settings.AppURL,
+			InvitationTTL: settings.WorkspaceInvitationTTL,
+		}), nil
+}
 
-	log.Println("Starting HTTP server...")
-	api.StartHttpServer(authService, userService, videoService)
+// newSsoService leaves single sign-on off when no issuer is configured.
+func newSsoService(database *sql.DB, settings *config.Settings, authService auth.AuthService) (ports.SsoService, error) {
+	ssoSettings, err := newSsoSettings(settings)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("Server started")
+	var provider ports.IdentityProvider
+	if settings.OidcIssuer != "" {
+		if settings.OidcClientID == "" {
+			return nil, errors.New("OIDC_CLIENT_ID is required with OIDC_ISSUER")
+		}
+		provider = oidc.New(settings)
+	}
+	return service.NewSsoService(repository.NewUserRepository(database), repository.NewOidcRepository(database),
+		repository.NewAuditRepository(database), authService, provider, ssoSettings), nil
 }
 
-func createTables(database *sql.DB) error {
-	return db.
-		NewTablesBuilder(database).
-		WithUsersTable().
-		WithVideosTable().
-		WithAnnotationsTable().
-		Build()
+// newSsoSettings reads the role mapping, OIDC_DEFAULT_ROLE none leaves the
+// default role empty so unmapped users are refused.
+func newSsoSettings(settings *config.Settings) (service.SsoSettings, error) {
+	roleMapping, err := service.ParseRoleMapping(settings.OidcRoleMapping)
+	if err != nil {
+		return service.SsoSettings{}, fmt.Errorf("OIDC_ROLE_MAPPING: %w", err)
+	}
+
+	defaultRole := settings.OidcDefaultRole
+	if strings.EqualFold(defaultRole, config.OidcRoleNone) {
+		defaultRole = ""
+	} else if err := validation.ValidateRole(defaultRole); err != nil {
+		return service.SsoSettings{}, fmt.Errorf("OIDC_DEFAULT_ROLE: %w", err)
+	}
+
+	return service.SsoSettings{RoleMapping: roleMapping, DefaultRole: defaultRole}, nil
 }
 
-func Cleanup() {
-	log.Println("Closing database connection...")
-	database.Close()
+func (a *app) Close() error {
+	return a.database.Close()
 }
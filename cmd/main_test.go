@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs the command against the database of the test and returns
+// its output.
+func runCommand(t *testing.T, database string, args ...string) (string, error) {
+	t.Setenv("DATABASE_PATH", database)
+	t.Setenv("JWT_KEY", "secret")
+	var out bytes.Buffer
+	err := run(context.Background(), args, &out)
+	return out.String(), err
+}
+
+func migratedDatabase(t *testing.T) string {
+	database := filepath.Join(t.TempDir(), "videos.db")
+	_, err := runCommand(t, database, "migrate", "up")
+	require.NoError(t, err)
+	return database
+}
+
+func TestRun_MigrateStatus(t *testing.T) {
+	// fixture
+	database := filepath.Join(t.TempDir(), "videos.db")
+
+	// test
+	before, err := runCommand(t, database, "migrate", "status")
+	require.NoError(t, err)
+	_, err = runCommand(t, database, "migrate", "up")
+	require.NoError(t, err)
+	after, err := runCommand(t, database, "migrate", "status")
+
+	// assert
+	require.NoError(t, err)
+	require.Regexp(t, `\n3\s+add user role and disabled\s+pending\n`, before)
+	require.NotContains(t, after, "pending")
+}
+
+func TestRun_PendingMigrations(t *testing.T) {
+	// fixture
+	database := filepath.Join(t.TempDir(), "videos.db")
+
+	// test
+	_, err := runCommand(t, database, "user", "create", "-email", "jane@example.com", "-password", "password123")
+
+	// assert
+	require.EqualError(t, err, "migration 1 create tables is pending, run 'videos-api migrate up' first")
+}
+
+func TestRun_UserCommands(t *testing.T) {
+	// fixture
+	database := migratedDatabase(t)
+
+	// test
+	created, err := runCommand(t, database, "user", "create", "-email", "jane@example.com", "-password", "password123", "-role", "admin")
+	require.NoError(t, err)
+	_, roleErr := runCommand(t, database, "user", "set-role", "-username", "jane", "-role", "owner")
+	unlocked, err := runCommand(t, database, "user", "unlock", "-username", "jane")
+	require.NoError(t, err)
+	_, unlockErr := runCommand(t, database, "user", "unlock", "-username", "john")
+	resetMfa, err := runCommand(t, database, "user", "reset-mfa", "-username", "jane")
+	require.NoError(t, err)
+	disabled, err := runCommand(t, database, "user", "disable", "-username", "jane")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "unlocked user jane\n", unlocked)
+	require.EqualError(t, unlockErr, "user not found")
+	require.Equal(t, "removed the second factor of user jane\n", resetMfa)
+	require.Equal(t, "created user jane with id 1 and role admin\n", created)
+	require.EqualError(t, roleErr, "role is invalid")
+	require.Equal(t, "disabled user jane\n", disabled)
+}
+
+func TestRun_VideoExportImport(t *testing.T) {
+	// fixture
+	source := migratedDatabase(t)
+	_, err := runCommand(t, source, "seed", "-videos", "2")
+	require.NoError(t, err)
+	export := filepath.Join(t.TempDir(), "videos.jsonl")
+	_, err = runCommand(t, source, "video", "export", "-output", export)
+	require.NoError(t, err)
+
+	target := migratedDatabase(t)
+	_, err = runCommand(t, target, "user", "create", "-email", "demo@example.org", "-password", "password123")
+	require.NoError(t, err)
+
+	// test
+	imported, err := runCommand(t, target, "video", "import", "-input", export)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "imported 2 videos with 4 annotations\n", imported)
+	exported, err := runCommand(t, target, "video", "export")
+	require.NoError(t, err)
+	require.Contains(t, exported, `"owner":"demo","video":{"ID":2,"UserID":1,"WorkspaceID":1,"Title":"Demo video 2"`)
+}
+
+func TestRun_ServeReleasesListenersWhenSetupFails(t *testing.T) {
+	// fixture
+	database := migratedDatabase(t)
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcAddress := free.Addr().String()
+	require.NoError(t, free.Close())
+	t.Setenv("HTTP_ADDRESS", taken.Addr().String())
+	t.Setenv("GRPC_ADDRESS", grpcAddress)
+
+	// test
+	_, err = runCommand(t, database, "serve")
+
+	// assert
+	require.ErrorContains(t, err, "address already in use")
+	listener, err := net.Listen("tcp", grpcAddress)
+	require.NoError(t, err)
+	listener.Close()
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	// test
+	_, err := runCommand(t, "", "replicate")
+
+	// assert
+	require.EqualError(t, err, `unknown command "replicate"`)
+}
+
+func TestRun_BackupAndRestore(t *testing.T) {
+	// fixture
+	database := migratedDatabase(t)
+	_, err := runCommand(t, database, "user", "create", "-email", "jane@example.com", "-password", "password123")
+	require.NoError(t, err)
+	backedUp, err := runCommand(t, database, "backup")
+	require.NoError(t, err)
+	_, err = runCommand(t, database, "user", "create", "-email", "john@example.com", "-password", "password123")
+	require.NoError(t, err)
+
+	// test
+	listed, listErr := runCommand(t, database, "backup", "-list")
+	_, beforeErr := runCommand(t, database, "restore", "-before", "2000-01-01T00:00:00Z")
+	restored, err := runCommand(t, database, "restore")
+
+	// assert
+	require.NoError(t, err)
+	backupDir := filepath.Join(filepath.Dir(database), "backups")
+	require.Contains(t, backedUp, "backed up "+database+" to "+backupDir+"/videos_")
+	require.NoError(t, listErr)
+	require.Contains(t, listed, "NAME")
+	require.Contains(t, listed, ".db.gz")
+	require.ErrorContains(t, beforeErr, "backup not found at or before 2000-01-01T00:00:00Z")
+	require.Contains(t, restored, "restored "+database+" from "+backupDir+"/videos_")
+	require.Contains(t, restored, "the replaced database was kept as ")
+
+	_, err = runCommand(t, database, "user", "disable", "-username", "jane")
+	require.NoError(t, err)
+	_, err = runCommand(t, database, "user", "disable", "-username", "john")
+	require.EqualError(t, err, "user not found")
+}
+
+func TestNewSsoSettings_DefaultRole(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":       "viewer",
+		"editor": "editor",
+		"none":   "",
+		"NONE":   "",
+	} {
+		t.Run(value, func(t *testing.T) {
+			// fixture
+			t.Setenv("DATABASE_PATH", "videos.db")
+			t.Setenv("JWT_KEY", "secret")
+			t.Setenv("OIDC_DEFAULT_ROLE", value)
+			settings, err := config.Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+			require.NoError(t, err)
+
+			// test
+			ssoSettings, err := newSsoSettings(settings)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, expected, ssoSettings.DefaultRole)
+		})
+	}
+}
+
+func TestNewSsoSettings_InvalidDefaultRole(t *testing.T) {
+	// fixture
+	t.Setenv("DATABASE_PATH", "videos.db")
+	t.Setenv("JWT_KEY", "secret")
+	t.Setenv("OIDC_DEFAULT_ROLE", "owner")
+	settings, err := config.Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	require.NoError(t, err)
+
+	// test
+	_, err = newSsoSettings(settings)
+
+	// assert
+	require.ErrorContains(t, err, "OIDC_DEFAULT_ROLE: ")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+)
+
+func migrate(ctx context.Context, args []string, out io.Writer) error {
+	return subcommand(ctx, "migrate", args, out, map[string]subcommandFunc{
+		"up":     migrateUp,
+		"down":   migrateDown,
+		"status": migrateStatus,
+	})
+}
+
+func migrateUp(ctx context.Context, name string, args []string, out io.Writer) error {
+	settings, err := loadSettings(newFlags(name), args)
+	if err != nil {
+		return err
+	}
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	applied, err := db.NewMigrator(database).Up(ctx)
+	for _, migration := range applied {
+		fmt.Fprintf(out, "applied %d %s\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Fprintln(out, "database is up to date")
+	}
+	return err
+}
+
+func migrateDown(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	steps := flags.Int("steps", 1, "number of migrations to revert, latest first")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+	if *steps < 1 {
+		return fmt.Errorf("-steps must be at least 1")
+	}
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	reverted, err := db.NewMigrator(database).Down(ctx, *steps)
+	for _, migration := range reverted {
+		fmt.Fprintf(out, "reverted %d %s\n", migration.Version, migration.Name)
+	}
+	return err
+}
+
+func migrateStatus(ctx context.Context, name string, args []string, out io.Writer) error {
+	settings, err := loadSettings(newFlags(name), args)
+	if err != nil {
+		return err
+	}
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	statuses, err := db.NewMigrator(database).Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tAPPLIED")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\n", status.Version, status.Name, applied)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/backup"
+)
+
+// restoreDatabase replaces the database with a backup, the server must be
+// stopped meanwhile.
+func restoreDatabase(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlags("restore")
+	from := flags.String("from", "", "backup `file` to restore, gzipped when it ends with .gz")
+	before := flags.String("before", "", "restore the latest backup taken at or before this RFC 3339 `time`, by default the latest backup")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	path := *from
+	if path != "" && *before != "" {
+		return errors.New("-from and -before cannot be used together")
+	}
+	if path == "" {
+		latestBefore := time.Now()
+		if *before != "" {
+			if latestBefore, err = time.Parse(time.RFC3339, *before); err != nil {
+				return fmt.Errorf("-before must be an RFC 3339 time such as 2024-01-02T15:04:05Z: %w", err)
+			}
+		}
+		found, err := backup.NewManager(nil, settings).Find(ctx, latestBefore)
+		if err != nil {
+			return err
+		}
+		path = found.Path
+	}
+
+	previous, err := backup.Restore(ctx, path, settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "restored %s from %s\n", settings.DatabaseURL, path)
+	if previous != "" {
+		fmt.Fprintf(out, "the replaced database was kept as %s\n", previous)
+	}
+	return nil
+}
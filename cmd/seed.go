@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// seed creates demo data through the services, for local development.
+func seed(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlags("seed")
+	email := flags.String("email", "demo@example.com", "email of the demo user, it is reused when it exists")
+	password := flags.String("password", "demo-password", "password of the demo user when it is created")
+	videos := flags.Int("videos", 3, "number of videos to create")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	owner, err := seedUser(ctx, app, *email, *password)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i <= *videos; i++ {
+		now := time.Now()
+		video := &model.Video{
+			UserID:      owner.ID,
+			Title:       fmt.Sprintf("Demo video %d", i),
+			Description: "A video created by the seed command.",
+			Link:        fmt.Sprintf("https://example.com/videos/demo-%d.mp4", i),
+			Duration:    10 * time.Minute,
+			CreatedAt:   now,
+		}
+		annotations := []*model.Annotation{
+			{UserID: owner.ID, VideoID: 1, StartTime: 30 * time.Second, EndTime: time.Minute, Type: "chapter", Note: "Introduction"},
+			{UserID: owner.ID, VideoID: 1, StartTime: 5 * time.Minute, EndTime: 6 * time.Minute, Type: "ad", Note: "Sponsor break"},
+		}
+		if err := app.videoService.Create(ctx, owner.Username, video, annotations); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "created %d videos of user %s\n", *videos, owner.Username)
+	return nil
+}
+
+func seedUser(ctx context.Context, app *app, email string, password string) (*model.User, error) {
+	// the username is the local part of the email, as on signup
+	username, _, _ := strings.Cut(email, "@")
+	existing, err := app.userRepository.FindByUsername(ctx, username)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, repository.UserNotFoundError) {
+		return nil, err
+	}
+	return app.userService.Create(ctx, email, password, model.RoleEditor)
+}
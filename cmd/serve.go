@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/outbox"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/webhook"
+	"github.com/juliocnsouzadev/go-videos-api/internal/api"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/backup"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+// serve runs the servers and the background workers until a signal asks
+// them to stop.
+func serve(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlags("serve")
+	printConfig := flags.Bool("print-config", false, "print the settings, secrets redacted, and exit")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+	if *printConfig {
+		return settings.Dump(out)
+	}
+
+	slog.Info("Starting server...")
+
+	slog.Info("Setting up tracing...", "exporter", settings.TracingExporter)
+	flushTraces, err := tracing.Setup(ctx, settings)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Connecting to database...")
+	database, err := db.Connect(settings.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		slog.Info("Closing database connection...")
+		database.Close()
+	}()
+
+	if err = metrics.RegisterDB(database, "main"); err != nil {
+		return err
+	}
+
+	slog.Info("Migrating database...")
+	migrator := db.NewMigrator(database)
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	authService, err := auth.New(settings)
+	if err != nil {
+		return err
+	}
+	userRepository := repository.NewUserRepository(database)
+	mfaRepository := repository.NewMfaRepository(database)
+	throttle := newLoginThrottle(database, settings)
+	accountService, err := newAccountService(database, settings, authService, throttle)
+	if err != nil {
+		return err
+	}
+	ssoService, err := newSsoService(database, settings, authService)
+	if err != nil {
+		return err
+	}
+	userService := service.NewUserService(userRepository, mfaRepository, accountService, authService, throttle)
+	mfaService := service.NewMfaService(userRepository, mfaRepository, repository.NewAuditRepository(database),
+		authService, throttle, settings.MfaIssuer)
+
+	webhookRepo := repository.NewWebhookRepository(database)
+	webhookService := service.NewWebhookService(webhookRepo, userRepository)
+	hub := stream.NewHub()
+
+	outboxRepo := repository.NewOutboxRepository(database)
+	dispatcher := outbox.NewDispatcher(outboxRepo, webhookService, hub)
+	transactor := repository.NewTransactor(database)
+	transactor.AfterCommit(dispatcher.Notify)
+
+	profileService, err := newProfileService(database, settings, authService, accountService, throttle, transactor)
+	if err != nil {
+		return err
+	}
+
+	videoRepo := repository.NewVideoRepository(database)
+	annotationRepo := repository.NewAnnotationRepository(database)
+	workspaceRepo := repository.NewWorkspaceRepository(database)
+	videoService := service.NewVideoService(videoRepo, annotationRepo, userRepository, workspaceRepo, transactor)
+	annotationService := service.NewAnnotationService(annotationRepo, videoRepo, userRepository, workspaceRepo, transactor)
+	workspaceService, err := newWorkspaceService(database, settings, authService)
+	if err != nil {
+		return err
+	}
+	apiKeyService := service.NewApiKeyService(repository.NewApiKeyRepository(database), userRepository, settings.ApiKeyTTL)
+
+	workers := sync.WaitGroup{}
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	// the workers use the database, they are done before it is closed
+	defer func() {
+		stopWorkers()
+		workers.Wait()
+	}()
+
+	slog.Info("Starting outbox dispatcher...")
+	workers.Go(func() { dispatcher.Run(workersCtx) })
+
+	slog.Info("Starting webhook delivery worker...")
+	webhookWorker := webhook.NewWorker(webhookRepo)
+	workers.Go(func() { webhookWorker.Run(workersCtx) })
+
+	backups := backup.NewManager(database, settings)
+	if backups.Interval > 0 {
+		slog.Info("Starting backup scheduler...", "interval", backups.Interval, "retention", backups.Retention)
+		workers.Go(func() { backups.Run(workersCtx) })
+	}
+
+	readiness := health.NewReadiness()
+	readiness.Add("database", database.PingContext)
+	readiness.Add("migrations", migrator.Current)
+	readiness.Add("outbox_dispatcher", health.Running(dispatcher.Running))
+	readiness.Add("webhook_worker", health.Running(webhookWorker.Running))
+	if backups.Interval > 0 {
+		readiness.Add("backup_scheduler", health.Running(backups.Running))
+	}
+
+	grpcServer := grpcapi.NewServer(authService, userService, accountService, videoService, annotationService)
+	httpServer, err := api.NewHttpServer(settings, authService, userService, profileService, mfaService, accountService, ssoService, videoService, annotationService, webhookService, workspaceService, apiKeyService, backups, hub, readiness)
+	if err != nil {
+		return err
+	}
+
+	// the servers close their listeners once they serve them
+	grpcListener, err := net.Listen("tcp", settings.GrpcAddress)
+	if err != nil {
+		return err
+	}
+	httpListener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		grpcListener.Close()
+		return err
+	}
+
+	serveErrors := make(chan error, 2)
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			serveErrors <- err
+		}
+	}()
+	go func() {
+		if err := httpServer.Serve(httpListener); !errors.Is(err, http.ErrServerClosed) {
+			serveErrors <- err
+		}
+	}()
+	slog.Info("Server started", "http_address", httpListener.Addr().String(), "grpc_address", grpcListener.Addr().String())
+
+	signals, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	var serveErr error
+	select {
+	case <-signals.Done():
+		slog.Info("Shutting down...")
+	case serveErr = <-serveErrors:
+		slog.Error("Server stopped unexpectedly, shutting down...", "error", serveErr)
+	}
+	// a second signal kills the process without waiting for the shutdown
+	stopSignals()
+
+	// probes see the server as not ready while it still takes requests, so
+	// it leaves the load balancer before the listeners close
+	readiness.Drain()
+	time.Sleep(settings.ShutdownDrainDelay)
+
+	shutdown(settings.ShutdownTimeout, httpServer, grpcServer, func() {
+		stopWorkers()
+		workers.Wait()
+	}, flushTraces)
+	// a server that failed makes the process exit with an error
+	return serveErr
+}
+
+// shutdown stops taking requests, lets the in-flight ones finish, stops the
+// background workers and flushes the pending spans, all before the timeout.
+// The database is closed last, when serve returns.
+func shutdown(timeout time.Duration, httpServer *http.Server, grpcServer *grpc.Server, stopWorkers func(), flushTraces func(context.Context) error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	slog.Info("Stopping HTTP server...")
+	if err := httpServer.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP requests did not finish in time", "error", err)
+		httpServer.Close()
+	}
+
+	slog.Info("Stopping gRPC server...")
+	if !waitFor(ctx, grpcServer.GracefulStop) {
+		slog.Warn("gRPC requests did not finish in time")
+		grpcServer.Stop()
+	}
+
+	slog.Info("Stopping background workers...")
+	if !waitFor(ctx, stopWorkers) {
+		slog.Warn("Background workers did not finish in time")
+	}
+
+	slog.Info("Flushing traces...")
+	if err := flushTraces(ctx); err != nil {
+		slog.Warn("Traces were not flushed", "error", err)
+	}
+}
+
+// waitFor runs fn and reports whether it returned before ctx was done.
+func waitFor(ctx context.Context, fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
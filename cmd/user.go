@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func user(ctx context.Context, args []string, out io.Writer) error {
+	return subcommand(ctx, "user", args, out, map[string]subcommandFunc{
+		"create":         userCreate,
+		"disable":        userDisable,
+		"set-role":       userSetRole,
+		"reset-password": userResetPassword,
+		"unlock":         userUnlock,
+		"reset-mfa":      userResetMfa,
+	})
+}
+
+func userCreate(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	email := flags.String("email", "", "email of the user, its local part becomes the username")
+	password := flags.String("password", "", "password of the user, read from standard input when not set")
+	role := flags.String("role", model.RoleEditor, "role of the user, one of "+strings.Join(model.Roles, ", "))
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+	if *password, err = readPassword(*password); err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	created, err := app.userService.Create(ctx, *email, *password, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "created user %s with id %d and role %s\n", created.Username, created.ID, created.Role)
+	return nil
+}
+
+func userDisable(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	username := flags.String("username", "", "user to disable, it can no longer log in")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if err := app.userService.Disable(ctx, *username); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "disabled user %s\n", *username)
+	return nil
+}
+
+func userUnlock(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	username := flags.String("username", "", "user to unlock after failed logins")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if err := app.userService.Unlock(ctx, *username); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "unlocked user %s\n", *username)
+	return nil
+}
+
+func userResetMfa(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	username := flags.String("username", "", "user who lost the second factor, the next login only takes the password")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if err := app.mfaService.Reset(ctx, *username); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "removed the second factor of user %s\n", *username)
+	return nil
+}
+
+func userSetRole(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	username := flags.String("username", "", "user to change")
+	role := flags.String("role", "", "new role, one of "+strings.Join(model.Roles, ", "))
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if err := app.userService.SetRole(ctx, *username, *role); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "user %s is now %s\n", *username, *role)
+	return nil
+}
+
+func userResetPassword(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	username := flags.String("username", "", "user to change")
+	password := flags.String("password", "", "new password, read from standard input when not set")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+	if *password, err = readPassword(*password); err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	if err := app.userService.ResetPassword(ctx, *username, *password); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "reset the password of user %s\n", *username)
+	return nil
+}
+
+// readPassword reads the first line of standard input when no password was
+// given, so it does not show in the process list or the shell history.
+func readPassword(password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
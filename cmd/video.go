@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+const exportBatch = 100
+
+// exportedVideo is a line of an export. The owner is kept by username since
+// ids differ from one database to another.
+type exportedVideo struct {
+	Owner       string              `json:"owner"`
+	Video       *model.Video        `json:"video"`
+	Annotations []*model.Annotation `json:"annotations"`
+}
+
+func video(ctx context.Context, args []string, out io.Writer) error {
+	return subcommand(ctx, "video", args, out, map[string]subcommandFunc{
+		"export": videoExport,
+		"import": videoImport,
+	})
+}
+
+func videoExport(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	output := flags.String("output", "-", "file to write, - for standard output")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	file, err := create(*output, out)
+	if err != nil {
+		return err
+	}
+	exported, err := exportVideos(ctx, app, json.NewEncoder(file))
+	if err := errors.Join(err, file.Close()); err != nil {
+		return err
+	}
+
+	if *output != "-" {
+		fmt.Fprintf(out, "exported %d videos to %s\n", exported, *output)
+	}
+	return nil
+}
+
+// exportVideos encodes every video, with its owner and annotations, a batch
+// at a time.
+func exportVideos(ctx context.Context, app *app, encoder *json.Encoder) (int, error) {
+	all, err := app.workspaceRepository.FindIds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	exported, after := 0, 0
+	for {
+		videos, err := app.videoRepository.FindAfter(ctx, all, after, exportBatch)
+		if err != nil || len(videos) == 0 {
+			return exported, err
+		}
+
+		videoIds, userIds := make([]int, 0, len(videos)), make([]int, 0, len(videos))
+		workspaceIds := make([]int, 0, len(videos))
+		for _, video := range videos {
+			videoIds = append(videoIds, video.ID)
+			userIds = append(userIds, video.UserID)
+			workspaceIds = append(workspaceIds, video.WorkspaceID)
+		}
+		found, err := app.annotationRepository.FindByVideoIds(ctx, workspaceIds, videoIds)
+		if err != nil {
+			return exported, err
+		}
+		annotations := map[int][]*model.Annotation{}
+		for _, annotation := range found {
+			annotations[annotation.VideoID] = append(annotations[annotation.VideoID], annotation)
+		}
+		owners, err := app.userService.FindMany(ctx, userIds)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, video := range videos {
+			owner, ok := owners[video.UserID]
+			if !ok {
+				return exported, fmt.Errorf("owner %d of video %d not found", video.UserID, video.ID)
+			}
+			line := exportedVideo{Owner: owner.Username, Video: video, Annotations: annotations[video.ID]}
+			if err := encoder.Encode(line); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+		after = videos[len(videos)-1].ID
+	}
+}
+
+// videoImport creates every video of an export through the video service,
+// as new videos of the owner with the same username, in the personal
+// workspace of the owner. It stops at the first
+// video that fails, the videos before it stay imported.
+func videoImport(ctx context.Context, name string, args []string, out io.Writer) error {
+	flags := newFlags(name)
+	input := flags.String("input", "-", "file to read, - for standard input")
+	settings, err := loadSettings(flags, args)
+	if err != nil {
+		return err
+	}
+
+	app, err := openApp(ctx, settings)
+	if err != nil {
+		return err
+	}
+	defer app.Close()
+
+	var file io.Reader = os.Stdin
+	if *input != "-" {
+		opened, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer opened.Close()
+		file = opened
+	}
+
+	decoder := json.NewDecoder(file)
+	imported, annotations := 0, 0
+	for {
+		line := exportedVideo{}
+		if err := decoder.Decode(&line); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("video %d of the import: %w", imported+1, err)
+		}
+
+		if err := importVideo(ctx, app, &line); err != nil {
+			return fmt.Errorf("video %d of the import, %q: %w", imported+1, line.Video.Title, err)
+		}
+		imported++
+		annotations += len(line.Annotations)
+	}
+
+	fmt.Fprintf(out, "imported %d videos with %d annotations\n", imported, annotations)
+	return nil
+}
+
+func importVideo(ctx context.Context, app *app, line *exportedVideo) error {
+	if line.Video == nil {
+		return errors.New("video is missing")
+	}
+	owner, err := app.userRepository.FindByUsername(ctx, line.Owner)
+	if err != nil {
+		return fmt.Errorf("owner %s: %w", line.Owner, err)
+	}
+
+	line.Video.ID, line.Video.UserID, line.Video.WorkspaceID, line.Video.Version = 0, owner.ID, 0, 0
+	for _, annotation := range line.Annotations {
+		annotation.ID, annotation.UserID, annotation.Version = 0, owner.ID, 0
+	}
+	return app.videoService.Create(ctx, owner.Username, line.Video, line.Annotations)
+}
+
+// create opens path for writing, or returns out when path is -.
+func create(path string, out io.Writer) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopCloser{out}, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
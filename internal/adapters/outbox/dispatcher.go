@@ -0,0 +1,160 @@
+// Package outbox dispatches the domain events stored in the outbox table to
+// the subscribers that react to them.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var tracer = tracing.Tracer("internal/adapters/outbox")
+
+const (
+	defaultPollInterval   = time.Second
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+	defaultBatchSize      = 100
+)
+
+// Dispatcher publishes outbox entries to every subscriber and only removes an
+// entry once all of them accepted it, so delivery is at least once and
+// subscribers must tolerate seeing an event id twice. An entry that fails is
+// retried with exponential backoff and holds back the later events of its
+// video, which keeps the events of each video in order.
+type Dispatcher struct {
+	repo        ports.OutboxRepository
+	subscribers []ports.EventPublisher
+	now         func() time.Time
+	wake        chan struct{}
+	running     atomic.Bool
+
+	PollInterval   time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewDispatcher(repo ports.OutboxRepository, subscribers ...ports.EventPublisher) *Dispatcher {
+	return &Dispatcher{
+		repo:           repo,
+		subscribers:    subscribers,
+		now:            time.Now,
+		wake:           make(chan struct{}, 1),
+		PollInterval:   defaultPollInterval,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// Notify makes a running dispatcher look for new entries right away instead
+// of waiting for the next poll.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Running reports whether Run is looping, readiness fails when the dispatcher
+// stopped.
+func (d *Dispatcher) Running() bool {
+	return d.running.Load()
+}
+
+// Run dispatches due entries until the context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.running.Store(true)
+	defer d.running.Store(false)
+
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchDue(ctx); err != nil {
+			slog.Error("dispatching outbox failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DispatchDue makes one attempt for every due entry.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	entries, err := d.repo.FindDue(ctx, d.now().UTC(), defaultBatchSize)
+	if err != nil {
+		return err
+	}
+
+	blocked := map[int]bool{}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		videoID := entry.Event.VideoID
+		if blocked[videoID] {
+			continue
+		}
+
+		if err := d.publish(ctx, entry.Event); err != nil {
+			blocked[videoID] = true
+			slog.Warn("dispatching event failed", "event_id", entry.Event.ID, "event_type", entry.Event.Type, "attempts", entry.Attempts+1, "error", err)
+
+			entry.Attempts++
+			entry.LastError = err.Error()
+			entry.NextAttemptAt = d.now().UTC().Add(d.backoff(entry.Attempts))
+			if err := d.repo.Reschedule(ctx, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.repo.Remove(ctx, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish starts a new trace for every event, the request that produced it
+// has long finished.
+func (d *Dispatcher) publish(ctx context.Context, event *model.Event) error {
+	ctx, span := tracer.Start(ctx, "Dispatcher.publish")
+	span.SetAttributes(
+		attribute.String("event.id", event.ID),
+		attribute.String("event.type", event.Type),
+		attribute.Int("video.id", event.VideoID),
+	)
+
+	errs := []error{}
+	for _, subscriber := range d.subscribers {
+		if err := subscriber.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	tracing.End(span, err)
+	return err
+}
+
+// backoff doubles the wait after every failed attempt, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.InitialBackoff
+	for i := 1; i < attempts && wait < d.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.MaxBackoff)
+}
@@ -0,0 +1,237 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_DispatchDue_HappyPath(t *testing.T) {
+	// fixture
+	repo := newFakeOutboxRepository(
+		&model.Event{ID: "evt-1", Type: model.EventVideoCreated, VideoID: 1},
+		&model.Event{ID: "evt-2", Type: model.EventAnnotationCreated, VideoID: 1},
+	)
+	first, second := &recordingPublisher{}, &recordingPublisher{}
+	dispatcher, _ := newTestDispatcher(repo, first, second)
+
+	// test
+	err := dispatcher.DispatchDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []string{"evt-1", "evt-2"}, first.ids)
+	require.Equal(t, []string{"evt-1", "evt-2"}, second.ids)
+	require.Empty(t, repo.entries)
+}
+
+func TestDispatcher_DispatchDue_RetriesWithBackoff(t *testing.T) {
+	// fixture
+	repo := newFakeOutboxRepository(&model.Event{ID: "evt-1", Type: model.EventVideoCreated, VideoID: 1})
+	recording := &recordingPublisher{}
+	failing := &recordingPublisher{failures: 2}
+	dispatcher, clock := newTestDispatcher(repo, recording, failing)
+	start := *clock
+
+	// test & assert
+	err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	entry := repo.entries[0]
+	require.Equal(t, 1, entry.Attempts)
+	require.Equal(t, "subscriber unavailable", entry.LastError)
+	require.Equal(t, start.Add(time.Second), entry.NextAttemptAt)
+
+	// nothing is due before the backoff elapsed
+	require.NoError(t, dispatcher.DispatchDue(context.Background()))
+	require.Len(t, recording.ids, 1)
+
+	*clock = clock.Add(time.Second)
+	require.NoError(t, dispatcher.DispatchDue(context.Background()))
+	require.Equal(t, clock.Add(2*time.Second), entry.NextAttemptAt)
+
+	*clock = clock.Add(2 * time.Second)
+	require.NoError(t, dispatcher.DispatchDue(context.Background()))
+	require.Empty(t, repo.entries)
+	require.Equal(t, []string{"evt-1", "evt-1", "evt-1"}, recording.ids)
+}
+
+func TestDispatcher_DispatchDue_FailureHoldsBackLaterEventsOfTheVideo(t *testing.T) {
+	// fixture
+	repo := newFakeOutboxRepository(
+		&model.Event{ID: "evt-1", Type: model.EventVideoCreated, VideoID: 1},
+		&model.Event{ID: "evt-2", Type: model.EventVideoCreated, VideoID: 2},
+		&model.Event{ID: "evt-3", Type: model.EventVideoUpdated, VideoID: 1},
+	)
+	publisher := &recordingPublisher{failing: map[string]bool{"evt-1": true}}
+	dispatcher, clock := newTestDispatcher(repo, publisher)
+
+	// test & assert
+	require.NoError(t, dispatcher.DispatchDue(context.Background()))
+	require.Equal(t, []string{"evt-1", "evt-2"}, publisher.ids)
+	require.Len(t, repo.entries, 2)
+
+	delete(publisher.failing, "evt-1")
+	*clock = clock.Add(time.Second)
+	require.NoError(t, dispatcher.DispatchDue(context.Background()))
+	require.Equal(t, []string{"evt-1", "evt-2", "evt-1", "evt-3"}, publisher.ids)
+	require.Empty(t, repo.entries)
+}
+
+func TestDispatcher_DispatchDue_RepositoryError(t *testing.T) {
+	// fixture
+	repo := newFakeOutboxRepository()
+	repo.err = errors.New("database error")
+	dispatcher, _ := newTestDispatcher(repo, &recordingPublisher{})
+
+	// test
+	err := dispatcher.DispatchDue(context.Background())
+
+	// assert
+	require.EqualError(t, err, "database error")
+}
+
+func TestDispatcher_Run_DispatchesOnNotify(t *testing.T) {
+	// fixture
+	repo := newFakeOutboxRepository()
+	publisher := &recordingPublisher{published: make(chan string, 1)}
+	dispatcher := NewDispatcher(repo, publisher)
+	dispatcher.PollInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	// test
+	repo.append(&model.Event{ID: "evt-1", Type: model.EventVideoCreated, VideoID: 1})
+	dispatcher.Notify()
+
+	// assert
+	select {
+	case id := <-publisher.published:
+		require.Equal(t, "evt-1", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not dispatched after Notify")
+	}
+	require.True(t, dispatcher.Running())
+	cancel()
+	<-done
+	require.False(t, dispatcher.Running())
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	// fixture
+	dispatcher := NewDispatcher(nil)
+
+	// assert
+	require.Equal(t, time.Second, dispatcher.backoff(1))
+	require.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	require.Equal(t, 8*time.Second, dispatcher.backoff(4))
+	require.Equal(t, 5*time.Minute, dispatcher.backoff(20))
+}
+
+func newTestDispatcher(repo *fakeOutboxRepository, subscribers ...*recordingPublisher) (*Dispatcher, *time.Time) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher := NewDispatcher(repo)
+	for _, subscriber := range subscribers {
+		dispatcher.subscribers = append(dispatcher.subscribers, subscriber)
+	}
+	dispatcher.now = func() time.Time { return clock }
+	return dispatcher, &clock
+}
+
+type recordingPublisher struct {
+	ids       []string
+	failures  int
+	failing   map[string]bool
+	published chan string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *model.Event) error {
+	p.ids = append(p.ids, event.ID)
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("subscriber unavailable")
+	}
+	if p.failing[event.ID] {
+		return errors.New("subscriber unavailable")
+	}
+	if p.published != nil {
+		p.published <- event.ID
+	}
+	return nil
+}
+
+// fakeOutboxRepository mirrors the ordering rule of the sql repository: an
+// entry that is not due yet holds back the later entries of its video.
+type fakeOutboxRepository struct {
+	mu      sync.Mutex
+	entries []*model.OutboxEntry
+	lastID  int
+	err     error
+}
+
+func newFakeOutboxRepository(events ...*model.Event) *fakeOutboxRepository {
+	repo := &fakeOutboxRepository{}
+	for _, event := range events {
+		repo.append(event)
+	}
+	return repo
+}
+
+func (r *fakeOutboxRepository) append(event *model.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.entries = append(r.entries, &model.OutboxEntry{ID: r.lastID, Event: event, NextAttemptAt: at, CreatedAt: at})
+}
+
+func (r *fakeOutboxRepository) Append(ctx context.Context, event *model.Event) error {
+	r.append(event)
+	return nil
+}
+
+func (r *fakeOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	due := []*model.OutboxEntry{}
+	held := map[int]bool{}
+	for _, entry := range r.entries {
+		if entry.NextAttemptAt.After(now) {
+			held[entry.Event.VideoID] = true
+			continue
+		}
+		if !held[entry.Event.VideoID] && len(due) < limit {
+			due = append(due, entry)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeOutboxRepository) Reschedule(ctx context.Context, entry *model.OutboxEntry) error {
+	return nil
+}
+
+func (r *fakeOutboxRepository) Remove(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, entry := range r.entries {
+		if entry.ID == id {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -1,36 +1,79 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 )
 
 var (
 	ErrAnnotationNotFound = fmt.Errorf("annotation not found")
 )
 
+const annotationColumns = `id, start_time, end_time, type, note, user_id, video_id, version`
+
 type annotationRepository struct {
-	db *sql.DB
+	db executor
 }
 
 func NewAnnotationRepository(db *sql.DB) *annotationRepository {
-	return &annotationRepository{db}
+	return &annotationRepository{traced(db)}
 }
 
-func (r *annotationRepository) Create(annotation *model.Annotation, userId, videoId int) error {
+// Create fills in the id, video id, user id and version of annotation.
+func (r *annotationRepository) Create(ctx context.Context, annotation *model.Annotation, videoId, userId int) error {
 	query := `INSERT INTO annotations (start_time, end_time, type, note, user_id, video_id) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := r.db.Exec(query, annotation.StartTime, annotation.EndTime, annotation.Type, annotation.Note, userId, videoId)
-	return err
+	result, err := r.db.ExecContext(ctx, query, annotation.StartTime, annotation.EndTime, annotation.Type, annotation.Note, userId, videoId)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	annotation.ID = int(id)
+	annotation.VideoID = videoId
+	annotation.UserID = userId
+	annotation.Version = 1
+	return nil
+}
+
+func (r *annotationRepository) FindById(ctx context.Context, workspaceIds []int, id int) (*model.Annotation, error) {
+	if len(workspaceIds) == 0 {
+		return nil, ErrAnnotationNotFound
+	}
+
+	annotation := &model.Annotation{}
+	scope, args := inWorkspaces(workspaceIds)
+	query := `SELECT ` + annotationColumns + ` FROM annotations WHERE id = ? AND ` + scope
+
+	err := r.db.QueryRowContext(ctx, query, append([]any{id}, args...)...).Scan(&annotation.ID, &annotation.StartTime, &annotation.EndTime,
+		&annotation.Type, &annotation.Note, &annotation.UserID, &annotation.VideoID, &annotation.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAnnotationNotFound
+		}
+		return nil, err
+	}
+
+	return annotation, nil
 }
 
-func (r *annotationRepository) FindVideoId(id int) ([]*model.Annotation, error) {
+func (r *annotationRepository) FindVideoId(ctx context.Context, workspaceIds []int, id int) ([]*model.Annotation, error) {
 
 	annotations := []*model.Annotation{}
-	query := `SELECT * FROM annotations WHERE id = ?`
+	if len(workspaceIds) == 0 {
+		return annotations, nil
+	}
+	scope, args := inWorkspaces(workspaceIds)
+	query := `SELECT ` + annotationColumns + ` FROM annotations WHERE video_id = ? AND ` + scope
 
-	rows, err := r.db.Query(query, id)
+	rows, err := r.db.QueryContext(ctx, query, append([]any{id}, args...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrAnnotationNotFound
@@ -42,7 +85,41 @@ func (r *annotationRepository) FindVideoId(id int) ([]*model.Annotation, error)
 	for rows.Next() {
 		annotation := &model.Annotation{}
 		err := rows.Scan(&annotation.ID, &annotation.StartTime, &annotation.EndTime,
-			&annotation.Type, &annotation.Note, &annotation.UserID, &annotation.VideoID)
+			&annotation.Type, &annotation.Note, &annotation.UserID, &annotation.VideoID, &annotation.Version)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, annotation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// FindByVideoIds loads the annotations of several videos with a single query.
+func (r *annotationRepository) FindByVideoIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Annotation, error) {
+	annotations := []*model.Annotation{}
+	if len(workspaceIds) == 0 || len(ids) == 0 {
+		return annotations, nil
+	}
+
+	in, args := inClause(ids)
+	scope, scopeArgs := inWorkspaces(workspaceIds)
+	query := `SELECT ` + annotationColumns + ` FROM annotations WHERE video_id IN ` + in + ` AND ` + scope + ` ORDER BY video_id, start_time`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, scopeArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		annotation := &model.Annotation{}
+		err := rows.Scan(&annotation.ID, &annotation.StartTime, &annotation.EndTime,
+			&annotation.Type, &annotation.Note, &annotation.UserID, &annotation.VideoID, &annotation.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -56,16 +133,140 @@ func (r *annotationRepository) FindVideoId(id int) ([]*model.Annotation, error)
 	return annotations, nil
 }
 
-func (r *annotationRepository) Update(id int, annotation *model.Annotation) error {
+// Update stores the previous state of the annotation as a new revision
+// whenever one of the tracked fields changes and returns it. The write is
+// rejected with ports.ErrVersionConflict when annotation.Version is stale.
+func (r *annotationRepository) Update(ctx context.Context, workspaceIds []int, id int, annotation *model.Annotation) (*model.AnnotationRevision, error) {
+	if len(workspaceIds) == 0 {
+		return nil, ErrAnnotationNotFound
+	}
+
+	var written *model.AnnotationRevision
+	scope, scopeArgs := inWorkspaces(workspaceIds)
+	err := inTransaction(ctx, r.db, func(tx executor) error {
+		previous := &model.Annotation{}
+		query := `SELECT start_time, end_time, type, note, version FROM annotations WHERE id = ? AND ` + scope
+		err := tx.QueryRowContext(ctx, query, append([]any{id}, scopeArgs...)...).Scan(&previous.StartTime, &previous.EndTime, &previous.Type, &previous.Note, &previous.Version)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrAnnotationNotFound
+			}
+			return err
+		}
+
+		if previous.Version != annotation.Version {
+			return ports.ErrVersionConflict
+		}
+
+		if annotationChanged(previous, annotation) {
+			if written, err = storeRevision(ctx, tx, id, previous); err != nil {
+				return err
+			}
+		}
+
+		query = `UPDATE annotations SET start_time = ?, end_time = ?, type = ?, note = ?, version = version + 1
+	WHERE id = ? AND version = ? AND ` + scope
+		args := append([]any{annotation.StartTime, annotation.EndTime, annotation.Type, annotation.Note, id, annotation.Version}, scopeArgs...)
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		return checkVersionedWrite(ctx, tx, result, "annotations", id, scope, scopeArgs, ErrAnnotationNotFound)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+// storeRevision keeps previous as the next revision of the annotation id.
+func storeRevision(ctx context.Context, tx executor, id int, previous *model.Annotation) (*model.AnnotationRevision, error) {
+	revision := &model.AnnotationRevision{AnnotationID: id, StartTime: previous.StartTime, EndTime: previous.EndTime,
+		Type: previous.Type, Note: previous.Note, CreatedAt: time.Now()}
+
+	query := `INSERT INTO annotation_revisions (annotation_id, revision, start_time, end_time, type, note, created_at)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ? FROM annotation_revisions WHERE annotation_id = ?`
+	result, err := tx.ExecContext(ctx, query, id, revision.StartTime, revision.EndTime, revision.Type, revision.Note, revision.CreatedAt, id)
+	if err != nil {
+		return nil, err
+	}
+	revisionId, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	revision.ID = int(revisionId)
+
+	query = `SELECT revision FROM annotation_revisions WHERE id = ?`
+	if err := tx.QueryRowContext(ctx, query, revision.ID).Scan(&revision.Revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (r *annotationRepository) FindRevisions(ctx context.Context, workspaceIds []int, annotationId int) ([]*model.AnnotationRevision, error) {
+	revisions := []*model.AnnotationRevision{}
+	if len(workspaceIds) == 0 {
+		return revisions, nil
+	}
+	scope, args := inWorkspaces(workspaceIds)
+	query := `SELECT id, annotation_id, revision, start_time, end_time, type, note, created_at
+	FROM annotation_revisions WHERE annotation_id = ? AND annotation_id IN (SELECT id FROM annotations WHERE ` + scope + `)
+	ORDER BY revision`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]any{annotationId}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		revision := &model.AnnotationRevision{}
+		err := rows.Scan(&revision.ID, &revision.AnnotationID, &revision.Revision, &revision.StartTime,
+			&revision.EndTime, &revision.Type, &revision.Note, &revision.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// inWorkspaces restricts a query of annotations to the videos of
+// workspaceIds.
+func inWorkspaces(workspaceIds []int) (string, []any) {
+	in, args := inClause(workspaceIds)
+	return `video_id IN (SELECT id FROM videos WHERE workspace_id IN ` + in + `)`, args
+}
+
+func annotationChanged(previous, current *model.Annotation) bool {
+	return previous.StartTime != current.StartTime ||
+		previous.EndTime != current.EndTime ||
+		previous.Type != current.Type ||
+		previous.Note != current.Note
+}
 
-	query := `UPDATE annotations SET start_time = ?, end_time = ?, type = ?, note = ? WHERE id = ?`
-	_, err := r.db.Exec(query, annotation.StartTime, annotation.EndTime, annotation.Type, annotation.Note, id)
+// Transfer gives the annotations of the video to userId, they belong to
+// the owner of their video.
+func (r *annotationRepository) Transfer(ctx context.Context, videoId int, userId int) error {
+	query := `UPDATE annotations SET user_id = ? WHERE video_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userId, videoId)
 	return err
 }
 
-func (r *annotationRepository) Remove(id int) error {
+// Remove deletes the annotation when its video belongs to one of
+// workspaceIds, otherwise it leaves it alone.
+func (r *annotationRepository) Remove(ctx context.Context, workspaceIds []int, id int) error {
+	if len(workspaceIds) == 0 {
+		return nil
+	}
 
-	query := `DELETE FROM annotations WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+	scope, args := inWorkspaces(workspaceIds)
+	query := `DELETE FROM annotations WHERE id = ? AND ` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]any{id}, args...)...)
 	return err
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,14 +31,14 @@ func TestAnnotationRepository_Create_HappyPath(t *testing.T) {
 		Note:      note,
 	}
 	videoId := 1
-	userId := 1
+	userId := 2
 
 	mock.ExpectExec("INSERT INTO annotations").
 		WithArgs(startTime, endTime, tp, note, userId, videoId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// test
-	err := repo.Create(annotation, userId, videoId)
+	err := repo.Create(context.Background(), annotation, videoId, userId)
 
 	// assertions
 	require.NoError(t, err)
@@ -57,10 +59,10 @@ func TestAnnotationRepository_Find_HappyPath(t *testing.T) {
 	tp := "test"
 	note := "test note"
 
-	rows := sqlmock.NewRows([]string{"id", "start_time", "end_time", "type", "note", "user_id", "video_id"}).
-		AddRow(id, startTime, endTime, tp, note, userId, videoId)
-	mock.ExpectQuery("SELECT \\* FROM annotations").
-		WithArgs(id).
+	rows := sqlmock.NewRows([]string{"id", "start_time", "end_time", "type", "note", "user_id", "video_id", "version"}).
+		AddRow(id, startTime, endTime, tp, note, userId, videoId, 1)
+	mock.ExpectQuery("SELECT (.+) FROM annotations WHERE video_id = \\? AND video_id IN \\(SELECT id FROM videos WHERE workspace_id IN \\(\\?\\)\\)").
+		WithArgs(id, 7).
 		WillReturnRows(rows)
 
 	expected := []*model.Annotation{
@@ -72,17 +74,58 @@ func TestAnnotationRepository_Find_HappyPath(t *testing.T) {
 			EndTime:   endTime,
 			Type:      tp,
 			Note:      note,
+			Version:   1,
 		},
 	}
 
 	// test
-	annotation, err := repo.FindVideoId(id)
+	annotation, err := repo.FindVideoId(context.Background(), []int{7}, id)
 
 	// assertions
 	require.NoError(t, err)
 	require.Equal(t, expected, annotation)
 }
 
+func TestAnnotationRepository_FindByVideoIds_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "start_time", "end_time", "type", "note", "user_id", "video_id", "version"}).
+		AddRow(1, time.Duration(0), time.Duration(2), "test", "first", 1, 1, 1).
+		AddRow(2, time.Duration(1), time.Duration(3), "test", "second", 1, 2, 1)
+	mock.ExpectQuery("SELECT (.+) FROM annotations WHERE video_id IN \\(\\?, \\?\\) AND video_id IN \\(SELECT id FROM videos WHERE workspace_id IN \\(\\?, \\?\\)\\)").
+		WithArgs(1, 2, 7, 8).
+		WillReturnRows(rows)
+
+	// test
+	annotations, err := repo.FindByVideoIds(context.Background(), []int{7, 8}, []int{1, 2})
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	require.Equal(t, 2, annotations[1].VideoID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnotationRepository_FindByVideoIds_HappyPath_NoIds(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	// test
+	annotations, err := repo.FindByVideoIds(context.Background(), []int{7}, nil)
+
+	// assertions
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestAnnotationRepository_Find_UnhappyPath_NotFound(t *testing.T) {
 	beforeEach(t)
 	defer afterEach()
@@ -92,12 +135,12 @@ func TestAnnotationRepository_Find_UnhappyPath_NotFound(t *testing.T) {
 
 	id := 1
 
-	mock.ExpectQuery("SELECT \\* FROM annotations").
-		WithArgs(id).
+	mock.ExpectQuery("SELECT (.+) FROM annotations WHERE video_id = ?").
+		WithArgs(id, 7).
 		WillReturnError(sql.ErrNoRows)
 
 	// test
-	annotation, err := repo.FindVideoId(id)
+	annotation, err := repo.FindVideoId(context.Background(), []int{7}, id)
 
 	// assertions
 	require.EqualError(t, err, ErrAnnotationNotFound.Error())
@@ -122,20 +165,39 @@ func TestAnnotationRepository_Update_HappyPath(t *testing.T) {
 		EndTime:   endTime,
 		Type:      tp,
 		Note:      note,
+		Version:   1,
 	}
 
+	previous := sqlmock.NewRows([]string{"start_time", "end_time", "type", "note", "version"}).
+		AddRow(startTime, endTime, tp, "old note", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT start_time, end_time, type, note, version FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
+		WillReturnRows(previous)
+	mock.ExpectExec("INSERT INTO annotation_revisions").
+		WithArgs(id, startTime, endTime, tp, "old note", sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectQuery("SELECT revision FROM annotation_revisions WHERE id = \\?").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"revision"}).AddRow(2))
 	mock.ExpectExec("UPDATE annotations").
-		WithArgs(startTime, endTime, tp, note, id).
+		WithArgs(startTime, endTime, tp, note, id, 1, 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// test
-	err := repo.Update(id, annotation)
+	written, err := repo.Update(context.Background(), []int{1}, id, annotation)
 
 	// assertions
 	require.NoError(t, err)
+	require.Equal(t, 4, written.ID)
+	require.Equal(t, 2, written.Revision)
+	require.Equal(t, "old note", written.Note)
+	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestAnnotationRepository_Update_UnhappyPath_NotFound(t *testing.T) {
+func TestAnnotationRepository_Update_HappyPath_Unchanged(t *testing.T) {
 	beforeEach(t)
 	defer afterEach()
 
@@ -153,17 +215,198 @@ func TestAnnotationRepository_Update_UnhappyPath_NotFound(t *testing.T) {
 		EndTime:   endTime,
 		Type:      tp,
 		Note:      note,
+		Version:   1,
 	}
 
+	previous := sqlmock.NewRows([]string{"start_time", "end_time", "type", "note", "version"}).
+		AddRow(startTime, endTime, tp, note, 1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT start_time, end_time, type, note, version FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
+		WillReturnRows(previous)
 	mock.ExpectExec("UPDATE annotations").
-		WithArgs(startTime, endTime, tp, note, id).
+		WithArgs(startTime, endTime, tp, note, id, 1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// test
+	written, err := repo.Update(context.Background(), []int{1}, id, annotation)
+
+	// assertions
+	require.NoError(t, err)
+	require.Nil(t, written)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnotationRepository_Update_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	id := 1
+	startTime := time.Duration(0)
+	endTime := time.Duration(2)
+	tp := "test"
+	note := "test note"
+
+	annotation := &model.Annotation{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Type:      tp,
+		Note:      note,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT start_time, end_time, type, note, version FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
 		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
 	// test
-	err := repo.Update(id, annotation)
+	_, err := repo.Update(context.Background(), []int{1}, id, annotation)
 
 	// assertions
-	require.Error(t, err)
+	require.EqualError(t, err, ErrAnnotationNotFound.Error())
+}
+
+func TestAnnotationRepository_Update_UnhappyPath_VersionConflict(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	id := 1
+	annotation := &model.Annotation{
+		StartTime: time.Duration(0),
+		EndTime:   time.Duration(2),
+		Type:      "test",
+		Note:      "test note",
+		Version:   1,
+	}
+
+	previous := sqlmock.NewRows([]string{"start_time", "end_time", "type", "note", "version"}).
+		AddRow(annotation.StartTime, annotation.EndTime, annotation.Type, "old note", 2)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT start_time, end_time, type, note, version FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
+		WillReturnRows(previous)
+	mock.ExpectRollback()
+
+	// test
+	_, err := repo.Update(context.Background(), []int{1}, id, annotation)
+
+	// assertions
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnotationRepository_FindById_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	id := 1
+	videoId := 1
+	userId := 1
+	startTime := time.Duration(0)
+	endTime := time.Duration(2)
+	tp := "test"
+	note := "test note"
+
+	rows := sqlmock.NewRows([]string{"id", "start_time", "end_time", "type", "note", "user_id", "video_id", "version"}).
+		AddRow(id, startTime, endTime, tp, note, userId, videoId, 1)
+	mock.ExpectQuery("SELECT (.+) FROM annotations WHERE id = \\? AND video_id IN \\(SELECT id FROM videos WHERE workspace_id IN \\(\\?\\)\\)").
+		WithArgs(id, 7).
+		WillReturnRows(rows)
+
+	expected := &model.Annotation{
+		ID:        id,
+		VideoID:   videoId,
+		UserID:    userId,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Type:      tp,
+		Note:      note,
+		Version:   1,
+	}
+
+	// test
+	annotation, err := repo.FindById(context.Background(), []int{7}, id)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, expected, annotation)
+}
+
+func TestAnnotationRepository_FindById_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	id := 1
+
+	mock.ExpectQuery("SELECT (.+) FROM annotations WHERE id = ?").
+		WithArgs(id, 7).
+		WillReturnError(sql.ErrNoRows)
+
+	// test
+	annotation, err := repo.FindById(context.Background(), []int{7}, id)
+
+	// assertions
+	require.EqualError(t, err, ErrAnnotationNotFound.Error())
+	require.Nil(t, annotation)
+}
+
+func TestAnnotationRepository_FindById_UnhappyPath_NoWorkspaces(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	// test
+	annotation, err := repo.FindById(context.Background(), nil, 1)
+
+	// assertions
+	require.ErrorIs(t, err, ErrAnnotationNotFound)
+	require.Nil(t, annotation)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnotationRepository_FindRevisions_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	repo := NewAnnotationRepository(db)
+
+	annotationId := 1
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "annotation_id", "revision", "start_time", "end_time", "type", "note", "created_at"}).
+		AddRow(1, annotationId, 1, time.Duration(1), time.Duration(2), "test", "first note", createdAt).
+		AddRow(2, annotationId, 2, time.Duration(1), time.Duration(3), "test", "second note", createdAt)
+	mock.ExpectQuery("SELECT (.+) FROM annotation_revisions WHERE annotation_id = \\? AND annotation_id IN \\(SELECT id FROM annotations WHERE video_id IN").
+		WithArgs(annotationId, 7).
+		WillReturnRows(rows)
+
+	// test
+	revisions, err := repo.FindRevisions(context.Background(), []int{7}, annotationId)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, 2, revisions[1].Revision)
+	require.Equal(t, "second note", revisions[1].Note)
 }
 
 func TestAnnotationRepository_Remove_HappyPath(t *testing.T) {
@@ -175,12 +418,12 @@ func TestAnnotationRepository_Remove_HappyPath(t *testing.T) {
 
 	id := 1
 
-	mock.ExpectExec("DELETE FROM annotations").
-		WithArgs(id).
+	mock.ExpectExec("DELETE FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// test
-	err := repo.Remove(id)
+	err := repo.Remove(context.Background(), []int{1}, id)
 
 	// assertions
 	require.NoError(t, err)
@@ -195,13 +438,32 @@ func TestAnnotationRepository_Remove_UnhappyPath_NotFound(t *testing.T) {
 
 	id := 1
 
-	mock.ExpectExec("DELETE FROM annotations").
-		WithArgs(id).
+	mock.ExpectExec("DELETE FROM annotations WHERE id = \\? AND video_id IN").
+		WithArgs(id, 1).
 		WillReturnError(errors.New("database error"))
 
 	// test
-	err := repo.Remove(id)
+	err := repo.Remove(context.Background(), []int{1}, id)
 
 	// assertions
 	require.Error(t, err)
 }
+
+func TestAnnotationRepository_Transfer_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	annotationRepo := NewAnnotationRepository(db)
+
+	mock.ExpectExec("UPDATE annotations SET user_id = \\? WHERE video_id = \\?").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// test
+	err := annotationRepo.Transfer(context.Background(), 1, 2)
+
+	// assertions
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+var ErrApiKeyNotFound = fmt.Errorf("api key not found")
+
+const apiKeyColumns = `id, user_id, name, key_hash, created_at, expires_at`
+
+type apiKeyRepository struct {
+	db executor
+}
+
+func NewApiKeyRepository(db *sql.DB) *apiKeyRepository {
+	return &apiKeyRepository{traced(db)}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.ApiKey) error {
+	query := `INSERT INTO api_keys (` + apiKeyColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.UserID, key.Name, key.Hash, key.CreatedAt, key.ExpiresAt)
+	return err
+}
+
+func (r *apiKeyRepository) Find(ctx context.Context, id string) (*model.ApiKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = ?`
+	key, err := scanApiKey(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrApiKeyNotFound
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) FindByUser(ctx context.Context, userId int) ([]*model.ApiKey, error) {
+	keys := []*model.ApiKey{}
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Remove(ctx context.Context, id string, userId int) error {
+	query := `DELETE FROM api_keys WHERE id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrApiKeyNotFound
+	}
+	return nil
+}
+
+func scanApiKey(row rowScanner) (*model.ApiKey, error) {
+	key := &model.ApiKey{}
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Hash, &key.CreatedAt, &key.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+var apiKeyRowColumns = []string{"id", "user_id", "name", "key_hash", "created_at", "expires_at"}
+
+func TestApiKeyRepository_Create_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	apiKeyRepo := NewApiKeyRepository(db)
+	now := time.Now()
+	key := &model.ApiKey{ID: "key-id", UserID: 1, Name: "ci", Hash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	mock.ExpectExec("INSERT INTO api_keys \\(id, user_id, name, key_hash, created_at, expires_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs("key-id", 1, "ci", "hash", key.CreatedAt, key.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// test
+	err := apiKeyRepo.Create(context.Background(), key)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApiKeyRepository_Find_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	apiKeyRepo := NewApiKeyRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE id = \\?").
+		WithArgs("key-id").
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).AddRow("key-id", 1, "ci", "hash", createdAt, createdAt.Add(time.Hour)))
+
+	// test
+	key, err := apiKeyRepo.Find(context.Background(), "key-id")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, &model.ApiKey{ID: "key-id", UserID: 1, Name: "ci", Hash: "hash", CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)}, key)
+}
+
+func TestApiKeyRepository_Find_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	apiKeyRepo := NewApiKeyRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE id = \\?").
+		WithArgs("key-id").
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns))
+
+	// test
+	key, err := apiKeyRepo.Find(context.Background(), "key-id")
+
+	// assert
+	require.ErrorIs(t, err, ErrApiKeyNotFound)
+	require.Nil(t, key)
+}
+
+func TestApiKeyRepository_FindByUser_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	apiKeyRepo := NewApiKeyRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE user_id = \\? ORDER BY created_at, id").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+			AddRow("first", 1, "ci", "hash", createdAt, createdAt).
+			AddRow("second", 1, "backup", "other-hash", createdAt, createdAt))
+
+	// test
+	keys, err := apiKeyRepo.FindByUser(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, "first", keys[0].ID)
+	require.Equal(t, "backup", keys[1].Name)
+}
+
+func TestApiKeyRepository_Remove(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	apiKeyRepo := NewApiKeyRepository(db)
+
+	mock.ExpectExec("DELETE FROM api_keys WHERE id = \\? AND user_id = \\?").
+		WithArgs("key-id", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM api_keys").
+		WithArgs("key-id", 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM api_keys").
+		WithArgs("key-id", 3).
+		WillReturnError(errors.New("database error"))
+
+	// test
+	err := apiKeyRepo.Remove(context.Background(), "key-id", 1)
+	notOwnedErr := apiKeyRepo.Remove(context.Background(), "key-id", 2)
+	failedErr := apiKeyRepo.Remove(context.Background(), "key-id", 3)
+
+	// assert
+	require.NoError(t, err)
+	require.ErrorIs(t, notOwnedErr, ErrApiKeyNotFound)
+	require.EqualError(t, failedErr, "database error")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type auditRepository struct {
+	db executor
+}
+
+func NewAuditRepository(db *sql.DB) *auditRepository {
+	return &auditRepository{traced(db)}
+}
+
+func (r *auditRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	query := `INSERT INTO audit_events (type, username, address, detail, occurred_at) VALUES (?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, event.Type, event.Username, event.Address, event.Detail, event.OccurredAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.ID = int(id)
+	return nil
+}
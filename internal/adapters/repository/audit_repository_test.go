@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestAuditRepository_Append_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	auditRepo := NewAuditRepository(db)
+	occurredAt := time.Now()
+	event := &model.AuditEvent{Type: model.AuditLoginLocked, Username: "johndoe", Address: "192.0.2.1", Detail: "5 failed logins", OccurredAt: occurredAt}
+
+	mock.ExpectExec("INSERT INTO audit_events").
+		WithArgs(model.AuditLoginLocked, "johndoe", "192.0.2.1", "5 failed logins", occurredAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	// test
+	err := auditRepo.Append(context.Background(), event)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 7, event.ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
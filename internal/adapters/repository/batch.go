@@ -0,0 +1,12 @@
+package repository
+
+import "strings"
+
+// inClause expands ids into "(?, ?, ?)" and the matching query arguments.
+func inClause(ids []int) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ")", args
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type loginAttemptRepository struct {
+	db executor
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *loginAttemptRepository {
+	return &loginAttemptRepository{traced(db)}
+}
+
+func (r *loginAttemptRepository) Find(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	query := `SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = ?`
+	attempts, err := scanLoginAttempts(r.db.QueryRowContext(ctx, query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return attempts, err
+}
+
+// AddFailure increments the count in a single statement, so concurrent
+// failures are all counted.
+func (r *loginAttemptRepository) AddFailure(ctx context.Context, key string, now time.Time, since time.Time) (*model.LoginAttempts, error) {
+	query := `INSERT INTO login_attempts (key, failures, last_failure_at) VALUES (?, 1, ?)
+	ON CONFLICT (key) DO UPDATE SET
+		failures = CASE WHEN last_failure_at < ? THEN 1 ELSE failures + 1 END,
+		locked_until = CASE WHEN last_failure_at < ? THEN NULL ELSE locked_until END,
+		last_failure_at = excluded.last_failure_at
+	RETURNING key, failures, last_failure_at, locked_until`
+	return scanLoginAttempts(r.db.QueryRowContext(ctx, query, key, now, since, since))
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = ? WHERE key = ?`
+	_, err := r.db.ExecContext(ctx, query, until, key)
+	return err
+}
+
+func (r *loginAttemptRepository) Delete(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts WHERE key = ?`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
+
+func scanLoginAttempts(row *sql.Row) (*model.LoginAttempts, error) {
+	attempts := &model.LoginAttempts{}
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&attempts.Key, &attempts.Failures, &attempts.LastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		attempts.LockedUntil = &lockedUntil.Time
+	}
+	return attempts, nil
+}
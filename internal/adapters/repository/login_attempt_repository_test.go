@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository_Find_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	loginAttemptRepo := NewLoginAttemptRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = \\?").
+		WithArgs("user:johndoe").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+			AddRow("user:johndoe", 5, now, now.Add(time.Minute)))
+
+	// test
+	attempts, err := loginAttemptRepo.Find(context.Background(), "user:johndoe")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 5, attempts.Failures)
+	require.Equal(t, now.Add(time.Minute), *attempts.LockedUntil)
+}
+
+func TestLoginAttemptRepository_Find_NoFailure(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	loginAttemptRepo := NewLoginAttemptRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM login_attempts").
+		WithArgs("user:johndoe").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}))
+
+	// test
+	attempts, err := loginAttemptRepo.Find(context.Background(), "user:johndoe")
+
+	// assert
+	require.NoError(t, err)
+	require.Nil(t, attempts)
+}
+
+func TestLoginAttemptRepository_AddFailure_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	loginAttemptRepo := NewLoginAttemptRepository(db)
+	now := time.Now()
+	since := now.Add(-15 * time.Minute)
+
+	mock.ExpectQuery("INSERT INTO login_attempts (.+) ON CONFLICT \\(key\\) DO UPDATE SET (.+) RETURNING").
+		WithArgs("ip:192.0.2.1", now, since, since).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+			AddRow("ip:192.0.2.1", 2, now, nil))
+
+	// test
+	attempts, err := loginAttemptRepo.AddFailure(context.Background(), "ip:192.0.2.1", now, since)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts.Failures)
+	require.Nil(t, attempts.LockedUntil)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_LockAndDelete(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	loginAttemptRepo := NewLoginAttemptRepository(db)
+	until := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectExec("UPDATE login_attempts SET locked_until = \\? WHERE key = \\?").
+		WithArgs(until, "user:johndoe").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM login_attempts WHERE key = \\?").
+		WithArgs("user:johndoe").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	lockErr := loginAttemptRepo.Lock(context.Background(), "user:johndoe", until)
+	deleteErr := loginAttemptRepo.Delete(context.Background(), "user:johndoe")
+
+	// assert
+	require.NoError(t, lockErr)
+	require.NoError(t, deleteErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
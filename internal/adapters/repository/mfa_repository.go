@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type mfaRepository struct {
+	db executor
+}
+
+func NewMfaRepository(db *sql.DB) *mfaRepository {
+	return &mfaRepository{traced(db)}
+}
+
+func (r *mfaRepository) Find(ctx context.Context, userID int) (*model.Mfa, error) {
+	mfa := &model.Mfa{}
+	query := `SELECT user_id, secret, enabled, last_step, created_at FROM user_mfa WHERE user_id = ?`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&mfa.UserID, &mfa.Secret, &mfa.Enabled, &mfa.LastStep, &mfa.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mfa, nil
+}
+
+func (r *mfaRepository) Save(ctx context.Context, mfa *model.Mfa) error {
+	query := `INSERT INTO user_mfa (user_id, secret, enabled, last_step, created_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (user_id) DO UPDATE SET
+		secret = excluded.secret,
+		enabled = excluded.enabled,
+		last_step = excluded.last_step,
+		created_at = excluded.created_at`
+	_, err := r.db.ExecContext(ctx, query, mfa.UserID, mfa.Secret, mfa.Enabled, mfa.LastStep, mfa.CreatedAt)
+	return err
+}
+
+// UseStep only moves the last step forward, so two requests with the same
+// code cannot both succeed.
+func (r *mfaRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET last_step = ? WHERE user_id = ? AND last_step < ?`
+	return affectsRow(r.db.ExecContext(ctx, query, step, userID, step))
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		for _, hash := range hashes {
+			query := `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES (?, ?)`
+			if _, err := tx.ExecContext(ctx, query, userID, hash); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error) {
+	query := `DELETE FROM mfa_recovery_codes WHERE user_id = ? AND code_hash = ?`
+	return affectsRow(r.db.ExecContext(ctx, query, userID, hash))
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID int) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = ?`, userID)
+		return err
+	})
+}
+
+func affectsRow(result sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
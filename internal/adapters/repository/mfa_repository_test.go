@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestMfaRepository_Find_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT user_id, secret, enabled, last_step, created_at FROM user_mfa WHERE user_id = \\?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled", "last_step", "created_at"}).
+			AddRow(1, "JBSWY3DPEHPK3PXP", true, 56789, now))
+
+	// test
+	mfa, err := mfaRepo.Find(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, &model.Mfa{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", Enabled: true, LastStep: 56789, CreatedAt: now}, mfa)
+}
+
+func TestMfaRepository_Find_NotEnrolled(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM user_mfa").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled", "last_step", "created_at"}))
+
+	// test
+	mfa, err := mfaRepo.Find(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.Nil(t, mfa)
+}
+
+func TestMfaRepository_Save_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+	mfa := &model.Mfa{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}
+
+	mock.ExpectExec("INSERT INTO user_mfa (.+) ON CONFLICT \\(user_id\\) DO UPDATE").
+		WithArgs(1, "JBSWY3DPEHPK3PXP", false, int64(0), mfa.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// test
+	err := mfaRepo.Save(context.Background(), mfa)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMfaRepository_UseStep(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectExec("UPDATE user_mfa SET last_step = \\? WHERE user_id = \\? AND last_step < \\?").
+		WithArgs(int64(56790), 1, int64(56790)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user_mfa SET last_step").
+		WithArgs(int64(56790), 1, int64(56790)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// test
+	first, firstErr := mfaRepo.UseStep(context.Background(), 1, 56790)
+	replayed, replayedErr := mfaRepo.UseStep(context.Background(), 1, 56790)
+
+	// assert
+	require.NoError(t, firstErr)
+	require.True(t, first)
+	require.NoError(t, replayedErr)
+	require.False(t, replayed)
+}
+
+func TestMfaRepository_ReplaceRecoveryCodes_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM mfa_recovery_codes WHERE user_id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO mfa_recovery_codes").
+		WithArgs(1, "hash-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO mfa_recovery_codes").
+		WithArgs(1, "hash-2").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := mfaRepo.ReplaceRecoveryCodes(context.Background(), 1, []string{"hash-1", "hash-2"})
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMfaRepository_ReplaceRecoveryCodes_UnhappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM mfa_recovery_codes").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO mfa_recovery_codes").
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	// test
+	err := mfaRepo.ReplaceRecoveryCodes(context.Background(), 1, []string{"hash-1", "hash-2"})
+
+	// assert
+	require.EqualError(t, err, "database error")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMfaRepository_UseRecoveryCode(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectExec("DELETE FROM mfa_recovery_codes WHERE user_id = \\? AND code_hash = \\?").
+		WithArgs(1, "hash-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM mfa_recovery_codes").
+		WithArgs(1, "hash-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// test
+	first, firstErr := mfaRepo.UseRecoveryCode(context.Background(), 1, "hash-1")
+	again, againErr := mfaRepo.UseRecoveryCode(context.Background(), 1, "hash-1")
+
+	// assert
+	require.NoError(t, firstErr)
+	require.True(t, first)
+	require.NoError(t, againErr)
+	require.False(t, again)
+}
+
+func TestMfaRepository_Delete_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mfaRepo := NewMfaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM mfa_recovery_codes WHERE user_id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec("DELETE FROM user_mfa WHERE user_id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := mfaRepo.Delete(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
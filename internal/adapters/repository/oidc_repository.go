@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type oidcRepository struct {
+	db executor
+}
+
+func NewOidcRepository(db *sql.DB) *oidcRepository {
+	return &oidcRepository{traced(db)}
+}
+
+func (r *oidcRepository) SaveLogin(ctx context.Context, login *model.OidcLogin) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM oidc_logins WHERE expires_at <= ?`, time.Now().UTC()); err != nil {
+			return err
+		}
+		query := `INSERT INTO oidc_logins (state, code_verifier, nonce, expires_at) VALUES (?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, login.State, login.CodeVerifier, login.Nonce, login.ExpiresAt)
+		return err
+	})
+}
+
+// TakeLogin deletes the login as it reads it, so a callback replayed at the
+// same time is refused.
+func (r *oidcRepository) TakeLogin(ctx context.Context, state string, now time.Time) (*model.OidcLogin, error) {
+	login := &model.OidcLogin{}
+	query := `DELETE FROM oidc_logins WHERE state = ? AND expires_at > ?
+	RETURNING state, code_verifier, nonce, expires_at`
+	err := r.db.QueryRowContext(ctx, query, state, now).Scan(&login.State, &login.CodeVerifier, &login.Nonce, &login.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return login, nil
+}
+
+func (r *oidcRepository) FindUser(ctx context.Context, issuer string, subject string) (*model.User, error) {
+	user := &model.User{}
+	query := `SELECT ` + userColumns + ` FROM users
+	WHERE id = (SELECT user_id FROM user_identities WHERE issuer = ? AND subject = ?)`
+	err := r.db.QueryRowContext(ctx, query, issuer, subject).Scan(userFields(user)...)
+	if err == sql.ErrNoRows {
+		return nil, UserNotFoundError
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *oidcRepository) Link(ctx context.Context, issuer string, subject string, userID int) error {
+	query := `INSERT INTO user_identities (issuer, subject, user_id, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, issuer, subject, userID, time.Now().UTC())
+	return err
+}
+
+func (r *oidcRepository) Provision(ctx context.Context, user *model.User, issuer string, subject string) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		if err := (&userRepository{db: tx}).Save(ctx, user); err != nil {
+			return err
+		}
+		return (&oidcRepository{db: tx}).Link(ctx, issuer, subject, user.ID)
+	})
+}
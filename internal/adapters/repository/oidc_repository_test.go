@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestOidcRepository_SaveLogin_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	oidcRepo := NewOidcRepository(db)
+	login := &model.OidcLogin{State: "state", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM oidc_logins WHERE expires_at <= \\?").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO oidc_logins \\(state, code_verifier, nonce, expires_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs("state", "verifier", "nonce", login.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := oidcRepo.SaveLogin(context.Background(), login)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOidcRepository_TakeLogin(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	oidcRepo := NewOidcRepository(db)
+	now := time.Now()
+	login := &model.OidcLogin{State: "state", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: now.Add(time.Minute)}
+
+	mock.ExpectQuery("DELETE FROM oidc_logins WHERE state = \\? AND expires_at > \\?\\s+RETURNING state, code_verifier, nonce, expires_at").
+		WithArgs("state", now).
+		WillReturnRows(sqlmock.NewRows([]string{"state", "code_verifier", "nonce", "expires_at"}).
+			AddRow(login.State, login.CodeVerifier, login.Nonce, login.ExpiresAt))
+	mock.ExpectQuery("DELETE FROM oidc_logins").
+		WithArgs("state", now).
+		WillReturnError(sql.ErrNoRows)
+
+	// test
+	taken, err := oidcRepo.TakeLogin(context.Background(), "state", now)
+	takenAgain, againErr := oidcRepo.TakeLogin(context.Background(), "state", now)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, login, taken)
+	require.NoError(t, againErr)
+	require.Nil(t, takenAgain)
+}
+
+func TestOidcRepository_FindUser_UnhappyPath_NotLinked(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	oidcRepo := NewOidcRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM users\\s+WHERE id = \\(SELECT user_id FROM user_identities WHERE issuer = \\? AND subject = \\?\\)").
+		WithArgs("https://idp.example.com", "subject").
+		WillReturnError(sql.ErrNoRows)
+
+	// test
+	user, err := oidcRepo.FindUser(context.Background(), "https://idp.example.com", "subject")
+
+	// assert
+	require.Nil(t, user)
+	require.ErrorIs(t, err, UserNotFoundError)
+}
+
+func TestOidcRepository_Provision_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	oidcRepo := NewOidcRepository(db)
+	user := &model.User{Username: "johndoe", Email: "johndoe@example.com", EmailVerified: true, Role: model.RoleViewer, CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs("johndoe", "", "johndoe@example.com", true, model.RoleViewer, false, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO workspaces").
+		WithArgs("johndoe", user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO workspace_members").
+		WithArgs(3, 7, model.RoleAdmin, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_identities \\(issuer, subject, user_id, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs("https://idp.example.com", "subject", 7, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := oidcRepo.Provision(context.Background(), user, "https://idp.example.com", "subject")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 7, user.ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOidcRepository_Provision_UnhappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	oidcRepo := NewOidcRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO workspaces").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO workspace_members").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_identities").
+		WillReturnError(errors.New("UNIQUE constraint failed"))
+	mock.ExpectRollback()
+
+	// test
+	err := oidcRepo.Provision(context.Background(), &model.User{Username: "johndoe"}, "https://idp.example.com", "subject")
+
+	// assert
+	require.EqualError(t, err, "UNIQUE constraint failed")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
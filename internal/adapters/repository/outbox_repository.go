@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type outboxRepository struct {
+	db executor
+}
+
+func NewOutboxRepository(db *sql.DB) *outboxRepository {
+	return &outboxRepository{traced(db)}
+}
+
+// Append stores the event to be dispatched, it is due right away.
+func (r *outboxRepository) Append(ctx context.Context, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox (event_id, event_type, video_id, payload, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = r.db.ExecContext(ctx, query, event.ID, event.Type, event.VideoID, payload, event.OccurredAt, event.OccurredAt)
+	return err
+}
+
+// FindDue keeps the events of a video in order: an entry waiting for its next
+// attempt holds back every later entry of the same video.
+func (r *outboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEntry, error) {
+	query := `SELECT o.id, o.payload, o.attempts, o.last_error, o.next_attempt_at, o.created_at FROM outbox o
+	WHERE o.next_attempt_at <= ? AND NOT EXISTS (
+		SELECT 1 FROM outbox p WHERE p.video_id = o.video_id AND p.id < o.id AND p.next_attempt_at > ?
+	) ORDER BY o.id LIMIT ?`
+
+	entries := []*model.OutboxEntry{}
+	rows, err := r.db.QueryContext(ctx, query, now, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &model.OutboxEntry{}
+		var payload []byte
+		err := rows.Scan(&entry.ID, &payload, &entry.Attempts, &entry.LastError, &entry.NextAttemptAt, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &entry.Event); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *outboxRepository) Reschedule(ctx context.Context, entry *model.OutboxEntry) error {
+	query := `UPDATE outbox SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, entry.Attempts, entry.LastError, entry.NextAttemptAt, entry.ID)
+	return err
+}
+
+func (r *outboxRepository) Remove(ctx context.Context, id int) error {
+	query := `DELETE FROM outbox WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_Append_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	outboxRepo := NewOutboxRepository(db)
+	occurredAt := time.Now()
+	event := &model.Event{ID: "evt-1", Type: model.EventVideoCreated, VideoID: 3, OccurredAt: occurredAt}
+	payload, _ := json.Marshal(event)
+
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs("evt-1", model.EventVideoCreated, 3, payload, occurredAt, occurredAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// test
+	err := outboxRepo.Append(context.Background(), event)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_FindDue_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	outboxRepo := NewOutboxRepository(db)
+	now := time.Now()
+	payload := []byte(`{"ID":"evt-1","Type":"video.created","VideoID":3}`)
+
+	mock.ExpectQuery("SELECT (.+) FROM outbox o WHERE o.next_attempt_at <= \\? AND NOT EXISTS").
+		WithArgs(now, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "attempts", "last_error", "next_attempt_at", "created_at"}).
+			AddRow(1, payload, 2, "subscriber unavailable", now, now))
+
+	// test
+	entries, err := outboxRepo.FindDue(context.Background(), now, 10)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, 2, entries[0].Attempts)
+	require.Equal(t, "evt-1", entries[0].Event.ID)
+	require.Equal(t, 3, entries[0].Event.VideoID)
+}
+
+func TestOutboxRepository_FindDue_InvalidPayload(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	outboxRepo := NewOutboxRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM outbox o").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "attempts", "last_error", "next_attempt_at", "created_at"}).
+			AddRow(1, []byte(`not json`), 0, "", now, now))
+
+	// test
+	_, err := outboxRepo.FindDue(context.Background(), now, 10)
+
+	// assert
+	require.Error(t, err)
+}
+
+func TestOutboxRepository_Reschedule_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	outboxRepo := NewOutboxRepository(db)
+	next := time.Now()
+	entry := &model.OutboxEntry{ID: 1, Attempts: 2, LastError: "subscriber unavailable", NextAttemptAt: next}
+
+	mock.ExpectExec("UPDATE outbox SET attempts = \\?, last_error = \\?, next_attempt_at = \\? WHERE id = \\?").
+		WithArgs(2, "subscriber unavailable", next, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := outboxRepo.Reschedule(context.Background(), entry)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_Remove_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	outboxRepo := NewOutboxRepository(db)
+
+	mock.ExpectExec("DELETE FROM outbox WHERE id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := outboxRepo.Remove(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
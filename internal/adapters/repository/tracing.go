@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var tracer = tracing.Tracer("internal/adapters/repository")
+
+// tracedExecutor runs every statement of the wrapped executor in a client
+// span and logs it at debug level. Spans of queries end once the rows are
+// returned, reading them is not part of the span.
+type tracedExecutor struct {
+	db executor
+}
+
+func traced(db executor) executor {
+	return &tracedExecutor{db}
+}
+
+func (t *tracedExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startStatement(ctx, query)
+	start := time.Now()
+	result, err := t.db.ExecContext(ctx, query, args...)
+	endStatement(ctx, span, query, start, err)
+	return result, err
+}
+
+func (t *tracedExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startStatement(ctx, query)
+	start := time.Now()
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	endStatement(ctx, span, query, start, err)
+	return rows, err
+}
+
+func (t *tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startStatement(ctx, query)
+	start := time.Now()
+	row := t.db.QueryRowContext(ctx, query, args...)
+	err := row.Err()
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	endStatement(ctx, span, query, start, err)
+	return row
+}
+
+// startStatement names the span after the SQL operation, the statement itself
+// carries placeholders only, never argument values. Statements outside of a
+// trace, like the polls of the background workers, are not traced or every
+// poll would start a trace of its own.
+func startStatement(ctx context.Context, query string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	operation := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+func endStatement(ctx context.Context, span trace.Span, query string, start time.Time, err error) {
+	tracing.End(span, err)
+
+	logger := logging.FromContext(ctx)
+	if err != nil {
+		logger = logger.With("error", err)
+	}
+	logger.Debug("sql statement", "statement", query, "duration", time.Since(start))
+}
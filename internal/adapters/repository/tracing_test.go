@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+func TestTracedExecutor_SpanPerStatement(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	spans := recordSpans()
+	transactor := NewTransactor(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM videos").
+		WithArgs(3, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	// test
+	err := transactor.Transaction(context.Background(), func(ctx context.Context, tx *ports.Tx) error {
+		return tx.Videos.Remove(ctx, []int{1}, 3, 1)
+	})
+
+	// assert
+	require.ErrorIs(t, err, VideoNotFoundError)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	ended := spans()
+	require.Len(t, ended, 3)
+	remove, count, transaction := ended[0], ended[1], ended[2]
+	require.Equal(t, "DELETE", remove.Name())
+	require.Contains(t, remove.Attributes(), attribute.String("db.statement", `DELETE FROM videos WHERE id = ? AND version = ? AND workspace_id IN (?)`))
+	require.Equal(t, "SELECT", count.Name())
+	require.Equal(t, "Transaction", transaction.Name())
+	require.Equal(t, codes.Error, transaction.Status().Code)
+	require.Equal(t, transaction.SpanContext().SpanID(), remove.Parent().SpanID())
+	require.Equal(t, transaction.SpanContext().SpanID(), count.Parent().SpanID())
+}
+
+func TestTracedExecutor_NoSpanOutsideOfATrace(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	spans := recordSpans()
+	outboxRepo := NewOutboxRepository(db)
+
+	mock.ExpectExec("DELETE FROM outbox").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := outboxRepo.Remove(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Empty(t, spans())
+}
+
+var (
+	spanRecorder = tracetest.NewSpanRecorder()
+	installOnce  sync.Once
+)
+
+// recordSpans returns the spans that ended after it was called.
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	installOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	start := len(spanRecorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return spanRecorder.Ended()[start:]
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+// executor is satisfied by both *sql.DB and *sql.Tx, repositories built by
+// the transactor run their statements in its transaction.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inTransaction runs fn in the transaction db belongs to, or in a new one
+// when db is not part of a transaction yet.
+func inTransaction(ctx context.Context, db executor, fn func(tx executor) error) error {
+	raw := db
+	if t, ok := db.(*tracedExecutor); ok {
+		raw = t.db
+	}
+	if _, ok := raw.(*sql.Tx); ok {
+		return fn(db)
+	}
+
+	tx, err := raw.(*sql.DB).BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(traced(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type transactor struct {
+	db          *sql.DB
+	afterCommit []func()
+}
+
+func NewTransactor(db *sql.DB) *transactor {
+	return &transactor{db: db}
+}
+
+// AfterCommit registers a hook that runs after every committed transaction.
+func (t *transactor) AfterCommit(hook func()) {
+	t.afterCommit = append(t.afterCommit, hook)
+}
+
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context, tx *ports.Tx) error) (err error) {
+	ctx, span := tracer.Start(ctx, "Transaction")
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	db := traced(tx)
+	err = fn(ctx, &ports.Tx{
+		Users:       &userRepository{db},
+		Videos:      &videoRepository{db},
+		Annotations: &annotationRepository{db},
+		Outbox:      &outboxRepository{db},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, hook := range t.afterCommit {
+		hook()
+	}
+	return nil
+}
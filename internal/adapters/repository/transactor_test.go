@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactor_Transaction_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	transactor := NewTransactor(db)
+	committed := 0
+	transactor.AfterCommit(func() { committed++ })
+	event := &model.Event{ID: "evt-1", Type: model.EventVideoDeleted, VideoID: 3, OccurredAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM videos").
+		WithArgs(3, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := transactor.Transaction(context.Background(), func(ctx context.Context, tx *ports.Tx) error {
+		if err := tx.Videos.Remove(ctx, []int{1}, 3, 1); err != nil {
+			return err
+		}
+		return tx.Outbox.Append(ctx, event)
+	})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 1, committed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_Transaction_RollsBackOnError(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	transactor := NewTransactor(db)
+	committed := 0
+	transactor.AfterCommit(func() { committed++ })
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM videos").
+		WithArgs(3, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	// test
+	err := transactor.Transaction(context.Background(), func(ctx context.Context, tx *ports.Tx) error {
+		if err := tx.Videos.Remove(ctx, []int{1}, 3, 1); err != nil {
+			return err
+		}
+		return errors.New("outbox error")
+	})
+
+	// assert
+	require.EqualError(t, err, "outbox error")
+	require.Zero(t, committed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,28 +1,36 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 )
 
 var (
-	UserNotFoundError = fmt.Errorf("user not found")
+	UserNotFoundError  = fmt.Errorf("user not found")
+	UsernameTakenError = fmt.Errorf("username is taken")
+	EmailTakenError    = fmt.Errorf("email is taken")
 )
 
 type userRepository struct {
-	db *sql.DB
+	db executor
 }
 
 func NewUserRepository(db *sql.DB) *userRepository {
-	return &userRepository{db: db}
+	return &userRepository{db: traced(db)}
 }
 
-func (u *userRepository) FindByUsername(username string) (*model.User, error) {
+func (u *userRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	user := &model.User{}
-	query := `SELECT * FROM users WHERE username = ?`
-	err := u.db.QueryRow(query, username).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.CreatedAt)
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = ?`
+	err := u.db.QueryRowContext(ctx, query, username).Scan(userFields(user)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, UserNotFoundError
@@ -33,8 +41,185 @@ func (u *userRepository) FindByUsername(username string) (*model.User, error) {
 	return user, nil
 }
 
-func (u *userRepository) Save(user *model.User) error {
-	query := `INSERT INTO users (username, password, email, created_at) VALUES (?, ?, ?, ?)`
-	_, err := u.db.Exec(query, user.Username, user.Password, user.Email, user.CreatedAt)
+func (u *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	user := &model.User{}
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = ?`
+	err := u.db.QueryRowContext(ctx, query, email).Scan(userFields(user)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, UserNotFoundError
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FindByIds loads several users with a single query, missing ids are skipped.
+func (u *userRepository) FindByIds(ctx context.Context, ids []int) ([]*model.User, error) {
+	users := []*model.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	in, args := inClause(ids)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id IN ` + in
+
+	rows, err := u.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &model.User{}
+		if err := rows.Scan(userFields(user)...); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Save also creates the personal workspace of user, named after it.
+func (u *userRepository) Save(ctx context.Context, user *model.User) error {
+	return inTransaction(ctx, u.db, func(tx executor) error {
+		query := `INSERT INTO users (username, password, email, email_verified, role, disabled, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		result, err := tx.ExecContext(ctx, query, user.Username, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.CreatedAt)
+		if err != nil {
+			return takenError(err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		user.ID = int(id)
+		return createWorkspace(ctx, tx, &model.Workspace{Name: user.Username, CreatedAt: user.CreatedAt}, user.ID)
+	})
+}
+
+// Update stores the password, email, its verification, role and disabled
+// flag of user.
+func (u *userRepository) Update(ctx context.Context, user *model.User) error {
+	query := `UPDATE users SET password = ?, email = ?, email_verified = ?, role = ?, disabled = ? WHERE id = ?`
+	result, err := u.db.ExecContext(ctx, query, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.ID)
+	if err != nil {
+		return takenError(err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return UserNotFoundError
+	}
+	return nil
+}
+
+// UsernameTaken reports whether a user other than userId has username, or
+// had it and its retirement has not ended at now.
+func (u *userRepository) UsernameTaken(ctx context.Context, username string, userId int, now time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE username = ? AND id != ?)
+	OR EXISTS (SELECT 1 FROM retired_usernames WHERE username = ? AND user_id != ? AND retired_until > ?)`
+	var taken bool
+	err := u.db.QueryRowContext(ctx, query, username, userId, username, userId, now).Scan(&taken)
+	return taken, err
+}
+
+// Rename gives user the username and retires the previous one until
+// retiredUntil.
+func (u *userRepository) Rename(ctx context.Context, user *model.User, username string, retiredUntil time.Time) error {
+	err := inTransaction(ctx, u.db, func(tx executor) error {
+		result, err := tx.ExecContext(ctx, `UPDATE users SET username = ? WHERE id = ?`, username, user.ID)
+		if err != nil {
+			return takenError(err)
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return UserNotFoundError
+		}
+
+		// users may take back a username they retired themselves
+		if _, err := tx.ExecContext(ctx, `DELETE FROM retired_usernames WHERE username = ? AND user_id = ?`, username, user.ID); err != nil {
+			return err
+		}
+		return retire(ctx, tx, user, retiredUntil)
+	})
+	if err != nil {
+		return err
+	}
+	user.Username = username
+	return nil
+}
+
+// Delete removes user with the rows that belong to it, the database does not
+// enforce the foreign keys, and retires the username until retiredUntil.
+func (u *userRepository) Delete(ctx context.Context, user *model.User, retiredUntil time.Time) error {
+	return inTransaction(ctx, u.db, func(tx executor) error {
+		statements := []string{
+			`DELETE FROM webhook_delivery_attempts WHERE delivery_id IN (SELECT id FROM webhook_deliveries
+			WHERE subscription_id IN (SELECT id FROM webhook_subscriptions WHERE user_id = ?))`,
+			`DELETE FROM webhook_deliveries WHERE subscription_id IN (SELECT id FROM webhook_subscriptions WHERE user_id = ?)`,
+			`DELETE FROM webhook_subscriptions WHERE user_id = ?`,
+			`DELETE FROM mfa_recovery_codes WHERE user_id = ?`,
+			`DELETE FROM user_mfa WHERE user_id = ?`,
+			`DELETE FROM user_tokens WHERE user_id = ?`,
+			`DELETE FROM user_identities WHERE user_id = ?`,
+			`DELETE FROM workspace_members WHERE user_id = ?`,
+			`DELETE FROM api_keys WHERE user_id = ?`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement, user.ID); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, user.ID)
+		if err != nil {
+			return err
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return UserNotFoundError
+		}
+		return retire(ctx, tx, user, retiredUntil)
+	})
+}
+
+func retire(ctx context.Context, tx executor, user *model.User, retiredUntil time.Time) error {
+	query := `INSERT OR REPLACE INTO retired_usernames (username, user_id, retired_until) VALUES (?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, user.Username, user.ID, retiredUntil)
 	return err
 }
+
+// takenError tells which unique column of users a write collided with.
+func takenError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique {
+		return err
+	}
+	switch {
+	case strings.Contains(sqliteErr.Error(), "users.username"):
+		return UsernameTakenError
+	case strings.Contains(sqliteErr.Error(), "users.email"):
+		return EmailTakenError
+	}
+	return err
+}
+
+const userColumns = `id, username, password, email, email_verified, role, disabled, created_at`
+
+func userFields(user *model.User) []any {
+	return []any{&user.ID, &user.Username, &user.Password, &user.Email, &user.EmailVerified, &user.Role, &user.Disabled, &user.CreatedAt}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -21,21 +22,22 @@ func TestUserRepository_FindByUsername_HappyPath(t *testing.T) {
 		Username:  "johndoe",
 		Password:  "password123",
 		Email:     "johndoe@example.com",
+		Role:      model.RoleEditor,
 		CreatedAt: time.Now(),
 		ID:        1,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "created_at"}).
-		AddRow(user.ID, user.Username, user.Password, user.Email, user.CreatedAt)
+	rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "email_verified", "role", "disabled", "created_at"}).
+		AddRow(user.ID, user.Username, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.CreatedAt)
 
-	mock.ExpectQuery("^SELECT \\* FROM users WHERE username = \\?$").
+	mock.ExpectQuery("^SELECT id, username, password, email, email_verified, role, disabled, created_at FROM users WHERE username = \\?$").
 		WithArgs(user.Username).
 		WillReturnRows(rows)
 
 	userRepo := NewUserRepository(db)
 
 	// test
-	result, err := userRepo.FindByUsername(user.Username)
+	result, err := userRepo.FindByUsername(context.Background(), user.Username)
 
 	// assert
 	require.NoError(t, err)
@@ -49,14 +51,14 @@ func TestUserRepository_FindByUsername_UnhappyPath_UserNotFound(t *testing.T) {
 	// fixture
 	username := "johndoe"
 
-	mock.ExpectQuery("^SELECT \\* FROM users WHERE username = \\?$").
+	mock.ExpectQuery("^SELECT id, username, password, email, email_verified, role, disabled, created_at FROM users WHERE username = \\?$").
 		WithArgs(username).
 		WillReturnError(sql.ErrNoRows)
 
 	userRepo := NewUserRepository(db)
 
 	// test
-	result, err := userRepo.FindByUsername(username)
+	result, err := userRepo.FindByUsername(context.Background(), username)
 
 	// assert
 	require.Nil(t, result)
@@ -70,20 +72,97 @@ func TestUserRepository_FindByUsername_UnhappyPath_DatabaseError(t *testing.T) {
 	// fixture
 	username := "johndoe"
 
-	mock.ExpectQuery("^SELECT \\* FROM users WHERE username = \\?$").
+	mock.ExpectQuery("^SELECT id, username, password, email, email_verified, role, disabled, created_at FROM users WHERE username = \\?$").
 		WithArgs(username).
 		WillReturnError(errors.New("database error"))
 
 	userRepo := NewUserRepository(db)
 
 	// test
-	result, err := userRepo.FindByUsername(username)
+	result, err := userRepo.FindByUsername(context.Background(), username)
 
 	// assert
 	require.Nil(t, result)
 	require.EqualError(t, err, "database error")
 }
 
+func TestUserRepository_FindByEmail_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{
+		ID:            1,
+		Username:      "johndoe",
+		Password:      "password123",
+		Email:         "johndoe@example.com",
+		EmailVerified: true,
+		Role:          model.RoleEditor,
+		CreatedAt:     time.Now(),
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "email_verified", "role", "disabled", "created_at"}).
+		AddRow(user.ID, user.Username, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.CreatedAt)
+
+	mock.ExpectQuery("^SELECT (.+) FROM users WHERE email = \\?$").
+		WithArgs(user.Email).
+		WillReturnRows(rows)
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	result, err := userRepo.FindByEmail(context.Background(), user.Email)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, user, result)
+}
+
+func TestUserRepository_FindByEmail_UnhappyPath_UserNotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	mock.ExpectQuery("^SELECT (.+) FROM users WHERE email = \\?$").
+		WithArgs("johndoe@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	result, err := userRepo.FindByEmail(context.Background(), "johndoe@example.com")
+
+	// assert
+	require.Nil(t, result)
+	require.EqualError(t, err, UserNotFoundError.Error())
+}
+
+func TestUserRepository_FindByIds_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "username", "password", "email", "email_verified", "role", "disabled", "created_at"}).
+		AddRow(1, "johndoe", "hash", "johndoe@example.com", true, model.RoleEditor, false, createdAt).
+		AddRow(2, "janedoe", "hash", "janedoe@example.com", false, model.RoleAdmin, false, createdAt)
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE id IN \\(\\?, \\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(rows)
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	users, err := userRepo.FindByIds(context.Background(), []int{1, 2})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "janedoe", users[1].Username)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUserRepository_Save_HappyPath(t *testing.T) {
 	beforeEach(t)
 	defer afterEach()
@@ -93,20 +172,31 @@ func TestUserRepository_Save_HappyPath(t *testing.T) {
 		Username:  "johndoe",
 		Password:  "password123",
 		Email:     "johndoe@example.com",
+		Role:      model.RoleEditor,
 		CreatedAt: time.Now(),
 	}
 
-	mock.ExpectExec("^INSERT INTO users \\(username, password, email, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)$").
-		WithArgs(user.Username, user.Password, user.Email, user.CreatedAt).
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO users \\(username, password, email, email_verified, role, disabled, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)$").
+		WithArgs(user.Username, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.CreatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^INSERT INTO workspaces \\(name, created_at\\)").
+		WithArgs(user.Username, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("^INSERT INTO workspace_members").
+		WithArgs(4, 1, model.RoleAdmin, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	userRepo := NewUserRepository(db)
 
 	// test
-	err := userRepo.Save(user)
+	err := userRepo.Save(context.Background(), user)
 
 	// assert
 	require.NoError(t, err)
+	require.Equal(t, 1, user.ID)
+	require.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUserRepository_Save_UnhappyPath_DatabaseError(t *testing.T) {
@@ -118,18 +208,165 @@ func TestUserRepository_Save_UnhappyPath_DatabaseError(t *testing.T) {
 		Username:  "johndoe",
 		Password:  "password123",
 		Email:     "johndoe@example.com",
+		Role:      model.RoleEditor,
 		CreatedAt: time.Now(),
 	}
 
-	mock.ExpectExec("^INSERT INTO users \\(username, password, email, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)$").
-		WithArgs(user.Username, user.Password, user.Email, user.CreatedAt).
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO users \\(username, password, email, email_verified, role, disabled, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?\\)$").
+		WithArgs(user.Username, user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.CreatedAt).
 		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
 
 	userRepo := NewUserRepository(db)
 
 	// test
-	err := userRepo.Save(user)
+	err := userRepo.Save(context.Background(), user)
 
 	// assert
 	require.EqualError(t, err, "database error")
 }
+
+func TestUserRepository_Update_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{ID: 1, Password: "hash", Email: "johndoe@example.com", Role: model.RoleAdmin, Disabled: true}
+
+	mock.ExpectExec("^UPDATE users SET password = \\?, email = \\?, email_verified = \\?, role = \\?, disabled = \\? WHERE id = \\?$").
+		WithArgs(user.Password, user.Email, user.EmailVerified, user.Role, user.Disabled, user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	err := userRepo.Update(context.Background(), user)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Update_UnhappyPath_UserNotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{ID: 1, Password: "hash", Email: "johndoe@example.com", Role: model.RoleEditor}
+
+	mock.ExpectExec("^UPDATE users SET (.+) WHERE id = \\?$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	err := userRepo.Update(context.Background(), user)
+
+	// assert
+	require.EqualError(t, err, UserNotFoundError.Error())
+}
+
+func TestUserRepository_UsernameTaken(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	now := time.Now()
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM users WHERE username = \\? AND id != \\?\\)\\s+OR EXISTS \\(SELECT 1 FROM retired_usernames").
+		WithArgs("johndoe", 2, "johndoe", 2, now).
+		WillReturnRows(sqlmock.NewRows([]string{"taken"}).AddRow(true))
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	taken, err := userRepo.UsernameTaken(context.Background(), "johndoe", 2, now)
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, taken)
+}
+
+func TestUserRepository_Rename_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{ID: 1, Username: "johndoe"}
+	retiredUntil := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE users SET username = \\? WHERE id = \\?$").
+		WithArgs("john.doe", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^DELETE FROM retired_usernames WHERE username = \\? AND user_id = \\?$").
+		WithArgs("john.doe", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^INSERT OR REPLACE INTO retired_usernames \\(username, user_id, retired_until\\) VALUES \\(\\?, \\?, \\?\\)$").
+		WithArgs("johndoe", 1, retiredUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	err := userRepo.Rename(context.Background(), user, "john.doe", retiredUntil)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "john.doe", user.Username)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Rename_UnhappyPath_UserNotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{ID: 1, Username: "johndoe"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE users SET username = \\? WHERE id = \\?$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	err := userRepo.Rename(context.Background(), user, "john.doe", time.Now())
+
+	// assert
+	require.ErrorIs(t, err, UserNotFoundError)
+	require.Equal(t, "johndoe", user.Username)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	user := &model.User{ID: 1, Username: "johndoe"}
+	retiredUntil := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"webhook_delivery_attempts", "webhook_deliveries", "webhook_subscriptions",
+		"mfa_recovery_codes", "user_mfa", "user_tokens", "user_identities", "workspace_members", "api_keys", "users"} {
+		mock.ExpectExec("^DELETE FROM " + table + " WHERE").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec("^INSERT OR REPLACE INTO retired_usernames").
+		WithArgs("johndoe", 1, retiredUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userRepo := NewUserRepository(db)
+
+	// test
+	err := userRepo.Delete(context.Background(), user, retiredUntil)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
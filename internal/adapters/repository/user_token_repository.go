@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type userTokenRepository struct {
+	db executor
+}
+
+func NewUserTokenRepository(db *sql.DB) *userTokenRepository {
+	return &userTokenRepository{traced(db)}
+}
+
+func (r *userTokenRepository) Issue(ctx context.Context, token *model.UserToken) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		query := `DELETE FROM user_tokens WHERE user_id = ? AND purpose = ? AND used_at IS NULL`
+		if _, err := tx.ExecContext(ctx, query, token.UserID, token.Purpose); err != nil {
+			return err
+		}
+		query = `INSERT INTO user_tokens (id, user_id, purpose, expires_at) VALUES (?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, token.ID, token.UserID, token.Purpose, token.ExpiresAt)
+		return err
+	})
+}
+
+// Use only updates an unused token, so a token racing itself succeeds once.
+func (r *userTokenRepository) Use(ctx context.Context, userID int, id string, purpose string, now time.Time) (bool, error) {
+	query := `UPDATE user_tokens SET used_at = ?
+	WHERE id = ? AND user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?`
+	return affectsRow(r.db.ExecContext(ctx, query, now, id, userID, purpose, now))
+}
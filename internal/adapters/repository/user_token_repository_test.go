@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestUserTokenRepository_Issue_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	tokenRepo := NewUserTokenRepository(db)
+	token := &model.UserToken{ID: "token-id", UserID: 1, Purpose: model.TokenPasswordReset, ExpiresAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user_tokens WHERE user_id = \\? AND purpose = \\? AND used_at IS NULL").
+		WithArgs(1, model.TokenPasswordReset).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_tokens \\(id, user_id, purpose, expires_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs("token-id", 1, model.TokenPasswordReset, token.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := tokenRepo.Issue(context.Background(), token)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokenRepository_Issue_UnhappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	tokenRepo := NewUserTokenRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user_tokens").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO user_tokens").
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	// test
+	err := tokenRepo.Issue(context.Background(), &model.UserToken{ID: "token-id", UserID: 1, Purpose: model.TokenPasswordReset})
+
+	// assert
+	require.EqualError(t, err, "database error")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserTokenRepository_Use(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	tokenRepo := NewUserTokenRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE user_tokens SET used_at = \\?\\s+WHERE id = \\? AND user_id = \\? AND purpose = \\? AND used_at IS NULL AND expires_at > \\?").
+		WithArgs(now, "token-id", 1, model.TokenEmailVerification, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE user_tokens").
+		WithArgs(now, "token-id", 1, model.TokenEmailVerification, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// test
+	used, err := tokenRepo.Use(context.Background(), 1, "token-id", model.TokenEmailVerification, now)
+	usedAgain, againErr := tokenRepo.Use(context.Background(), 1, "token-id", model.TokenEmailVerification, now)
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, used)
+	require.NoError(t, againErr)
+	require.False(t, usedAgain)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// checkVersionedWrite inspects the result of a compare-and-swap statement.
+// When no row was touched it tells a missing row apart from a stale version,
+// a row outside scope counts as missing.
+func checkVersionedWrite(ctx context.Context, db queryRower, result sql.Result, table string, id int,
+	scope string, scopeArgs []any, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var exists int
+	query := `SELECT COUNT(1) FROM ` + table + ` WHERE id = ? AND ` + scope
+	if err := db.QueryRowContext(ctx, query, append([]any{id}, scopeArgs...)...).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return notFound
+	}
+	return ports.ErrVersionConflict
+}
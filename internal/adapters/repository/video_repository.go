@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -11,30 +12,35 @@ var (
 	VideoNotFoundError = fmt.Errorf("video not found")
 )
 
+const videoColumns = `id, created_at, duration, description, link, title, user_id, workspace_id, version`
+
 type videoRepository struct {
-	db *sql.DB
+	db executor
 }
 
 func NewVideoRepository(db *sql.DB) *videoRepository {
-	return &videoRepository{db}
+	return &videoRepository{traced(db)}
 }
 
-func (r *videoRepository) Create(video *model.Video, userId int) (int, error) {
-	query := `INSERT INTO videos (title, description, description, user_id, created_at) VALUES (?, ?, ?, ?, ?)`
-	result, err := r.db.Exec(query, video.Title, video.Description, video.Link, userId, video.CreatedAt)
+func (r *videoRepository) Create(ctx context.Context, video *model.Video, userId int) (int, error) {
+	query := `INSERT INTO videos (title, description, link, duration, user_id, workspace_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, video.Title, video.Description, video.Link, video.Duration, userId, video.WorkspaceID, video.CreatedAt)
 	if err != nil {
 		return 0, err
 	}
 	id, err := result.LastInsertId()
-	return int(id), nil
+	return int(id), err
 }
 
-func (r *videoRepository) FindById(id int) (*model.Video, error) {
+func (r *videoRepository) FindById(ctx context.Context, workspaceIds []int, id int) (*model.Video, error) {
+	if len(workspaceIds) == 0 {
+		return nil, VideoNotFoundError
+	}
 
 	video := &model.Video{}
-	query := `SELECT * FROM videos WHERE id = ?`
-	err := r.db.QueryRow(query, id).Scan(&video.ID, &video.CreatedAt, &video.Duration,
-		&video.Description, &video.Link, &video.Title, &video.UserID)
+	in, args := inClause(workspaceIds)
+	query := `SELECT ` + videoColumns + ` FROM videos WHERE id = ? AND workspace_id IN ` + in
+	err := r.db.QueryRowContext(ctx, query, append([]any{id}, args...)...).Scan(videoFields(video)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, VideoNotFoundError
@@ -44,16 +50,103 @@ func (r *videoRepository) FindById(id int) (*model.Video, error) {
 	return video, nil
 }
 
-func (r *videoRepository) Update(id int, video *model.Video) error {
+// FindByIds loads several videos with a single query, missing ids and videos
+// of other workspaces are skipped.
+func (r *videoRepository) FindByIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Video, error) {
+	if len(workspaceIds) == 0 || len(ids) == 0 {
+		return []*model.Video{}, nil
+	}
+
+	in, args := inClause(ids)
+	workspacesIn, workspaceArgs := inClause(workspaceIds)
+	query := `SELECT ` + videoColumns + ` FROM videos WHERE id IN ` + in + ` AND workspace_id IN ` + workspacesIn
+	return r.query(ctx, query, append(args, workspaceArgs...)...)
+}
+
+func (r *videoRepository) FindByWorkspaceId(ctx context.Context, workspaceId int) ([]*model.Video, error) {
+	query := `SELECT ` + videoColumns + ` FROM videos WHERE workspace_id = ? ORDER BY id`
+	return r.query(ctx, query, workspaceId)
+}
+
+// FindAfter pages through the videos of workspaceIds by id, it returns up to
+// limit videos whose id is greater than afterId.
+func (r *videoRepository) FindAfter(ctx context.Context, workspaceIds []int, afterId int, limit int) ([]*model.Video, error) {
+	if len(workspaceIds) == 0 {
+		return []*model.Video{}, nil
+	}
+
+	in, args := inClause(workspaceIds)
+	query := `SELECT ` + videoColumns + ` FROM videos WHERE id > ? AND workspace_id IN ` + in + ` ORDER BY id LIMIT ?`
+	return r.query(ctx, query, append(append([]any{afterId}, args...), limit)...)
+}
+
+func (r *videoRepository) FindByUserId(ctx context.Context, workspaceIds []int, userId int) ([]*model.Video, error) {
+	if len(workspaceIds) == 0 {
+		return []*model.Video{}, nil
+	}
+
+	in, args := inClause(workspaceIds)
+	query := `SELECT ` + videoColumns + ` FROM videos WHERE user_id = ? AND workspace_id IN ` + in + ` ORDER BY id`
+	return r.query(ctx, query, append([]any{userId}, args...)...)
+}
+
+func (r *videoRepository) query(ctx context.Context, query string, args ...any) ([]*model.Video, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := []*model.Video{}
+	for rows.Next() {
+		video := &model.Video{}
+		if err := rows.Scan(videoFields(video)...); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+func (r *videoRepository) Update(ctx context.Context, workspaceIds []int, id int, video *model.Video) error {
+	query := `UPDATE videos SET title = ?, description = ?, link = ?, version = version + 1 WHERE id = ? AND version = ?`
+	return r.write(ctx, workspaceIds, id, query, video.Title, video.Description, video.Link, id, video.Version)
+}
 
-	query := `UPDATE videos SET title = ?, description = ?, link = ? WHERE id = ?`
-	_, err := r.db.Exec(query, video.Title, video.Description, video.Link, id)
-	return err
+// Transfer gives the video to userId in workspaceId, it counts as a change of
+// the video.
+func (r *videoRepository) Transfer(ctx context.Context, workspaceIds []int, id int, version int, userId int, workspaceId int) error {
+	query := `UPDATE videos SET user_id = ?, workspace_id = ?, version = version + 1 WHERE id = ? AND version = ?`
+	return r.write(ctx, workspaceIds, id, query, userId, workspaceId, id, version)
 }
 
-func (r *videoRepository) Remove(id int) error {
+func (r *videoRepository) Remove(ctx context.Context, workspaceIds []int, id int, version int) error {
+	query := `DELETE FROM videos WHERE id = ? AND version = ?`
+	return r.write(ctx, workspaceIds, id, query, id, version)
+}
+
+// write runs the compare-and-swap statement query on the video id, restricted
+// to the videos of workspaceIds.
+func (r *videoRepository) write(ctx context.Context, workspaceIds []int, id int, query string, args ...any) error {
+	if len(workspaceIds) == 0 {
+		return VideoNotFoundError
+	}
+
+	in, scopeArgs := inClause(workspaceIds)
+	scope := `workspace_id IN ` + in
+	result, err := r.db.ExecContext(ctx, query+` AND `+scope, append(args, scopeArgs...)...)
+	if err != nil {
+		return err
+	}
+	return checkVersionedWrite(ctx, r.db, result, "videos", id, scope, scopeArgs, VideoNotFoundError)
+}
 
-	query := `DELETE FROM videos WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+func videoFields(video *model.Video) []any {
+	return []any{&video.ID, &video.CreatedAt, &video.Duration, &video.Description,
+		&video.Link, &video.Title, &video.UserID, &video.WorkspaceID, &video.Version}
 }
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 
 	"github.com/stretchr/testify/require"
 )
@@ -27,11 +29,11 @@ func TestVideoRepository_Create_HappyPath(t *testing.T) {
 	userId := 1
 
 	mock.ExpectExec("INSERT INTO videos").
-		WithArgs(video.Title, video.Description, video.Link, userId, video.CreatedAt).
+		WithArgs(video.Title, video.Description, video.Link, video.Duration, userId, video.WorkspaceID, video.CreatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// test
-	_, err := videoRepo.Create(video, userId)
+	_, err := videoRepo.Create(context.Background(), video, userId)
 
 	// assertions
 	require.NoError(t, err)
@@ -53,9 +55,9 @@ func TestVideoRepository_Create_UnhappyPath(t *testing.T) {
 	}
 	userId := 1
 
-	mock.ExpectExec("INSERT INTO videos").WithArgs(video.Title, video.Description, video.Link, userId, video.CreatedAt).WillReturnError(errors.New("database error"))
+	mock.ExpectExec("INSERT INTO videos").WithArgs(video.Title, video.Description, video.Link, video.Duration, userId, video.WorkspaceID, video.CreatedAt).WillReturnError(errors.New("database error"))
 
-	_, err = videoRepo.Create(video, userId)
+	_, err = videoRepo.Create(context.Background(), video, userId)
 	require.Error(t, err)
 }
 
@@ -76,16 +78,18 @@ func TestVideoRepository_Find_HappyPath(t *testing.T) {
 		Duration:    time.Duration(1),
 		ID:          videoID,
 		UserID:      userID,
+		WorkspaceID: 2,
+		Version:     1,
 	}
 
 	rows := sqlmock.
-		NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id"}).
-		AddRow(video.ID, video.CreatedAt, video.Duration, video.Description, video.Link, video.Title, video.UserID)
+		NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id", "workspace_id", "version"}).
+		AddRow(video.ID, video.CreatedAt, video.Duration, video.Description, video.Link, video.Title, video.UserID, video.WorkspaceID, video.Version)
 
-	mock.ExpectQuery("SELECT \\* FROM videos").WithArgs(videoID).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM videos WHERE id = \\? AND workspace_id IN \\(\\?, \\?\\)").WithArgs(videoID, 2, 5).WillReturnRows(rows)
 
 	// test
-	result, err := videoRepo.FindById(videoID)
+	result, err := videoRepo.FindById(context.Background(), []int{2, 5}, videoID)
 
 	// assertions
 	require.NoError(t, err)
@@ -101,15 +105,105 @@ func TestVideoRepository_Find_UnhappyPath(t *testing.T) {
 
 	videoID := 1
 
-	mock.ExpectQuery("SELECT \\* FROM videos").WithArgs(videoID).WillReturnError(VideoNotFoundError)
+	mock.ExpectQuery("SELECT (.+) FROM videos").WithArgs(videoID, 2).WillReturnError(VideoNotFoundError)
 
 	// test
-	_, err := videoRepo.FindById(videoID)
+	_, err := videoRepo.FindById(context.Background(), []int{2}, videoID)
 
 	// assertions
 	require.Error(t, err)
 }
 
+func TestVideoRepository_FindByIds_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id", "workspace_id", "version"}).
+		AddRow(1, createdAt, time.Minute, "first description", "https://example.com/1.mp4", "First", 1, 5, 1).
+		AddRow(3, createdAt, time.Minute, "third description", "https://example.com/3.mp4", "Third", 2, 5, 4)
+	mock.ExpectQuery("SELECT (.+) FROM videos WHERE id IN \\(\\?, \\?, \\?\\) AND workspace_id IN \\(\\?\\)").
+		WithArgs(1, 2, 3, 5).
+		WillReturnRows(rows)
+
+	// test
+	videos, err := videoRepo.FindByIds(context.Background(), []int{5}, []int{1, 2, 3})
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, videos, 2)
+	require.Equal(t, "Third", videos[1].Title)
+	require.Equal(t, 4, videos[1].Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVideoRepository_FindByIds_HappyPath_NoWorkspaces(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+
+	// test
+	videos, err := videoRepo.FindByIds(context.Background(), nil, []int{1, 2, 3})
+
+	// assertions
+	require.NoError(t, err)
+	require.Empty(t, videos)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVideoRepository_FindByWorkspaceId_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id", "workspace_id", "version"}).
+		AddRow(2, time.Now(), time.Minute, "description", "https://example.com/2.mp4", "second", 1, 5, 1).
+		AddRow(6, time.Now(), time.Minute, "description", "https://example.com/6.mp4", "sixth", 3, 5, 1)
+
+	mock.ExpectQuery("FROM videos WHERE workspace_id = \\? ORDER BY id").WithArgs(5).WillReturnRows(rows)
+
+	// test
+	videos, err := videoRepo.FindByWorkspaceId(context.Background(), 5)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, videos, 2)
+	require.Equal(t, 3, videos[1].UserID)
+	require.Equal(t, 5, videos[1].WorkspaceID)
+}
+
+func TestVideoRepository_FindAfter_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id", "workspace_id", "version"}).
+		AddRow(3, createdAt, time.Minute, "third description", "https://example.com/3.mp4", "Third", 2, 2, 1).
+		AddRow(4, createdAt, time.Minute, "fourth description", "https://example.com/4.mp4", "Fourth", 1, 1, 2)
+	mock.ExpectQuery("SELECT (.+) FROM videos WHERE id > \\? AND workspace_id IN \\(\\?, \\?\\) ORDER BY id LIMIT \\?").
+		WithArgs(2, 1, 2, 2).
+		WillReturnRows(rows)
+
+	// test
+	videos, err := videoRepo.FindAfter(context.Background(), []int{1, 2}, 2, 2)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, videos, 2)
+	require.Equal(t, 3, videos[0].ID)
+	require.Equal(t, "Fourth", videos[1].Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestVideoRepository_Update_HappyPath(t *testing.T) {
 	beforeEach(t)
 	defer afterEach()
@@ -123,19 +217,80 @@ func TestVideoRepository_Update_HappyPath(t *testing.T) {
 		Description: "This is a test video",
 		Link:        "https://example.com/test.mp4",
 		CreatedAt:   time.Now(),
+		Version:     1,
 	}
 
 	mock.ExpectExec("UPDATE videos").
-		WithArgs(video.Title, video.Description, video.Link, videoID).
+		WithArgs(video.Title, video.Description, video.Link, videoID, video.Version, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// test
-	err := videoRepo.Update(videoID, video)
+	err := videoRepo.Update(context.Background(), []int{1}, videoID, video)
 
 	// assertions
 	require.NoError(t, err)
 }
 
+func TestVideoRepository_Update_UnhappyPath_VersionConflict(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+
+	videoID := 1
+	video := &model.Video{
+		Title:       "Test Video",
+		Description: "This is a test video",
+		Link:        "https://example.com/test.mp4",
+		CreatedAt:   time.Now(),
+		Version:     1,
+	}
+
+	mock.ExpectExec("UPDATE videos").
+		WithArgs(video.Title, video.Description, video.Link, videoID, video.Version, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(1\\) FROM videos WHERE id = \\? AND workspace_id IN \\(\\?\\)").
+		WithArgs(videoID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	// test
+	err := videoRepo.Update(context.Background(), []int{1}, videoID, video)
+
+	// assertions
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+}
+
+func TestVideoRepository_Update_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+
+	videoID := 1
+	video := &model.Video{
+		Title:       "Test Video",
+		Description: "This is a test video",
+		Link:        "https://example.com/test.mp4",
+		CreatedAt:   time.Now(),
+		Version:     1,
+	}
+
+	mock.ExpectExec("UPDATE videos").
+		WithArgs(video.Title, video.Description, video.Link, videoID, video.Version, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(1\\) FROM videos WHERE id = \\? AND workspace_id IN \\(\\?\\)").
+		WithArgs(videoID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	// test
+	err := videoRepo.Update(context.Background(), []int{1}, videoID, video)
+
+	// assertions
+	require.ErrorIs(t, err, VideoNotFoundError)
+}
+
 func TestVideoRepository_Update_UnhappyPath(t *testing.T) {
 	beforeEach(t)
 	defer afterEach()
@@ -152,11 +307,11 @@ func TestVideoRepository_Update_UnhappyPath(t *testing.T) {
 	}
 
 	mock.ExpectExec("UPDATE videos").
-		WithArgs(video.Title, video.Description, video.Link, videoID).
+		WithArgs(video.Title, video.Description, video.Link, videoID, video.Version, 1).
 		WillReturnError(errors.New("database error"))
 
 	// test
-	err := videoRepo.Update(videoID, video)
+	err := videoRepo.Update(context.Background(), []int{1}, videoID, video)
 
 	// assertions
 	require.Error(t, err)
@@ -171,10 +326,10 @@ func TestVideoRepository_Remove_HappyPath(t *testing.T) {
 
 	videoID := 1
 
-	mock.ExpectExec("DELETE FROM videos").WithArgs(videoID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM videos").WithArgs(videoID, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// test
-	err := videoRepo.Remove(videoID)
+	err := videoRepo.Remove(context.Background(), []int{1}, videoID, 1)
 
 	// assertions
 	require.NoError(t, err)
@@ -189,11 +344,51 @@ func TestVideoRepository_Remove_UnhappyPath(t *testing.T) {
 
 	videoID := 1
 
-	mock.ExpectExec("DELETE FROM videos").WithArgs(videoID).WillReturnError(errors.New("database error"))
+	mock.ExpectExec("DELETE FROM videos").WithArgs(videoID, 1, 1).WillReturnError(errors.New("database error"))
 
 	// test
-	err := videoRepo.Remove(videoID)
+	err := videoRepo.Remove(context.Background(), []int{1}, videoID, 1)
 
 	// assertions
 	require.Error(t, err)
 }
+
+func TestVideoRepository_FindByUserId_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "created_at", "duration", "description", "link", "title", "user_id", "workspace_id", "version"}).
+		AddRow(1, time.Now(), 10*time.Minute, "description", "https://example.com/1.mp4", "first", 7, 7, 1).
+		AddRow(3, time.Now(), 10*time.Minute, "description", "https://example.com/3.mp4", "third", 7, 9, 2)
+
+	mock.ExpectQuery("FROM videos WHERE user_id = \\? AND workspace_id IN \\(\\?, \\?\\) ORDER BY id").WithArgs(7, 7, 9).WillReturnRows(rows)
+
+	// test
+	videos, err := videoRepo.FindByUserId(context.Background(), []int{7, 9}, 7)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, videos, 2)
+	require.Equal(t, 3, videos[1].ID)
+}
+
+func TestVideoRepository_Transfer_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	videoRepo := NewVideoRepository(db)
+
+	mock.ExpectExec("UPDATE videos SET user_id = \\?, workspace_id = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\? AND workspace_id IN \\(\\?\\)").
+		WithArgs(2, 4, 1, 3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := videoRepo.Transfer(context.Background(), []int{5}, 1, 3, 2, 4)
+
+	// assertions
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
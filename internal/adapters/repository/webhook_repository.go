@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+var (
+	ErrWebhookNotFound  = fmt.Errorf("webhook subscription not found")
+	ErrDeliveryNotFound = fmt.Errorf("webhook delivery not found")
+)
+
+const (
+	subscriptionColumns = `id, user_id, url, secret, event_types, annotation_types, created_at`
+	deliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, delivered_at`
+)
+
+type webhookRepository struct {
+	db executor
+}
+
+func NewWebhookRepository(db *sql.DB) *webhookRepository {
+	return &webhookRepository{traced(db)}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (int, error) {
+	query := `INSERT INTO webhook_subscriptions (user_id, url, secret, event_types, annotation_types, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, subscription.UserID, subscription.URL, subscription.Secret,
+		joinList(subscription.EventTypes), joinList(subscription.AnnotationTypes), subscription.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+func (r *webhookRepository) FindSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = ?`
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *webhookRepository) FindSubscriptionsByUser(ctx context.Context, userId int) ([]*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE user_id = ? ORDER BY id`
+	return r.querySubscriptions(ctx, query, userId)
+}
+
+func (r *webhookRepository) FindSubscriptionsByWorkspace(ctx context.Context, workspaceId int) ([]*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions
+	WHERE user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = ?) ORDER BY id`
+	return r.querySubscriptions(ctx, query, workspaceId)
+}
+
+func (r *webhookRepository) querySubscriptions(ctx context.Context, query string, args ...any) ([]*model.WebhookSubscription, error) {
+	subscriptions := []*model.WebhookSubscription{}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) RemoveSubscription(ctx context.Context, id, userId int) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Payload,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	delivery.ID = int(id)
+	return err
+}
+
+// FindDueDeliveries returns pending deliveries whose next attempt is due,
+// oldest first.
+func (r *webhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+	WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`
+	return r.queryDeliveries(ctx, query, model.DeliveryPending, now, limit)
+}
+
+// FindDeliveries returns the latest deliveries of a subscription first.
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionId int, limit int) ([]*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = ? ORDER BY id DESC LIMIT ?`
+	return r.queryDeliveries(ctx, query, subscriptionId, limit)
+}
+
+func (r *webhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]*model.WebhookDelivery, error) {
+	deliveries := []*model.WebhookDelivery{}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery := &model.WebhookDelivery{}
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) FindAttempts(ctx context.Context, deliveryIds []int) ([]*model.WebhookDeliveryAttempt, error) {
+	attempts := []*model.WebhookDeliveryAttempt{}
+	if len(deliveryIds) == 0 {
+		return attempts, nil
+	}
+
+	in, args := inClause(deliveryIds)
+	query := `SELECT id, delivery_id, attempt, response_code, error, duration, attempted_at
+	FROM webhook_delivery_attempts WHERE delivery_id IN ` + in + ` ORDER BY delivery_id, attempt`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attempt := &model.WebhookDeliveryAttempt{}
+		err := rows.Scan(&attempt.ID, &attempt.DeliveryID, &attempt.Attempt, &attempt.ResponseCode,
+			&attempt.Error, &attempt.Duration, &attempt.AttemptedAt)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		query := `INSERT INTO webhook_delivery_attempts (delivery_id, attempt, response_code, error, duration, attempted_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+		_, err := tx.ExecContext(ctx, query, delivery.ID, attempt.Attempt, attempt.ResponseCode, attempt.Error, attempt.Duration, attempt.AttemptedAt)
+		if err != nil {
+			return err
+		}
+
+		query = `UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, delivered_at = ? WHERE id = ?`
+		result, err := tx.ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrDeliveryNotFound
+		}
+		return nil
+	})
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (*model.WebhookSubscription, error) {
+	subscription := &model.WebhookSubscription{}
+	var eventTypes, annotationTypes string
+	err := row.Scan(&subscription.ID, &subscription.UserID, &subscription.URL, &subscription.Secret,
+		&eventTypes, &annotationTypes, &subscription.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	subscription.EventTypes = splitList(eventTypes)
+	subscription.AnnotationTypes = splitList(annotationTypes)
+	return subscription, nil
+}
+
+// Filter lists are stored as comma separated text, an empty string means
+// "match everything".
+func joinList(values []string) string {
+	return strings.Join(values, ",")
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+var subscriptionRowColumns = []string{"id", "user_id", "url", "secret", "event_types", "annotation_types", "created_at"}
+
+var deliveryRowColumns = []string{"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts",
+	"next_attempt_at", "created_at", "delivered_at"}
+
+func TestWebhookRepository_CreateSubscription_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	subscription := &model.WebhookSubscription{
+		UserID:          1,
+		URL:             "https://example.com/hook",
+		Secret:          "0123456789abcdef",
+		EventTypes:      []string{model.EventVideoCreated, model.EventAnnotationUpdated},
+		AnnotationTypes: []string{"note"},
+		CreatedAt:       time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO webhook_subscriptions").
+		WithArgs(1, subscription.URL, subscription.Secret, "video.created,annotation.updated", "note", subscription.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	// test
+	id, err := webhookRepo.CreateSubscription(context.Background(), subscription)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 7, id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_FindSubscription_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id = ?").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(subscriptionRowColumns).
+			AddRow(7, 1, "https://example.com/hook", "secret", "video.created", "", createdAt))
+
+	// test
+	subscription, err := webhookRepo.FindSubscription(context.Background(), 7)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 7, subscription.ID)
+	require.Equal(t, []string{model.EventVideoCreated}, subscription.EventTypes)
+	require.Empty(t, subscription.AnnotationTypes)
+}
+
+func TestWebhookRepository_FindSubscription_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id = ?").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(subscriptionRowColumns))
+
+	// test
+	_, err := webhookRepo.FindSubscription(context.Background(), 7)
+
+	// assert
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestWebhookRepository_FindSubscriptionsByUser_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE user_id = ?").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(subscriptionRowColumns).
+			AddRow(1, 1, "https://example.com/a", "secret", "", "", createdAt).
+			AddRow(2, 1, "https://example.com/b", "secret", "annotation.created", "note,tag", createdAt))
+
+	// test
+	subscriptions, err := webhookRepo.FindSubscriptionsByUser(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	require.Equal(t, []string{"note", "tag"}, subscriptions[1].AnnotationTypes)
+}
+
+func TestWebhookRepository_FindSubscriptionsByWorkspace_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions\\s+WHERE user_id IN \\(SELECT user_id FROM workspace_members WHERE workspace_id = \\?\\)").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(subscriptionRowColumns).
+			AddRow(1, 1, "https://example.com/a", "secret", "", "", time.Now()))
+
+	// test
+	subscriptions, err := webhookRepo.FindSubscriptionsByWorkspace(context.Background(), 3)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RemoveSubscription_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+
+	mock.ExpectExec("DELETE FROM webhook_subscriptions").
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := webhookRepo.RemoveSubscription(context.Background(), 7, 1)
+
+	// assert
+	require.NoError(t, err)
+}
+
+func TestWebhookRepository_RemoveSubscription_NotOwned(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+
+	mock.ExpectExec("DELETE FROM webhook_subscriptions").
+		WithArgs(7, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// test
+	err := webhookRepo.RemoveSubscription(context.Background(), 7, 2)
+
+	// assert
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestWebhookRepository_CreateDelivery_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	now := time.Now()
+	delivery := &model.WebhookDelivery{
+		SubscriptionID: 7,
+		EventID:        "evt",
+		EventType:      model.EventVideoCreated,
+		Payload:        []byte(`{}`),
+		Status:         model.DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+
+	mock.ExpectExec("INSERT INTO webhook_deliveries").
+		WithArgs(7, "evt", model.EventVideoCreated, []byte(`{}`), model.DeliveryPending, 0, now, now).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	// test
+	err := webhookRepo.CreateDelivery(context.Background(), delivery)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 3, delivery.ID)
+}
+
+func TestWebhookRepository_FindDueDeliveries_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	now := time.Now()
+	deliveredAt := now
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_deliveries WHERE status = \\? AND next_attempt_at <= \\?").
+		WithArgs(model.DeliveryPending, now, 10).
+		WillReturnRows(sqlmock.NewRows(deliveryRowColumns).
+			AddRow(1, 7, "evt-1", model.EventVideoCreated, []byte(`{}`), model.DeliveryPending, 0, now, now, nil).
+			AddRow(2, 7, "evt-2", model.EventVideoUpdated, []byte(`{}`), model.DeliveryPending, 2, now, now, deliveredAt))
+
+	// test
+	deliveries, err := webhookRepo.FindDueDeliveries(context.Background(), now, 10)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Nil(t, deliveries[0].DeliveredAt)
+	require.NotNil(t, deliveries[1].DeliveredAt)
+}
+
+func TestWebhookRepository_FindAttempts_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_delivery_attempts WHERE delivery_id IN \\(\\?, \\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "delivery_id", "attempt", "response_code", "error", "duration", "attempted_at"}).
+			AddRow(1, 1, 1, 500, "", time.Second, now).
+			AddRow(2, 2, 1, 0, "connection refused", time.Millisecond, now))
+
+	// test
+	attempts, err := webhookRepo.FindAttempts(context.Background(), []int{1, 2})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	require.Equal(t, "connection refused", attempts[1].Error)
+}
+
+func TestWebhookRepository_FindAttempts_NoIds(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+
+	// test
+	attempts, err := webhookRepo.FindAttempts(context.Background(), nil)
+
+	// assert
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RecordAttempt_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	now := time.Now()
+	delivery := &model.WebhookDelivery{ID: 3, Status: model.DeliverySucceeded, Attempts: 1, NextAttemptAt: now, DeliveredAt: &now}
+	attempt := &model.WebhookDeliveryAttempt{Attempt: 1, ResponseCode: 204, Duration: time.Millisecond, AttemptedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempts").
+		WithArgs(3, 1, 204, "", time.Millisecond, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE webhook_deliveries").
+		WithArgs(model.DeliverySucceeded, 1, now, &now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := webhookRepo.RecordAttempt(context.Background(), delivery, attempt)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RecordAttempt_UnhappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	webhookRepo := NewWebhookRepository(db)
+	now := time.Now()
+	delivery := &model.WebhookDelivery{ID: 3, Status: model.DeliveryPending, Attempts: 1, NextAttemptAt: now}
+	attempt := &model.WebhookDeliveryAttempt{Attempt: 1, AttemptedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery_attempts").
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	// test
+	err := webhookRepo.RecordAttempt(context.Background(), delivery, attempt)
+
+	// assert
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+var (
+	ErrMemberNotFound     = fmt.Errorf("workspace member not found")
+	ErrInvitationNotFound = fmt.Errorf("workspace invitation not found")
+)
+
+type workspaceRepository struct {
+	db executor
+}
+
+func NewWorkspaceRepository(db *sql.DB) *workspaceRepository {
+	return &workspaceRepository{traced(db)}
+}
+
+func (r *workspaceRepository) Create(ctx context.Context, workspace *model.Workspace, userId int) error {
+	return inTransaction(ctx, r.db, func(tx executor) error {
+		return createWorkspace(ctx, tx, workspace, userId)
+	})
+}
+
+// createWorkspace runs in the transaction tx, which also creates users with
+// their personal workspace.
+func createWorkspace(ctx context.Context, tx executor, workspace *model.Workspace, userId int) error {
+	result, err := tx.ExecContext(ctx, `INSERT INTO workspaces (name, created_at) VALUES (?, ?)`, workspace.Name, workspace.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, id, userId, model.RoleAdmin, workspace.CreatedAt); err != nil {
+		return err
+	}
+	workspace.ID, workspace.Role = int(id), model.RoleAdmin
+	return nil
+}
+
+func (r *workspaceRepository) FindByUserId(ctx context.Context, userId int) ([]*model.Workspace, error) {
+	query := `SELECT w.id, w.name, m.role, w.created_at FROM workspaces w
+	JOIN workspace_members m ON m.workspace_id = w.id WHERE m.user_id = ? ORDER BY m.created_at, w.id`
+
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workspaces := []*model.Workspace{}
+	for rows.Next() {
+		workspace := &model.Workspace{}
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &workspace.Role, &workspace.CreatedAt); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (r *workspaceRepository) FindIds(ctx context.Context) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM workspaces ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *workspaceRepository) FindMembers(ctx context.Context, workspaceId int) ([]*model.WorkspaceMember, error) {
+	query := `SELECT m.workspace_id, m.user_id, u.username, m.role, m.created_at FROM workspace_members m
+	JOIN users u ON u.id = m.user_id WHERE m.workspace_id = ? ORDER BY u.username`
+
+	rows, err := r.db.QueryContext(ctx, query, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*model.WorkspaceMember{}
+	for rows.Next() {
+		member := &model.WorkspaceMember{}
+		if err := rows.Scan(memberFields(member)...); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *workspaceRepository) FindMember(ctx context.Context, workspaceId int, userId int) (*model.WorkspaceMember, error) {
+	query := `SELECT m.workspace_id, m.user_id, u.username, m.role, m.created_at FROM workspace_members m
+	JOIN users u ON u.id = m.user_id WHERE m.workspace_id = ? AND m.user_id = ?`
+
+	member := &model.WorkspaceMember{}
+	err := r.db.QueryRowContext(ctx, query, workspaceId, userId).Scan(memberFields(member)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// SaveMember keeps the time the member joined when its role changes.
+func (r *workspaceRepository) SaveMember(ctx context.Context, member *model.WorkspaceMember) error {
+	query := `INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?)
+	ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = excluded.role`
+	_, err := r.db.ExecContext(ctx, query, member.WorkspaceID, member.UserID, member.Role, member.CreatedAt)
+	return err
+}
+
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceId int, userId int) error {
+	query := `DELETE FROM workspace_members WHERE workspace_id = ? AND user_id = ?`
+	removed, err := affectsRow(r.db.ExecContext(ctx, query, workspaceId, userId))
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *workspaceRepository) CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error {
+	query := `INSERT INTO workspace_invitations (id, workspace_id, email, role, invited_by, expires_at, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, invitation.ID, invitation.WorkspaceID, invitation.Email, invitation.Role,
+		invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt)
+	return err
+}
+
+func (r *workspaceRepository) FindInvitation(ctx context.Context, id string) (*model.WorkspaceInvitation, error) {
+	query := `SELECT id, workspace_id, email, role, invited_by, expires_at, accepted_at, created_at
+	FROM workspace_invitations WHERE id = ?`
+
+	invitation := &model.WorkspaceInvitation{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&invitation.ID, &invitation.WorkspaceID, &invitation.Email,
+		&invitation.Role, &invitation.InvitedBy, &invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation only updates an unaccepted invitation, so an invitation
+// racing itself adds the member once. A member keeps the role it has.
+func (r *workspaceRepository) AcceptInvitation(ctx context.Context, id string, member *model.WorkspaceMember, now time.Time) (bool, error) {
+	accepted := false
+	err := inTransaction(ctx, r.db, func(tx executor) error {
+		query := `UPDATE workspace_invitations SET accepted_at = ? WHERE id = ? AND accepted_at IS NULL AND expires_at > ?`
+		updated, err := affectsRow(tx.ExecContext(ctx, query, now, id, now))
+		if err != nil || !updated {
+			return err
+		}
+
+		query = `INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, member.WorkspaceID, member.UserID, member.Role, member.CreatedAt); err != nil {
+			return err
+		}
+		accepted = true
+		return nil
+	})
+	return accepted, err
+}
+
+func memberFields(member *model.WorkspaceMember) []any {
+	return []any{&member.WorkspaceID, &member.UserID, &member.Username, &member.Role, &member.CreatedAt}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestWorkspaceRepository_Create_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+	workspace := &model.Workspace{Name: "Annotators", CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO workspaces \\(name, created_at\\) VALUES \\(\\?, \\?\\)").
+		WithArgs("Annotators", workspace.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec("INSERT INTO workspace_members \\(workspace_id, user_id, role, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(5, 2, model.RoleAdmin, workspace.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// test
+	err := workspaceRepo.Create(context.Background(), workspace, 2)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 5, workspace.ID)
+	require.Equal(t, model.RoleAdmin, workspace.Role)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspaceRepository_FindByUserId_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery("SELECT w.id, w.name, m.role, w.created_at FROM workspaces w\\s+JOIN workspace_members m ON m.workspace_id = w.id WHERE m.user_id = \\?").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "created_at"}).
+			AddRow(2, "johndoe", model.RoleAdmin, createdAt).
+			AddRow(5, "Annotators", model.RoleViewer, createdAt))
+
+	// test
+	workspaces, err := workspaceRepo.FindByUserId(context.Background(), 2)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []*model.Workspace{
+		{ID: 2, Name: "johndoe", Role: model.RoleAdmin, CreatedAt: createdAt},
+		{ID: 5, Name: "Annotators", Role: model.RoleViewer, CreatedAt: createdAt},
+	}, workspaces)
+}
+
+func TestWorkspaceRepository_FindIds_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+
+	mock.ExpectQuery("SELECT id FROM workspaces ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+
+	// test
+	ids, err := workspaceRepo.FindIds(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 3}, ids)
+}
+
+func TestWorkspaceRepository_FindMember_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+
+	mock.ExpectQuery("FROM workspace_members m\\s+JOIN users u ON u.id = m.user_id WHERE m.workspace_id = \\? AND m.user_id = \\?").
+		WithArgs(5, 3).
+		WillReturnError(sql.ErrNoRows)
+
+	// test
+	member, err := workspaceRepo.FindMember(context.Background(), 5, 3)
+
+	// assert
+	require.Nil(t, member)
+	require.ErrorIs(t, err, ErrMemberNotFound)
+}
+
+func TestWorkspaceRepository_SaveMember_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+	member := &model.WorkspaceMember{WorkspaceID: 5, UserID: 3, Role: model.RoleEditor, CreatedAt: time.Now()}
+
+	mock.ExpectExec("INSERT INTO workspace_members (.+) ON CONFLICT \\(workspace_id, user_id\\) DO UPDATE SET role = excluded.role").
+		WithArgs(5, 3, model.RoleEditor, member.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// test
+	err := workspaceRepo.SaveMember(context.Background(), member)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspaceRepository_RemoveMember_UnhappyPath_NotFound(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+
+	mock.ExpectExec("DELETE FROM workspace_members WHERE workspace_id = \\? AND user_id = \\?").
+		WithArgs(5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// test
+	err := workspaceRepo.RemoveMember(context.Background(), 5, 3)
+
+	// assert
+	require.ErrorIs(t, err, ErrMemberNotFound)
+}
+
+func TestWorkspaceRepository_AcceptInvitation_HappyPath(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+	now := time.Now()
+	member := &model.WorkspaceMember{WorkspaceID: 5, UserID: 3, Role: model.RoleEditor, CreatedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE workspace_invitations SET accepted_at = \\? WHERE id = \\? AND accepted_at IS NULL AND expires_at > \\?").
+		WithArgs(now, "invitation-id", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO workspace_members (.+) ON CONFLICT \\(workspace_id, user_id\\) DO NOTHING").
+		WithArgs(5, 3, model.RoleEditor, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// test
+	accepted, err := workspaceRepo.AcceptInvitation(context.Background(), "invitation-id", member, now)
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, accepted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspaceRepository_AcceptInvitation_UnhappyPath_AlreadyAccepted(t *testing.T) {
+	beforeEach(t)
+	defer afterEach()
+
+	// fixture
+	workspaceRepo := NewWorkspaceRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE workspace_invitations SET accepted_at").
+		WithArgs(now, "invitation-id", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// test
+	accepted, err := workspaceRepo.AcceptInvitation(context.Background(), "invitation-id",
+		&model.WorkspaceMember{WorkspaceID: 5, UserID: 3, Role: model.RoleEditor}, now)
+
+	// assert
+	require.NoError(t, err)
+	require.False(t, accepted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
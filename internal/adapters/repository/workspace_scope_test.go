@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	migrations "github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/stretchr/testify/require"
+)
+
+// otherWorkspace stores a video with an annotation in the personal workspace
+// of a second user, it returns them with the id of the personal workspace of
+// the first user.
+func otherWorkspace(t *testing.T) (*sql.DB, int, *model.Video, *model.Annotation) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// every connection would open its own in-memory database
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+
+	ctx := context.Background()
+	_, err = migrations.NewMigrator(database).Up(ctx)
+	require.NoError(t, err)
+
+	userRepo := NewUserRepository(database)
+	john := &model.User{Username: "johndoe", Email: "john@example.com", Role: model.RoleEditor, CreatedAt: time.Now().UTC()}
+	jane := &model.User{Username: "janedoe", Email: "jane@example.com", Role: model.RoleEditor, CreatedAt: time.Now().UTC()}
+	require.NoError(t, userRepo.Save(ctx, john))
+	require.NoError(t, userRepo.Save(ctx, jane))
+
+	workspaceRepo := NewWorkspaceRepository(database)
+	johns, err := workspaceRepo.FindByUserId(ctx, john.ID)
+	require.NoError(t, err)
+	janes, err := workspaceRepo.FindByUserId(ctx, jane.ID)
+	require.NoError(t, err)
+
+	video := &model.Video{Title: "jane's video", Link: "https://example.com/jane.mp4", Duration: time.Minute,
+		WorkspaceID: janes[0].ID, CreatedAt: time.Now().UTC()}
+	video.ID, err = NewVideoRepository(database).Create(ctx, video, jane.ID)
+	require.NoError(t, err)
+	video.UserID, video.Version = jane.ID, 1
+
+	annotation := &model.Annotation{StartTime: 0, EndTime: time.Second, Type: "note", Note: "jane's note"}
+	require.NoError(t, NewAnnotationRepository(database).Create(ctx, annotation, video.ID, jane.ID))
+
+	return database, johns[0].ID, video, annotation
+}
+
+func TestVideoRepository_Writes_UnhappyPath_OtherWorkspace(t *testing.T) {
+	// fixture
+	database, workspaceId, video, _ := otherWorkspace(t)
+	videoRepo := NewVideoRepository(database)
+	ctx := context.Background()
+	changed := *video
+	changed.Title = "taken over"
+
+	// test
+	updateErr := videoRepo.Update(ctx, []int{workspaceId}, video.ID, &changed)
+	transferErr := videoRepo.Transfer(ctx, []int{workspaceId}, video.ID, video.Version, 1, workspaceId)
+	removeErr := videoRepo.Remove(ctx, []int{workspaceId}, video.ID, video.Version)
+
+	// assertions
+	require.ErrorIs(t, updateErr, VideoNotFoundError)
+	require.ErrorIs(t, transferErr, VideoNotFoundError)
+	require.ErrorIs(t, removeErr, VideoNotFoundError)
+
+	stored, err := videoRepo.FindById(ctx, []int{video.WorkspaceID}, video.ID)
+	require.NoError(t, err)
+	require.Equal(t, "jane's video", stored.Title)
+	require.Equal(t, video.UserID, stored.UserID)
+	require.Equal(t, video.WorkspaceID, stored.WorkspaceID)
+	require.Equal(t, 1, stored.Version)
+}
+
+func TestVideoRepository_Finds_HappyPath_OtherWorkspace(t *testing.T) {
+	// fixture
+	database, workspaceId, video, _ := otherWorkspace(t)
+	videoRepo := NewVideoRepository(database)
+	ctx := context.Background()
+
+	// test
+	after, afterErr := videoRepo.FindAfter(ctx, []int{workspaceId}, 0, 10)
+	owned, ownedErr := videoRepo.FindByUserId(ctx, []int{workspaceId}, video.UserID)
+
+	// assertions
+	require.NoError(t, afterErr)
+	require.Empty(t, after)
+	require.NoError(t, ownedErr)
+	require.Empty(t, owned)
+}
+
+func TestAnnotationRepository_Writes_UnhappyPath_OtherWorkspace(t *testing.T) {
+	// fixture
+	database, workspaceId, video, annotation := otherWorkspace(t)
+	annotationRepo := NewAnnotationRepository(database)
+	ctx := context.Background()
+	changed := *annotation
+	changed.Note = "taken over"
+
+	// test
+	_, updateErr := annotationRepo.Update(ctx, []int{workspaceId}, annotation.ID, &changed)
+	removeErr := annotationRepo.Remove(ctx, []int{workspaceId}, annotation.ID)
+
+	// assertions
+	require.ErrorIs(t, updateErr, ErrAnnotationNotFound)
+	require.NoError(t, removeErr)
+
+	stored, err := annotationRepo.FindById(ctx, []int{video.WorkspaceID}, annotation.ID)
+	require.NoError(t, err)
+	require.Equal(t, "jane's note", stored.Note)
+	require.Equal(t, 1, stored.Version)
+
+	revisions, err := annotationRepo.FindRevisions(ctx, []int{video.WorkspaceID}, annotation.ID)
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
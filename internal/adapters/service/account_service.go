@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var (
+	ErrEmailNotVerified     = errors.New("email address is not verified")
+	ErrReadOnlyRole         = errors.New("your role only allows reading")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	ErrUserTokenInvalid     = errors.New("link is invalid, expired or was already used")
+)
+
+// AccountSettings tell where the mailed links point and how long they work.
+type AccountSettings struct {
+	AppURL               string
+	VerificationTTL      time.Duration
+	ResetTTL             time.Duration
+	RequireVerifiedEmail bool
+}
+
+type accountService struct {
+	userRepo  ports.UserRepository
+	tokenRepo ports.UserTokenRepository
+	audit     ports.AuditRepository
+	auth      auth.AuthService
+	mailer    ports.Mailer
+	throttle  *LoginThrottle
+	settings  AccountSettings
+	now       func() time.Time
+}
+
+func NewAccountService(userRepo ports.UserRepository, tokenRepo ports.UserTokenRepository, audit ports.AuditRepository,
+	auth auth.AuthService, mailer ports.Mailer, throttle *LoginThrottle, settings AccountSettings) *accountService {
+	return &accountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		audit:     audit,
+		auth:      auth,
+		mailer:    mailer,
+		throttle:  throttle,
+		settings:  settings,
+		now:       time.Now,
+	}
+}
+
+func (s *accountService) SendVerification(ctx context.Context, username string) error {
+	ctx, span := tracer.Start(ctx, "AccountService.SendVerification")
+	defer span.End()
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	link, err := s.link(ctx, user, model.TokenEmailVerification, s.settings.VerificationTTL, "/verify-email")
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, &model.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nopen this link within %s to verify your email address:\n\n%s\n\n"+
+			"If you did not sign up, ignore this email.\n", user.Username, validity(s.settings.VerificationTTL), link),
+	})
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) error {
+	ctx, span := tracer.Start(ctx, "AccountService.VerifyEmail")
+	defer span.End()
+
+	user, err := s.use(ctx, token, model.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	user.EmailVerified = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditEmailVerified, user.Username)
+	return nil
+}
+
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	ctx, span := tracer.Start(ctx, "AccountService.RequestPasswordReset")
+	defer span.End()
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, repository.UserNotFoundError) {
+		logging.FromContext(ctx).Info("password reset not sent", "reason", "unknown email")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Disabled {
+		logging.FromContext(ctx).Info("password reset not sent", "username", user.Username, "reason", "disabled user")
+		return nil
+	}
+	// users of single sign-on have no password, a local one would bypass the identity provider
+	if user.Password == "" {
+		logging.FromContext(ctx).Info("password reset not sent", "username", user.Username, "reason", "single sign-on user")
+		return nil
+	}
+
+	link, err := s.link(ctx, user, model.TokenPasswordReset, s.settings.ResetTTL, "/reset-password")
+	if err != nil {
+		return err
+	}
+	err = s.mailer.Send(ctx, &model.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nopen this link within %s to choose a new password:\n\n%s\n\n"+
+			"If you did not ask for it, ignore this email, your password stays the same.\n", user.Username, validity(s.settings.ResetTTL), link),
+	})
+	if err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditPasswordResetRequested, user.Username)
+	return nil
+}
+
+// ResetPassword also verifies the email, the link reached its inbox, and
+// lifts the login lockout of the user.
+func (s *accountService) ResetPassword(ctx context.Context, token string, password string) error {
+	ctx, span := tracer.Start(ctx, "AccountService.ResetPassword")
+	defer span.End()
+
+	// the password is checked first, an invalid one leaves the link usable
+	hash, err := hashPassword(password)
+	if err != nil {
+		return countValidation(err)
+	}
+
+	user, err := s.use(ctx, token, model.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	user.EmailVerified = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.throttle.Succeeded(ctx, user.Username); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditPasswordReset, user.Username)
+	return nil
+}
+
+func (s *accountService) AuthorizeWrite(ctx context.Context, username string) error {
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return err
+	}
+	if !model.RoleAllows(user.Role, model.RoleEditor) {
+		return ErrReadOnlyRole
+	}
+	if s.settings.RequireVerifiedEmail && !user.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// link issues a token for purpose and returns the link of the app to path
+// carrying it.
+func (s *accountService) link(ctx context.Context, user *model.User, purpose string, ttl time.Duration, path string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(random)
+
+	token, err := s.auth.GenerateActionToken(user.Username, purpose, id, ttl)
+	if err != nil {
+		return "", err
+	}
+	err = s.tokenRepo.Issue(ctx, &model.UserToken{ID: id, UserID: user.ID, Purpose: purpose, ExpiresAt: s.now().Add(ttl).UTC()})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(s.settings.AppURL, "/") + path + "?token=" + url.QueryEscape(token), nil
+}
+
+// use checks the signature of token and marks it used, every reason to
+// refuse it answers the same.
+func (s *accountService) use(ctx context.Context, token string, purpose string) (*model.User, error) {
+	ok, username, id := s.auth.ValidateActionToken(token, purpose)
+	if !ok {
+		return nil, ErrUserTokenInvalid
+	}
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if errors.Is(err, repository.UserNotFoundError) {
+		return nil, ErrUserTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.tokenRepo.Use(ctx, user.ID, id, purpose, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrUserTokenInvalid
+	}
+	return user, nil
+}
+
+// validity reads ttl the way people write it, "24 hours" rather than "24h0m0s".
+func validity(ttl time.Duration) string {
+	count, unit := int64(ttl/time.Second), "second"
+	switch {
+	case ttl%time.Hour == 0:
+		count, unit = int64(ttl/time.Hour), "hour"
+	case ttl%time.Minute == 0:
+		count, unit = int64(ttl/time.Minute), "minute"
+	}
+	if count != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", count, unit)
+}
+
+func (s *accountService) record(ctx context.Context, eventType string, username string) {
+	recordAudit(ctx, s.audit, &model.AuditEvent{Type: eventType, Username: username, Address: auth.ClientAddress(ctx), OccurredAt: s.now().UTC()})
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type accountFixture struct {
+	accounts    *accountService
+	userService *userService
+	userRepo    *mockUserRepository
+	mailer      *mockMailer
+	throttle    *LoginThrottle
+	clock       *clock
+	audit       *mockAuditRepository
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	password, err := hashPassword("password123")
+	require.NoError(t, err)
+	f := &accountFixture{
+		userRepo: &mockUserRepository{
+			users: map[string]*model.User{
+				"johndoe": {ID: 1, Username: "johndoe", Email: "johndoe@example.com", Password: password, Role: model.RoleEditor},
+			},
+		},
+		mailer: &mockMailer{},
+		clock:  &clock{now: time.Now()},
+		audit:  &mockAuditRepository{},
+	}
+
+	authService := auth.NewAuthService("secret-key")
+	f.throttle = NewLoginThrottle(&mockLoginAttemptRepository{attempts: map[string]*model.LoginAttempts{}}, f.audit)
+	f.throttle.now = f.clock.Now
+	f.accounts = NewAccountService(f.userRepo, &mockUserTokenRepository{tokens: map[string]*model.UserToken{}}, f.audit,
+		authService, f.mailer, f.throttle, AccountSettings{
+			AppURL:               "https://app.example.com/",
+			VerificationTTL:      24 * time.Hour,
+			ResetTTL:             time.Hour,
+			RequireVerifiedEmail: true,
+		})
+	f.accounts.now = f.clock.Now
+	f.userService = NewUserService(f.userRepo, newMfaRepository(), f.accounts, authService, f.throttle)
+	return f
+}
+
+func TestAccountService_VerifyEmail_HappyPath(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	require.NoError(t, f.accounts.SendVerification(context.Background(), "johndoe"))
+	token := f.mailer.token(t, "https://app.example.com/verify-email")
+
+	// test
+	err := f.accounts.VerifyEmail(context.Background(), token)
+	reusedErr := f.accounts.VerifyEmail(context.Background(), token)
+
+	// assertions
+	require.NoError(t, err)
+	require.True(t, f.userRepo.users["johndoe"].EmailVerified)
+	require.ErrorIs(t, reusedErr, ErrUserTokenInvalid)
+	require.Equal(t, "johndoe@example.com", f.mailer.sent[0].To)
+	require.Contains(t, f.mailer.sent[0].Body, "within 24 hours")
+	require.Len(t, f.audit.keys(model.AuditEmailVerified), 1)
+
+	againErr := f.accounts.SendVerification(context.Background(), "johndoe")
+	require.ErrorIs(t, againErr, ErrEmailAlreadyVerified)
+}
+
+func TestAccountService_VerifyEmail_UnhappyPath_Expired(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	require.NoError(t, f.accounts.SendVerification(context.Background(), "johndoe"))
+	token := f.mailer.token(t, "https://app.example.com/verify-email")
+	f.clock.Advance(25 * time.Hour)
+
+	// test
+	err := f.accounts.VerifyEmail(context.Background(), token)
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserTokenInvalid)
+	require.False(t, f.userRepo.users["johndoe"].EmailVerified)
+}
+
+func TestAccountService_VerifyEmail_OnlyTheLatestLinkWorks(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	require.NoError(t, f.accounts.SendVerification(context.Background(), "johndoe"))
+	first := f.mailer.token(t, "https://app.example.com/verify-email")
+	require.NoError(t, f.accounts.SendVerification(context.Background(), "johndoe"))
+	latest := f.mailer.token(t, "https://app.example.com/verify-email")
+
+	// test
+	firstErr := f.accounts.VerifyEmail(context.Background(), first)
+	err := f.accounts.VerifyEmail(context.Background(), latest)
+
+	// assertions
+	require.ErrorIs(t, firstErr, ErrUserTokenInvalid)
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResetPassword_HappyPath(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	for range f.throttle.MaxFailures {
+		_, err := f.userService.Login(context.Background(), "johndoe", "wrong-password")
+		require.ErrorIs(t, err, UserOrPasswordNotFoundError)
+		f.clock.Advance(time.Minute)
+	}
+	require.NoError(t, f.accounts.RequestPasswordReset(context.Background(), "johndoe@example.com"))
+	token := f.mailer.token(t, "https://app.example.com/reset-password")
+	require.Contains(t, f.mailer.sent[0].Body, "within 1 hour")
+
+	// test
+	err := f.accounts.ResetPassword(context.Background(), token, "new-password")
+	reusedErr := f.accounts.ResetPassword(context.Background(), token, "other-password")
+
+	// assertions
+	require.NoError(t, err)
+	require.ErrorIs(t, reusedErr, ErrUserTokenInvalid)
+	require.True(t, f.userRepo.users["johndoe"].EmailVerified)
+	session, err := f.userService.Login(context.Background(), "johndoe", "new-password")
+	require.NoError(t, err, "the reset lifts the lockout")
+	require.NotEmpty(t, session)
+	require.Len(t, f.audit.keys(model.AuditPasswordResetRequested), 1)
+	require.Len(t, f.audit.keys(model.AuditPasswordReset), 1)
+}
+
+func TestAccountService_ResetPassword_InvalidPasswordKeepsTheLink(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	require.NoError(t, f.accounts.RequestPasswordReset(context.Background(), "johndoe@example.com"))
+	token := f.mailer.token(t, "https://app.example.com/reset-password")
+
+	// test
+	invalidErr := f.accounts.ResetPassword(context.Background(), token, "")
+	err := f.accounts.ResetPassword(context.Background(), token, "new-password")
+
+	// assertions
+	require.ErrorIs(t, invalidErr, validation.ErrPasswordIsInvalid)
+	require.NoError(t, err)
+}
+
+func TestAccountService_ResetPassword_UnhappyPath_VerificationToken(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	require.NoError(t, f.accounts.SendVerification(context.Background(), "johndoe"))
+	token := f.mailer.token(t, "https://app.example.com/verify-email")
+
+	// test
+	err := f.accounts.ResetPassword(context.Background(), token, "new-password")
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserTokenInvalid)
+}
+
+func TestAccountService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+
+	// test
+	err := f.accounts.RequestPasswordReset(context.Background(), "janedoe@example.com")
+
+	// assertions
+	require.NoError(t, err)
+	require.Empty(t, f.mailer.sent)
+}
+
+func TestAccountService_RequestPasswordReset_SingleSignOnUser(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	f.userRepo.users["johndoe"].Password = ""
+
+	// test
+	err := f.accounts.RequestPasswordReset(context.Background(), "johndoe@example.com")
+
+	// assertions
+	require.NoError(t, err)
+	require.Empty(t, f.mailer.sent)
+}
+
+func TestAccountService_AuthorizeWrite(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+
+	// test
+	unverifiedErr := f.accounts.AuthorizeWrite(context.Background(), "johndoe")
+	f.userRepo.users["johndoe"].EmailVerified = true
+	verifiedErr := f.accounts.AuthorizeWrite(context.Background(), "johndoe")
+
+	// assertions
+	require.ErrorIs(t, unverifiedErr, ErrEmailNotVerified)
+	require.NoError(t, verifiedErr)
+}
+
+func TestAccountService_AuthorizeWrite_NotRequired(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	f.accounts.settings.RequireVerifiedEmail = false
+
+	// test
+	err := f.accounts.AuthorizeWrite(context.Background(), "johndoe")
+
+	// assertions
+	require.NoError(t, err)
+}
+
+func TestAccountService_AuthorizeWrite_Viewer(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	f.accounts.settings.RequireVerifiedEmail = false
+	f.userRepo.users["johndoe"].Role = model.RoleViewer
+
+	// test
+	err := f.accounts.AuthorizeWrite(context.Background(), "johndoe")
+
+	// assertions
+	require.ErrorIs(t, err, ErrReadOnlyRole)
+}
+
+func TestAccountService_SendVerification_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	f.userRepo.users["johndoe"].Disabled = true
+
+	// test
+	err := f.accounts.SendVerification(context.Background(), "johndoe")
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserDisabled)
+	require.Empty(t, f.mailer.sent)
+}
+
+func TestAccountService_AuthorizeWrite_Disabled(t *testing.T) {
+	// fixture
+	f := newAccountFixture(t)
+	f.accounts.settings.RequireVerifiedEmail = false
+	f.userRepo.users["johndoe"].Disabled = true
+
+	// test
+	err := f.accounts.AuthorizeWrite(context.Background(), "johndoe")
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserDisabled)
+}
+
+type mockMailer struct {
+	sent []*model.Mail
+}
+
+func (m *mockMailer) Send(ctx context.Context, mail *model.Mail) error {
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+var linkRegexp = regexp.MustCompile(`(https://\S+)\?token=(\S+)`)
+
+// token returns the token of the link to page in the latest email.
+func (m *mockMailer) token(t *testing.T, page string) string {
+	require.NotEmpty(t, m.sent)
+	match := linkRegexp.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	require.NotNil(t, match)
+	require.Equal(t, page, match[1])
+	token, err := url.QueryUnescape(match[2])
+	require.NoError(t, err)
+	return token
+}
+
+type mockUserTokenRepository struct {
+	tokens map[string]*model.UserToken
+}
+
+func (r *mockUserTokenRepository) Issue(ctx context.Context, token *model.UserToken) error {
+	for id, issued := range r.tokens {
+		if issued.UserID == token.UserID && issued.Purpose == token.Purpose && issued.UsedAt == nil {
+			delete(r.tokens, id)
+		}
+	}
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *mockUserTokenRepository) Use(ctx context.Context, userID int, id string, purpose string, now time.Time) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.UserID != userID || token.Purpose != purpose || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return false, nil
+	}
+	token.UsedAt = &now
+	return true, nil
+}
+
+// mockAccountService records the users it was asked to mail a verification.
+type mockAccountService struct {
+	verifications []string
+}
+
+func (s *mockAccountService) SendVerification(ctx context.Context, username string) error {
+	s.verifications = append(s.verifications, username)
+	return nil
+}
+
+func (s *mockAccountService) VerifyEmail(ctx context.Context, token string) error {
+	return nil
+}
+
+func (s *mockAccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	return nil
+}
+
+func (s *mockAccountService) ResetPassword(ctx context.Context, token string, password string) error {
+	return nil
+}
+
+func (s *mockAccountService) AuthorizeWrite(ctx context.Context, username string) error {
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+var ErrAnnotationNotFound = fmt.Errorf("annotation not found")
+var ErrRevisionNotFound = fmt.Errorf("revision not found")
+
+type annotationService struct {
+	annotationsRepo ports.AnnotationRepository
+	videoRepo       ports.VideoRepository
+	userRepo        ports.UserRepository
+	workspaceRepo   ports.WorkspaceRepository
+	transactor      ports.Transactor
+}
+
+func NewAnnotationService(
+	annotationsRepo ports.AnnotationRepository,
+	videoRepo ports.VideoRepository,
+	userRepo ports.UserRepository,
+	workspaceRepo ports.WorkspaceRepository,
+	transactor ports.Transactor) ports.AnnotationService {
+	return &annotationService{
+		annotationsRepo: annotationsRepo,
+		videoRepo:       videoRepo,
+		userRepo:        userRepo,
+		workspaceRepo:   workspaceRepo,
+		transactor:      transactor,
+	}
+}
+
+// Revisions lists the states of an annotation from the oldest one. The stored
+// revisions are the states it had before each update, the current state comes
+// last with the number it gets once an update replaces it.
+func (s *annotationService) Revisions(ctx context.Context, username string, annotationId int) ([]*model.AnnotationRevision, error) {
+	ctx, span := tracer.Start(ctx, "AnnotationService.Revisions")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.revisions(ctx, scope.ids(model.RoleViewer), annotationId)
+}
+
+func (s *annotationService) revisions(ctx context.Context, workspaceIds []int, annotationId int) ([]*model.AnnotationRevision, error) {
+	annotation, err := s.annotationsRepo.FindById(ctx, workspaceIds, annotationId)
+	if err != nil {
+		return nil, ErrAnnotationNotFound
+	}
+	revisions, err := s.annotationsRepo.FindRevisions(ctx, workspaceIds, annotationId)
+	if err != nil {
+		return nil, err
+	}
+	return append(revisions, currentRevision(annotation, revisions)), nil
+}
+
+// currentRevision is the state of annotation as a revision that is not stored
+// yet, it has no id and dates from the update that replaced the stored
+// revision before it.
+func currentRevision(annotation *model.Annotation, stored []*model.AnnotationRevision) *model.AnnotationRevision {
+	current := &model.AnnotationRevision{AnnotationID: annotation.ID, Revision: 1, StartTime: annotation.StartTime,
+		EndTime: annotation.EndTime, Type: annotation.Type, Note: annotation.Note}
+	if len(stored) > 0 {
+		last := stored[len(stored)-1]
+		current.Revision, current.CreatedAt = last.Revision+1, last.CreatedAt
+	}
+	return current
+}
+
+func (s *annotationService) Diff(ctx context.Context, username string, annotationId, from, to int) ([]*model.AnnotationFieldChange, error) {
+	ctx, span := tracer.Start(ctx, "AnnotationService.Diff")
+	defer span.End()
+
+	revisions, err := s.Revisions(ctx, username, annotationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromRevision, toRevision *model.AnnotationRevision
+	if fromRevision = findRevision(revisions, from); fromRevision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	if toRevision = findRevision(revisions, to); toRevision == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	return diffRevisions(fromRevision, toRevision), nil
+}
+
+// Revert restores the fields of the given revision. The state being replaced
+// is kept as a new revision, which is returned, so reverting never loses
+// history. Nothing is written when the annotation already has those fields,
+// the given revision is returned then.
+func (s *annotationService) Revert(ctx context.Context, username string, annotationId, revision int) (*model.AnnotationRevision, error) {
+	ctx, span := tracer.Start(ctx, "AnnotationService.Revert")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	workspaceIds := scope.ids(model.RoleViewer)
+
+	revisions, err := s.revisions(ctx, workspaceIds, annotationId)
+	if err != nil {
+		return nil, err
+	}
+
+	target := findRevision(revisions, revision)
+	if target == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	current, err := s.annotationsRepo.FindById(ctx, workspaceIds, annotationId)
+	if err != nil {
+		return nil, ErrAnnotationNotFound
+	}
+
+	video, err := s.videoRepo.FindById(ctx, workspaceIds, current.VideoID)
+	if err != nil {
+		return nil, ErrAnnotationNotFound
+	}
+	if !scope.allows(video.WorkspaceID, model.RoleEditor) {
+		return nil, ErrNotAllowed
+	}
+	if current.StartTime == target.StartTime && current.EndTime == target.EndTime &&
+		current.Type == target.Type && current.Note == target.Note {
+		return target, nil
+	}
+
+	annotation := &model.Annotation{
+		ID:        annotationId,
+		Version:   current.Version,
+		StartTime: target.StartTime,
+		EndTime:   target.EndTime,
+		Type:      target.Type,
+		Note:      target.Note,
+	}
+	var written *model.AnnotationRevision
+	err = s.transactor.Transaction(ctx, func(ctx context.Context, tx *ports.Tx) error {
+		if written, err = tx.Annotations.Update(ctx, []int{video.WorkspaceID}, annotationId, annotation); err != nil {
+			return err
+		}
+
+		updated := *annotation
+		updated.VideoID, updated.UserID, updated.Version = current.VideoID, current.UserID, current.Version+1
+		return appendEvents(ctx, tx.Outbox, annotationEvent(model.EventAnnotationUpdated, video.WorkspaceID, &updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+func findRevision(revisions []*model.AnnotationRevision, revision int) *model.AnnotationRevision {
+	for _, r := range revisions {
+		if r.Revision == revision {
+			return r
+		}
+	}
+	return nil
+}
+
+func diffRevisions(from, to *model.AnnotationRevision) []*model.AnnotationFieldChange {
+	changes := []*model.AnnotationFieldChange{}
+	if from.StartTime != to.StartTime {
+		changes = append(changes, &model.AnnotationFieldChange{Field: "start_time", From: from.StartTime, To: to.StartTime})
+	}
+	if from.EndTime != to.EndTime {
+		changes = append(changes, &model.AnnotationFieldChange{Field: "end_time", From: from.EndTime, To: to.EndTime})
+	}
+	if from.Type != to.Type {
+		changes = append(changes, &model.AnnotationFieldChange{Field: "type", From: from.Type, To: to.Type})
+	}
+	if from.Note != to.Note {
+		changes = append(changes, &model.AnnotationFieldChange{Field: "note", From: from.Note, To: to.Note})
+	}
+	return changes
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/stretchr/testify/require"
+)
+
+var ErrMockAnnotationNotFound = fmt.Errorf("annotation not found")
+
+func TestAnnotationService_Revisions_HappyPath(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	service := NewAnnotationService(repo, newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, repo))
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	repo.update(t, &update)
+
+	// test
+	revisions, err := service.Revisions(context.Background(), "johndoe", 1)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, 1, revisions[0].Revision)
+	require.Equal(t, "first note", revisions[0].Note)
+	require.Equal(t, 2, revisions[1].Revision)
+	require.Equal(t, "second note", revisions[1].Note)
+	require.Zero(t, revisions[1].ID)
+}
+
+func TestAnnotationService_Revisions_UnhappyPath_AnnotationNotFound(t *testing.T) {
+	// fixture
+	service := NewAnnotationService(newMockAnnotationRepository(), newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, nil))
+
+	// test
+	revisions, err := service.Revisions(context.Background(), "johndoe", 42)
+
+	// assertions
+	require.EqualError(t, err, ErrAnnotationNotFound.Error())
+	require.Nil(t, revisions)
+}
+
+func TestAnnotationService_Diff_HappyPath(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	service := NewAnnotationService(repo, newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, repo))
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	update.EndTime = 3 * time.Minute
+	repo.update(t, &update)
+	update = *repo.annotations[1]
+	update.Type = "other"
+	repo.update(t, &update)
+
+	// test
+	changes, err := service.Diff(context.Background(), "johndoe", 1, 1, 2)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, []*model.AnnotationFieldChange{
+		{Field: "end_time", From: 2 * time.Minute, To: 3 * time.Minute},
+		{Field: "note", From: "first note", To: "second note"},
+	}, changes)
+}
+
+func TestAnnotationService_Diff_HappyPath_CurrentState(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	service := NewAnnotationService(repo, newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, repo))
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	repo.update(t, &update)
+
+	// test
+	changes, err := service.Diff(context.Background(), "johndoe", 1, 1, 2)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, []*model.AnnotationFieldChange{
+		{Field: "note", From: "first note", To: "second note"},
+	}, changes)
+}
+
+func TestAnnotationService_Diff_UnhappyPath_RevisionNotFound(t *testing.T) {
+	// fixture
+	service := NewAnnotationService(newMockAnnotationRepository(), newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, nil))
+
+	// test
+	changes, err := service.Diff(context.Background(), "johndoe", 1, 1, 5)
+
+	// assertions
+	require.EqualError(t, err, ErrRevisionNotFound.Error())
+	require.Nil(t, changes)
+}
+
+func TestAnnotationService_Revert_HappyPath(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	transactor := newMockTransactor(nil, repo)
+	service := NewAnnotationService(repo, newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	repo.update(t, &update)
+
+	// test
+	written, err := service.Revert(context.Background(), "johndoe", 1, 1)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, 2, written.Revision)
+	require.Equal(t, "second note", written.Note)
+	require.Equal(t, "first note", repo.annotations[1].Note)
+	require.Len(t, repo.revisions[1], 2)
+	require.Equal(t, []string{model.EventAnnotationUpdated}, transactor.outbox.types())
+	require.Equal(t, 3, transactor.outbox.events[0].Annotation.Version)
+	require.Equal(t, 1, transactor.outbox.events[0].WorkspaceID)
+}
+
+func TestAnnotationService_Revert_HappyPath_AlreadyAtRevision(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	transactor := newMockTransactor(nil, repo)
+	service := NewAnnotationService(repo, newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	repo.update(t, &update)
+	update = *repo.annotations[1]
+	update.Note = "first note"
+	repo.update(t, &update)
+
+	// test
+	written, err := service.Revert(context.Background(), "johndoe", 1, 1)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, 1, written.Revision)
+	require.Len(t, repo.revisions[1], 2)
+	require.Empty(t, transactor.outbox.types())
+}
+
+func TestAnnotationService_Revert_UnhappyPath_NotAllowed(t *testing.T) {
+	// fixture
+	repo := newMockAnnotationRepository()
+	workspaceRepo := newMockWorkspaceRepository()
+	workspaceRepo.join(1, 2, model.RoleViewer)
+	userRepo := newMockUserRepository()
+	userRepo.users["janedoe"] = &model.User{ID: 2, Username: "janedoe"}
+	userRepo.users["outsider"] = &model.User{ID: 3, Username: "outsider"}
+	service := NewAnnotationService(repo, newMockVideoRepository(), userRepo, workspaceRepo, newMockTransactor(nil, repo))
+
+	update := *repo.annotations[1]
+	update.Note = "second note"
+	repo.update(t, &update)
+
+	// test
+	revisions, viewerErr := service.Revisions(context.Background(), "janedoe", 1)
+	_, revertErr := service.Revert(context.Background(), "janedoe", 1, 1)
+	_, outsiderErr := service.Revisions(context.Background(), "outsider", 1)
+
+	// assertions
+	require.NoError(t, viewerErr)
+	require.Len(t, revisions, 2)
+	require.ErrorIs(t, revertErr, ErrNotAllowed)
+	require.ErrorIs(t, outsiderErr, ErrAnnotationNotFound)
+	require.Len(t, repo.revisions[1], 1)
+}
+
+func TestAnnotationService_Revert_UnhappyPath_RevisionNotFound(t *testing.T) {
+	// fixture
+	service := NewAnnotationService(newMockAnnotationRepository(), newMockVideoRepository(), newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(nil, nil))
+
+	// test
+	written, err := service.Revert(context.Background(), "johndoe", 1, 7)
+
+	// assertions
+	require.EqualError(t, err, ErrRevisionNotFound.Error())
+	require.Nil(t, written)
+}
+
+type mockAnnotationRepository struct {
+	annotations map[int]*model.Annotation
+	revisions   map[int][]*model.AnnotationRevision
+	// workspaces holds the workspace of the videos by their id
+	workspaces map[int]int
+}
+
+func newMockAnnotationRepository() *mockAnnotationRepository {
+	return &mockAnnotationRepository{
+		annotations: map[int]*model.Annotation{
+			1: {
+				ID:        1,
+				VideoID:   1,
+				UserID:    1,
+				StartTime: 1 * time.Minute,
+				EndTime:   2 * time.Minute,
+				Type:      "advertisement",
+				Note:      "first note",
+				Version:   1,
+			},
+		},
+		revisions:  map[int][]*model.AnnotationRevision{},
+		workspaces: map[int]int{1: 1},
+	}
+}
+
+func (r *mockAnnotationRepository) inScope(workspaceIds []int, videoId int) bool {
+	workspaceId, ok := r.workspaces[videoId]
+	return ok && slices.Contains(workspaceIds, workspaceId)
+}
+
+func (r *mockAnnotationRepository) Create(ctx context.Context, annotation *model.Annotation, videoId, userId int) error {
+	annotation.ID = len(r.annotations) + 1
+	annotation.VideoID, annotation.UserID, annotation.Version = videoId, userId, 1
+	r.annotations[annotation.ID] = annotation
+	return nil
+}
+
+func (r *mockAnnotationRepository) FindById(ctx context.Context, workspaceIds []int, id int) (*model.Annotation, error) {
+	annotation, ok := r.annotations[id]
+	if !ok || !r.inScope(workspaceIds, annotation.VideoID) {
+		return nil, ErrMockAnnotationNotFound
+	}
+	return annotation, nil
+}
+
+func (r *mockAnnotationRepository) FindVideoId(ctx context.Context, workspaceIds []int, videoId int) ([]*model.Annotation, error) {
+	annotations := []*model.Annotation{}
+	for _, annotation := range r.annotations {
+		if annotation.VideoID == videoId && r.inScope(workspaceIds, videoId) {
+			annotations = append(annotations, annotation)
+		}
+	}
+	return annotations, nil
+}
+
+func (r *mockAnnotationRepository) FindByVideoIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Annotation, error) {
+	annotations := []*model.Annotation{}
+	for _, id := range ids {
+		found, _ := r.FindVideoId(ctx, workspaceIds, id)
+		annotations = append(annotations, found...)
+	}
+	return annotations, nil
+}
+
+func (r *mockAnnotationRepository) FindRevisions(ctx context.Context, workspaceIds []int, annotationId int) ([]*model.AnnotationRevision, error) {
+	revisions := append([]*model.AnnotationRevision{}, r.revisions[annotationId]...)
+	return revisions, nil
+}
+
+func (r *mockAnnotationRepository) Update(ctx context.Context, workspaceIds []int, id int, annotation *model.Annotation) (*model.AnnotationRevision, error) {
+	previous, ok := r.annotations[id]
+	if !ok || !r.inScope(workspaceIds, previous.VideoID) {
+		return nil, ErrMockAnnotationNotFound
+	}
+	if previous.Version != annotation.Version {
+		return nil, ports.ErrVersionConflict
+	}
+	written := &model.AnnotationRevision{
+		ID:           len(r.revisions[id]) + 1,
+		AnnotationID: id,
+		Revision:     len(r.revisions[id]) + 1,
+		StartTime:    previous.StartTime,
+		EndTime:      previous.EndTime,
+		Type:         previous.Type,
+		Note:         previous.Note,
+	}
+	r.revisions[id] = append(r.revisions[id], written)
+	updated := *previous
+	updated.StartTime = annotation.StartTime
+	updated.EndTime = annotation.EndTime
+	updated.Type = annotation.Type
+	updated.Note = annotation.Note
+	updated.Version++
+	r.annotations[id] = &updated
+	return written, nil
+}
+
+// update changes the annotation like an update by its owner would.
+func (r *mockAnnotationRepository) update(t *testing.T, annotation *model.Annotation) {
+	_, err := r.Update(context.Background(), []int{1}, annotation.ID, annotation)
+	require.NoError(t, err)
+}
+
+func (r *mockAnnotationRepository) Transfer(ctx context.Context, videoId int, userId int) error {
+	for _, annotation := range r.annotations {
+		if annotation.VideoID == videoId {
+			annotation.UserID = userId
+		}
+	}
+	return nil
+}
+
+func (r *mockAnnotationRepository) Remove(ctx context.Context, workspaceIds []int, id int) error {
+	if annotation, ok := r.annotations[id]; ok && r.inScope(workspaceIds, annotation.VideoID) {
+		delete(r.annotations, id)
+	}
+	return nil
+}
+
+type mockTransactor struct {
+	users       ports.UserRepository
+	videos      ports.VideoRepository
+	annotations ports.AnnotationRepository
+	outbox      *mockOutboxRepository
+}
+
+func newMockTransactor(videos ports.VideoRepository, annotations ports.AnnotationRepository) *mockTransactor {
+	return &mockTransactor{videos: videos, annotations: annotations, outbox: &mockOutboxRepository{}}
+}
+
+func (t *mockTransactor) Transaction(ctx context.Context, fn func(ctx context.Context, tx *ports.Tx) error) error {
+	return fn(ctx, &ports.Tx{Users: t.users, Videos: t.videos, Annotations: t.annotations, Outbox: t.outbox})
+}
+
+type mockOutboxRepository struct {
+	events []*model.Event
+}
+
+func (r *mockOutboxRepository) Append(ctx context.Context, event *model.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *mockOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEntry, error) {
+	return nil, nil
+}
+
+func (r *mockOutboxRepository) Reschedule(ctx context.Context, entry *model.OutboxEntry) error {
+	return nil
+}
+
+func (r *mockOutboxRepository) Remove(ctx context.Context, id int) error {
+	return nil
+}
+
+func (r *mockOutboxRepository) types() []string {
+	types := []string{}
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+var (
+	ErrApiKeyNotFound = fmt.Errorf("api key not found")
+	ErrApiKeyInvalid  = fmt.Errorf("api key is unknown, expired or revoked")
+)
+
+type apiKeyService struct {
+	apiKeyRepo ports.ApiKeyRepository
+	userRepo   ports.UserRepository
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// NewApiKeyService issues keys that expire after ttl.
+func NewApiKeyService(apiKeyRepo ports.ApiKeyRepository, userRepo ports.UserRepository, ttl time.Duration) ports.ApiKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, username string, name string) (*model.ApiKey, string, error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.Create")
+	defer span.End()
+
+	if err := validation.ValidateApiKeyName(name); err != nil {
+		return nil, "", countValidation(err)
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey, id, hash, err := auth.GenerateApiKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now().UTC()
+	key := &model.ApiKey{ID: id, UserID: user.ID, Name: name, Hash: hash, CreatedAt: now, ExpiresAt: now.Add(s.ttl)}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, apiKey, nil
+}
+
+func (s *apiKeyService) Keys(ctx context.Context, username string) ([]*model.ApiKey, error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.Keys")
+	defer span.End()
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.apiKeyRepo.FindByUser(ctx, user.ID)
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, username string, id string) error {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.Revoke")
+	defer span.End()
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.apiKeyRepo.Remove(ctx, id, user.ID); err != nil {
+		if errors.Is(err, repository.ErrApiKeyNotFound) {
+			return ErrApiKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Authenticate looks the user up by id, so a key keeps working when its user
+// is renamed, and refuses the keys of disabled users.
+func (s *apiKeyService) Authenticate(ctx context.Context, apiKey string) (*model.ApiKey, string, error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.Authenticate")
+	defer span.End()
+
+	id, hash, ok := auth.ParseApiKey(apiKey)
+	if !ok {
+		return nil, "", ErrApiKeyInvalid
+	}
+
+	key, err := s.apiKeyRepo.Find(ctx, id)
+	if errors.Is(err, repository.ErrApiKeyNotFound) {
+		return nil, "", ErrApiKeyInvalid
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash)) != 1 || !s.now().Before(key.ExpiresAt) {
+		return nil, "", ErrApiKeyInvalid
+	}
+
+	users, err := s.userRepo.FindByIds(ctx, []int{key.UserID})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(users) == 0 || users[0].Disabled {
+		return nil, "", ErrApiKeyInvalid
+	}
+	return key, users[0].Username, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func TestApiKeyService_Create_HappyPath(t *testing.T) {
+	// fixture
+	apiKeyRepo := newMockApiKeyRepository()
+	service := NewApiKeyService(apiKeyRepo, newMockUserRepository(), time.Hour)
+
+	// test
+	key, apiKey, err := service.Create(context.Background(), "johndoe", "ci")
+
+	// assertions
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(apiKey, auth.ApiKeyPrefix))
+	require.Equal(t, 1, key.UserID)
+	require.Equal(t, "ci", key.Name)
+	require.Equal(t, time.Hour, key.ExpiresAt.Sub(key.CreatedAt))
+	require.NotContains(t, apiKey, key.Hash)
+	require.Same(t, key, apiKeyRepo.keys[key.ID])
+}
+
+func TestApiKeyService_Create_UnhappyPath_InvalidName(t *testing.T) {
+	// fixture
+	apiKeyRepo := newMockApiKeyRepository()
+	service := NewApiKeyService(apiKeyRepo, newMockUserRepository(), time.Hour)
+
+	// test
+	_, _, err := service.Create(context.Background(), "johndoe", " ")
+
+	// assertions
+	require.ErrorIs(t, err, validation.ErrApiKeyNameIsInvalid)
+	require.Empty(t, apiKeyRepo.keys)
+}
+
+func TestApiKeyService_Revoke_UnhappyPath_NotOwned(t *testing.T) {
+	// fixture
+	apiKeyRepo := newMockApiKeyRepository()
+	apiKeyRepo.keys["key-id"] = &model.ApiKey{ID: "key-id", UserID: 2}
+	service := NewApiKeyService(apiKeyRepo, newMockUserRepository(), time.Hour)
+
+	// test
+	err := service.Revoke(context.Background(), "johndoe", "key-id")
+
+	// assertions
+	require.ErrorIs(t, err, ErrApiKeyNotFound)
+	require.Len(t, apiKeyRepo.keys, 1)
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	// fixture
+	apiKeyRepo := newMockApiKeyRepository()
+	userRepo := newMockUserRepository()
+	service := NewApiKeyService(apiKeyRepo, userRepo, time.Hour).(*apiKeyService)
+
+	key, apiKey, err := service.Create(context.Background(), "johndoe", "ci")
+	require.NoError(t, err)
+	forged := apiKey[:len(apiKey)-1] + "0"
+	if forged == apiKey {
+		forged = apiKey[:len(apiKey)-1] + "1"
+	}
+
+	// test
+	found, username, err := service.Authenticate(context.Background(), apiKey)
+	_, _, forgedErr := service.Authenticate(context.Background(), forged)
+	_, _, tokenErr := service.Authenticate(context.Background(), "eyJhbGciOiJIUzI1NiJ9.e30.signature")
+
+	userRepo.users["johndoe"].Disabled = true
+	_, _, disabledErr := service.Authenticate(context.Background(), apiKey)
+	userRepo.users["johndoe"].Disabled = false
+
+	service.now = func() time.Time { return key.ExpiresAt }
+	_, _, expiredErr := service.Authenticate(context.Background(), apiKey)
+	service.now = time.Now
+
+	require.NoError(t, service.Revoke(context.Background(), "johndoe", key.ID))
+	_, _, revokedErr := service.Authenticate(context.Background(), apiKey)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, key.ID, found.ID)
+	require.Equal(t, "johndoe", username)
+	require.ErrorIs(t, forgedErr, ErrApiKeyInvalid)
+	require.ErrorIs(t, tokenErr, ErrApiKeyInvalid)
+	require.ErrorIs(t, disabledErr, ErrApiKeyInvalid)
+	require.ErrorIs(t, expiredErr, ErrApiKeyInvalid)
+	require.ErrorIs(t, revokedErr, ErrApiKeyInvalid)
+}
+
+type mockApiKeyRepository struct {
+	keys map[string]*model.ApiKey
+}
+
+func newMockApiKeyRepository() *mockApiKeyRepository {
+	return &mockApiKeyRepository{keys: map[string]*model.ApiKey{}}
+}
+
+func (r *mockApiKeyRepository) Create(ctx context.Context, key *model.ApiKey) error {
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *mockApiKeyRepository) Find(ctx context.Context, id string) (*model.ApiKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, repository.ErrApiKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *mockApiKeyRepository) FindByUser(ctx context.Context, userId int) ([]*model.ApiKey, error) {
+	keys := []*model.ApiKey{}
+	for _, key := range r.keys {
+		if key.UserID == userId {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *mockApiKeyRepository) Remove(ctx context.Context, id string, userId int) error {
+	key, ok := r.keys[id]
+	if !ok || key.UserID != userId {
+		return repository.ErrApiKeyNotFound
+	}
+	delete(r.keys, id)
+	return nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+// recordAudit logs and stores an audit event, a failure to store it is
+// logged rather than failing the action it records.
+func recordAudit(ctx context.Context, audit ports.AuditRepository, event *model.AuditEvent) {
+	logger := logging.FromContext(ctx)
+	logger.Info("audit", "event", event.Type, "username", event.Username, "address", event.Address, "detail", event.Detail)
+	if err := audit.Append(ctx, event); err != nil {
+		logger.Error("storing audit event failed", "event", event.Type, "error", err)
+	}
+}
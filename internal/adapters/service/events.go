@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+// appendEvents records domain events in the outbox of the transaction that
+// stores the change, they are dispatched once it commits.
+func appendEvents(ctx context.Context, outbox ports.OutboxRepository, events ...*model.Event) error {
+	for _, event := range events {
+		if err := outbox.Append(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func videoEvent(eventType string, video *model.Video) *model.Event {
+	return &model.Event{ID: rand.Text(), Type: eventType, WorkspaceID: video.WorkspaceID, VideoID: video.ID, Video: video,
+		OccurredAt: time.Now().UTC()}
+}
+
+func annotationEvent(eventType string, workspaceId int, annotation *model.Annotation) *model.Event {
+	return &model.Event{ID: rand.Text(), Type: eventType, WorkspaceID: workspaceId, VideoID: annotation.VideoID,
+		Annotation: annotation, OccurredAt: time.Now().UTC()}
+}
+
+// MarshalEvent encodes the JSON body sent to webhook and stream subscribers.
+func MarshalEvent(event *model.Event) ([]byte, error) {
+	return json.Marshal(newEventPayload(event))
+}
+
+type eventPayload struct {
+	ID          string             `json:"id"`
+	Type        string             `json:"type"`
+	OccurredAt  time.Time          `json:"occurred_at"`
+	WorkspaceID int                `json:"workspace_id"`
+	VideoID     int                `json:"video_id"`
+	Video       *videoPayload      `json:"video,omitempty"`
+	Annotation  *annotationPayload `json:"annotation,omitempty"`
+}
+
+type videoPayload struct {
+	ID              int     `json:"id"`
+	UserID          int     `json:"user_id"`
+	WorkspaceID     int     `json:"workspace_id"`
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	Link            string  `json:"link"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Version         int     `json:"version"`
+}
+
+type annotationPayload struct {
+	ID           int     `json:"id"`
+	VideoID      int     `json:"video_id"`
+	UserID       int     `json:"user_id"`
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Type         string  `json:"type"`
+	Note         string  `json:"note"`
+	Version      int     `json:"version"`
+}
+
+func newEventPayload(event *model.Event) *eventPayload {
+	payload := &eventPayload{
+		ID:          event.ID,
+		Type:        event.Type,
+		OccurredAt:  event.OccurredAt,
+		WorkspaceID: event.WorkspaceID,
+		VideoID:     event.VideoID,
+	}
+	if video := event.Video; video != nil {
+		payload.Video = &videoPayload{
+			ID:              video.ID,
+			UserID:          video.UserID,
+			WorkspaceID:     video.WorkspaceID,
+			Title:           video.Title,
+			Description:     video.Description,
+			Link:            video.Link,
+			DurationSeconds: video.Duration.Seconds(),
+			Version:         video.Version,
+		}
+	}
+	if annotation := event.Annotation; annotation != nil {
+		payload.Annotation = &annotationPayload{
+			ID:           annotation.ID,
+			VideoID:      annotation.VideoID,
+			UserID:       annotation.UserID,
+			StartSeconds: annotation.StartTime.Seconds(),
+			EndSeconds:   annotation.EndTime.Seconds(),
+			Type:         annotation.Type,
+			Note:         annotation.Note,
+			Version:      annotation.Version,
+		}
+	}
+	return payload
+}
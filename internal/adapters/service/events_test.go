@@ -0,0 +1,33 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalEvent(t *testing.T) {
+	// fixture
+	event := &model.Event{
+		ID:         "evt",
+		Type:       model.EventVideoUpdated,
+		VideoID:    1,
+		Video:      &model.Video{ID: 1, Title: "title", Duration: 90 * time.Second, Version: 2},
+		OccurredAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// test
+	data, err := MarshalEvent(event)
+
+	// assertions
+	require.NoError(t, err)
+	payload := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &payload))
+	require.Equal(t, "evt", payload["id"])
+	require.Equal(t, "2024-01-01T12:00:00Z", payload["occurred_at"])
+	require.Equal(t, 90.0, payload["video"].(map[string]any)["duration_seconds"])
+	require.NotContains(t, payload, "annotation")
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+const (
+	defaultMaxFailures        = 5
+	defaultMaxAddressFailures = 20
+	defaultFailureDelay       = time.Second
+	defaultLockout            = 15 * time.Minute
+)
+
+var ErrLoginThrottled = errors.New("too many failed logins, retry later")
+
+// LoginThrottledError tells when a throttled login can be tried again, it
+// matches ErrLoginThrottled.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrLoginThrottled
+}
+
+// LoginThrottle slows down the logins of a username after each failure,
+// doubling FailureDelay every time, and locks the username out for Lockout
+// after MaxFailures, or an address after MaxAddressFailures. Usernames are
+// counted whether they exist or not, so the answers do not tell them apart.
+// Counts start over once Lockout has passed since the last failure.
+type LoginThrottle struct {
+	attempts ports.LoginAttemptRepository
+	audit    ports.AuditRepository
+	now      func() time.Time
+
+	MaxFailures        int
+	MaxAddressFailures int
+	FailureDelay       time.Duration
+	Lockout            time.Duration
+}
+
+func NewLoginThrottle(attempts ports.LoginAttemptRepository, audit ports.AuditRepository) *LoginThrottle {
+	return &LoginThrottle{
+		attempts:           attempts,
+		audit:              audit,
+		now:                time.Now,
+		MaxFailures:        defaultMaxFailures,
+		MaxAddressFailures: defaultMaxAddressFailures,
+		FailureDelay:       defaultFailureDelay,
+		Lockout:            defaultLockout,
+	}
+}
+
+// Check fails with a LoginThrottledError while the username or the address
+// is locked, or the username waits out the delay of its last failure.
+func (t *LoginThrottle) Check(ctx context.Context, username string, address string) error {
+	now := t.now().UTC()
+
+	for _, key := range t.keys(username, address) {
+		attempts, err := t.attempts.Find(ctx, key)
+		if err != nil {
+			return err
+		}
+		if attempts == nil {
+			continue
+		}
+
+		wait := time.Duration(0)
+		if attempts.LockedUntil != nil {
+			wait = attempts.LockedUntil.Sub(now)
+		}
+		if key == usernameKey(username) {
+			wait = max(wait, attempts.LastFailureAt.Add(t.delay(attempts.Failures)).Sub(now))
+		}
+		if wait > 0 {
+			t.record(ctx, model.AuditLoginRejected, username, address, "retry in "+wait.Round(time.Second).String())
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// Failed counts a failed login of username from address and locks either
+// once it reached its maximum.
+func (t *LoginThrottle) Failed(ctx context.Context, username string, address string) error {
+	now := t.now().UTC()
+	t.record(ctx, model.AuditLoginFailed, username, address, "")
+
+	for _, key := range t.keys(username, address) {
+		attempts, err := t.attempts.AddFailure(ctx, key, now, now.Add(-t.Lockout))
+		if err != nil {
+			return err
+		}
+
+		maxFailures := t.MaxAddressFailures
+		if key == usernameKey(username) {
+			maxFailures = t.MaxFailures
+		}
+		if attempts.Failures < maxFailures || attempts.LockedUntil != nil {
+			continue
+		}
+
+		if err := t.attempts.Lock(ctx, key, now.Add(t.Lockout)); err != nil {
+			return err
+		}
+		t.record(ctx, model.AuditLoginLocked, username, address,
+			fmt.Sprintf("%s locked for %s after %d failed logins", key, t.Lockout, attempts.Failures))
+	}
+	return nil
+}
+
+// Succeeded clears the failures of username, those of the address stay.
+func (t *LoginThrottle) Succeeded(ctx context.Context, username string) error {
+	return t.attempts.Delete(ctx, usernameKey(username))
+}
+
+// Unlock clears the failures and the lock of username.
+func (t *LoginThrottle) Unlock(ctx context.Context, username string) error {
+	if err := t.attempts.Delete(ctx, usernameKey(username)); err != nil {
+		return err
+	}
+	t.record(ctx, model.AuditLoginUnlocked, username, "", "")
+	return nil
+}
+
+// delay is the time to wait after the last of failures before the next
+// login, it never exceeds the lockout.
+func (t *LoginThrottle) delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	delay := t.FailureDelay
+	for range failures - 1 {
+		if delay >= t.Lockout {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, t.Lockout)
+}
+
+func (t *LoginThrottle) keys(username string, address string) []string {
+	keys := []string{usernameKey(username)}
+	if address != "" {
+		keys = append(keys, "address:"+address)
+	}
+	return keys
+}
+
+func (t *LoginThrottle) record(ctx context.Context, eventType string, username string, address string, detail string) {
+	recordAudit(ctx, t.audit, &model.AuditEvent{Type: eventType, Username: username, Address: address, Detail: detail, OccurredAt: t.now().UTC()})
+}
+
+func usernameKey(username string) string {
+	return "username:" + username
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func newLoginThrottle() *LoginThrottle {
+	return NewLoginThrottle(&mockLoginAttemptRepository{attempts: map[string]*model.LoginAttempts{}}, &mockAuditRepository{})
+}
+
+// clock stands in for the time of a throttle, tests move it forward.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
+
+func newThrottledUserService(t *testing.T) (*userService, *LoginThrottle, *clock, *mockAuditRepository) {
+	password, err := hashPassword("password123")
+	require.NoError(t, err)
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Password: password, Role: model.RoleEditor},
+		},
+	}
+
+	audit := &mockAuditRepository{}
+	throttle := NewLoginThrottle(&mockLoginAttemptRepository{attempts: map[string]*model.LoginAttempts{}}, audit)
+	now := &clock{now: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}
+	throttle.now = now.Now
+	throttle.MaxFailures = 3
+	throttle.MaxAddressFailures = 5
+
+	return NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), throttle), throttle, now, audit
+}
+
+func loginFrom(address string) context.Context {
+	return auth.WithClientAddress(context.Background(), address)
+}
+
+func TestLoginThrottle_DelaysDoubleAfterEachFailure(t *testing.T) {
+	// fixture
+	userService, _, now, _ := newThrottledUserService(t)
+	ctx := loginFrom("192.0.2.1")
+	_, err := userService.Login(ctx, "johndoe", "wrong-password")
+	require.ErrorIs(t, err, UserOrPasswordNotFoundError)
+
+	// test
+	_, tooSoon := userService.Login(ctx, "johndoe", "password123")
+	now.Advance(time.Second)
+	_, secondFailure := userService.Login(ctx, "johndoe", "wrong-password")
+	now.Advance(time.Second)
+	_, stillTooSoon := userService.Login(ctx, "johndoe", "password123")
+	now.Advance(time.Second)
+	token, err := userService.Login(ctx, "johndoe", "password123")
+
+	// assertions
+	var throttled *LoginThrottledError
+	require.ErrorAs(t, tooSoon, &throttled)
+	require.Equal(t, time.Second, throttled.RetryAfter)
+	require.ErrorIs(t, secondFailure, UserOrPasswordNotFoundError)
+	require.ErrorAs(t, stillTooSoon, &throttled)
+	require.Equal(t, time.Second, throttled.RetryAfter)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
+func TestLoginThrottle_LocksUnknownAndExistingUsernamesAlike(t *testing.T) {
+	// fixture
+	userService, throttle, now, audit := newThrottledUserService(t)
+
+	failUntilLocked := func(username string, address string) error {
+		for range throttle.MaxFailures {
+			_, err := userService.Login(loginFrom(address), username, "wrong-password")
+			require.ErrorIs(t, err, UserOrPasswordNotFoundError)
+			now.Advance(time.Minute)
+		}
+		_, err := userService.Login(loginFrom(address), username, "password123")
+		return err
+	}
+
+	// test
+	existing := failUntilLocked("johndoe", "192.0.2.1")
+	unknown := failUntilLocked("janedoe", "192.0.2.2")
+
+	// assertions
+	var existingThrottled, unknownThrottled *LoginThrottledError
+	require.ErrorAs(t, existing, &existingThrottled)
+	require.ErrorAs(t, unknown, &unknownThrottled)
+	require.Equal(t, existing.Error(), unknown.Error())
+	require.Equal(t, throttle.Lockout-time.Minute, existingThrottled.RetryAfter)
+	require.Equal(t, existingThrottled.RetryAfter, unknownThrottled.RetryAfter)
+	require.Equal(t, []string{"username:johndoe", "username:janedoe"}, audit.keys(model.AuditLoginLocked))
+
+	now.Advance(throttle.Lockout)
+	token, err := userService.Login(loginFrom("192.0.2.1"), "johndoe", "password123")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
+func TestLoginThrottle_LocksAddress(t *testing.T) {
+	// fixture
+	userService, throttle, now, _ := newThrottledUserService(t)
+	for i := range throttle.MaxAddressFailures {
+		_, err := userService.Login(loginFrom("192.0.2.1"), fmt.Sprintf("user%d", i), "wrong-password")
+		require.ErrorIs(t, err, UserOrPasswordNotFoundError)
+		now.Advance(time.Second)
+	}
+
+	// test
+	_, sameAddress := userService.Login(loginFrom("192.0.2.1"), "johndoe", "password123")
+	token, err := userService.Login(loginFrom("192.0.2.2"), "johndoe", "password123")
+
+	// assertions
+	require.ErrorIs(t, sameAddress, ErrLoginThrottled)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
+func TestUserService_Unlock(t *testing.T) {
+	// fixture
+	userService, throttle, now, audit := newThrottledUserService(t)
+	for range throttle.MaxFailures {
+		_, err := userService.Login(loginFrom("192.0.2.1"), "johndoe", "wrong-password")
+		require.ErrorIs(t, err, UserOrPasswordNotFoundError)
+		now.Advance(time.Minute)
+	}
+
+	// test
+	err := userService.Unlock(context.Background(), "johndoe")
+	unknownErr := userService.Unlock(context.Background(), "janedoe")
+
+	// assertions
+	require.NoError(t, err)
+	require.ErrorIs(t, unknownErr, ErrUserNotFound)
+	token, err := userService.Login(loginFrom("192.0.2.1"), "johndoe", "password123")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Len(t, audit.keys(model.AuditLoginUnlocked), 1)
+}
+
+type mockLoginAttemptRepository struct {
+	attempts map[string]*model.LoginAttempts
+}
+
+func (r *mockLoginAttemptRepository) Find(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	attempts, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *attempts
+	return &copied, nil
+}
+
+func (r *mockLoginAttemptRepository) AddFailure(ctx context.Context, key string, now time.Time, since time.Time) (*model.LoginAttempts, error) {
+	attempts, ok := r.attempts[key]
+	if !ok || attempts.LastFailureAt.Before(since) {
+		attempts = &model.LoginAttempts{Key: key}
+		r.attempts[key] = attempts
+	}
+	attempts.Failures++
+	attempts.LastFailureAt = now
+	return r.Find(ctx, key)
+}
+
+func (r *mockLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	attempts, ok := r.attempts[key]
+	if !ok {
+		return errors.New("no failure to lock")
+	}
+	attempts.LockedUntil = &until
+	return nil
+}
+
+func (r *mockLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	delete(r.attempts, key)
+	return nil
+}
+
+type mockAuditRepository struct {
+	events []*model.AuditEvent
+}
+
+func (r *mockAuditRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// keys returns the first word of the details of the events of a type, the
+// locked key of lock events.
+func (r *mockAuditRepository) keys(eventType string) []string {
+	keys := []string{}
+	for _, event := range r.events {
+		if event.Type == eventType {
+			key, _, _ := strings.Cut(event.Detail, " ")
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
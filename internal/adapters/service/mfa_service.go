@@ -0,0 +1,330 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+)
+
+const (
+	recoveryCodeCount = 10
+	qrCodeSize        = 256
+)
+
+var (
+	ErrMfaRequired         = errors.New("second factor required")
+	ErrMfaNotEnrolled      = errors.New("two-factor authentication is not set up")
+	ErrMfaAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMfaChallengeInvalid = errors.New("login challenge is invalid or expired")
+	InvalidMfaCodeError    = errors.New("invalid two-factor code")
+)
+
+// MfaRequiredError answers a login with the right password of a user with a
+// second factor, Challenge is sent back along with a code to finish it. It
+// matches ErrMfaRequired.
+type MfaRequiredError struct {
+	Challenge string
+}
+
+func (e *MfaRequiredError) Error() string {
+	return ErrMfaRequired.Error()
+}
+
+func (e *MfaRequiredError) Is(target error) bool {
+	return target == ErrMfaRequired
+}
+
+type mfaService struct {
+	userRepo ports.UserRepository
+	mfaRepo  ports.MfaRepository
+	audit    ports.AuditRepository
+	auth     auth.AuthService
+	throttle *LoginThrottle
+	issuer   string
+	now      func() time.Time
+}
+
+func NewMfaService(userRepo ports.UserRepository, mfaRepo ports.MfaRepository, audit ports.AuditRepository,
+	auth auth.AuthService, throttle *LoginThrottle, issuer string) *mfaService {
+	return &mfaService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		audit:    audit,
+		auth:     auth,
+		throttle: throttle,
+		issuer:   issuer,
+		now:      time.Now,
+	}
+}
+
+func (s *mfaService) Enroll(ctx context.Context, username string) (*model.MfaEnrollment, error) {
+	ctx, span := tracer.Start(ctx, "MfaService.Enroll")
+	defer span.End()
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.mfaRepo.Find(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, ErrMfaAlreadyEnabled
+	}
+
+	key, err := auth.NewTotpKey(s.issuer, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	qrCode := &bytes.Buffer{}
+	if err := png.Encode(qrCode, image); err != nil {
+		return nil, err
+	}
+
+	mfa = &model.Mfa{UserID: user.ID, Secret: key.Secret(), CreatedAt: s.now().UTC()}
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, err
+	}
+	return &model.MfaEnrollment{Secret: key.Secret(), URI: key.URL(), QRCode: qrCode.Bytes()}, nil
+}
+
+func (s *mfaService) Confirm(ctx context.Context, username string, code string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "MfaService.Confirm")
+	defer span.End()
+
+	user, mfa, err := s.find(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, ErrMfaAlreadyEnabled
+	}
+
+	step, ok := auth.MatchTotp(mfa.Secret, code, s.now())
+	if !ok {
+		return nil, countValidation(validation.ErrMfaCodeIsInvalid)
+	}
+	mfa.Enabled = true
+	mfa.LastStep = step
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, model.AuditMfaEnabled, user.Username)
+	return codes, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, username string, code string) error {
+	ctx, span := tracer.Start(ctx, "MfaService.Disable")
+	defer span.End()
+
+	user, err := s.verifyEnabled(ctx, username, code)
+	if err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditMfaDisabled, user.Username)
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, username string, code string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "MfaService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	user, err := s.verifyEnabled(ctx, username, code)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, model.AuditMfaRecoveryCodesRegenerated, user.Username)
+	return codes, nil
+}
+
+func (s *mfaService) Reset(ctx context.Context, username string) error {
+	ctx, span := tracer.Start(ctx, "MfaService.Reset")
+	defer span.End()
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditMfaReset, user.Username)
+	return nil
+}
+
+// Login counts a wrong code as a failed login, so codes cannot be guessed
+// faster than passwords.
+func (s *mfaService) Login(ctx context.Context, challenge string, code string) (string, error) {
+	ctx, span := tracer.Start(ctx, "MfaService.Login")
+	defer span.End()
+
+	ok, username := s.auth.ValidateMfaChallenge(challenge)
+	if !ok {
+		return "", ErrMfaChallengeInvalid
+	}
+
+	address := auth.ClientAddress(ctx)
+	if err := s.throttle.Check(ctx, username, address); err != nil {
+		logging.FromContext(ctx).Info("login failed", "username", username, "reason", metrics.LoginThrottled)
+		metrics.LoginFailures.WithLabelValues(metrics.LoginThrottled).Inc()
+		return "", err
+	}
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return "", err
+	}
+	mfa, err := s.mfaRepo.Find(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if user.Disabled || mfa == nil || !mfa.Enabled {
+		return "", ErrMfaChallengeInvalid
+	}
+
+	accepted, err := s.verify(ctx, user, mfa, code)
+	if err != nil {
+		return "", err
+	}
+	if !accepted {
+		logging.FromContext(ctx).Info("login failed", "username", username, "reason", metrics.LoginWrongCode)
+		metrics.LoginFailures.WithLabelValues(metrics.LoginWrongCode).Inc()
+		if err := s.throttle.Failed(ctx, username, address); err != nil {
+			return "", err
+		}
+		return "", InvalidMfaCodeError
+	}
+
+	if err := s.throttle.Succeeded(ctx, username); err != nil {
+		return "", err
+	}
+	return s.auth.GenerateJwtToken(user.Username)
+}
+
+func (s *mfaService) find(ctx context.Context, username string) (*model.User, *model.Mfa, error) {
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	mfa, err := s.mfaRepo.Find(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mfa == nil {
+		return nil, nil, ErrMfaNotEnrolled
+	}
+	return user, mfa, nil
+}
+
+// verifyEnabled checks code before a change of an enabled second factor, a
+// wrong code counts as a failed login.
+func (s *mfaService) verifyEnabled(ctx context.Context, username string, code string) (*model.User, error) {
+	user, mfa, err := s.find(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled {
+		return nil, ErrMfaNotEnrolled
+	}
+
+	address := auth.ClientAddress(ctx)
+	if err := s.throttle.Check(ctx, username, address); err != nil {
+		return nil, err
+	}
+	accepted, err := s.verify(ctx, user, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		if err := s.throttle.Failed(ctx, username, address); err != nil {
+			return nil, err
+		}
+		return nil, countValidation(validation.ErrMfaCodeIsInvalid)
+	}
+	return user, nil
+}
+
+// verify accepts a current code that was not used yet, or else a recovery
+// code, which is used up.
+func (s *mfaService) verify(ctx context.Context, user *model.User, mfa *model.Mfa, code string) (bool, error) {
+	if step, ok := auth.MatchTotp(mfa.Secret, code, s.now()); ok {
+		return s.mfaRepo.UseStep(ctx, user.ID, step)
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if used {
+		s.record(ctx, model.AuditMfaRecoveryCodeUsed, user.Username)
+	}
+	return used, err
+}
+
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, userID int) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaService) record(ctx context.Context, eventType string, username string) {
+	recordAudit(ctx, s.audit, &model.AuditEvent{Type: eventType, Username: username, Address: auth.ClientAddress(ctx), OccurredAt: s.now().UTC()})
+}
+
+// newRecoveryCode returns ten random characters, e.g. "k3x7q-m2p9a".
+func newRecoveryCode() (string, error) {
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes. Recovery codes are
+// random enough for a plain SHA-256 to protect them.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
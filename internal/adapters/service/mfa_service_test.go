@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func newMfaRepository() *mockMfaRepository {
+	return &mockMfaRepository{mfas: map[int]*model.Mfa{}, recoveryCodes: map[int][]string{}}
+}
+
+type mfaFixture struct {
+	userRepo    *mockUserRepository
+	userService *userService
+	mfaService  *mfaService
+	throttle    *LoginThrottle
+	clock       *clock
+	audit       *mockAuditRepository
+}
+
+func newMfaFixture(t *testing.T) *mfaFixture {
+	password, err := hashPassword("password123")
+	require.NoError(t, err)
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Password: password, Role: model.RoleAdmin},
+		},
+	}
+
+	f := &mfaFixture{userRepo: userRepo, clock: &clock{now: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}, audit: &mockAuditRepository{}}
+	mfaRepo := newMfaRepository()
+	authService := auth.NewAuthService("secret-key")
+	f.throttle = NewLoginThrottle(&mockLoginAttemptRepository{attempts: map[string]*model.LoginAttempts{}}, f.audit)
+	f.throttle.now = f.clock.Now
+	f.userService = NewUserService(userRepo, mfaRepo, &mockAccountService{}, authService, f.throttle)
+	f.mfaService = NewMfaService(userRepo, mfaRepo, f.audit, authService, f.throttle, "videos-api")
+	f.mfaService.now = f.clock.Now
+	return f
+}
+
+// enable enrolls johndoe and returns the secret and the recovery codes.
+func (f *mfaFixture) enable(t *testing.T) (string, []string) {
+	enrollment, err := f.mfaService.Enroll(context.Background(), "johndoe")
+	require.NoError(t, err)
+	recoveryCodes, err := f.mfaService.Confirm(context.Background(), "johndoe", f.code(t, enrollment.Secret))
+	require.NoError(t, err)
+	f.clock.Advance(30 * time.Second)
+	return enrollment.Secret, recoveryCodes
+}
+
+func (f *mfaFixture) code(t *testing.T, secret string) string {
+	code, err := totp.GenerateCode(secret, f.clock.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// challenge logs johndoe in with the password and returns the challenge.
+func (f *mfaFixture) challenge(t *testing.T) string {
+	_, err := f.userService.Login(context.Background(), "johndoe", "password123")
+	var required *MfaRequiredError
+	require.ErrorAs(t, err, &required)
+	return required.Challenge
+}
+
+func TestMfaService_Enroll(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+
+	// test
+	enrollment, err := f.mfaService.Enroll(context.Background(), "johndoe")
+
+	// assertions
+	require.NoError(t, err)
+	require.NotEmpty(t, enrollment.Secret)
+	require.Contains(t, enrollment.URI, "otpauth://totp/videos-api:johndoe?")
+	require.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	require.Equal(t, []byte("\x89PNG"), enrollment.QRCode[:4])
+
+	token, err := f.userService.Login(context.Background(), "johndoe", "password123")
+	require.NoError(t, err, "an unconfirmed second factor is not asked for")
+	require.NotEmpty(t, token)
+}
+
+func TestMfaService_Confirm_UnhappyPath_WrongCode(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	_, err := f.mfaService.Enroll(context.Background(), "johndoe")
+	require.NoError(t, err)
+
+	// test
+	codes, err := f.mfaService.Confirm(context.Background(), "johndoe", "000000")
+
+	// assertions
+	require.ErrorIs(t, err, validation.ErrMfaCodeIsInvalid)
+	require.Nil(t, codes)
+}
+
+func TestMfaService_Login_HappyPath(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, recoveryCodes := f.enable(t)
+	code := f.code(t, secret)
+
+	// test
+	token, err := f.mfaService.Login(context.Background(), f.challenge(t), code)
+	_, replayErr := f.mfaService.Login(context.Background(), f.challenge(t), code)
+
+	// assertions
+	require.NoError(t, err)
+	valid, username := auth.NewAuthService("secret-key").ValidateJwtToken(token)
+	require.True(t, valid)
+	require.Equal(t, "johndoe", username)
+	require.ErrorIs(t, replayErr, InvalidMfaCodeError)
+	require.Len(t, recoveryCodes, recoveryCodeCount)
+	require.Equal(t, []string{""}, f.audit.keys(model.AuditMfaEnabled))
+}
+
+func TestMfaService_Login_RecoveryCode(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	_, recoveryCodes := f.enable(t)
+
+	// test
+	token, err := f.mfaService.Login(context.Background(), f.challenge(t), recoveryCodes[0])
+	f.clock.Advance(time.Minute)
+	_, reusedErr := f.mfaService.Login(context.Background(), f.challenge(t), recoveryCodes[0])
+
+	// assertions
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.ErrorIs(t, reusedErr, InvalidMfaCodeError)
+	require.Len(t, f.audit.keys(model.AuditMfaRecoveryCodeUsed), 1)
+}
+
+func TestMfaService_Login_UnhappyPath_InvalidChallenge(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, _ := f.enable(t)
+	session, err := auth.NewAuthService("secret-key").GenerateJwtToken("johndoe")
+	require.NoError(t, err)
+
+	// test
+	_, err = f.mfaService.Login(context.Background(), session, f.code(t, secret))
+
+	// assertions
+	require.ErrorIs(t, err, ErrMfaChallengeInvalid)
+}
+
+func TestMfaService_Login_WrongCodesAreThrottled(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, _ := f.enable(t)
+	challenge := f.challenge(t)
+	for range f.throttle.MaxFailures {
+		_, err := f.mfaService.Login(context.Background(), challenge, "000000")
+		require.ErrorIs(t, err, InvalidMfaCodeError)
+		f.clock.Advance(time.Minute)
+	}
+
+	// test
+	_, err := f.mfaService.Login(context.Background(), challenge, f.code(t, secret))
+
+	// assertions
+	require.ErrorIs(t, err, ErrLoginThrottled)
+}
+
+func TestMfaService_Disable(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, _ := f.enable(t)
+
+	// test
+	wrongErr := f.mfaService.Disable(context.Background(), "johndoe", "000000")
+	f.clock.Advance(time.Minute)
+	err := f.mfaService.Disable(context.Background(), "johndoe", f.code(t, secret))
+
+	// assertions
+	require.ErrorIs(t, wrongErr, validation.ErrMfaCodeIsInvalid)
+	require.NoError(t, err)
+	token, err := f.userService.Login(context.Background(), "johndoe", "password123")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Len(t, f.audit.keys(model.AuditMfaDisabled), 1)
+}
+
+func TestMfaService_RegenerateRecoveryCodes(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, oldCodes := f.enable(t)
+
+	// test
+	newCodes, err := f.mfaService.RegenerateRecoveryCodes(context.Background(), "johndoe", f.code(t, secret))
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, newCodes, recoveryCodeCount)
+	require.False(t, slices.Contains(newCodes, oldCodes[0]))
+	_, oldErr := f.mfaService.Login(context.Background(), f.challenge(t), oldCodes[0])
+	require.ErrorIs(t, oldErr, InvalidMfaCodeError)
+	f.clock.Advance(time.Minute)
+	token, err := f.mfaService.Login(context.Background(), f.challenge(t), newCodes[0])
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
+func TestMfaService_Enroll_UnhappyPath_AlreadyEnabled(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	f.enable(t)
+
+	// test
+	_, err := f.mfaService.Enroll(context.Background(), "johndoe")
+
+	// assertions
+	require.ErrorIs(t, err, ErrMfaAlreadyEnabled)
+}
+
+func TestMfaService_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	secret, _ := f.enable(t)
+	challenge := f.challenge(t)
+	f.userRepo.users["johndoe"].Disabled = true
+
+	// test
+	_, enrollErr := f.mfaService.Enroll(context.Background(), "johndoe")
+	disableErr := f.mfaService.Disable(context.Background(), "johndoe", f.code(t, secret))
+	_, loginErr := f.mfaService.Login(context.Background(), challenge, f.code(t, secret))
+
+	// assertions
+	require.ErrorIs(t, enrollErr, ErrUserDisabled)
+	require.ErrorIs(t, disableErr, ErrUserDisabled)
+	require.ErrorIs(t, loginErr, ErrUserDisabled)
+}
+
+func TestMfaService_Reset(t *testing.T) {
+	// fixture
+	f := newMfaFixture(t)
+	f.enable(t)
+
+	// test
+	err := f.mfaService.Reset(context.Background(), "johndoe")
+
+	// assertions
+	require.NoError(t, err)
+	token, err := f.userService.Login(context.Background(), "johndoe", "password123")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
+func TestHashRecoveryCode_IgnoresCaseAndDashes(t *testing.T) {
+	// test
+	hashed := hashRecoveryCode("K3X7Q M2P9A")
+
+	// assertions
+	require.Equal(t, hashRecoveryCode("k3x7q-m2p9a"), hashed)
+	require.NotEqual(t, hashRecoveryCode("k3x7q-m2p9b"), hashed)
+}
+
+type mockMfaRepository struct {
+	mfas          map[int]*model.Mfa
+	recoveryCodes map[int][]string
+}
+
+func (r *mockMfaRepository) Find(ctx context.Context, userID int) (*model.Mfa, error) {
+	mfa, ok := r.mfas[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *mfa
+	return &copied, nil
+}
+
+func (r *mockMfaRepository) Save(ctx context.Context, mfa *model.Mfa) error {
+	copied := *mfa
+	r.mfas[mfa.UserID] = &copied
+	return nil
+}
+
+func (r *mockMfaRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	mfa, ok := r.mfas[userID]
+	if !ok || mfa.LastStep >= step {
+		return false, nil
+	}
+	mfa.LastStep = step
+	return true, nil
+}
+
+func (r *mockMfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error {
+	r.recoveryCodes[userID] = slices.Clone(hashes)
+	return nil
+}
+
+func (r *mockMfaRepository) UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error) {
+	index := slices.Index(r.recoveryCodes[userID], hash)
+	if index < 0 {
+		return false, nil
+	}
+	r.recoveryCodes[userID] = slices.Delete(r.recoveryCodes[userID], index, index+1)
+	return true, nil
+}
+
+func (r *mockMfaRepository) Delete(ctx context.Context, userID int) error {
+	delete(r.mfas, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
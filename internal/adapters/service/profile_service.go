@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var (
+	ErrWrongPassword = errors.New("the current password is wrong")
+	ErrNoPassword    = errors.New("the account signs in with single sign-on and has no password")
+)
+
+type profileService struct {
+	userRepo      ports.UserRepository
+	workspaceRepo ports.WorkspaceRepository
+	audit         ports.AuditRepository
+	transactor    ports.Transactor
+	auth          auth.AuthService
+	accounts      ports.AccountService
+	mailer        ports.Mailer
+	throttle      *LoginThrottle
+	now           func() time.Time
+}
+
+func NewProfileService(userRepo ports.UserRepository, workspaceRepo ports.WorkspaceRepository, audit ports.AuditRepository,
+	transactor ports.Transactor, auth auth.AuthService, accounts ports.AccountService, mailer ports.Mailer,
+	throttle *LoginThrottle) *profileService {
+	return &profileService{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		audit:         audit,
+		transactor:    transactor,
+		auth:          auth,
+		accounts:      accounts,
+		mailer:        mailer,
+		throttle:      throttle,
+		now:           time.Now,
+	}
+}
+
+func (s *profileService) Find(ctx context.Context, username string) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "ProfileService.Find")
+	defer span.End()
+
+	return loadUser(ctx, s.userRepo, username)
+}
+
+func (s *profileService) Rename(ctx context.Context, username string, newUsername string) (string, error) {
+	ctx, span := tracer.Start(ctx, "ProfileService.Rename")
+	defer span.End()
+
+	if err := validation.ValidateUsername(newUsername); err != nil {
+		return "", countValidation(err)
+	}
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return "", err
+	}
+
+	if newUsername != user.Username {
+		taken, err := s.userRepo.UsernameTaken(ctx, newUsername, user.ID, s.now().UTC())
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", repository.UsernameTakenError
+		}
+		if err := s.userRepo.Rename(ctx, user, newUsername, s.retiredUntil()); err != nil {
+			return "", err
+		}
+		s.record(ctx, model.AuditUserRenamed, user.Username, "was "+username)
+	}
+	return s.auth.GenerateJwtToken(user.Username)
+}
+
+func (s *profileService) ChangePassword(ctx context.Context, username string, current string, password string) error {
+	ctx, span := tracer.Start(ctx, "ProfileService.ChangePassword")
+	defer span.End()
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return countValidation(err)
+	}
+	user, err := s.verify(ctx, username, current)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditPasswordChanged, user.Username, "")
+	return nil
+}
+
+// ChangeEmail also tells the previous address, so its owner notices a
+// change they did not make.
+func (s *profileService) ChangeEmail(ctx context.Context, username string, password string, email string) error {
+	ctx, span := tracer.Start(ctx, "ProfileService.ChangeEmail")
+	defer span.End()
+
+	if err := validation.ValidateEmail(email); err != nil {
+		return countValidation(err)
+	}
+	user, err := s.verify(ctx, username, password)
+	if err != nil {
+		return err
+	}
+	if email == user.Email {
+		return nil
+	}
+
+	previous := user.Email
+	user.Email = email
+	user.EmailVerified = false
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditEmailChanged, user.Username, "")
+
+	logger := logging.FromContext(ctx)
+	if err := s.accounts.SendVerification(ctx, user.Username); err != nil {
+		logger.Error("sending email verification failed", "username", user.Username, "error", err)
+	}
+	err = s.mailer.Send(ctx, &model.Mail{
+		To:      previous,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("Hello %s,\n\nthe email address of your account was changed to %s.\n\n"+
+			"If you did not change it, reset your password and contact an administrator.\n", user.Username, email),
+	})
+	if err != nil {
+		logger.Error("sending email change notice failed", "username", user.Username, "error", err)
+	}
+	return nil
+}
+
+// Delete runs in one transaction, the account is never gone with some of
+// its videos left behind. Only the videos in the workspaces the user is still
+// a member of go with it, the ones left in a workspace the user was removed
+// from belong to that workspace.
+func (s *profileService) Delete(ctx context.Context, username string, password string, videos string, transferTo string) error {
+	ctx, span := tracer.Start(ctx, "ProfileService.Delete")
+	defer span.End()
+
+	if videos != model.VideosTransfer && videos != model.VideosDelete {
+		return countValidation(validation.ErrVideosIsInvalid)
+	}
+	user, err := s.verify(ctx, username, password)
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := s.workspaceRepo.FindByUserId(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	owner := &scope{user: user, workspaces: workspaces}
+
+	var target *scope
+	if videos == model.VideosTransfer {
+		if target, err = s.transferTarget(ctx, user, transferTo); err != nil {
+			return err
+		}
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context, tx *ports.Tx) error {
+		owned, err := tx.Videos.FindByUserId(ctx, owner.ids(model.RoleViewer), user.ID)
+		if err != nil {
+			return err
+		}
+		for _, video := range owned {
+			if target == nil {
+				err = removeVideo(ctx, tx, video.WorkspaceID, video.ID, video.Version)
+			} else {
+				err = transferVideo(ctx, tx, video, target)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Users.Delete(ctx, user, s.retiredUntil())
+	})
+	if err != nil {
+		return err
+	}
+
+	detail := "videos deleted"
+	if target != nil {
+		detail = "videos transferred to " + target.user.Username
+	}
+	s.record(ctx, model.AuditUserDeleted, user.Username, detail)
+	return nil
+}
+
+// transferTarget finds the user taking over the videos, one who can still
+// edit them.
+func (s *profileService) transferTarget(ctx context.Context, user *model.User, transferTo string) (*scope, error) {
+	target, err := loadScope(ctx, s.userRepo, s.workspaceRepo, transferTo)
+	if errors.Is(err, repository.UserNotFoundError) {
+		return nil, countValidation(validation.ErrTransferToIsInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.user.ID == user.ID || target.user.Disabled || target.user.Role == model.RoleViewer ||
+		len(target.ids(model.RoleEditor)) == 0 {
+		return nil, countValidation(validation.ErrTransferToIsInvalid)
+	}
+	return target, nil
+}
+
+// transferVideo keeps the video in its workspace when the target can edit it
+// there, otherwise it moves to the first workspace the target can edit.
+func transferVideo(ctx context.Context, tx *ports.Tx, video *model.Video, target *scope) error {
+	userId, workspaceId := target.user.ID, video.WorkspaceID
+	if !target.allows(workspaceId, model.RoleEditor) {
+		workspaceId = target.ids(model.RoleEditor)[0]
+	}
+
+	if err := tx.Videos.Transfer(ctx, []int{video.WorkspaceID}, video.ID, video.Version, userId, workspaceId); err != nil {
+		return err
+	}
+	if err := tx.Annotations.Transfer(ctx, video.ID, userId); err != nil {
+		return err
+	}
+	video.UserID, video.WorkspaceID, video.Version = userId, workspaceId, video.Version+1
+	return appendEvents(ctx, tx.Outbox, videoEvent(model.EventVideoUpdated, video))
+}
+
+// verify checks the password before a sensitive change, a wrong one counts
+// as a failed login so a stolen session cannot guess it.
+func (s *profileService) verify(ctx context.Context, username string, password string) (*model.User, error) {
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Password == "" {
+		return nil, ErrNoPassword
+	}
+
+	address := auth.ClientAddress(ctx)
+	if err := s.throttle.Check(ctx, username, address); err != nil {
+		return nil, err
+	}
+	if err := comparePassword(user.Password, password); err != nil {
+		if err := s.throttle.Failed(ctx, username, address); err != nil {
+			return nil, err
+		}
+		return nil, ErrWrongPassword
+	}
+	return user, nil
+}
+
+// retiredUntil keeps a released username from others while the session
+// tokens issued to it still work.
+func (s *profileService) retiredUntil() time.Time {
+	return s.now().Add(auth.SessionTTL).UTC()
+}
+
+func (s *profileService) record(ctx context.Context, eventType string, username string, detail string) {
+	recordAudit(ctx, s.audit, &model.AuditEvent{Type: eventType, Username: username, Address: auth.ClientAddress(ctx), Detail: detail, OccurredAt: s.now().UTC()})
+}
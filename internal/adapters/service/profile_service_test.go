@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type profileFixture struct {
+	profiles      *profileService
+	userRepo      *mockUserRepository
+	workspaceRepo *mockWorkspaceRepository
+	videos        *mockVideoRepository
+	annotations   *mockAnnotationRepository
+	transactor    *mockTransactor
+	accounts      *mockAccountService
+	mailer        *mockMailer
+	audit         *mockAuditRepository
+	auth          auth.AuthService
+}
+
+func newProfileFixture(t *testing.T) *profileFixture {
+	password, err := hashPassword("password123")
+	require.NoError(t, err)
+	f := &profileFixture{
+		userRepo: &mockUserRepository{
+			users: map[string]*model.User{
+				"johndoe": {ID: 1, Username: "johndoe", Email: "johndoe@example.com", Password: password, Role: model.RoleEditor, EmailVerified: true},
+				"janedoe": {ID: 2, Username: "janedoe", Email: "janedoe@example.com", Password: password, Role: model.RoleEditor},
+				"viewer":  {ID: 3, Username: "viewer", Email: "viewer@example.com", Password: password, Role: model.RoleViewer},
+				"ssouser": {ID: 4, Username: "ssouser", Email: "ssouser@example.com", Role: model.RoleEditor},
+			},
+		},
+		videos:      newMockVideoRepository(),
+		annotations: newMockAnnotationRepository(),
+		accounts:    &mockAccountService{},
+		mailer:      &mockMailer{},
+		audit:       &mockAuditRepository{},
+		auth:        auth.NewAuthService("secret-key"),
+	}
+	f.transactor = newMockTransactor(f.videos, f.annotations)
+	f.transactor.users = f.userRepo
+	f.workspaceRepo = newMockWorkspaceRepository()
+	for id := 2; id <= 4; id++ {
+		f.workspaceRepo.join(id, id, model.RoleAdmin)
+	}
+	f.profiles = NewProfileService(f.userRepo, f.workspaceRepo, f.audit, f.transactor, f.auth, f.accounts, f.mailer, newLoginThrottle())
+	return f
+}
+
+func TestProfileService_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+	f.userRepo.users["johndoe"].Disabled = true
+
+	// test
+	_, findErr := f.profiles.Find(context.Background(), "johndoe")
+	changeErr := f.profiles.ChangePassword(context.Background(), "johndoe", "password123", "password456")
+
+	// assertions
+	require.ErrorIs(t, findErr, ErrUserDisabled)
+	require.ErrorIs(t, changeErr, ErrUserDisabled)
+}
+
+func TestProfileService_Rename_HappyPath(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	token, err := f.profiles.Rename(context.Background(), "johndoe", "john.doe")
+
+	// assertions
+	require.NoError(t, err)
+	ok, username := f.auth.ValidateJwtToken(token)
+	require.True(t, ok)
+	require.Equal(t, "john.doe", username)
+	require.Equal(t, 1, f.userRepo.users["john.doe"].ID)
+
+	taken, err := f.userRepo.UsernameTaken(context.Background(), "johndoe", 2, time.Now())
+	require.NoError(t, err)
+	require.True(t, taken)
+	require.Len(t, f.audit.keys(model.AuditUserRenamed), 1)
+}
+
+func TestProfileService_Rename_UnhappyPath_Taken(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+	f.userRepo.retired = map[string]int{"jdoe": 2}
+
+	// test
+	_, takenErr := f.profiles.Rename(context.Background(), "johndoe", "janedoe")
+	_, retiredErr := f.profiles.Rename(context.Background(), "johndoe", "jdoe")
+	_, invalidErr := f.profiles.Rename(context.Background(), "johndoe", "john doe")
+
+	// assertions
+	require.ErrorIs(t, takenErr, repository.UsernameTakenError)
+	require.ErrorIs(t, retiredErr, repository.UsernameTakenError)
+	require.ErrorIs(t, invalidErr, validation.ErrNameIsInvalid)
+	require.Contains(t, f.userRepo.users, "johndoe")
+}
+
+func TestProfileService_ChangePassword_HappyPath(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	err := f.profiles.ChangePassword(context.Background(), "johndoe", "password123", "new-password")
+
+	// assertions
+	require.NoError(t, err)
+	require.NoError(t, comparePassword(f.userRepo.users["johndoe"].Password, "new-password"))
+	require.Len(t, f.audit.keys(model.AuditPasswordChanged), 1)
+}
+
+func TestProfileService_ChangePassword_UnhappyPath_WrongPassword(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	err := f.profiles.ChangePassword(context.Background(), "johndoe", "wrong-password", "new-password")
+	ssoErr := f.profiles.ChangePassword(context.Background(), "ssouser", "", "new-password")
+
+	// assertions
+	require.ErrorIs(t, err, ErrWrongPassword)
+	require.ErrorIs(t, ssoErr, ErrNoPassword)
+	require.NoError(t, comparePassword(f.userRepo.users["johndoe"].Password, "password123"))
+	require.Empty(t, f.userRepo.users["ssouser"].Password)
+}
+
+func TestProfileService_ChangeEmail_HappyPath(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	err := f.profiles.ChangeEmail(context.Background(), "johndoe", "password123", "john@example.org")
+
+	// assertions
+	require.NoError(t, err)
+	user := f.userRepo.users["johndoe"]
+	require.Equal(t, "john@example.org", user.Email)
+	require.False(t, user.EmailVerified)
+	require.Equal(t, []string{"johndoe"}, f.accounts.verifications)
+	require.Equal(t, "johndoe@example.com", f.mailer.sent[0].To)
+	require.Contains(t, f.mailer.sent[0].Body, "john@example.org")
+}
+
+func TestProfileService_Delete_TransferVideos(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	err := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosTransfer, "janedoe")
+
+	// assertions
+	require.NoError(t, err)
+	require.NotContains(t, f.userRepo.users, "johndoe")
+	require.Equal(t, 2, f.videos.videos[1].UserID)
+	require.Equal(t, 2, f.videos.videos[1].Version)
+	require.Equal(t, 2, f.videos.videos[1].WorkspaceID)
+	require.Equal(t, 2, f.annotations.annotations[1].UserID)
+	require.Equal(t, []string{model.EventVideoUpdated}, f.transactor.outbox.types())
+	require.Equal(t, 2, f.transactor.outbox.events[0].WorkspaceID)
+	require.Equal(t, 1, f.userRepo.retired["johndoe"])
+	require.Len(t, f.audit.keys(model.AuditUserDeleted), 1)
+}
+
+func TestProfileService_Delete_TransferVideos_KeepsSharedWorkspace(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+	f.workspaceRepo.join(1, 2, model.RoleEditor)
+
+	// test
+	err := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosTransfer, "janedoe")
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, 2, f.videos.videos[1].UserID)
+	require.Equal(t, 1, f.videos.videos[1].WorkspaceID)
+}
+
+func TestProfileService_Delete_DeleteVideos(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	err := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosDelete, "")
+
+	// assertions
+	require.NoError(t, err)
+	require.NotContains(t, f.userRepo.users, "johndoe")
+	require.Empty(t, f.videos.videos)
+	require.Empty(t, f.annotations.annotations)
+	require.Equal(t, []string{model.EventAnnotationDeleted, model.EventVideoDeleted}, f.transactor.outbox.types())
+}
+
+func TestProfileService_Delete_UnhappyPath_InvalidTransfer(t *testing.T) {
+	// fixture
+	f := newProfileFixture(t)
+
+	// test
+	viewerErr := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosTransfer, "viewer")
+	selfErr := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosTransfer, "johndoe")
+	unknownErr := f.profiles.Delete(context.Background(), "johndoe", "password123", model.VideosTransfer, "nobody")
+	choiceErr := f.profiles.Delete(context.Background(), "johndoe", "password123", "keep", "")
+	passwordErr := f.profiles.Delete(context.Background(), "johndoe", "wrong-password", model.VideosDelete, "")
+
+	// assertions
+	require.ErrorIs(t, viewerErr, validation.ErrTransferToIsInvalid)
+	require.ErrorIs(t, selfErr, validation.ErrTransferToIsInvalid)
+	require.ErrorIs(t, unknownErr, validation.ErrTransferToIsInvalid)
+	require.ErrorIs(t, choiceErr, validation.ErrVideosIsInvalid)
+	require.ErrorIs(t, passwordErr, ErrWrongPassword)
+	require.Contains(t, f.userRepo.users, "johndoe")
+	require.Len(t, f.videos.videos, 1)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+var (
+	ErrNotAllowed   = fmt.Errorf("your role in the workspace does not allow it")
+	ErrUserDisabled = fmt.Errorf("the user is disabled")
+)
+
+// scope holds the workspaces of a user with its role in each of them, videos
+// outside of them are reported as not found.
+type scope struct {
+	user       *model.User
+	workspaces []*model.Workspace
+}
+
+func loadScope(ctx context.Context, userRepo ports.UserRepository, workspaceRepo ports.WorkspaceRepository, username string) (*scope, error) {
+	user, err := loadUser(ctx, userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := workspaceRepo.FindByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &scope{user: user, workspaces: workspaces}, nil
+}
+
+// loadUser finds the user acting on a request. The tokens of a disabled user
+// stay valid until they expire, so every request checks the flag.
+func loadUser(ctx context.Context, userRepo ports.UserRepository, username string) (*model.User, error) {
+	user, err := userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	return user, nil
+}
+
+// ids lists the workspaces where the role of the user allows role.
+func (s *scope) ids(role string) []int {
+	ids := []int{}
+	for _, workspace := range s.workspaces {
+		if model.RoleAllows(workspace.Role, role) {
+			ids = append(ids, workspace.ID)
+		}
+	}
+	return ids
+}
+
+// role is empty when the user is not a member of the workspace.
+func (s *scope) role(workspaceId int) string {
+	for _, workspace := range s.workspaces {
+		if workspace.ID == workspaceId {
+			return workspace.Role
+		}
+	}
+	return ""
+}
+
+func (s *scope) allows(workspaceId int, role string) bool {
+	return model.RoleAllows(s.role(workspaceId), role)
+}
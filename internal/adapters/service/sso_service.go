@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+)
+
+const (
+	// SsoLoginTTL is how long users have to log in at the identity provider
+	SsoLoginTTL = 10 * time.Minute
+)
+
+var (
+	ErrSsoNotConfigured = errors.New("single sign-on is not configured")
+	ErrSsoLoginInvalid  = errors.New("login is invalid, expired or was already finished")
+	ErrSsoRejected      = errors.New("the identity provider did not confirm the login")
+	ErrSsoNoRole        = errors.New("no role is mapped to the groups of the user")
+)
+
+// SsoSettings map the values of the role claim to roles, users no value maps
+// get DefaultRole or are refused when it is empty.
+type SsoSettings struct {
+	RoleMapping map[string]string
+	DefaultRole string
+}
+
+// ParseRoleMapping reads comma separated value=role pairs.
+func ParseRoleMapping(mapping string) (map[string]string, error) {
+	roles := map[string]string{}
+	for pair := range strings.SplitSeq(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		value, role, ok := strings.Cut(pair, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("role mapping %q is not value=role", pair)
+		}
+		if err := validation.ValidateRole(role); err != nil {
+			return nil, fmt.Errorf("role mapping %q: %w", pair, err)
+		}
+		roles[value] = role
+	}
+	return roles, nil
+}
+
+type ssoService struct {
+	userRepo ports.UserRepository
+	oidcRepo ports.OidcRepository
+	audit    ports.AuditRepository
+	auth     auth.AuthService
+	provider ports.IdentityProvider
+	settings SsoSettings
+	now      func() time.Time
+}
+
+// NewSsoService takes a nil provider when single sign-on is not configured.
+func NewSsoService(userRepo ports.UserRepository, oidcRepo ports.OidcRepository, audit ports.AuditRepository,
+	auth auth.AuthService, provider ports.IdentityProvider, settings SsoSettings) *ssoService {
+	return &ssoService{
+		userRepo: userRepo,
+		oidcRepo: oidcRepo,
+		audit:    audit,
+		auth:     auth,
+		provider: provider,
+		settings: settings,
+		now:      time.Now,
+	}
+}
+
+func (s *ssoService) Begin(ctx context.Context) (string, string, error) {
+	ctx, span := tracer.Start(ctx, "SsoService.Begin")
+	defer span.End()
+
+	if s.provider == nil {
+		return "", "", ErrSsoNotConfigured
+	}
+
+	login := &model.OidcLogin{ExpiresAt: s.now().Add(SsoLoginTTL).UTC()}
+	for _, value := range []*string{&login.State, &login.CodeVerifier, &login.Nonce} {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return "", "", err
+		}
+		*value = base64.RawURLEncoding.EncodeToString(random)
+	}
+
+	url, err := s.provider.AuthCodeURL(ctx, login.State, login.Nonce, login.CodeVerifier)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.oidcRepo.SaveLogin(ctx, login); err != nil {
+		return "", "", err
+	}
+	return login.State, url, nil
+}
+
+// Finish provisions users on their first login and sets their role from the
+// role claim on every login, the identity provider decides who is who.
+func (s *ssoService) Finish(ctx context.Context, state string, code string) (string, error) {
+	ctx, span := tracer.Start(ctx, "SsoService.Finish")
+	defer span.End()
+
+	if s.provider == nil {
+		return "", ErrSsoNotConfigured
+	}
+
+	login, err := s.oidcRepo.TakeLogin(ctx, state, s.now().UTC())
+	if err != nil {
+		return "", err
+	}
+	if login == nil {
+		return "", ErrSsoLoginInvalid
+	}
+
+	identity, err := s.provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		logging.FromContext(ctx).Warn("single sign-on failed", "reason", metrics.LoginSsoRejected, "error", err)
+		metrics.LoginFailures.WithLabelValues(metrics.LoginSsoRejected).Inc()
+		return "", ErrSsoRejected
+	}
+
+	role, ok := s.role(identity.Groups)
+	if !ok {
+		s.rejected(ctx, identity.Email, metrics.LoginNoRole)
+		return "", ErrSsoNoRole
+	}
+
+	user, err := s.user(ctx, identity, role)
+	if err != nil {
+		return "", err
+	}
+	if user.Disabled {
+		s.rejected(ctx, user.Username, metrics.LoginDisabledUser)
+		return "", ErrSsoRejected
+	}
+
+	s.record(ctx, model.AuditLoginSso, user.Username, identity.Issuer)
+	return s.auth.GenerateJwtToken(user.Username)
+}
+
+// role returns the most allowed role mapped to one of values.
+func (s *ssoService) role(values []string) (string, bool) {
+	best := -1
+	for _, value := range values {
+		if role, ok := s.settings.RoleMapping[value]; ok {
+			best = max(best, slices.Index(model.Roles, role))
+		}
+	}
+	if best >= 0 {
+		return model.Roles[best], true
+	}
+	return s.settings.DefaultRole, s.settings.DefaultRole != ""
+}
+
+// user finds the user linked to identity, links the user with its verified
+// email or provisions a new one. Provisioned users have no password.
+func (s *ssoService) user(ctx context.Context, identity *model.ExternalIdentity, role string) (*model.User, error) {
+	user, err := s.oidcRepo.FindUser(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return s.sync(ctx, user, role)
+	}
+	if !errors.Is(err, repository.UserNotFoundError) {
+		return nil, err
+	}
+
+	if identity.Email == "" {
+		s.rejected(ctx, identity.Subject, metrics.LoginSsoRejected)
+		return nil, ErrSsoRejected
+	}
+	user, err = s.userRepo.FindByEmail(ctx, identity.Email)
+	if err == nil {
+		// an unverified email could be anyone's, the account is not handed over
+		if !identity.EmailVerified {
+			s.rejected(ctx, user.Username, metrics.LoginSsoRejected)
+			return nil, ErrSsoRejected
+		}
+		if err := s.oidcRepo.Link(ctx, identity.Issuer, identity.Subject, user.ID); err != nil {
+			return nil, err
+		}
+		return s.sync(ctx, user, role)
+	}
+	if !errors.Is(err, repository.UserNotFoundError) {
+		return nil, err
+	}
+
+	username, err := s.username(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	user = &model.User{
+		Username:      username,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Role:          role,
+		CreatedAt:     s.now(),
+	}
+	if err := s.oidcRepo.Provision(ctx, user, identity.Issuer, identity.Subject); err != nil {
+		return nil, err
+	}
+	s.record(ctx, model.AuditUserProvisioned, user.Username, identity.Issuer)
+	return user, nil
+}
+
+func (s *ssoService) sync(ctx context.Context, user *model.User, role string) (*model.User, error) {
+	if user.Role == role {
+		return user, nil
+	}
+	user.Role = role
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// username takes the preferred username, or the start of the email, and
+// numbers it when it is taken.
+func (s *ssoService) username(ctx context.Context, identity *model.ExternalIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if validation.ValidateUsername(base) != nil {
+		var err error
+		if base, err = extractUserName(identity.Email); err != nil {
+			return "", err
+		}
+	}
+	return freeUsername(ctx, s.userRepo, base, s.now())
+}
+
+func (s *ssoService) rejected(ctx context.Context, username string, reason string) {
+	metrics.LoginFailures.WithLabelValues(reason).Inc()
+	s.record(ctx, model.AuditLoginRejected, username, "single sign-on: "+reason)
+}
+
+func (s *ssoService) record(ctx context.Context, eventType string, username string, detail string) {
+	recordAudit(ctx, s.audit, &model.AuditEvent{Type: eventType, Username: username, Address: auth.ClientAddress(ctx), Detail: detail, OccurredAt: s.now().UTC()})
+}
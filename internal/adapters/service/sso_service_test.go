@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+const testIssuer = "https://idp.example.com"
+
+type ssoFixture struct {
+	sso      *ssoService
+	userRepo *mockUserRepository
+	oidcRepo *mockOidcRepository
+	provider *mockIdentityProvider
+	clock    *clock
+	audit    *mockAuditRepository
+}
+
+func newSsoFixture() *ssoFixture {
+	f := &ssoFixture{
+		userRepo: &mockUserRepository{
+			users: map[string]*model.User{
+				"johndoe": {ID: 1, Username: "johndoe", Email: "johndoe@example.com", Password: "hash", Role: model.RoleViewer},
+			},
+		},
+		provider: &mockIdentityProvider{},
+		clock:    &clock{now: time.Now()},
+		audit:    &mockAuditRepository{},
+	}
+	f.oidcRepo = &mockOidcRepository{logins: map[string]*model.OidcLogin{}, identities: map[string]int{}, userRepo: f.userRepo}
+	f.sso = NewSsoService(f.userRepo, f.oidcRepo, f.audit, auth.NewAuthService("secret-key"), f.provider, SsoSettings{
+		RoleMapping: map[string]string{"video-admins": model.RoleAdmin, "video-editors": model.RoleEditor},
+		DefaultRole: model.RoleViewer,
+	})
+	f.sso.now = f.clock.Now
+	return f
+}
+
+// login starts a login and finishes it as identity.
+func (f *ssoFixture) login(t *testing.T, identity *model.ExternalIdentity) (string, error) {
+	state, url, err := f.sso.Begin(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.example.com/auth?state="+state, url)
+	f.provider.identity = identity
+	return f.sso.Finish(context.Background(), state, "code")
+}
+
+func TestSsoService_Finish_ProvisionsUser(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+
+	// test
+	token, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "jane", Email: "jane@example.com",
+		EmailVerified: true, PreferredUsername: "janedoe", Groups: []string{"video-editors", "staff"}})
+
+	// assertions
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	user := f.userRepo.users["janedoe"]
+	require.NotNil(t, user)
+	require.Equal(t, model.RoleEditor, user.Role)
+	require.Empty(t, user.Password)
+	require.True(t, user.EmailVerified)
+	require.Equal(t, user.ID, f.oidcRepo.identities[testIssuer+" jane"])
+	require.Len(t, f.audit.keys(model.AuditUserProvisioned), 1)
+	require.Len(t, f.audit.keys(model.AuditLoginSso), 1)
+}
+
+func TestSsoService_Finish_SyncsRoleOfLinkedUser(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+	identity := &model.ExternalIdentity{Issuer: testIssuer, Subject: "jane", Email: "jane@example.com", Groups: []string{"video-admins"}}
+	_, err := f.login(t, identity)
+	require.NoError(t, err)
+	identity.Groups = nil
+
+	// test
+	_, err = f.login(t, identity)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, model.RoleViewer, f.userRepo.users["jane"].Role)
+	require.Len(t, f.audit.keys(model.AuditUserProvisioned), 1)
+}
+
+func TestSsoService_Finish_LinksUserWithVerifiedEmail(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+
+	// test
+	unverifiedErr := func() error {
+		_, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "john", Email: "johndoe@example.com"})
+		return err
+	}()
+	_, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "john", Email: "johndoe@example.com",
+		EmailVerified: true, Groups: []string{"video-admins"}})
+
+	// assertions
+	require.ErrorIs(t, unverifiedErr, ErrSsoRejected)
+	require.NoError(t, err)
+	require.Equal(t, 1, f.oidcRepo.identities[testIssuer+" john"])
+	require.Equal(t, model.RoleAdmin, f.userRepo.users["johndoe"].Role)
+	require.Len(t, f.userRepo.users, 1)
+}
+
+func TestSsoService_Finish_NumbersTakenUsername(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+
+	// test
+	_, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "other-john", Email: "johndoe@example.org"})
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, "johndoe@example.org", f.userRepo.users["johndoe-2"].Email)
+}
+
+func TestSsoService_Finish_UnhappyPath_NoRole(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+	f.sso.settings.DefaultRole = ""
+
+	// test
+	_, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "jane", Email: "jane@example.com", Groups: []string{"staff"}})
+
+	// assertions
+	require.ErrorIs(t, err, ErrSsoNoRole)
+	require.Len(t, f.userRepo.users, 1)
+	require.Len(t, f.audit.keys(model.AuditLoginRejected), 1)
+}
+
+func TestSsoService_Finish_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+	f.userRepo.users["johndoe"].Disabled = true
+
+	// test
+	_, err := f.login(t, &model.ExternalIdentity{Issuer: testIssuer, Subject: "john", Email: "johndoe@example.com", EmailVerified: true})
+
+	// assertions
+	require.ErrorIs(t, err, ErrSsoRejected)
+	require.Empty(t, f.audit.keys(model.AuditLoginSso))
+}
+
+func TestSsoService_Finish_UnhappyPath_StateUsedOnce(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+	state, _, err := f.sso.Begin(context.Background())
+	require.NoError(t, err)
+	f.provider.identity = &model.ExternalIdentity{Issuer: testIssuer, Subject: "jane", Email: "jane@example.com"}
+	_, err = f.sso.Finish(context.Background(), state, "code")
+	require.NoError(t, err)
+	expired, _, err := f.sso.Begin(context.Background())
+	require.NoError(t, err)
+	f.clock.Advance(SsoLoginTTL)
+
+	// test
+	replayedErr := func() error {
+		_, err := f.sso.Finish(context.Background(), state, "code")
+		return err
+	}()
+	_, expiredErr := f.sso.Finish(context.Background(), expired, "code")
+
+	// assertions
+	require.ErrorIs(t, replayedErr, ErrSsoLoginInvalid)
+	require.ErrorIs(t, expiredErr, ErrSsoLoginInvalid)
+}
+
+func TestSsoService_Finish_UnhappyPath_ProviderRefuses(t *testing.T) {
+	// fixture
+	f := newSsoFixture()
+	f.provider.err = errors.New("id token signature is invalid")
+
+	// test
+	_, err := f.login(t, nil)
+
+	// assertions
+	require.ErrorIs(t, err, ErrSsoRejected)
+}
+
+func TestSsoService_NotConfigured(t *testing.T) {
+	// fixture
+	sso := NewSsoService(nil, nil, nil, nil, nil, SsoSettings{})
+
+	// test
+	_, _, beginErr := sso.Begin(context.Background())
+	_, finishErr := sso.Finish(context.Background(), "state", "code")
+
+	// assertions
+	require.ErrorIs(t, beginErr, ErrSsoNotConfigured)
+	require.ErrorIs(t, finishErr, ErrSsoNotConfigured)
+}
+
+func TestParseRoleMapping(t *testing.T) {
+	// test
+	roles, err := ParseRoleMapping(" video-admins=admin, video-editors = editor ,")
+	_, invalidErr := ParseRoleMapping("video-admins=owner")
+	_, malformedErr := ParseRoleMapping("video-admins")
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"video-admins": "admin", "video-editors": "editor"}, roles)
+	require.Error(t, invalidErr)
+	require.Error(t, malformedErr)
+}
+
+type mockIdentityProvider struct {
+	identity *model.ExternalIdentity
+	err      error
+}
+
+func (p *mockIdentityProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	return "https://idp.example.com/auth?state=" + state, nil
+}
+
+func (p *mockIdentityProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*model.ExternalIdentity, error) {
+	return p.identity, p.err
+}
+
+type mockOidcRepository struct {
+	logins     map[string]*model.OidcLogin
+	identities map[string]int
+	userRepo   *mockUserRepository
+}
+
+func (r *mockOidcRepository) SaveLogin(ctx context.Context, login *model.OidcLogin) error {
+	r.logins[login.State] = login
+	return nil
+}
+
+func (r *mockOidcRepository) TakeLogin(ctx context.Context, state string, now time.Time) (*model.OidcLogin, error) {
+	login, ok := r.logins[state]
+	delete(r.logins, state)
+	if !ok || !login.ExpiresAt.After(now) {
+		return nil, nil
+	}
+	return login, nil
+}
+
+func (r *mockOidcRepository) FindUser(ctx context.Context, issuer string, subject string) (*model.User, error) {
+	id, ok := r.identities[issuer+" "+subject]
+	if !ok {
+		return nil, repository.UserNotFoundError
+	}
+	users, err := r.userRepo.FindByIds(ctx, []int{id})
+	if err != nil || len(users) == 0 {
+		return nil, repository.UserNotFoundError
+	}
+	return users[0], nil
+}
+
+func (r *mockOidcRepository) Link(ctx context.Context, issuer string, subject string, userID int) error {
+	r.identities[issuer+" "+subject] = userID
+	return nil
+}
+
+func (r *mockOidcRepository) Provision(ctx context.Context, user *model.User, issuer string, subject string) error {
+	user.ID = len(r.userRepo.users) + 1
+	if err := r.userRepo.Save(ctx, user); err != nil {
+		return err
+	}
+	return r.Link(ctx, issuer, subject, user.ID)
+}
@@ -1,68 +1,235 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
 )
 
 var UserOrPasswordNotFoundError = fmt.Errorf("invalid username or password")
 
 type userService struct {
 	userRepo ports.UserRepository
+	mfaRepo  ports.MfaRepository
+	accounts ports.AccountService
 	auth     auth.AuthService
+	throttle *LoginThrottle
 }
 
-func NewUserService(userRepo ports.UserRepository, auth auth.AuthService) *userService {
+func NewUserService(userRepo ports.UserRepository, mfaRepo ports.MfaRepository, accounts ports.AccountService,
+	auth auth.AuthService, throttle *LoginThrottle) *userService {
 	return &userService{
 		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		accounts: accounts,
 		auth:     auth,
+		throttle: throttle,
 	}
 }
 
-func (s *userService) Login(username string, password string) (string, error) {
-	user, err := s.userRepo.FindByUsername(username)
+// dummyHash is compared with the password of unknown usernames, so they take
+// as long to fail as a wrong password.
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := hashPassword("dummy-password")
+	return hash
+})
+
+func (s *userService) Login(ctx context.Context, username string, password string) (string, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	address := auth.ClientAddress(ctx)
+	if err := s.throttle.Check(ctx, username, address); err != nil {
+		logging.FromContext(ctx).Info("login failed", "username", username, "reason", metrics.LoginThrottled)
+		metrics.LoginFailures.WithLabelValues(metrics.LoginThrottled).Inc()
+		return "", err
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
 	if err != nil {
-		return "", UserOrPasswordNotFoundError
+		comparePassword(dummyHash(), password)
+		return "", s.loginFailed(ctx, username, address, metrics.LoginUnknownUser, err)
 	}
 
 	if err := comparePassword(user.Password, password); err != nil {
-		log.Printf("error comparing password: %v", err)
-		return "", UserOrPasswordNotFoundError
+		return "", s.loginFailed(ctx, username, address, metrics.LoginWrongPassword, nil)
+	}
+
+	if user.Disabled {
+		return "", s.loginFailed(ctx, username, address, metrics.LoginDisabledUser, nil)
+	}
+
+	// the failures are only cleared once the second factor passed too, or
+	// the password would reset the count of wrong codes
+	mfa, err := s.mfaRepo.Find(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if mfa != nil && mfa.Enabled {
+		challenge, err := s.auth.GenerateMfaChallenge(user.Username)
+		if err != nil {
+			return "", err
+		}
+		return "", &MfaRequiredError{Challenge: challenge}
+	}
+
+	if err := s.throttle.Succeeded(ctx, username); err != nil {
+		return "", err
+	}
+	return s.createSession(user)
+}
+
+// loginFailed counts the failure, every failure answers the same so the
+// reason stays in the logs and metrics.
+func (s *userService) loginFailed(ctx context.Context, username string, address string, reason string, cause error) error {
+	logging.FromContext(ctx).Info("login failed", "username", username, "reason", reason, "error", cause)
+	metrics.LoginFailures.WithLabelValues(reason).Inc()
+	if err := s.throttle.Failed(ctx, username, address); err != nil {
+		return err
+	}
+	return UserOrPasswordNotFoundError
+}
+
+// Signup mails a link to verify the email, failing to send it does not fail
+// the signup as the user can ask for another one.
+func (s *userService) Signup(ctx context.Context, email string, password string) (string, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Signup")
+	defer span.End()
+
+	user, err := s.save(ctx, email, password, model.RoleEditor, false)
+	if err != nil {
+		return "", err
 	}
 
+	if err := s.accounts.SendVerification(ctx, user.Username); err != nil {
+		logging.FromContext(ctx).Error("sending email verification failed", "username", user.Username, "error", err)
+	}
 	return s.createSession(user)
 }
 
-func (s *userService) Signup(email string, password string) (string, error) {
+// Create trusts the email given by the administrator.
+func (s *userService) Create(ctx context.Context, email string, password string, role string) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	return s.save(ctx, email, password, role, true)
+}
+
+func (s *userService) save(ctx context.Context, email string, password string, role string, verified bool) (*model.User, error) {
 	var err error
 
 	user := &model.User{
-		CreatedAt: time.Now(),
-		Email:     email,
+		CreatedAt:     time.Now(),
+		Email:         email,
+		EmailVerified: verified,
+		Role:          role,
 	}
 
 	if user.Password, err = hashPassword(password); err != nil {
-		return "", err
+		return nil, countValidation(err)
 	}
 	if user.Username, err = extractUserName(email); err != nil {
-		return "", err
+		return nil, countValidation(err)
+	}
+	if user.Username != "" {
+		if user.Username, err = freeUsername(ctx, s.userRepo, user.Username, user.CreatedAt); err != nil {
+			return nil, err
+		}
 	}
 
-	if err = validation.ValidateUser(user); err != nil {
-		return "", err
+	if err = validation.UserErrors(user).Err(); err != nil {
+		return nil, countValidation(err)
 	}
 
-	if err = s.userRepo.Save(user); err != nil {
-		return "", err
+	if err = s.userRepo.Save(ctx, user); err != nil {
+		return nil, err
 	}
 
-	return s.createSession(user)
+	return user, nil
+}
+
+func (s *userService) Disable(ctx context.Context, username string) error {
+	ctx, span := tracer.Start(ctx, "UserService.Disable")
+	defer span.End()
+
+	return s.update(ctx, username, func(user *model.User) error {
+		user.Disabled = true
+		return nil
+	})
+}
+
+func (s *userService) SetRole(ctx context.Context, username string, role string) error {
+	ctx, span := tracer.Start(ctx, "UserService.SetRole")
+	defer span.End()
+
+	return s.update(ctx, username, func(user *model.User) error {
+		user.Role = role
+		return countValidation(validation.ValidateRole(role))
+	})
+}
+
+func (s *userService) ResetPassword(ctx context.Context, username string, password string) error {
+	ctx, span := tracer.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	return s.update(ctx, username, func(user *model.User) (err error) {
+		user.Password, err = hashPassword(password)
+		return countValidation(err)
+	})
+}
+
+func (s *userService) Unlock(ctx context.Context, username string) error {
+	ctx, span := tracer.Start(ctx, "UserService.Unlock")
+	defer span.End()
+
+	if _, err := s.userRepo.FindByUsername(ctx, username); err != nil {
+		return err
+	}
+	return s.throttle.Unlock(ctx, username)
+}
+
+// update loads the user, applies change and stores the user when change
+// succeeds.
+func (s *userService) update(ctx context.Context, username string, change func(user *model.User) error) error {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err := change(user); err != nil {
+		return err
+	}
+	return s.userRepo.Update(ctx, user)
+}
+
+func (s *userService) Find(ctx context.Context, username string) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Find")
+	defer span.End()
+
+	return loadUser(ctx, s.userRepo, username)
+}
+
+func (s *userService) FindMany(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.FindMany")
+	defer span.End()
+
+	users, err := s.userRepo.FindByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int]*model.User, len(users))
+	for _, user := range users {
+		byId[user.ID] = user
+	}
+	return byId, nil
 }
 
 func (s *userService) createSession(user *model.User) (string, error) {
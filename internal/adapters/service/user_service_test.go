@@ -1,18 +1,22 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
 var (
-	ErrUserNotFound      = fmt.Errorf("user not found")
+	ErrUserNotFound      = repository.UserNotFoundError
 	ErrUserAlreadyExists = fmt.Errorf("user already exists")
 )
 
@@ -28,10 +32,10 @@ func TestUserService_Login_HappyPath(t *testing.T) {
 	}
 
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
 
 	// test
-	token, err := userService.Login("johndoe", "password123")
+	token, err := userService.Login(context.Background(), "johndoe", "password123")
 
 	// assertions
 	require.NoError(t, err)
@@ -44,14 +48,16 @@ func TestUserService_Login_UnhappyPath_UserNotFound(t *testing.T) {
 		users: map[string]*model.User{},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
+	failures := testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginUnknownUser))
 
 	// test
-	token, err := userService.Login("johndoe", "password123")
+	token, err := userService.Login(context.Background(), "johndoe", "password123")
 
 	// assertions
 	require.EqualError(t, err, UserOrPasswordNotFoundError.Error())
 	require.Empty(t, token)
+	require.Equal(t, failures+1, testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginUnknownUser)))
 }
 
 func TestUserService_Login_UnhappyPath_InvalidPassword(t *testing.T) {
@@ -65,14 +71,16 @@ func TestUserService_Login_UnhappyPath_InvalidPassword(t *testing.T) {
 		},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
+	failures := testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginWrongPassword))
 
 	// test
-	token, err := userService.Login("johndoe", "wrongpassword")
+	token, err := userService.Login(context.Background(), "johndoe", "wrongpassword")
 
 	// assertions
 	require.EqualError(t, err, UserOrPasswordNotFoundError.Error())
 	require.Empty(t, token)
+	require.Equal(t, failures+1, testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginWrongPassword)))
 }
 
 func TestUserService_Signup_HappyPath(t *testing.T) {
@@ -81,20 +89,23 @@ func TestUserService_Signup_HappyPath(t *testing.T) {
 		users: map[string]*model.User{},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	accounts := &mockAccountService{}
+	userService := NewUserService(userRepo, newMfaRepository(), accounts, authService, newLoginThrottle())
 
 	// test
-	token, err := userService.Signup("johndoe@example.com", "password123")
+	token, err := userService.Signup(context.Background(), "johndoe@example.com", "password123")
 
 	// assertions
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	user, err := userRepo.FindByUsername("johndoe")
+	user, err := userRepo.FindByUsername(context.Background(), "johndoe")
 	require.NoError(t, err)
 	require.Equal(t, "johndoe", user.Username)
 	require.NotEmpty(t, user.Password)
+	require.False(t, user.EmailVerified)
 	require.WithinDuration(t, time.Now(), user.CreatedAt, 1*time.Second)
+	require.Equal(t, []string{"johndoe"}, accounts.verifications)
 }
 
 func TestUserService_Signup_UnhappyPath_UserAlreadyExists(t *testing.T) {
@@ -102,32 +113,55 @@ func TestUserService_Signup_UnhappyPath_UserAlreadyExists(t *testing.T) {
 	userRepo := &mockUserRepository{
 		users: map[string]*model.User{
 			"johndoe": {
+				ID:       1,
 				Username: "johndoe",
+				Email:    "johndoe@example.com",
 				Password: "$2a$10$7GRkdPm.s1IrBpXKlb.SOu7vOIvFKUG0H/QSJEGCZVzHqq/ZSbBW.",
 			},
 		},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
 
 	// test
-	token, err := userService.Signup("johndoe@example.com", "password123")
+	token, err := userService.Signup(context.Background(), "johndoe@example.com", "password123")
 
 	// assertions
-	require.EqualError(t, err, ErrUserAlreadyExists.Error())
+	require.ErrorIs(t, err, repository.EmailTakenError)
 	require.Empty(t, token)
 }
 
+func TestUserService_Signup_NumbersTakenUsername(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Email: "johndoe@example.com"},
+		},
+		retired: map[string]int{"johndoe-2": 2},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	token, err := userService.Signup(context.Background(), "johndoe@example.org", "password123")
+
+	// assertions
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	user, err := userRepo.FindByEmail(context.Background(), "johndoe@example.org")
+	require.NoError(t, err)
+	require.Equal(t, "johndoe-3", user.Username)
+}
+
 func TestUserService_Signup_UnhappyPath_InvalidEmail(t *testing.T) {
 	// fixture
 	userRepo := &mockUserRepository{
 		users: map[string]*model.User{},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
 
 	// test
-	token, err := userService.Signup("johndoeexample.com", "password123")
+	token, err := userService.Signup(context.Background(), "johndoeexample.com", "password123")
 
 	// assertions
 	require.EqualError(t, err, validation.ErrEmailIsInvalid.Error())
@@ -140,21 +174,165 @@ func TestUserService_Signup_UnhappyPath_InvalidPassword(t *testing.T) {
 		users: map[string]*model.User{},
 	}
 	authService := auth.NewAuthService("secret-key")
-	userService := NewUserService(userRepo, authService)
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, authService, newLoginThrottle())
 
 	// test
-	token, err := userService.Signup("johndoe@example.com", "")
+	token, err := userService.Signup(context.Background(), "johndoe@example.com", "")
 
 	// assertions
 	require.EqualError(t, err, validation.ErrPasswordIsInvalid.Error())
 	require.Empty(t, token)
 }
 
+func TestUserService_FindMany_HappyPath(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe"},
+			"janedoe": {ID: 2, Username: "janedoe"},
+		},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret"), newLoginThrottle())
+
+	// test
+	users, err := userService.FindMany(context.Background(), []int{2, 3})
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "janedoe", users[2].Username)
+}
+
+func TestUserService_Login_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {
+				Username: "johndoe",
+				Password: "$2a$10$7GRkdPm.s1IrBpXKlb.SOu7vOIvFKUG0H/QSJEGCZVzHqq/ZSbBW.",
+				Disabled: true,
+			},
+		},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+	failures := testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginDisabledUser))
+
+	// test
+	token, err := userService.Login(context.Background(), "johndoe", "password123")
+
+	// assertions
+	require.EqualError(t, err, UserOrPasswordNotFoundError.Error())
+	require.Empty(t, token)
+	require.Equal(t, failures+1, testutil.ToFloat64(metrics.LoginFailures.WithLabelValues(metrics.LoginDisabledUser)))
+}
+
+func TestUserService_Create_HappyPath(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	user, err := userService.Create(context.Background(), "janedoe@example.com", "password123", model.RoleAdmin)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, "janedoe", user.Username)
+	require.Equal(t, model.RoleAdmin, user.Role)
+	require.True(t, user.EmailVerified)
+	require.Same(t, user, userRepo.users["janedoe"])
+}
+
+func TestUserService_Create_UnhappyPath_InvalidRole(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	user, err := userService.Create(context.Background(), "janedoe@example.com", "password123", "owner")
+
+	// assertions
+	require.EqualError(t, err, validation.ErrRoleIsInvalid.Error())
+	require.Nil(t, user)
+	require.Empty(t, userRepo.users)
+}
+
+func TestUserService_Disable_HappyPath(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Role: model.RoleEditor},
+		},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	err := userService.Disable(context.Background(), "johndoe")
+
+	// assertions
+	require.NoError(t, err)
+	require.True(t, userRepo.users["johndoe"].Disabled)
+}
+
+func TestUserService_SetRole_UnhappyPath_InvalidRole(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Role: model.RoleEditor},
+		},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	err := userService.SetRole(context.Background(), "johndoe", "owner")
+
+	// assertions
+	require.EqualError(t, err, validation.ErrRoleIsInvalid.Error())
+}
+
+func TestUserService_SetRole_UnhappyPath_UserNotFound(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	err := userService.SetRole(context.Background(), "johndoe", model.RoleAdmin)
+
+	// assertions
+	require.EqualError(t, err, ErrUserNotFound.Error())
+}
+
+func TestUserService_ResetPassword_HappyPath(t *testing.T) {
+	// fixture
+	userRepo := &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe", Password: "old-hash", Role: model.RoleEditor},
+		},
+	}
+	userService := NewUserService(userRepo, newMfaRepository(), &mockAccountService{}, auth.NewAuthService("secret-key"), newLoginThrottle())
+
+	// test
+	err := userService.ResetPassword(context.Background(), "johndoe", "new-password")
+
+	// assertions
+	require.NoError(t, err)
+	token, err := userService.Login(context.Background(), "johndoe", "new-password")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+}
+
 type mockUserRepository struct {
 	users map[string]*model.User
+	// retired holds the id of the user who released a username
+	retired map[string]int
 }
 
-func (r *mockUserRepository) FindByUsername(username string) (*model.User, error) {
+func (r *mockUserRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	user, ok := r.users[username]
 	if !ok {
 		return nil, ErrUserNotFound
@@ -162,10 +340,75 @@ func (r *mockUserRepository) FindByUsername(username string) (*model.User, error
 	return user, nil
 }
 
-func (r *mockUserRepository) Save(user *model.User) error {
+func (r *mockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, repository.UserNotFoundError
+}
+
+func (r *mockUserRepository) FindByIds(ctx context.Context, ids []int) ([]*model.User, error) {
+	users := []*model.User{}
+	for _, user := range r.users {
+		for _, id := range ids {
+			if user.ID == id {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
+
+func (r *mockUserRepository) Save(ctx context.Context, user *model.User) error {
 	if _, ok := r.users[user.Username]; ok {
 		return ErrUserAlreadyExists
 	}
+	if _, err := r.FindByEmail(ctx, user.Email); err == nil {
+		return repository.EmailTakenError
+	}
 	r.users[user.Username] = user
 	return nil
 }
+
+func (r *mockUserRepository) Update(ctx context.Context, user *model.User) error {
+	for username, stored := range r.users {
+		if stored.ID == user.ID {
+			r.users[username] = user
+			return nil
+		}
+	}
+	return ErrUserNotFound
+}
+
+func (r *mockUserRepository) UsernameTaken(ctx context.Context, username string, userId int, now time.Time) (bool, error) {
+	if user, ok := r.users[username]; ok && (userId == 0 || user.ID != userId) {
+		return true, nil
+	}
+	if id, ok := r.retired[username]; ok && (userId == 0 || id != userId) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *mockUserRepository) Rename(ctx context.Context, user *model.User, username string, retiredUntil time.Time) error {
+	r.retire(user)
+	delete(r.users, user.Username)
+	user.Username = username
+	r.users[username] = user
+	return nil
+}
+
+func (r *mockUserRepository) Delete(ctx context.Context, user *model.User, retiredUntil time.Time) error {
+	r.retire(user)
+	delete(r.users, user.Username)
+	return nil
+}
+
+func (r *mockUserRepository) retire(user *model.User) {
+	if r.retired == nil {
+		r.retired = map[string]int{}
+	}
+	r.retired[user.Username] = user.ID
+}
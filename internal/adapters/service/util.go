@@ -1,12 +1,33 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var tracer = tracing.Tracer("internal/adapters/service")
+
+// maxUsernameSuffix bounds the search for a free username
+const maxUsernameSuffix = 100
+
+// countValidation records a validation failure in the metrics and hands the
+// error back.
+func countValidation(err error) error {
+	if err != nil {
+		metrics.ValidationFailed(err)
+	}
+	return err
+}
+
 func comparePassword(hashedPasword, password string) error {
 	if err := bcrypt.CompareHashAndPassword([]byte(hashedPasword), []byte(password)); err != nil {
 		return err
@@ -33,3 +54,22 @@ func extractUserName(email string) (string, error) {
 	}
 	return tokens[0], nil
 }
+
+// freeUsername numbers base, "bob", "bob-2" and so on, until it finds a
+// username nobody has or retired.
+func freeUsername(ctx context.Context, users ports.UserRepository, base string, now time.Time) (string, error) {
+	for suffix := 1; suffix <= maxUsernameSuffix; suffix++ {
+		username := base
+		if suffix > 1 {
+			username += "-" + strconv.Itoa(suffix)
+		}
+		taken, err := users.UsernameTaken(ctx, username, 0, now)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return username, nil
+		}
+	}
+	return "", fmt.Errorf("no free username for %q", base)
+}
@@ -1,118 +1,319 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
 )
 
 var ErrVideoNotFound = fmt.Errorf("video not found")
 var ErrAnnotationsNotFound = fmt.Errorf("video not found")
 
+// serverAssigned are the rules on fields the service sets itself, clients do
+// not send them.
+var serverAssigned = []error{
+	validation.ErrVideoUserIdIsInvalid,
+	validation.ErrVideoCreatedAtIsInvalid,
+	validation.ErrAnnotationUserIdIsInvalid,
+	validation.ErrAnnotationVideoIdIdIsInvalid,
+}
+
 type videoService struct {
 	videoRepo       ports.VideoRepository
 	annotationsRepo ports.AnnotationRepository
 	userRepo        ports.UserRepository
+	workspaceRepo   ports.WorkspaceRepository
+	transactor      ports.Transactor
 }
 
 func NewVideoService(
 	videoRepo ports.VideoRepository,
 	annotationsRepo ports.AnnotationRepository,
-	userRepo ports.UserRepository) ports.VideoService {
+	userRepo ports.UserRepository,
+	workspaceRepo ports.WorkspaceRepository,
+	transactor ports.Transactor) ports.VideoService {
 	return &videoService{
 		videoRepo:       videoRepo,
 		annotationsRepo: annotationsRepo,
 		userRepo:        userRepo,
+		workspaceRepo:   workspaceRepo,
+		transactor:      transactor,
 	}
 }
 
-func (s *videoService) Create(username string, video *model.Video, annotaions []*model.Annotation) error {
+// Create adds the video to its workspace, or to the first workspace username
+// can edit when it has none.
+func (s *videoService) Create(ctx context.Context, username string, video *model.Video, annotaions []*model.Annotation) error {
+	ctx, span := tracer.Start(ctx, "VideoService.Create")
+	defer span.End()
+
 	if err := s.validate(video, annotaions); err != nil {
 		return err
 	}
 
-	var err error
-	var userId int
-	if _, err = s.userRepo.FindByUsername(username); err != nil {
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
 		return err
 	}
-
-	var videoId int
-	if videoId, err = s.videoRepo.Create(video, userId); err != nil {
-		return err
+	if video.WorkspaceID == 0 {
+		if editable := scope.ids(model.RoleEditor); len(editable) > 0 {
+			video.WorkspaceID = editable[0]
+		}
+	}
+	if scope.role(video.WorkspaceID) == "" {
+		return countValidation(validation.ErrWorkspaceIdIsInvalid)
+	}
+	if !scope.allows(video.WorkspaceID, model.RoleEditor) {
+		return ErrNotAllowed
+	}
+	userId := scope.user.ID
+	if video.CreatedAt.IsZero() {
+		video.CreatedAt = time.Now().UTC()
 	}
 
-	for _, annotation := range annotaions {
-		if err := s.annotationsRepo.Create(annotation, videoId, userId); err != nil {
+	err = s.transactor.Transaction(ctx, func(ctx context.Context, tx *ports.Tx) error {
+		videoId, err := tx.Videos.Create(ctx, video, userId)
+		if err != nil {
 			return err
 		}
+		video.ID, video.UserID, video.Version = videoId, userId, 1
+
+		events := []*model.Event{videoEvent(model.EventVideoCreated, video)}
+		for _, annotation := range annotaions {
+			if err := tx.Annotations.Create(ctx, annotation, videoId, userId); err != nil {
+				return err
+			}
+			events = append(events, annotationEvent(model.EventAnnotationCreated, video.WorkspaceID, annotation))
+		}
+
+		return appendEvents(ctx, tx.Outbox, events...)
+	})
+	if err != nil {
+		return err
 	}
 
+	metrics.VideosCreated.Inc()
+	metrics.AnnotationsCreated.Add(float64(len(annotaions)))
 	return nil
 }
 
+// validate reports every failing field of the video and its annotations at
+// once, annotation fields are prefixed with their position in the request.
 func (*videoService) validate(video *model.Video, annotaions []*model.Annotation) error {
-	if err := validation.ValidateVideo(video); err != nil {
-		return err
+	if video == nil {
+		return countValidation(validation.ErrVideoIsNil)
 	}
 
-	for _, annotation := range annotaions {
-		if err := validation.ValidateAnnotation(annotation, video.Duration); err != nil {
-			return err
-		}
+	errs := validation.VideoErrors(video)
+	for i, annotation := range annotaions {
+		prefix := fmt.Sprintf("annotations[%d].", i)
+		errs = append(errs, validation.AnnotationErrors(annotation, video.Duration).WithPrefix(prefix)...)
 	}
-	return nil
+	errs = slices.DeleteFunc(errs, func(err *validation.FieldError) bool { return slices.Contains(serverAssigned, err.Err) })
+
+	return countValidation(errs.Err())
 }
 
-func (s *videoService) Find(videoId int) (*model.Video, []*model.Annotation, error) {
+func (s *videoService) Find(ctx context.Context, username string, videoId int) (*model.Video, []*model.Annotation, error) {
+	ctx, span := tracer.Start(ctx, "VideoService.Find")
+	defer span.End()
 
-	video, err := s.videoRepo.FindById(videoId)
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	workspaceIds := scope.ids(model.RoleViewer)
+
+	video, err := s.videoRepo.FindById(ctx, workspaceIds, videoId)
 	if err != nil {
 		return nil, nil, ErrVideoNotFound
 	}
 
-	annotations, err := s.annotationsRepo.FindVideoId(videoId)
+	annotations, err := s.annotationsRepo.FindVideoId(ctx, workspaceIds, videoId)
 	if err != nil {
 		return nil, nil, ErrAnnotationsNotFound
 	}
 	return video, annotations, nil
 }
 
-func (s *videoService) Update(videoId int, video *model.Video, annotaions []*model.Annotation) error {
-	if err := s.validate(video, annotaions); err != nil {
-		return err
+func (s *videoService) FindMany(ctx context.Context, username string, ids []int) (map[int]*model.Video, error) {
+	ctx, span := tracer.Start(ctx, "VideoService.FindMany")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.videoRepo.Update(videoId, video); err != nil {
-		return err
+	videos, err := s.videoRepo.FindByIds(ctx, scope.ids(model.RoleViewer), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int]*model.Video, len(videos))
+	for _, video := range videos {
+		byId[video.ID] = video
+	}
+	return byId, nil
+}
+
+func (s *videoService) FindAnnotations(ctx context.Context, username string, videoIds []int) (map[int][]*model.Annotation, error) {
+	ctx, span := tracer.Start(ctx, "VideoService.FindAnnotations")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
 	}
 
+	annotations, err := s.annotationsRepo.FindByVideoIds(ctx, scope.ids(model.RoleViewer), videoIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byVideo := make(map[int][]*model.Annotation, len(videoIds))
+	for _, annotation := range annotations {
+		byVideo[annotation.VideoID] = append(byVideo[annotation.VideoID], annotation)
+	}
+	return byVideo, nil
+}
+
+func (s *videoService) List(ctx context.Context, username string, workspaceId int) ([]*model.Video, error) {
+	ctx, span := tracer.Start(ctx, "VideoService.List")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	if scope.role(workspaceId) == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	return s.videoRepo.FindByWorkspaceId(ctx, workspaceId)
+}
+
+// Update keeps the video in its workspace and its duration, a video without
+// one keeps the stored duration and a different one fails validation. The
+// annotations are checked against the stored duration and must already belong
+// to the video.
+func (s *videoService) Update(ctx context.Context, username string, videoId int, video *model.Video, annotaions []*model.Annotation) error {
+	ctx, span := tracer.Start(ctx, "VideoService.Update")
+	defer span.End()
+
+	if video == nil {
+		return countValidation(validation.ErrVideoIsNil)
+	}
+
+	current, existing, err := s.editable(ctx, username, videoId)
+	if err != nil {
+		return err
+	}
+	if video.Duration != 0 && video.Duration != current.Duration {
+		field := validation.FieldOf(validation.ErrDurationIsReadOnly)
+		return countValidation(&validation.FieldError{Field: field, Err: validation.ErrDurationIsReadOnly})
+	}
+	video.Duration = current.Duration
+	if err := s.validate(video, annotaions); err != nil {
+		return err
+	}
 	for _, annotation := range annotaions {
-		if err := s.annotationsRepo.Update(annotation.ID, annotation); err != nil {
+		if !slices.ContainsFunc(existing, func(a *model.Annotation) bool { return a.ID == annotation.ID }) {
+			return ErrAnnotationNotFound
+		}
+	}
+
+	return s.transactor.Transaction(ctx, func(ctx context.Context, tx *ports.Tx) error {
+		if err := tx.Videos.Update(ctx, []int{current.WorkspaceID}, videoId, video); err != nil {
 			return err
 		}
+
+		updated := *video
+		updated.ID, updated.UserID, updated.WorkspaceID, updated.Version = videoId, current.UserID, current.WorkspaceID, video.Version+1
+		updated.CreatedAt = current.CreatedAt
+		events := []*model.Event{videoEvent(model.EventVideoUpdated, &updated)}
+
+		for _, annotation := range annotaions {
+			if _, err := tx.Annotations.Update(ctx, []int{current.WorkspaceID}, annotation.ID, annotation); err != nil {
+				return err
+			}
+			updated := *annotation
+			updated.VideoID, updated.Version = videoId, annotation.Version+1
+			if i := slices.IndexFunc(existing, func(a *model.Annotation) bool { return a.ID == annotation.ID }); i >= 0 {
+				updated.UserID = existing[i].UserID
+			}
+			events = append(events, annotationEvent(model.EventAnnotationUpdated, current.WorkspaceID, &updated))
+		}
+
+		return appendEvents(ctx, tx.Outbox, events...)
+	})
+}
+
+// editable loads the video with its annotations when username may change it.
+func (s *videoService) editable(ctx context.Context, username string, videoId int) (*model.Video, []*model.Annotation, error) {
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, nil, err
 	}
+	workspaceIds := scope.ids(model.RoleViewer)
 
-	return nil
+	video, err := s.videoRepo.FindById(ctx, workspaceIds, videoId)
+	if err != nil {
+		return nil, nil, ErrVideoNotFound
+	}
+	if !scope.allows(video.WorkspaceID, model.RoleEditor) {
+		return nil, nil, ErrNotAllowed
+	}
+
+	annotations, err := s.annotationsRepo.FindVideoId(ctx, workspaceIds, videoId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return video, annotations, nil
 }
 
-func (s *videoService) Remove(id int) error {
+// Remove deletes the video and its annotations in one transaction, so that a
+// version conflict leaves the annotations untouched.
+func (s *videoService) Remove(ctx context.Context, username string, id int, version int) error {
+	ctx, span := tracer.Start(ctx, "VideoService.Remove")
+	defer span.End()
 
-	annotations, err := s.annotationsRepo.FindVideoId(id)
+	video, _, err := s.editable(ctx, username, id)
 	if err != nil {
 		return err
 	}
 
+	return s.transactor.Transaction(ctx, func(ctx context.Context, tx *ports.Tx) error {
+		return removeVideo(ctx, tx, video.WorkspaceID, id, version)
+	})
+}
+
+// removeVideo deletes the video of the workspace with its annotations and
+// records the deletions in the outbox of tx.
+func removeVideo(ctx context.Context, tx *ports.Tx, workspaceId int, id int, version int) error {
+	annotations, err := tx.Annotations.FindVideoId(ctx, []int{workspaceId}, id)
+	if err != nil {
+		return err
+	}
+
+	events := []*model.Event{}
 	for _, annotation := range annotations {
-		if err := s.annotationsRepo.Remove(annotation.ID); err != nil {
+		if err := tx.Annotations.Remove(ctx, []int{workspaceId}, annotation.ID); err != nil {
 			return err
 		}
+		events = append(events, annotationEvent(model.EventAnnotationDeleted, workspaceId, annotation))
 	}
 
-	if err := s.videoRepo.Remove(id); err != nil {
+	if err := tx.Videos.Remove(ctx, []int{workspaceId}, id, version); err != nil {
 		return err
 	}
-	return nil
+
+	video := &model.Video{ID: id, WorkspaceID: workspaceId, Version: version}
+	return appendEvents(ctx, tx.Outbox, append(events, videoEvent(model.EventVideoDeleted, video))...)
 }
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoService_Create_AppendsEvents(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	video := &model.Video{UserID: 1, Title: "title", Description: "description", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, CreatedAt: time.Now()}
+	annotation := &model.Annotation{VideoID: 1, UserID: 1, StartTime: time.Minute, EndTime: 2 * time.Minute, Type: "note", Note: "note"}
+	videosCreated, annotationsCreated := testutil.ToFloat64(metrics.VideosCreated), testutil.ToFloat64(metrics.AnnotationsCreated)
+
+	// test
+	err := service.Create(context.Background(), "johndoe", video, []*model.Annotation{annotation})
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, videosCreated+1, testutil.ToFloat64(metrics.VideosCreated))
+	require.Equal(t, annotationsCreated+1, testutil.ToFloat64(metrics.AnnotationsCreated))
+	require.Equal(t, []string{model.EventVideoCreated, model.EventAnnotationCreated}, transactor.outbox.types())
+	require.Equal(t, 2, transactor.outbox.events[0].VideoID)
+	require.Equal(t, 1, transactor.outbox.events[0].Video.UserID)
+	require.Equal(t, 1, transactor.outbox.events[0].WorkspaceID)
+	require.Equal(t, 2, transactor.outbox.events[1].Annotation.VideoID)
+	require.Equal(t, 1, videoRepo.videos[2].WorkspaceID)
+}
+
+func TestVideoService_Create_UnhappyPath_NotAMember(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), newMockTransactor(videoRepo, annotationRepo))
+
+	video := &model.Video{UserID: 1, WorkspaceID: 2, Title: "title", Description: "description", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, CreatedAt: time.Now()}
+
+	// test
+	err := service.Create(context.Background(), "johndoe", video, nil)
+
+	// assertions
+	require.ErrorIs(t, err, validation.ErrWorkspaceIdIsInvalid)
+	require.Len(t, videoRepo.videos, 1)
+}
+
+func TestVideoService_Find_UnhappyPath_OtherWorkspace(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	workspaceRepo := newMockWorkspaceRepository()
+	workspaceRepo.join(2, 2, model.RoleAdmin)
+	videoRepo.videos[2] = &model.Video{ID: 2, UserID: 2, WorkspaceID: 2, Version: 1}
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), workspaceRepo, newMockTransactor(videoRepo, annotationRepo))
+
+	// test
+	video, _, err := service.Find(context.Background(), "johndoe", 2)
+	videos, manyErr := service.FindMany(context.Background(), "johndoe", []int{1, 2})
+	_, listErr := service.List(context.Background(), "johndoe", 2)
+
+	// assertions
+	require.ErrorIs(t, err, ErrVideoNotFound)
+	require.Nil(t, video)
+	require.NoError(t, manyErr)
+	require.Equal(t, []int{1}, slices.Collect(maps.Keys(videos)))
+	require.ErrorIs(t, listErr, ErrWorkspaceNotFound)
+}
+
+func TestVideoService_Find_UnhappyPath_DisabledUser(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	userRepo := newMockUserRepository()
+	userRepo.users["johndoe"].Disabled = true
+	service := NewVideoService(videoRepo, annotationRepo, userRepo, newMockWorkspaceRepository(), newMockTransactor(videoRepo, annotationRepo))
+
+	// test
+	video, _, err := service.Find(context.Background(), "johndoe", 1)
+	removeErr := service.Remove(context.Background(), "johndoe", 1, 1)
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserDisabled)
+	require.Nil(t, video)
+	require.ErrorIs(t, removeErr, ErrUserDisabled)
+}
+
+func TestVideoService_Update_AppendsEvents(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	video := &model.Video{UserID: 1, Title: "new title", Description: "description", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, CreatedAt: time.Now(), Version: 1}
+	annotation := &model.Annotation{ID: 1, VideoID: 1, UserID: 1, StartTime: time.Minute, EndTime: 2 * time.Minute, Type: "note", Note: "changed", Version: 1}
+
+	// test
+	err := service.Update(context.Background(), "johndoe", 1, video, []*model.Annotation{annotation})
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, []string{model.EventVideoUpdated, model.EventAnnotationUpdated}, transactor.outbox.types())
+	require.Equal(t, 2, transactor.outbox.events[0].Video.Version)
+	require.Equal(t, 2, transactor.outbox.events[1].Annotation.Version)
+}
+
+func TestVideoService_Update_UnhappyPath_NotAllowed(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	workspaceRepo := newMockWorkspaceRepository()
+	workspaceRepo.join(1, 2, model.RoleViewer)
+	userRepo := newMockUserRepository()
+	userRepo.users["janedoe"] = &model.User{ID: 2, Username: "janedoe"}
+	annotationRepo.annotations[2] = &model.Annotation{ID: 2, VideoID: 3, Version: 1}
+	service := NewVideoService(videoRepo, annotationRepo, userRepo, workspaceRepo, transactor)
+
+	video := &model.Video{UserID: 1, Title: "new title", Description: "description", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, CreatedAt: time.Now(), Version: 1}
+	annotation := &model.Annotation{ID: 2, VideoID: 1, UserID: 1, StartTime: time.Minute, EndTime: 2 * time.Minute, Type: "note", Note: "changed", Version: 1}
+
+	// test
+	viewerErr := service.Update(context.Background(), "janedoe", 1, video, nil)
+	removeErr := service.Remove(context.Background(), "janedoe", 1, 1)
+	otherVideoErr := service.Update(context.Background(), "johndoe", 1, video, []*model.Annotation{annotation})
+
+	// assertions
+	require.ErrorIs(t, viewerErr, ErrNotAllowed)
+	require.ErrorIs(t, removeErr, ErrNotAllowed)
+	require.ErrorIs(t, otherVideoErr, ErrAnnotationNotFound)
+	require.Empty(t, transactor.outbox.events)
+	require.Equal(t, "title", videoRepo.videos[1].Title)
+}
+
+func TestVideoService_Update_UnhappyPath_AnnotationPastStoredDuration(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	video := &model.Video{Title: "new title", Description: "description", Link: "https://example.com/video.mp4", Version: 1}
+	annotation := &model.Annotation{ID: 1, StartTime: time.Minute, EndTime: 2 * time.Hour, Type: "note", Note: "changed", Version: 1}
+
+	// test
+	err := service.Update(context.Background(), "johndoe", 1, video, []*model.Annotation{annotation})
+
+	// assertions
+	require.ErrorIs(t, err, validation.ErrEndtimeIsAfterVideoEnd)
+	require.Empty(t, transactor.outbox.events)
+	require.Equal(t, 10*time.Minute, videoRepo.videos[1].Duration)
+}
+
+func TestVideoService_Update_UnhappyPath_DurationChanged(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	video := &model.Video{Title: "new title", Description: "description", Link: "https://example.com/video.mp4", Duration: 3 * time.Hour, Version: 1}
+
+	// test
+	err := service.Update(context.Background(), "johndoe", 1, video, nil)
+
+	// assertions
+	require.ErrorIs(t, err, validation.ErrDurationIsReadOnly)
+	errs, ok := validation.AsErrors(err)
+	require.True(t, ok)
+	require.Equal(t, "duration", errs[0].Field)
+	require.Empty(t, transactor.outbox.events)
+	require.Equal(t, "title", videoRepo.videos[1].Title)
+}
+
+func TestVideoService_Update_UnhappyPath_VersionConflictAppendsNothing(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	video := &model.Video{UserID: 1, Title: "new title", Description: "description", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, CreatedAt: time.Now(), Version: 5}
+
+	// test
+	err := service.Update(context.Background(), "johndoe", 1, video, nil)
+
+	// assertions
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	require.Empty(t, transactor.outbox.events)
+}
+
+func TestVideoService_Remove_AppendsEvents(t *testing.T) {
+	// fixture
+	videoRepo, annotationRepo := newMockVideoRepository(), newMockAnnotationRepository()
+	transactor := newMockTransactor(videoRepo, annotationRepo)
+	service := NewVideoService(videoRepo, annotationRepo, newMockUserRepository(), newMockWorkspaceRepository(), transactor)
+
+	// test
+	err := service.Remove(context.Background(), "johndoe", 1, 1)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, []string{model.EventAnnotationDeleted, model.EventVideoDeleted}, transactor.outbox.types())
+	require.Equal(t, 1, transactor.outbox.events[1].VideoID)
+}
+
+type mockVideoRepository struct {
+	videos map[int]*model.Video
+}
+
+func newMockVideoRepository() *mockVideoRepository {
+	return &mockVideoRepository{
+		videos: map[int]*model.Video{
+			1: {ID: 1, UserID: 1, WorkspaceID: 1, Title: "title", Link: "https://example.com/video.mp4", Duration: 10 * time.Minute, Version: 1},
+		},
+	}
+}
+
+func (r *mockVideoRepository) Create(ctx context.Context, video *model.Video, userId int) (int, error) {
+	id := len(r.videos) + 1
+	stored := *video
+	stored.ID, stored.UserID, stored.Version = id, userId, 1
+	r.videos[id] = &stored
+	return id, nil
+}
+
+func (r *mockVideoRepository) FindById(ctx context.Context, workspaceIds []int, id int) (*model.Video, error) {
+	video, ok := r.videos[id]
+	if !ok || !slices.Contains(workspaceIds, video.WorkspaceID) {
+		return nil, ErrVideoNotFound
+	}
+	return video, nil
+}
+
+func (r *mockVideoRepository) FindByIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Video, error) {
+	videos := []*model.Video{}
+	for _, id := range ids {
+		if video, ok := r.videos[id]; ok && slices.Contains(workspaceIds, video.WorkspaceID) {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+func (r *mockVideoRepository) FindByWorkspaceId(ctx context.Context, workspaceId int) ([]*model.Video, error) {
+	videos := []*model.Video{}
+	for _, id := range slices.Sorted(maps.Keys(r.videos)) {
+		if r.videos[id].WorkspaceID == workspaceId {
+			videos = append(videos, r.videos[id])
+		}
+	}
+	return videos, nil
+}
+
+func (r *mockVideoRepository) FindAfter(ctx context.Context, workspaceIds []int, afterId int, limit int) ([]*model.Video, error) {
+	videos := []*model.Video{}
+	for _, id := range slices.Sorted(maps.Keys(r.videos)) {
+		if id > afterId && len(videos) < limit && slices.Contains(workspaceIds, r.videos[id].WorkspaceID) {
+			videos = append(videos, r.videos[id])
+		}
+	}
+	return videos, nil
+}
+
+func (r *mockVideoRepository) FindByUserId(ctx context.Context, workspaceIds []int, userId int) ([]*model.Video, error) {
+	videos := []*model.Video{}
+	for _, id := range slices.Sorted(maps.Keys(r.videos)) {
+		if r.videos[id].UserID == userId && slices.Contains(workspaceIds, r.videos[id].WorkspaceID) {
+			videos = append(videos, r.videos[id])
+		}
+	}
+	return videos, nil
+}
+
+func (r *mockVideoRepository) Update(ctx context.Context, workspaceIds []int, id int, video *model.Video) error {
+	stored, ok := r.videos[id]
+	if !ok || !slices.Contains(workspaceIds, stored.WorkspaceID) {
+		return ErrVideoNotFound
+	}
+	if stored.Version != video.Version {
+		return ports.ErrVersionConflict
+	}
+	updated := *video
+	updated.ID, updated.UserID, updated.WorkspaceID, updated.Version = id, stored.UserID, stored.WorkspaceID, stored.Version+1
+	r.videos[id] = &updated
+	return nil
+}
+
+func (r *mockVideoRepository) Transfer(ctx context.Context, workspaceIds []int, id int, version int, userId int, workspaceId int) error {
+	stored, ok := r.videos[id]
+	if !ok || !slices.Contains(workspaceIds, stored.WorkspaceID) {
+		return ErrVideoNotFound
+	}
+	if stored.Version != version {
+		return ports.ErrVersionConflict
+	}
+	transferred := *stored
+	transferred.UserID, transferred.WorkspaceID, transferred.Version = userId, workspaceId, stored.Version+1
+	r.videos[id] = &transferred
+	return nil
+}
+
+func (r *mockVideoRepository) Remove(ctx context.Context, workspaceIds []int, id int, version int) error {
+	stored, ok := r.videos[id]
+	if !ok || !slices.Contains(workspaceIds, stored.WorkspaceID) {
+		return ErrVideoNotFound
+	}
+	if stored.Version != version {
+		return ports.ErrVersionConflict
+	}
+	delete(r.videos, id)
+	return nil
+}
+
+func newMockUserRepository() *mockUserRepository {
+	return &mockUserRepository{
+		users: map[string]*model.User{
+			"johndoe": {ID: 1, Username: "johndoe"},
+		},
+	}
+}
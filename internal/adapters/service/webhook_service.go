@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+var ErrWebhookNotFound = fmt.Errorf("webhook subscription not found")
+
+const deliveriesPageSize = 50
+
+type webhookService struct {
+	webhookRepo ports.WebhookRepository
+	userRepo    ports.UserRepository
+	now         func() time.Time
+}
+
+func NewWebhookService(webhookRepo ports.WebhookRepository, userRepo ports.UserRepository) ports.WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+		now:         time.Now,
+	}
+}
+
+// Subscribe generates a signing secret when none is given, the caller reads
+// it back from the subscription.
+func (s *webhookService) Subscribe(ctx context.Context, username string, subscription *model.WebhookSubscription) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Subscribe")
+	defer span.End()
+
+	if subscription != nil && subscription.Secret == "" {
+		secret, err := randomHex(32)
+		if err != nil {
+			return err
+		}
+		subscription.Secret = secret
+	}
+
+	if err := validation.WebhookErrors(subscription).Err(); err != nil {
+		return countValidation(err)
+	}
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return err
+	}
+
+	subscription.UserID = user.ID
+	subscription.CreatedAt = s.now().UTC()
+
+	id, err := s.webhookRepo.CreateSubscription(ctx, subscription)
+	if err != nil {
+		return err
+	}
+	subscription.ID = id
+	return nil
+}
+
+func (s *webhookService) Subscriptions(ctx context.Context, username string) ([]*model.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Subscriptions")
+	defer span.End()
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindSubscriptionsByUser(ctx, user.ID)
+}
+
+func (s *webhookService) Unsubscribe(ctx context.Context, username string, id int) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Unsubscribe")
+	defer span.End()
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.RemoveSubscription(ctx, id, user.ID); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Deliveries only reports subscriptions owned by the user, anybody else's
+// look like they do not exist.
+func (s *webhookService) Deliveries(ctx context.Context, username string, id int) ([]*model.WebhookDelivery, map[int][]*model.WebhookDeliveryAttempt, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Deliveries")
+	defer span.End()
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription, err := s.webhookRepo.FindSubscription(ctx, id)
+	if err != nil || subscription.UserID != user.ID {
+		return nil, nil, ErrWebhookNotFound
+	}
+
+	deliveries, err := s.webhookRepo.FindDeliveries(ctx, id, deliveriesPageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deliveryIds := make([]int, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryIds = append(deliveryIds, delivery.ID)
+	}
+
+	attempts, err := s.webhookRepo.FindAttempts(ctx, deliveryIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byDelivery := make(map[int][]*model.WebhookDeliveryAttempt, len(deliveries))
+	for _, attempt := range attempts {
+		byDelivery[attempt.DeliveryID] = append(byDelivery[attempt.DeliveryID], attempt)
+	}
+	return deliveries, byDelivery, nil
+}
+
+// Publish queues one delivery per matching subscription of a member of the
+// workspace of the event, the delivery worker sends them.
+func (s *webhookService) Publish(ctx context.Context, event *model.Event) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+
+	if event.ID == "" {
+		id, err := randomHex(16)
+		if err != nil {
+			return err
+		}
+		event.ID = id
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = s.now().UTC()
+	}
+
+	subscriptions, err := s.webhookRepo.FindSubscriptionsByWorkspace(ctx, event.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, subscription := range subscriptions {
+		if !subscriptionMatches(subscription, event) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = MarshalEvent(event); err != nil {
+				return err
+			}
+		}
+
+		delivery := &model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  event.OccurredAt,
+			CreatedAt:      event.OccurredAt,
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func subscriptionMatches(subscription *model.WebhookSubscription, event *model.Event) bool {
+	if len(subscription.EventTypes) > 0 && !slices.Contains(subscription.EventTypes, event.Type) {
+		return false
+	}
+	if event.Annotation != nil && len(subscription.AnnotationTypes) > 0 {
+		return slices.Contains(subscription.AnnotationTypes, event.Annotation.Type)
+	}
+	return true
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
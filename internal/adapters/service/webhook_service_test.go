@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_Subscribe_HappyPath(t *testing.T) {
+	// fixture
+	webhookRepo := newMockWebhookRepository()
+	service := NewWebhookService(webhookRepo, newMockUserRepository())
+	subscription := &model.WebhookSubscription{URL: "https://example.com/hook"}
+
+	// test
+	err := service.Subscribe(context.Background(), "johndoe", subscription)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, 1, subscription.ID)
+	require.Equal(t, 1, subscription.UserID)
+	require.Len(t, subscription.Secret, 64)
+}
+
+func TestWebhookService_Subscribe_UnhappyPath_Invalid(t *testing.T) {
+	// fixture
+	service := NewWebhookService(newMockWebhookRepository(), newMockUserRepository())
+	subscription := &model.WebhookSubscription{URL: "not a url", EventTypes: []string{"video.watched"}}
+
+	// test
+	err := service.Subscribe(context.Background(), "johndoe", subscription)
+
+	// assertions
+	errs, ok := validation.AsErrors(err)
+	require.True(t, ok)
+	require.Len(t, errs, 2)
+}
+
+func TestWebhookService_Unsubscribe_UnhappyPath_NotOwned(t *testing.T) {
+	// fixture
+	webhookRepo := newMockWebhookRepository()
+	webhookRepo.subscriptions[1] = &model.WebhookSubscription{ID: 1, UserID: 2}
+	service := NewWebhookService(webhookRepo, newMockUserRepository())
+
+	// test
+	err := service.Unsubscribe(context.Background(), "johndoe", 1)
+
+	// assertions
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+	require.Len(t, webhookRepo.subscriptions, 1)
+}
+
+func TestWebhookService_Deliveries_HappyPath(t *testing.T) {
+	// fixture
+	webhookRepo := newMockWebhookRepository()
+	webhookRepo.subscriptions[1] = &model.WebhookSubscription{ID: 1, UserID: 1}
+	webhookRepo.deliveries = []*model.WebhookDelivery{{ID: 1, SubscriptionID: 1}, {ID: 2, SubscriptionID: 1}}
+	webhookRepo.attempts = []*model.WebhookDeliveryAttempt{{DeliveryID: 2, Attempt: 1}, {DeliveryID: 2, Attempt: 2}}
+	service := NewWebhookService(webhookRepo, newMockUserRepository())
+
+	// test
+	deliveries, attempts, err := service.Deliveries(context.Background(), "johndoe", 1)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Empty(t, attempts[1])
+	require.Len(t, attempts[2], 2)
+}
+
+func TestWebhookService_Deliveries_UnhappyPath_NotOwned(t *testing.T) {
+	// fixture
+	webhookRepo := newMockWebhookRepository()
+	webhookRepo.subscriptions[1] = &model.WebhookSubscription{ID: 1, UserID: 2}
+	service := NewWebhookService(webhookRepo, newMockUserRepository())
+
+	// test
+	_, _, err := service.Deliveries(context.Background(), "johndoe", 1)
+
+	// assertions
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestWebhookService_Publish_FiltersSubscriptions(t *testing.T) {
+	// fixture
+	webhookRepo := newMockWebhookRepository()
+	webhookRepo.subscriptions[1] = &model.WebhookSubscription{ID: 1, UserID: 1}
+	webhookRepo.subscriptions[2] = &model.WebhookSubscription{ID: 2, UserID: 1, EventTypes: []string{model.EventVideoCreated}}
+	webhookRepo.subscriptions[3] = &model.WebhookSubscription{ID: 3, UserID: 1, AnnotationTypes: []string{"advertisement"}}
+	webhookRepo.subscriptions[4] = &model.WebhookSubscription{ID: 4, UserID: 1, AnnotationTypes: []string{"note"}}
+	webhookRepo.subscriptions[5] = &model.WebhookSubscription{ID: 5, UserID: 2}
+	webhookRepo.members = map[int][]int{7: {1}}
+	service := NewWebhookService(webhookRepo, newMockUserRepository())
+
+	event := &model.Event{
+		Type:        model.EventAnnotationUpdated,
+		WorkspaceID: 7,
+		VideoID:     1,
+		Annotation:  &model.Annotation{ID: 1, VideoID: 1, StartTime: time.Minute, Type: "note", Note: "note", Version: 2},
+	}
+
+	// test
+	err := service.Publish(context.Background(), event)
+
+	// assertions
+	require.NoError(t, err)
+	require.Len(t, webhookRepo.deliveries, 2)
+	require.Equal(t, 1, webhookRepo.deliveries[0].SubscriptionID)
+	require.Equal(t, 4, webhookRepo.deliveries[1].SubscriptionID)
+	require.Equal(t, model.DeliveryPending, webhookRepo.deliveries[0].Status)
+	require.NotEmpty(t, event.ID)
+
+	payload := map[string]any{}
+	require.NoError(t, json.Unmarshal(webhookRepo.deliveries[0].Payload, &payload))
+	require.Equal(t, model.EventAnnotationUpdated, payload["type"])
+	require.Equal(t, event.ID, payload["id"])
+	require.Equal(t, 7.0, payload["workspace_id"])
+	require.Equal(t, 60.0, payload["annotation"].(map[string]any)["start_seconds"])
+	require.NotContains(t, payload, "video")
+}
+
+type mockWebhookRepository struct {
+	subscriptions map[int]*model.WebhookSubscription
+	deliveries    []*model.WebhookDelivery
+	attempts      []*model.WebhookDeliveryAttempt
+	// members holds the user ids of the workspaces by their id
+	members map[int][]int
+}
+
+func newMockWebhookRepository() *mockWebhookRepository {
+	return &mockWebhookRepository{subscriptions: map[int]*model.WebhookSubscription{}}
+}
+
+func (r *mockWebhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (int, error) {
+	id := len(r.subscriptions) + 1
+	r.subscriptions[id] = subscription
+	return id, nil
+}
+
+func (r *mockWebhookRepository) FindSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, repository.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+func (r *mockWebhookRepository) FindSubscriptionsByUser(ctx context.Context, userId int) ([]*model.WebhookSubscription, error) {
+	subscriptions := []*model.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if subscription.UserID == userId {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *mockWebhookRepository) FindSubscriptionsByWorkspace(ctx context.Context, workspaceId int) ([]*model.WebhookSubscription, error) {
+	subscriptions := []*model.WebhookSubscription{}
+	for id := 1; id <= len(r.subscriptions); id++ {
+		if slices.Contains(r.members[workspaceId], r.subscriptions[id].UserID) {
+			subscriptions = append(subscriptions, r.subscriptions[id])
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *mockWebhookRepository) RemoveSubscription(ctx context.Context, id, userId int) error {
+	subscription, ok := r.subscriptions[id]
+	if !ok || subscription.UserID != userId {
+		return repository.ErrWebhookNotFound
+	}
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func (r *mockWebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	delivery.ID = len(r.deliveries) + 1
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *mockWebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	return r.deliveries, nil
+}
+
+func (r *mockWebhookRepository) FindDeliveries(ctx context.Context, subscriptionId int, limit int) ([]*model.WebhookDelivery, error) {
+	deliveries := []*model.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionId {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *mockWebhookRepository) FindAttempts(ctx context.Context, deliveryIds []int) ([]*model.WebhookDeliveryAttempt, error) {
+	return r.attempts, nil
+}
+
+func (r *mockWebhookRepository) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrLastAdmin         = errors.New("a workspace needs at least one admin")
+)
+
+// WorkspaceSettings tell where the mailed invitations point and how long
+// they work.
+type WorkspaceSettings struct {
+	AppURL        string
+	InvitationTTL time.Duration
+}
+
+type workspaceService struct {
+	userRepo      ports.UserRepository
+	workspaceRepo ports.WorkspaceRepository
+	audit         ports.AuditRepository
+	auth          auth.AuthService
+	mailer        ports.Mailer
+	settings      WorkspaceSettings
+	now           func() time.Time
+}
+
+func NewWorkspaceService(userRepo ports.UserRepository, workspaceRepo ports.WorkspaceRepository, audit ports.AuditRepository,
+	auth auth.AuthService, mailer ports.Mailer, settings WorkspaceSettings) *workspaceService {
+	return &workspaceService{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		audit:         audit,
+		auth:          auth,
+		mailer:        mailer,
+		settings:      settings,
+		now:           time.Now,
+	}
+}
+
+func (s *workspaceService) Create(ctx context.Context, username string, name string) (*model.Workspace, error) {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.Create")
+	defer span.End()
+
+	if err := validation.ValidateWorkspaceName(name); err != nil {
+		return nil, countValidation(err)
+	}
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := &model.Workspace{Name: strings.TrimSpace(name), CreatedAt: s.now().UTC()}
+	if err := s.workspaceRepo.Create(ctx, workspace, user.ID); err != nil {
+		return nil, err
+	}
+	s.record(ctx, model.AuditWorkspaceCreated, username, workspace, "")
+	return workspace, nil
+}
+
+func (s *workspaceService) List(ctx context.Context, username string) ([]*model.Workspace, error) {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.List")
+	defer span.End()
+
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	return scope.workspaces, nil
+}
+
+func (s *workspaceService) Members(ctx context.Context, username string, workspaceId int) ([]*model.WorkspaceMember, error) {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.Members")
+	defer span.End()
+
+	if _, _, err := s.workspace(ctx, username, workspaceId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.FindMembers(ctx, workspaceId)
+}
+
+func (s *workspaceService) Invite(ctx context.Context, username string, workspaceId int, email string, role string) error {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.Invite")
+	defer span.End()
+
+	if err := validation.InvitationErrors(email, role).Err(); err != nil {
+		return countValidation(err)
+	}
+
+	user, workspace, err := s.workspace(ctx, username, workspaceId, model.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	token, err := s.auth.GenerateActionToken(email, model.TokenWorkspaceInvitation, id, s.settings.InvitationTTL)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	err = s.workspaceRepo.CreateInvitation(ctx, &model.WorkspaceInvitation{
+		ID:          id,
+		WorkspaceID: workspaceId,
+		Email:       email,
+		Role:        role,
+		InvitedBy:   user.ID,
+		ExpiresAt:   now.Add(s.settings.InvitationTTL),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimSuffix(s.settings.AppURL, "/") + "/workspaces/join?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(ctx, &model.Mail{
+		To:      email,
+		Subject: fmt.Sprintf("Join %s", workspace.Name),
+		Body: fmt.Sprintf("Hello,\n\n%s invited you to the workspace %s as %s. Open this link within %s to join it:\n\n%s\n\n"+
+			"You need an account with this email address. If you do not know %s, ignore this email.\n",
+			username, workspace.Name, role, validity(s.settings.InvitationTTL), link, username),
+	})
+	if err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditWorkspaceInvited, username, workspace, email+" as "+role)
+	return nil
+}
+
+// Join answers the same for every reason to refuse the token, as the links
+// of the account service do.
+func (s *workspaceService) Join(ctx context.Context, username string, token string) (*model.Workspace, error) {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.Join")
+	defer span.End()
+
+	ok, email, id := s.auth.ValidateActionToken(token, model.TokenWorkspaceInvitation)
+	if !ok {
+		return nil, ErrUserTokenInvalid
+	}
+
+	user, err := loadUser(ctx, s.userRepo, username)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := s.workspaceRepo.FindInvitation(ctx, id)
+	if errors.Is(err, repository.ErrInvitationNotFound) {
+		return nil, ErrUserTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, email) || !strings.EqualFold(invitation.Email, email) {
+		return nil, ErrUserTokenInvalid
+	}
+
+	now := s.now().UTC()
+	member := &model.WorkspaceMember{WorkspaceID: invitation.WorkspaceID, UserID: user.ID, Role: invitation.Role, CreatedAt: now}
+	accepted, err := s.workspaceRepo.AcceptInvitation(ctx, id, member, now)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrUserTokenInvalid
+	}
+
+	_, workspace, err := s.workspace(ctx, username, invitation.WorkspaceID, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, model.AuditWorkspaceJoined, username, workspace, "as "+workspace.Role)
+	return workspace, nil
+}
+
+func (s *workspaceService) ChangeRole(ctx context.Context, username string, workspaceId int, member string, role string) error {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.ChangeRole")
+	defer span.End()
+
+	if err := validation.ValidateRole(role); err != nil {
+		return countValidation(err)
+	}
+
+	_, workspace, err := s.workspace(ctx, username, workspaceId, model.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	current, err := s.member(ctx, workspaceId, member)
+	if err != nil {
+		return err
+	}
+	if current.Role == model.RoleAdmin && role != model.RoleAdmin {
+		if err := s.keepAdmin(ctx, workspaceId); err != nil {
+			return err
+		}
+	}
+
+	current.Role = role
+	if err := s.workspaceRepo.SaveMember(ctx, current); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditWorkspaceRoleChanged, username, workspace, member+" to "+role)
+	return nil
+}
+
+func (s *workspaceService) RemoveMember(ctx context.Context, username string, workspaceId int, member string) error {
+	ctx, span := tracer.Start(ctx, "WorkspaceService.RemoveMember")
+	defer span.End()
+
+	required := model.RoleAdmin
+	if member == username {
+		required = model.RoleViewer
+	}
+	_, workspace, err := s.workspace(ctx, username, workspaceId, required)
+	if err != nil {
+		return err
+	}
+	current, err := s.member(ctx, workspaceId, member)
+	if err != nil {
+		return err
+	}
+	if current.Role == model.RoleAdmin {
+		if err := s.keepAdmin(ctx, workspaceId); err != nil {
+			return err
+		}
+	}
+
+	if err := s.workspaceRepo.RemoveMember(ctx, workspaceId, current.UserID); err != nil {
+		return err
+	}
+	s.record(ctx, model.AuditWorkspaceLeft, member, workspace, "removed by "+username)
+	return nil
+}
+
+// workspace loads the workspace of username, it is not found for those who
+// are not a member and not allowed for members below required.
+func (s *workspaceService) workspace(ctx context.Context, username string, workspaceId int, required string) (*model.User, *model.Workspace, error) {
+	scope, err := loadScope(ctx, s.userRepo, s.workspaceRepo, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, workspace := range scope.workspaces {
+		if workspace.ID != workspaceId {
+			continue
+		}
+		if !model.RoleAllows(workspace.Role, required) {
+			return nil, nil, ErrNotAllowed
+		}
+		return scope.user, workspace, nil
+	}
+	return nil, nil, ErrWorkspaceNotFound
+}
+
+func (s *workspaceService) member(ctx context.Context, workspaceId int, username string) (*model.WorkspaceMember, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if errors.Is(err, repository.UserNotFoundError) {
+		return nil, repository.ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.FindMember(ctx, workspaceId, user.ID)
+}
+
+// keepAdmin refuses to demote or remove the last admin of the workspace.
+func (s *workspaceService) keepAdmin(ctx context.Context, workspaceId int) error {
+	members, err := s.workspaceRepo.FindMembers(ctx, workspaceId)
+	if err != nil {
+		return err
+	}
+	admins := 0
+	for _, member := range members {
+		if member.Role == model.RoleAdmin {
+			admins++
+		}
+	}
+	if admins < 2 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+func (s *workspaceService) record(ctx context.Context, eventType string, username string, workspace *model.Workspace, detail string) {
+	detail = strings.TrimSpace(fmt.Sprintf("workspace %d %s", workspace.ID, detail))
+	recordAudit(ctx, s.audit, &model.AuditEvent{Type: eventType, Username: username, Address: auth.ClientAddress(ctx), Detail: detail, OccurredAt: s.now().UTC()})
+}
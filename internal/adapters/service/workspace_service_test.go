@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type workspaceFixture struct {
+	workspaces    *workspaceService
+	userRepo      *mockUserRepository
+	workspaceRepo *mockWorkspaceRepository
+	mailer        *mockMailer
+	audit         *mockAuditRepository
+}
+
+func newWorkspaceFixture() *workspaceFixture {
+	f := &workspaceFixture{
+		userRepo: &mockUserRepository{
+			users: map[string]*model.User{
+				"johndoe": {ID: 1, Username: "johndoe", Email: "johndoe@example.com", Role: model.RoleEditor},
+				"janedoe": {ID: 2, Username: "janedoe", Email: "janedoe@example.com", Role: model.RoleEditor},
+			},
+		},
+		workspaceRepo: newMockWorkspaceRepository(),
+		mailer:        &mockMailer{},
+		audit:         &mockAuditRepository{},
+	}
+	f.workspaceRepo.join(2, 2, model.RoleAdmin)
+	f.workspaces = NewWorkspaceService(f.userRepo, f.workspaceRepo, f.audit, auth.NewAuthService("secret-key"), f.mailer,
+		WorkspaceSettings{AppURL: "https://app.example.com", InvitationTTL: 7 * 24 * time.Hour})
+	return f
+}
+
+func TestWorkspaceService_Create_HappyPath(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+
+	// test
+	workspace, err := f.workspaces.Create(context.Background(), "johndoe", "  Annotators ")
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, "Annotators", workspace.Name)
+	require.Equal(t, model.RoleAdmin, workspace.Role)
+	workspaces, err := f.workspaces.List(context.Background(), "johndoe")
+	require.NoError(t, err)
+	require.Len(t, workspaces, 2)
+	require.Len(t, f.audit.keys(model.AuditWorkspaceCreated), 1)
+}
+
+func TestWorkspaceService_Invite_HappyPath(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+	require.NoError(t, f.workspaces.Invite(context.Background(), "johndoe", 1, "janedoe@example.com", model.RoleEditor))
+	token := f.mailer.token(t, "https://app.example.com/workspaces/join")
+
+	// test
+	workspace, err := f.workspaces.Join(context.Background(), "janedoe", token)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, 1, workspace.ID)
+	require.Equal(t, model.RoleEditor, workspace.Role)
+	require.Equal(t, "janedoe@example.com", f.mailer.sent[0].To)
+	_, err = f.workspaces.Join(context.Background(), "janedoe", token)
+	require.ErrorIs(t, err, ErrUserTokenInvalid)
+}
+
+func TestWorkspaceService_Invite_UnhappyPath_NotAdmin(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+	f.workspaceRepo.join(1, 2, model.RoleEditor)
+
+	// test
+	notAdminErr := f.workspaces.Invite(context.Background(), "janedoe", 1, "someone@example.com", model.RoleViewer)
+	notMemberErr := f.workspaces.Invite(context.Background(), "johndoe", 2, "someone@example.com", model.RoleViewer)
+	invalidErr := f.workspaces.Invite(context.Background(), "johndoe", 1, "someone", "owner")
+
+	// assertions
+	require.ErrorIs(t, notAdminErr, ErrNotAllowed)
+	require.ErrorIs(t, notMemberErr, ErrWorkspaceNotFound)
+	require.ErrorIs(t, invalidErr, validation.ErrEmailIsInvalid)
+	require.ErrorIs(t, invalidErr, validation.ErrRoleIsInvalid)
+	require.Empty(t, f.mailer.sent)
+}
+
+func TestWorkspaceService_Join_UnhappyPath_OtherEmail(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+	require.NoError(t, f.workspaces.Invite(context.Background(), "janedoe", 2, "someone@example.com", model.RoleEditor))
+	token := f.mailer.token(t, "https://app.example.com/workspaces/join")
+
+	// test
+	workspace, err := f.workspaces.Join(context.Background(), "johndoe", token)
+
+	// assertions
+	require.ErrorIs(t, err, ErrUserTokenInvalid)
+	require.Nil(t, workspace)
+	require.Empty(t, f.workspaceRepo.members[2][1])
+}
+
+func TestWorkspaceService_ChangeRole_HappyPath(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+	f.workspaceRepo.join(1, 2, model.RoleViewer)
+
+	// test
+	err := f.workspaces.ChangeRole(context.Background(), "johndoe", 1, "janedoe", model.RoleEditor)
+
+	// assertions
+	require.NoError(t, err)
+	require.Equal(t, model.RoleEditor, f.workspaceRepo.members[1][2])
+	require.Len(t, f.audit.keys(model.AuditWorkspaceRoleChanged), 1)
+}
+
+func TestWorkspaceService_RemoveMember_UnhappyPath_LastAdmin(t *testing.T) {
+	// fixture
+	f := newWorkspaceFixture()
+	f.workspaceRepo.join(1, 2, model.RoleViewer)
+
+	// test
+	leaveErr := f.workspaces.RemoveMember(context.Background(), "johndoe", 1, "johndoe")
+	demoteErr := f.workspaces.ChangeRole(context.Background(), "johndoe", 1, "johndoe", model.RoleEditor)
+	removeErr := f.workspaces.RemoveMember(context.Background(), "janedoe", 1, "johndoe")
+
+	// assertions
+	require.ErrorIs(t, leaveErr, ErrLastAdmin)
+	require.ErrorIs(t, demoteErr, ErrLastAdmin)
+	require.ErrorIs(t, removeErr, ErrNotAllowed)
+	require.NoError(t, f.workspaces.RemoveMember(context.Background(), "janedoe", 1, "janedoe"))
+	require.NotContains(t, f.workspaceRepo.members[1], 2)
+}
+
+type mockWorkspaceRepository struct {
+	workspaces map[int]*model.Workspace
+	// members holds the role of the members of the workspaces by their id
+	members     map[int]map[int]string
+	invitations map[string]*model.WorkspaceInvitation
+}
+
+// newMockWorkspaceRepository holds the personal workspace of the user 1.
+func newMockWorkspaceRepository() *mockWorkspaceRepository {
+	r := &mockWorkspaceRepository{
+		workspaces:  map[int]*model.Workspace{},
+		members:     map[int]map[int]string{},
+		invitations: map[string]*model.WorkspaceInvitation{},
+	}
+	r.join(1, 1, model.RoleAdmin)
+	return r
+}
+
+// join adds the user to the workspace, creating it when needed.
+func (r *mockWorkspaceRepository) join(workspaceId int, userId int, role string) {
+	if _, ok := r.workspaces[workspaceId]; !ok {
+		r.workspaces[workspaceId] = &model.Workspace{ID: workspaceId, Name: "workspace"}
+		r.members[workspaceId] = map[int]string{}
+	}
+	r.members[workspaceId][userId] = role
+}
+
+func (r *mockWorkspaceRepository) Create(ctx context.Context, workspace *model.Workspace, userId int) error {
+	workspace.ID, workspace.Role = len(r.workspaces)+1, model.RoleAdmin
+	r.workspaces[workspace.ID] = workspace
+	r.members[workspace.ID] = map[int]string{userId: model.RoleAdmin}
+	return nil
+}
+
+func (r *mockWorkspaceRepository) FindByUserId(ctx context.Context, userId int) ([]*model.Workspace, error) {
+	workspaces := []*model.Workspace{}
+	for _, id := range slices.Sorted(maps.Keys(r.workspaces)) {
+		if role, ok := r.members[id][userId]; ok {
+			workspace := *r.workspaces[id]
+			workspace.Role = role
+			workspaces = append(workspaces, &workspace)
+		}
+	}
+	return workspaces, nil
+}
+
+func (r *mockWorkspaceRepository) FindIds(ctx context.Context) ([]int, error) {
+	return slices.Sorted(maps.Keys(r.workspaces)), nil
+}
+
+func (r *mockWorkspaceRepository) FindMembers(ctx context.Context, workspaceId int) ([]*model.WorkspaceMember, error) {
+	members := []*model.WorkspaceMember{}
+	for _, userId := range slices.Sorted(maps.Keys(r.members[workspaceId])) {
+		members = append(members, &model.WorkspaceMember{WorkspaceID: workspaceId, UserID: userId, Role: r.members[workspaceId][userId]})
+	}
+	return members, nil
+}
+
+func (r *mockWorkspaceRepository) FindMember(ctx context.Context, workspaceId int, userId int) (*model.WorkspaceMember, error) {
+	role, ok := r.members[workspaceId][userId]
+	if !ok {
+		return nil, repository.ErrMemberNotFound
+	}
+	return &model.WorkspaceMember{WorkspaceID: workspaceId, UserID: userId, Role: role}, nil
+}
+
+func (r *mockWorkspaceRepository) SaveMember(ctx context.Context, member *model.WorkspaceMember) error {
+	r.join(member.WorkspaceID, member.UserID, member.Role)
+	return nil
+}
+
+func (r *mockWorkspaceRepository) RemoveMember(ctx context.Context, workspaceId int, userId int) error {
+	if _, ok := r.members[workspaceId][userId]; !ok {
+		return repository.ErrMemberNotFound
+	}
+	delete(r.members[workspaceId], userId)
+	return nil
+}
+
+func (r *mockWorkspaceRepository) CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error {
+	r.invitations[invitation.ID] = invitation
+	return nil
+}
+
+func (r *mockWorkspaceRepository) FindInvitation(ctx context.Context, id string) (*model.WorkspaceInvitation, error) {
+	invitation, ok := r.invitations[id]
+	if !ok {
+		return nil, repository.ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+func (r *mockWorkspaceRepository) AcceptInvitation(ctx context.Context, id string, member *model.WorkspaceMember, now time.Time) (bool, error) {
+	invitation, ok := r.invitations[id]
+	if !ok || invitation.AcceptedAt != nil || !invitation.ExpiresAt.After(now) {
+		return false, nil
+	}
+	invitation.AcceptedAt = &now
+	if _, ok := r.members[member.WorkspaceID][member.UserID]; !ok {
+		r.join(member.WorkspaceID, member.UserID, member.Role)
+	}
+	return true, nil
+}
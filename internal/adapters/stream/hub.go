@@ -0,0 +1,243 @@
+// Package stream fans annotation events out to the clients watching a video.
+package stream
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// EventReset tells a resuming client that events were lost, it has to reload
+// the video and continue from the id of the reset message.
+const EventReset = "reset"
+
+const (
+	defaultBacklogSize   = 256
+	defaultIdleRetention = time.Minute
+	subscriberBuffer     = 64
+)
+
+// Message is one event as sent to clients. IDs increase by one per video, so
+// a client that reconnects can tell whether it missed anything.
+type Message struct {
+	ID   uint64
+	Type string
+	Data []byte
+}
+
+// Hub keeps the latest events of every video so reconnecting clients can
+// resume where they stopped. It only lives in memory: a video nobody follows
+// is forgotten after idleRetention, and a deleted one right away, clients
+// resuming after that or after a restart are told to reload.
+type Hub struct {
+	mu            sync.Mutex
+	videos        map[int]*videoStream
+	backlogSize   int
+	idleRetention time.Duration
+	closed        bool
+	// firstID is above the id of every message of a forgotten video, new
+	// streams start from it so no client takes them for the ones it followed
+	firstID uint64
+}
+
+type videoStream struct {
+	// workspaceID is where the subscribers were allowed to follow the video
+	workspaceID int
+	lastID      uint64
+	backlog     []*Message
+	subscribers map[*Subscription]struct{}
+	// idle forgets the stream once it had no subscriber for idleRetention
+	idle *time.Timer
+}
+
+// Subscription delivers the events of one video. Its channel is closed when
+// the subscriber falls too far behind, or the video is deleted or moves to
+// another workspace, the client is expected to reconnect and resume.
+type Subscription struct {
+	C <-chan *Message
+
+	c       chan *Message
+	hub     *Hub
+	videoID int
+	once    sync.Once
+}
+
+func NewHub() *Hub {
+	return &Hub{videos: map[int]*videoStream{}, backlogSize: defaultBacklogSize, idleRetention: defaultIdleRetention}
+}
+
+// Publish implements ports.EventPublisher, only annotation events and video
+// deletions are streamed. A video update that moves the video to another
+// workspace ends its subscriptions, so their clients are authorized again
+// when they reconnect.
+func (h *Hub) Publish(ctx context.Context, event *model.Event) error {
+	if event.Type == model.EventVideoUpdated {
+		h.moved(event.VideoID, event.WorkspaceID)
+		return nil
+	}
+	if !strings.HasPrefix(event.Type, "annotation.") && event.Type != model.EventVideoDeleted {
+		return nil
+	}
+
+	data, err := service.MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream := h.stream(event.VideoID)
+	stream.lastID++
+	message := &Message{ID: stream.lastID, Type: event.Type, Data: data}
+
+	stream.backlog = append(stream.backlog, message)
+	if len(stream.backlog) > h.backlogSize {
+		stream.backlog = stream.backlog[len(stream.backlog)-h.backlogSize:]
+	}
+
+	for subscription := range stream.subscribers {
+		select {
+		case subscription.c <- message:
+		default:
+			h.drop(stream, subscription)
+		}
+	}
+
+	if event.Type == model.EventVideoDeleted {
+		for subscription := range stream.subscribers {
+			h.drop(stream, subscription)
+		}
+		h.forget(event.VideoID, stream)
+	} else if len(stream.subscribers) == 0 {
+		h.idle(event.VideoID, stream)
+	}
+	return nil
+}
+
+// Subscribe starts following a video of workspaceID. With resume set, the
+// events after lastEventID are returned first, or a single EventReset message
+// when some of them are no longer kept.
+func (h *Hub) Subscribe(videoID int, workspaceID int, lastEventID uint64, resume bool) (*Subscription, []*Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c := make(chan *Message, subscriberBuffer)
+	sub := &Subscription{C: c, c: c, hub: h, videoID: videoID}
+	if h.closed {
+		sub.once.Do(func() { close(c) })
+		return sub, nil
+	}
+	stream := h.stream(videoID)
+	stream.workspaceID = workspaceID
+	stream.subscribers[sub] = struct{}{}
+	if stream.idle != nil {
+		stream.idle.Stop()
+		stream.idle = nil
+	}
+
+	if !resume || lastEventID == stream.lastID {
+		return sub, nil
+	}
+
+	missed := stream.lastID - lastEventID
+	if lastEventID > stream.lastID || missed > uint64(len(stream.backlog)) {
+		return sub, []*Message{{ID: stream.lastID, Type: EventReset, Data: []byte("{}")}}
+	}
+	return sub, append([]*Message{}, stream.backlog[uint64(len(stream.backlog))-missed:]...)
+}
+
+// Close ends every subscription and the ones made afterwards, so open
+// streams finish when the server shuts down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for videoID, stream := range h.videos {
+		for subscription := range stream.subscribers {
+			h.drop(stream, subscription)
+		}
+		h.forget(videoID, stream)
+	}
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if stream, ok := s.hub.videos[s.videoID]; ok {
+		s.hub.drop(stream, s)
+	}
+}
+
+// moved ends the subscriptions of the video when it is no longer in the
+// workspace they were made for.
+func (h *Hub) moved(videoID int, workspaceID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream, ok := h.videos[videoID]
+	if !ok || workspaceID == 0 || stream.workspaceID == workspaceID {
+		return
+	}
+	stream.workspaceID = workspaceID
+	for subscription := range stream.subscribers {
+		h.drop(stream, subscription)
+	}
+}
+
+func (h *Hub) stream(videoID int) *videoStream {
+	stream, ok := h.videos[videoID]
+	if !ok {
+		stream = &videoStream{lastID: h.firstID, subscribers: map[*Subscription]struct{}{}}
+		h.videos[videoID] = stream
+	}
+	return stream
+}
+
+func (h *Hub) drop(stream *videoStream, subscription *Subscription) {
+	delete(stream.subscribers, subscription)
+	subscription.once.Do(func() { close(subscription.c) })
+
+	if len(stream.subscribers) == 0 {
+		h.idle(subscription.videoID, stream)
+	}
+}
+
+// idle forgets the stream after idleRetention unless it is subscribed to
+// again by then.
+func (h *Hub) idle(videoID int, stream *videoStream) {
+	if h.idleRetention <= 0 {
+		h.forget(videoID, stream)
+		return
+	}
+	if stream.idle != nil {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(h.idleRetention, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if stream.idle == timer {
+			h.forget(videoID, stream)
+		}
+	})
+	stream.idle = timer
+}
+
+func (h *Hub) forget(videoID int, stream *videoStream) {
+	if stream.idle != nil {
+		stream.idle.Stop()
+		stream.idle = nil
+	}
+	if h.videos[videoID] == stream {
+		delete(h.videos, videoID)
+		h.firstID = max(h.firstID, stream.lastID+1)
+	}
+}
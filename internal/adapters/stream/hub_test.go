@@ -0,0 +1,200 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_Publish_DeliversAnnotationEvents(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	sub, backlog := hub.Subscribe(1, 1, 0, false)
+	defer sub.Close()
+	other, _ := hub.Subscribe(2, 1, 0, false)
+	defer other.Close()
+
+	// test
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationCreated, 1)))
+	require.NoError(t, hub.Publish(context.Background(), &model.Event{Type: model.EventVideoUpdated, VideoID: 1}))
+
+	// assert
+	require.Empty(t, backlog)
+	message := <-sub.C
+	require.Equal(t, uint64(1), message.ID)
+	require.Equal(t, model.EventAnnotationCreated, message.Type)
+
+	payload := map[string]any{}
+	require.NoError(t, json.Unmarshal(message.Data, &payload))
+	require.Equal(t, "note", payload["annotation"].(map[string]any)["type"])
+
+	require.Empty(t, sub.C)
+	require.Empty(t, other.C)
+}
+
+func TestHub_Subscribe_ResumesFromLastEventID(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	for range 3 {
+		require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+	}
+
+	// test
+	sub, backlog := hub.Subscribe(1, 1, 1, true)
+	defer sub.Close()
+
+	// assert
+	require.Len(t, backlog, 2)
+	require.Equal(t, uint64(2), backlog[0].ID)
+	require.Equal(t, uint64(3), backlog[1].ID)
+}
+
+func TestHub_Subscribe_ResetsWhenEventsWereLost(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	hub.backlogSize = 2
+	for range 5 {
+		require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+	}
+
+	// test
+	sub, backlog := hub.Subscribe(1, 1, 1, true)
+	defer sub.Close()
+	ahead, aheadBacklog := hub.Subscribe(1, 1, 42, true)
+	defer ahead.Close()
+
+	// assert
+	require.Equal(t, []*Message{{ID: 5, Type: EventReset, Data: []byte("{}")}}, backlog)
+	require.Equal(t, EventReset, aheadBacklog[0].Type)
+}
+
+func TestHub_Publish_DropsSlowSubscribers(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	sub, _ := hub.Subscribe(1, 1, 0, false)
+
+	// test
+	for range subscriberBuffer + 1 {
+		require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+	}
+
+	// assert
+	received := 0
+	for range sub.C {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+	sub.Close()
+}
+
+func TestHub_Publish_VideoDeletedClosesSubscriptions(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	sub, _ := hub.Subscribe(1, 1, 0, false)
+
+	// test
+	require.NoError(t, hub.Publish(context.Background(), &model.Event{Type: model.EventVideoDeleted, VideoID: 1, Video: &model.Video{ID: 1}}))
+
+	// assert
+	message, ok := <-sub.C
+	require.True(t, ok)
+	require.Equal(t, model.EventVideoDeleted, message.Type)
+	_, ok = <-sub.C
+	require.False(t, ok)
+	require.Zero(t, videoCount(hub))
+}
+
+func TestHub_Publish_VideoMovedClosesSubscriptions(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	sub, _ := hub.Subscribe(1, 1, 0, false)
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+
+	// test
+	editErr := hub.Publish(context.Background(), &model.Event{Type: model.EventVideoUpdated, WorkspaceID: 1, VideoID: 1})
+	edited := len(sub.C)
+	moveErr := hub.Publish(context.Background(), &model.Event{Type: model.EventVideoUpdated, WorkspaceID: 2, VideoID: 1})
+
+	// assert
+	require.NoError(t, editErr)
+	require.Equal(t, 1, edited)
+	require.NoError(t, moveErr)
+	message, ok := <-sub.C
+	require.True(t, ok)
+	require.Equal(t, model.EventAnnotationUpdated, message.Type)
+	_, ok = <-sub.C
+	require.False(t, ok)
+}
+
+func TestHub_ForgetsVideosWithoutSubscribers(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	hub.idleRetention = 0
+	sub, _ := hub.Subscribe(1, 1, 0, false)
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+
+	// test
+	sub.Close()
+	forgotten := videoCount(hub)
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 2)))
+	resumed, backlog := hub.Subscribe(1, 1, 1, true)
+	defer resumed.Close()
+
+	// assert
+	require.Zero(t, forgotten)
+	require.Equal(t, EventReset, backlog[0].Type)
+	require.Equal(t, 1, videoCount(hub))
+}
+
+func TestHub_ForgetsIdleVideosAfterRetention(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	hub.idleRetention = 50 * time.Millisecond
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 1)))
+	require.NoError(t, hub.Publish(context.Background(), annotationEvent(model.EventAnnotationUpdated, 2)))
+
+	// test
+	sub, backlog := hub.Subscribe(2, 1, 0, true)
+	defer sub.Close()
+
+	// assert
+	require.Len(t, backlog, 1)
+	require.Eventually(t, func() bool { return videoCount(hub) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(2 * hub.idleRetention)
+	require.Equal(t, 1, videoCount(hub))
+}
+
+func TestHub_Close_EndsSubscriptions(t *testing.T) {
+	// fixture
+	hub := NewHub()
+	sub, _ := hub.Subscribe(1, 1, 0, false)
+
+	// test
+	hub.Close()
+	late, _ := hub.Subscribe(2, 1, 0, false)
+	late.Close()
+
+	// assert
+	_, ok := <-sub.C
+	require.False(t, ok)
+	_, ok = <-late.C
+	require.False(t, ok)
+}
+
+func videoCount(hub *Hub) int {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return len(hub.videos)
+}
+
+func annotationEvent(eventType string, videoID int) *model.Event {
+	return &model.Event{
+		Type:       eventType,
+		VideoID:    videoID,
+		Annotation: &model.Annotation{ID: 1, VideoID: videoID, Type: "note", Note: "note"},
+	}
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var ErrAddressNotAllowed = errors.New("address is not public")
+
+// newClient checks the address of every connection once it is resolved, so
+// neither a hostname nor a DNS answer changed after the subscription was
+// validated reaches an internal service. Redirects are not followed, the
+// subscriber gets the 3xx as a failed attempt.
+func newClient(allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allow(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: tracing.NewTransport(transport),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header value for a payload sent at the given
+// time: "t=<unix seconds>,v1=<hex hmac-sha256 of "<t>.<payload>">". Including
+// the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac(secret, t, payload)))
+}
+
+// Verify checks a signature header produced by Sign, receivers can use it as
+// a reference implementation.
+func Verify(secret, header string, payload []byte) bool {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	signature, err := hex.DecodeString(v1)
+	if err != nil || t == "" {
+		return false
+	}
+	return hmac.Equal(signature, mac(secret, t, payload))
+}
+
+func mac(secret, timestamp string, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
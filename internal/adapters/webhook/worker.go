@@ -0,0 +1,201 @@
+// Package webhook delivers queued webhook events to their subscribers.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var tracer = tracing.Tracer("internal/adapters/webhook")
+
+const (
+	defaultPollInterval   = 5 * time.Second
+	defaultInitialBackoff = 10 * time.Second
+	defaultMaxBackoff     = time.Hour
+	defaultMaxAttempts    = 8
+	defaultBatchSize      = 50
+	requestTimeout        = 10 * time.Second
+	maxErrorLength        = 512
+	maxDrainLength        = 64 << 10
+)
+
+// Worker polls for due deliveries and posts them to the subscriber. Failed
+// deliveries are retried with exponential backoff until MaxAttempts is
+// reached, every attempt is recorded.
+type Worker struct {
+	repo    ports.WebhookRepository
+	client  *http.Client
+	now     func() time.Time
+	running atomic.Bool
+
+	PollInterval   time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxAttempts    int
+}
+
+func NewWorker(repo ports.WebhookRepository) *Worker {
+	return &Worker{
+		repo:           repo,
+		client:         newClient(validation.PublicAddress),
+		now:            time.Now,
+		PollInterval:   defaultPollInterval,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		MaxAttempts:    defaultMaxAttempts,
+	}
+}
+
+// Running reports whether Run is looping, readiness fails when the worker
+// stopped.
+func (w *Worker) Running() bool {
+	return w.running.Load()
+}
+
+// Run delivers due events until the context is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	w.running.Store(true)
+	defer w.running.Store(false)
+
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.DeliverDue(ctx); err != nil {
+			slog.Error("delivering webhooks failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue makes one attempt for every delivery that is due.
+func (w *Worker) DeliverDue(ctx context.Context) error {
+	deliveries, err := w.repo.FindDueDeliveries(ctx, w.now().UTC(), defaultBatchSize)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := map[int]*model.WebhookSubscription{}
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			// A removed subscription has nothing left to deliver to.
+			if subscription, err = w.repo.FindSubscription(ctx, delivery.SubscriptionID); err != nil {
+				subscription = nil
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if err := w.deliver(ctx, subscription, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) deliver(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (err error) {
+	ctx, span := tracer.Start(ctx, "Worker.deliver")
+	span.SetAttributes(
+		attribute.Int("webhook.delivery.id", delivery.ID),
+		attribute.String("event.id", delivery.EventID),
+		attribute.String("event.type", delivery.EventType),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	attempt := &model.WebhookDeliveryAttempt{
+		DeliveryID:  delivery.ID,
+		Attempt:     delivery.Attempts + 1,
+		AttemptedAt: w.now().UTC(),
+	}
+	delivery.Attempts = attempt.Attempt
+
+	if subscription == nil {
+		attempt.Error = "subscription no longer exists"
+		delivery.Status = model.DeliveryFailed
+		return w.repo.RecordAttempt(ctx, delivery, attempt)
+	}
+
+	attempt.ResponseCode, attempt.Error = w.post(ctx, subscription, delivery)
+	attempt.Duration = w.now().UTC().Sub(attempt.AttemptedAt)
+
+	switch {
+	case attempt.Error == "":
+		deliveredAt := w.now().UTC()
+		delivery.Status = model.DeliverySucceeded
+		delivery.DeliveredAt = &deliveredAt
+	case delivery.Attempts >= w.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+	default:
+		delivery.NextAttemptAt = attempt.AttemptedAt.Add(w.backoff(delivery.Attempts))
+	}
+
+	if attempt.Error != "" {
+		span.SetAttributes(attribute.String("webhook.delivery.error", attempt.Error))
+	}
+	return w.repo.RecordAttempt(ctx, delivery, attempt)
+}
+
+// post sends the delivery and returns the response code and, unless the
+// subscriber answered with a 2xx, what went wrong. The response body is never
+// kept, subscribers read the attempts back.
+func (w *Worker) post(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, string) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "go-videos-api-webhooks")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, delivery.EventID)
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, w.now(), delivery.Payload))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, truncate(err.Error())
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainLength))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Sprintf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, ""
+}
+
+// backoff doubles the wait after every failed attempt, capped at MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	wait := w.InitialBackoff
+	for i := 1; i < attempts && wait < w.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, w.MaxBackoff)
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
@@ -0,0 +1,330 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const testSecret = "0123456789abcdef"
+
+func TestWorker_DeliverDue_HappyPath(t *testing.T) {
+	// fixture
+	received := make(chan *http.Request, 1)
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := newFakeWebhookRepository(receiver.URL)
+	worker, _ := newTestWorker(repo)
+
+	// test
+	err := worker.DeliverDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	request := <-received
+	require.Equal(t, model.EventVideoCreated, request.Header.Get(EventHeader))
+	require.Equal(t, "evt-1", request.Header.Get(DeliveryHeader))
+	require.True(t, Verify(testSecret, request.Header.Get(SignatureHeader), body))
+	require.JSONEq(t, `{"id":"evt-1"}`, string(body))
+
+	delivery := repo.deliveries[0]
+	require.Equal(t, model.DeliverySucceeded, delivery.Status)
+	require.NotNil(t, delivery.DeliveredAt)
+	require.Len(t, repo.attempts, 1)
+	require.Equal(t, http.StatusNoContent, repo.attempts[0].ResponseCode)
+	require.Empty(t, repo.attempts[0].Error)
+}
+
+func TestWorker_DeliverDue_PropagatesTraceContext(t *testing.T) {
+	// fixture
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	worker, _ := newTestWorker(newFakeWebhookRepository(receiver.URL))
+
+	// test
+	err := worker.DeliverDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	request := <-received
+	require.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, request.Header.Get("traceparent"))
+}
+
+func TestWorker_DeliverDue_RetriesWithBackoff(t *testing.T) {
+	// fixture
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := newFakeWebhookRepository(receiver.URL)
+	worker, clock := newTestWorker(repo)
+	start := *clock
+
+	// test & assert
+	require.NoError(t, worker.DeliverDue(context.Background()))
+	delivery := repo.deliveries[0]
+	require.Equal(t, model.DeliveryPending, delivery.Status)
+	require.Equal(t, start.Add(time.Second), delivery.NextAttemptAt)
+	require.Equal(t, "unexpected status 503", repo.attempts[0].Error)
+
+	// nothing is due before the backoff elapsed
+	require.NoError(t, worker.DeliverDue(context.Background()))
+	require.Len(t, repo.attempts, 1)
+
+	*clock = clock.Add(time.Second)
+	require.NoError(t, worker.DeliverDue(context.Background()))
+	require.Equal(t, clock.Add(2*time.Second), delivery.NextAttemptAt)
+
+	*clock = clock.Add(2 * time.Second)
+	require.NoError(t, worker.DeliverDue(context.Background()))
+	require.Equal(t, model.DeliverySucceeded, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Len(t, repo.attempts, 3)
+}
+
+func TestWorker_DeliverDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	// fixture
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := newFakeWebhookRepository(receiver.URL)
+	worker, clock := newTestWorker(repo)
+	worker.MaxAttempts = 2
+
+	// test
+	require.NoError(t, worker.DeliverDue(context.Background()))
+	*clock = clock.Add(time.Minute)
+	require.NoError(t, worker.DeliverDue(context.Background()))
+
+	// assert
+	require.Equal(t, model.DeliveryFailed, repo.deliveries[0].Status)
+	require.Len(t, repo.attempts, 2)
+	require.Equal(t, http.StatusInternalServerError, repo.attempts[1].ResponseCode)
+}
+
+func TestWorker_DeliverDue_SubscriptionRemoved(t *testing.T) {
+	// fixture
+	repo := newFakeWebhookRepository("http://127.0.0.1:0")
+	delete(repo.subscriptions, 1)
+	worker, _ := newTestWorker(repo)
+
+	// test
+	err := worker.DeliverDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, model.DeliveryFailed, repo.deliveries[0].Status)
+	require.Equal(t, "subscription no longer exists", repo.attempts[0].Error)
+}
+
+func TestWorker_Run_ReportsRunning(t *testing.T) {
+	// fixture
+	repo := newFakeWebhookRepository("http://127.0.0.1:0")
+	delete(repo.subscriptions, 1)
+	worker, _ := newTestWorker(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// test
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, worker.Running, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// assert
+	require.False(t, worker.Running())
+}
+
+func TestWorker_DeliverDue_RefusesInternalAddress(t *testing.T) {
+	// fixture
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer receiver.Close()
+
+	repo := newFakeWebhookRepository(strings.Replace(receiver.URL, "127.0.0.1", "localhost", 1))
+	worker := NewWorker(repo)
+
+	// test
+	err := worker.DeliverDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Zero(t, calls.Load())
+	require.Contains(t, repo.attempts[0].Error, ErrAddressNotAllowed.Error())
+	require.Equal(t, model.DeliveryPending, repo.deliveries[0].Status)
+}
+
+func TestWorker_DeliverDue_DoesNotFollowRedirects(t *testing.T) {
+	// fixture
+	var redirected atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Add(1)
+	}))
+	defer target.Close()
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	repo := newFakeWebhookRepository(receiver.URL)
+	worker, _ := newTestWorker(repo)
+
+	// test
+	err := worker.DeliverDue(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Zero(t, redirected.Load())
+	require.Equal(t, http.StatusTemporaryRedirect, repo.attempts[0].ResponseCode)
+	require.Equal(t, "unexpected status 307", repo.attempts[0].Error)
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	// fixture
+	worker := NewWorker(nil)
+
+	// assert
+	require.Equal(t, 10*time.Second, worker.backoff(1))
+	require.Equal(t, 20*time.Second, worker.backoff(2))
+	require.Equal(t, 80*time.Second, worker.backoff(4))
+	require.Equal(t, time.Hour, worker.backoff(20))
+}
+
+func TestSign_Verify(t *testing.T) {
+	// fixture
+	payload := []byte(`{"id":"evt-1"}`)
+	header := Sign(testSecret, time.Unix(1700000000, 0), payload)
+
+	// assert
+	require.Equal(t, "t=1700000000", header[:12])
+	require.True(t, Verify(testSecret, header, payload))
+	require.False(t, Verify("another secret!!", header, payload))
+	require.False(t, Verify(testSecret, header, []byte(`{"id":"evt-2"}`)))
+	require.False(t, Verify(testSecret, "v1=zz", payload))
+}
+
+func newTestWorker(repo *fakeWebhookRepository) (*Worker, *time.Time) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	worker := NewWorker(repo)
+	// the receivers of the tests listen on the loopback interface
+	worker.client = newClient(func(netip.Addr) bool { return true })
+	worker.now = func() time.Time { return clock }
+	worker.InitialBackoff = time.Second
+	return worker, &clock
+}
+
+type fakeWebhookRepository struct {
+	subscriptions map[int]*model.WebhookSubscription
+	deliveries    []*model.WebhookDelivery
+	attempts      []*model.WebhookDeliveryAttempt
+}
+
+func newFakeWebhookRepository(url string) *fakeWebhookRepository {
+	return &fakeWebhookRepository{
+		subscriptions: map[int]*model.WebhookSubscription{
+			1: {ID: 1, UserID: 1, URL: url, Secret: testSecret},
+		},
+		deliveries: []*model.WebhookDelivery{{
+			ID:             1,
+			SubscriptionID: 1,
+			EventID:        "evt-1",
+			EventType:      model.EventVideoCreated,
+			Payload:        []byte(`{"id":"evt-1"}`),
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func (r *fakeWebhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeWebhookRepository) FindSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, repository.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+func (r *fakeWebhookRepository) FindSubscriptionsByUser(ctx context.Context, userId int) ([]*model.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) FindSubscriptionsByWorkspace(ctx context.Context, workspaceId int) ([]*model.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) RemoveSubscription(ctx context.Context, id, userId int) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	due := []*model.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepository) FindDeliveries(ctx context.Context, subscriptionId int, limit int) ([]*model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) FindAttempts(ctx context.Context, deliveryIds []int) ([]*model.WebhookDeliveryAttempt, error) {
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
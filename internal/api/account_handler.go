@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type AccountHandler struct {
+	accountService ports.AccountService
+	authService    auth.AuthService
+}
+
+func NewAccountHandler(accountService ports.AccountService, authService auth.AuthService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		authService:    authService,
+	}
+}
+
+// RequestVerificationHandler mails a new verification link to the logged in
+// user.
+func (h *AccountHandler) RequestVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	if err := h.accountService.SendVerification(r.Context(), username); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *AccountHandler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	verifyDto := &VerifyEmailDto{}
+	if err := json.NewDecoder(r.Body).Decode(verifyDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.accountService.VerifyEmail(ctx, verifyDto.Token); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestPasswordResetHandler answers the same whether the email belongs to
+// a user or not, so it cannot be used to find out who has an account.
+func (h *AccountHandler) RequestPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	emailDto := &EmailDto{}
+	if err := json.NewDecoder(r.Body).Decode(emailDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.accountService.RequestPasswordReset(ctx, emailDto.Email); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *AccountHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	resetDto := &PasswordResetDto{}
+	if err := json.NewDecoder(r.Body).Decode(resetDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.accountService.ResetPassword(ctx, resetDto.Token, resetDto.Password); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
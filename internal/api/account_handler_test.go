@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+func TestAccountHandler_RequestVerificationHandler(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewAccountHandler(accountServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	accountServiceMock.On("SendVerification", "test-user").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RequestVerificationHandler(rr, mfaRequest(t, "/verify-email/request", token, nil))
+
+	// Verify
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	accountServiceMock.AssertExpectations(t)
+}
+
+func TestAccountHandler_RequestVerificationHandler_AlreadyVerified(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewAccountHandler(accountServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	accountServiceMock.On("SendVerification", "test-user").Return(service.ErrEmailAlreadyVerified)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RequestVerificationHandler(rr, mfaRequest(t, "/verify-email/request", token, nil))
+
+	// Verify
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestAccountHandler_VerifyEmailHandler(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	handler := NewAccountHandler(accountServiceMock, new(AuthService))
+
+	accountServiceMock.On("VerifyEmail", "link-token").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.VerifyEmailHandler(rr, mfaRequest(t, "/verify-email", "", &VerifyEmailDto{Token: "link-token"}))
+
+	// Verify
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	accountServiceMock.AssertExpectations(t)
+}
+
+func TestAccountHandler_VerifyEmailHandler_InvalidLink(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	handler := NewAccountHandler(accountServiceMock, new(AuthService))
+
+	accountServiceMock.On("VerifyEmail", "used-token").Return(service.ErrUserTokenInvalid)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.VerifyEmailHandler(rr, mfaRequest(t, "/verify-email", "", &VerifyEmailDto{Token: "used-token"}))
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeInvalidLink)
+}
+
+func TestAccountHandler_RequestPasswordResetHandler(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	handler := NewAccountHandler(accountServiceMock, new(AuthService))
+
+	accountServiceMock.On("RequestPasswordReset", "johndoe@example.com").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RequestPasswordResetHandler(rr, mfaRequest(t, "/reset-password/request", "", &EmailDto{Email: "johndoe@example.com"}))
+
+	// Verify
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	accountServiceMock.AssertExpectations(t)
+}
+
+func TestAccountHandler_ResetPasswordHandler(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	handler := NewAccountHandler(accountServiceMock, new(AuthService))
+
+	accountServiceMock.On("ResetPassword", "link-token", "new-password").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ResetPasswordHandler(rr, mfaRequest(t, "/reset-password", "", &PasswordResetDto{Token: "link-token", Password: "new-password"}))
+
+	// Verify
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	accountServiceMock.AssertExpectations(t)
+}
+
+func TestAccountHandler_ResetPasswordHandler_InvalidPassword(t *testing.T) {
+	// Setup
+	accountServiceMock := new(AccountServiceMock)
+	handler := NewAccountHandler(accountServiceMock, new(AuthService))
+
+	accountServiceMock.On("ResetPassword", "link-token", "").Return(validation.ErrPasswordIsInvalid)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ResetPasswordHandler(rr, mfaRequest(t, "/reset-password", "", &PasswordResetDto{Token: "link-token"}))
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"password"`)
+}
+
+type AccountServiceMock struct {
+	mock.Mock
+}
+
+func (s *AccountServiceMock) SendVerification(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) VerifyEmail(ctx context.Context, token string) error {
+	args := s.Called(token)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) RequestPasswordReset(ctx context.Context, email string) error {
+	args := s.Called(email)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) ResetPassword(ctx context.Context, token string, password string) error {
+	args := s.Called(token, password)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) AuthorizeWrite(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
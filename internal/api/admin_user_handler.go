@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type AdminUserHandler struct {
+	userService ports.UserService
+	authService auth.AuthService
+}
+
+func NewAdminUserHandler(userService ports.UserService, authService auth.AuthService) *AdminUserHandler {
+	return &AdminUserHandler{
+		userService: userService,
+		authService: authService,
+	}
+}
+
+func (h *AdminUserHandler) UnlockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	if err := authorizeAdmin(r, h.authService, h.userService); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	if err := h.userService.Unlock(r.Context(), mux.Vars(r)["username"]); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
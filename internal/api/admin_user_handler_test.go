@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func unlockRequest(t *testing.T, token string, username string) *http.Request {
+	req, err := http.NewRequest("POST", "/admin/users/"+username+"/unlock", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	return mux.SetURLVars(req, map[string]string{"username": username})
+}
+
+func TestAdminUserHandler_UnlockHandler(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewAdminUserHandler(&mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "admin-user")
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.UnlockHandler(rr, unlockRequest(t, token, "johndoe"))
+
+	// Verify
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestAdminUserHandler_UnlockHandler_UnknownUser(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewAdminUserHandler(&mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "admin-user")
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.UnlockHandler(rr, unlockRequest(t, token, "unknown-user"))
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAdminUserHandler_UnlockHandler_NotAdmin(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewAdminUserHandler(&mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.UnlockHandler(rr, unlockRequest(t, token, "johndoe"))
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type AnnotationHandler struct {
+	annotationService ports.AnnotationService
+	authService       auth.AuthService
+}
+
+func NewAnnotationHandler(service ports.AnnotationService, authService auth.AuthService) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotationService: service,
+		authService:       authService,
+	}
+}
+
+func (h *AnnotationHandler) RevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	annotationId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	revisions, err := h.annotationService.Revisions(r.Context(), username, annotationId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+func (h *AnnotationHandler) DiffHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	annotationId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	changes, err := h.annotationService.Diff(r.Context(), username, annotationId, from, to)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+func (h *AnnotationHandler) RevertHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	annotationId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+	revision, err := strconv.Atoi(vars["revision"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	written, err := h.annotationService.Revert(r.Context(), username, annotationId, revision)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(written)
+}
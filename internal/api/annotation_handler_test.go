@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestAnnotationHandler_RevisionsHandler(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	revisions := []*model.AnnotationRevision{
+		{AnnotationID: 1, Revision: 1, StartTime: 10, EndTime: 20, Type: "test-type", Note: "first"},
+		{AnnotationID: 1, Revision: 2, StartTime: 10, EndTime: 25, Type: "test-type", Note: "second"},
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	annotationServiceMock.On("Revisions", "test-user", 1).Return(revisions, nil)
+
+	req, err := http.NewRequest("GET", "/annotations/1/revisions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RevisionsHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []*model.AnnotationRevision
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, revisions, response)
+	assert.Contains(t, rr.Body.String(), `"annotation_id":1`)
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestAnnotationHandler_RevisionsHandler_AnnotationNotFound(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	annotationServiceMock.On("Revisions", "test-user", 1).Return(nil, service.ErrAnnotationNotFound)
+
+	req, err := http.NewRequest("GET", "/annotations/1/revisions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RevisionsHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestAnnotationHandler_DiffHandler(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	changes := []*model.AnnotationFieldChange{
+		{Field: "note", From: "first", To: "second"},
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	annotationServiceMock.On("Diff", "test-user", 1, 1, 2).Return(changes, nil)
+
+	req, err := http.NewRequest("GET", "/annotations/1/revisions/diff?from=1&to=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DiffHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"field":"note","from":"first","to":"second"}]`, rr.Body.String())
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestAnnotationHandler_DiffHandler_InvalidRevision(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	req, err := http.NewRequest("GET", "/annotations/1/revisions/diff?from=a&to=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DiffHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestAnnotationHandler_RevertHandler(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	written := &model.AnnotationRevision{ID: 3, AnnotationID: 1, Revision: 3, StartTime: 10, EndTime: 25, Type: "test-type", Note: "second"}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	annotationServiceMock.On("Revert", "test-user", 1, 1).Return(written, nil)
+
+	req, err := http.NewRequest("POST", "/annotations/1/revisions/1/revert", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "revision": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RevertHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestAnnotationHandler_RevertHandler_RevisionNotFound(t *testing.T) {
+	// Setup
+	annotationServiceMock := new(AnnotationServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewAnnotationHandler(annotationServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	annotationServiceMock.On("Revert", "test-user", 1, 9).Return(nil, service.ErrRevisionNotFound)
+
+	req, err := http.NewRequest("POST", "/annotations/1/revisions/9/revert", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "revision": "9"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RevertHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	annotationServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+type AnnotationServiceMock struct {
+	mock.Mock
+}
+
+func (s *AnnotationServiceMock) Revisions(ctx context.Context, username string, annotationId int) ([]*model.AnnotationRevision, error) {
+	args := s.Called(username, annotationId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AnnotationRevision), args.Error(1)
+}
+
+func (s *AnnotationServiceMock) Diff(ctx context.Context, username string, annotationId, from, to int) ([]*model.AnnotationFieldChange, error) {
+	args := s.Called(username, annotationId, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AnnotationFieldChange), args.Error(1)
+}
+
+func (s *AnnotationServiceMock) Revert(ctx context.Context, username string, annotationId, revision int) (*model.AnnotationRevision, error) {
+	args := s.Called(username, annotationId, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AnnotationRevision), args.Error(1)
+}
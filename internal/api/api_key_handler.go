@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type ApiKeyHandler struct {
+	apiKeyService ports.ApiKeyService
+	authService   auth.AuthService
+}
+
+func NewApiKeyHandler(service ports.ApiKeyService, authService auth.AuthService) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		apiKeyService: service,
+		authService:   authService,
+	}
+}
+
+func (h *ApiKeyHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	nameDto := &ApiKeyNameDto{}
+	if err := json.NewDecoder(r.Body).Decode(nameDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	key, apiKey, err := h.apiKeyService.Create(r.Context(), username, nameDto.Name)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newApiKeyDto(key, apiKey))
+}
+
+func (h *ApiKeyHandler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	keys, err := h.apiKeyService.Keys(r.Context(), username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*ApiKeyDto{}
+	for _, key := range keys {
+		dtos = append(dtos, newApiKeyDto(key, ""))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (h *ApiKeyHandler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(r.Context(), username, mux.Vars(r)["id"]); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
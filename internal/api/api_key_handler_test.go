@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+func TestApiKeyHandler_CreateHandler(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewApiKeyHandler(apiKeyServiceMock, authServiceMock)
+
+	token := "test-token"
+	createdAt := time.Now().UTC()
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	apiKeyServiceMock.On("Create", "test-user", "ci").
+		Return(&model.ApiKey{ID: "key-id", UserID: 1, Name: "ci", Hash: "hash", CreatedAt: createdAt}, "vak_key-id_secret", nil)
+
+	req, err := http.NewRequest("POST", "/account/api-keys", bytes.NewReader([]byte(`{"name":"ci"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response ApiKeyDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "key-id", response.ID)
+	assert.Equal(t, "vak_key-id_secret", response.Key)
+	assert.NotContains(t, rr.Body.String(), "hash")
+	apiKeyServiceMock.AssertExpectations(t)
+}
+
+func TestApiKeyHandler_CreateHandler_ValidationFailed(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewApiKeyHandler(apiKeyServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	apiKeyServiceMock.On("Create", "test-user", "").Return(nil, "", validation.ErrApiKeyNameIsInvalid)
+
+	req, err := http.NewRequest("POST", "/account/api-keys", bytes.NewReader([]byte(`{}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	apiKeyServiceMock.AssertExpectations(t)
+}
+
+func TestApiKeyHandler_ListHandler_HidesKey(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewApiKeyHandler(apiKeyServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	apiKeyServiceMock.On("Keys", "test-user").Return([]*model.ApiKey{
+		{ID: "key-id", UserID: 1, Name: "ci", Hash: "very-secret-hash"},
+	}, nil)
+
+	req, err := http.NewRequest("GET", "/account/api-keys", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ListHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"name":"ci"`)
+	assert.NotContains(t, rr.Body.String(), "very-secret-hash")
+	assert.NotContains(t, rr.Body.String(), `"key"`)
+	apiKeyServiceMock.AssertExpectations(t)
+}
+
+func TestApiKeyHandler_DeleteHandler_NotFound(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewApiKeyHandler(apiKeyServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	apiKeyServiceMock.On("Revoke", "test-user", "key-id").Return(service.ErrApiKeyNotFound)
+
+	req, err := http.NewRequest("DELETE", "/account/api-keys/key-id", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "key-id"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	apiKeyServiceMock.AssertExpectations(t)
+}
+
+type ApiKeyServiceMock struct {
+	mock.Mock
+}
+
+func (s *ApiKeyServiceMock) Create(ctx context.Context, username string, name string) (*model.ApiKey, string, error) {
+	args := s.Called(username, name)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*model.ApiKey), args.String(1), args.Error(2)
+}
+
+func (s *ApiKeyServiceMock) Keys(ctx context.Context, username string) ([]*model.ApiKey, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.ApiKey), args.Error(1)
+}
+
+func (s *ApiKeyServiceMock) Revoke(ctx context.Context, username string, id string) error {
+	args := s.Called(username, id)
+	return args.Error(0)
+}
+
+func (s *ApiKeyServiceMock) Authenticate(ctx context.Context, apiKey string) (*model.ApiKey, string, error) {
+	args := s.Called(apiKey)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*model.ApiKey), args.String(1), args.Error(2)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var ErrApiKeyNotAllowed = fmt.Errorf("%w: api keys only work on the video, annotation and webhook routes", ErrForbidden)
+
+// apiKeyRoutes are the only routes an API key opens. Keys are meant for
+// scripts, so they never reach the account, the key management or the admin
+// routes, where a leaked key could lock its user out or mint more keys.
+var apiKeyRoutes = []string{"/videos/", "/annotations/", "/webhooks/"}
+
+type apiKeyContextKey struct{}
+
+// apiKeyIdentity is the key a request was authenticated with.
+type apiKeyIdentity struct {
+	id       string
+	username string
+}
+
+// ApiKeyMiddleware resolves the API key sent in place of a token once per
+// request and puts it on the request context, where authenticate and the
+// rate limiter find it. An unknown, expired or revoked key leaves the request
+// anonymous, a key sent to any other route is refused.
+func ApiKeyMiddleware(apiKeyService ports.ApiKeyService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("Authorization")
+			if _, _, ok := auth.ParseApiKey(apiKey); !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !isApiKeyRoute(r.URL.Path) {
+				respondWithError(w, r, ErrApiKeyNotAllowed)
+				return
+			}
+
+			key, username, err := apiKeyService.Authenticate(r.Context(), apiKey)
+			if errors.Is(err, service.ErrApiKeyInvalid) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				respondWithError(w, r, err)
+				return
+			}
+
+			logging.With(r.Context(), "api_key", key.ID)
+			identity := &apiKeyIdentity{id: key.ID, username: username}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, identity)))
+		})
+	}
+}
+
+func isApiKeyRoute(path string) bool {
+	for _, prefix := range apiKeyRoutes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyFrom returns the API key the request was authenticated with, if any.
+func apiKeyFrom(ctx context.Context) (*apiKeyIdentity, bool) {
+	identity, ok := ctx.Value(apiKeyContextKey{}).(*apiKeyIdentity)
+	return identity, ok
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func newApiKeyRouter(apiKeyService *ApiKeyServiceMock, authService *AuthService) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(ApiKeyMiddleware(apiKeyService))
+	whoami := func(w http.ResponseWriter, r *http.Request) {
+		username, ok := authenticate(r, authService, r.Header.Get("Authorization"))
+		if !ok {
+			respondWithError(w, r, ErrUnauthorized)
+			return
+		}
+		w.Write([]byte(username))
+	}
+	router.HandleFunc("/videos/", whoami)
+	router.HandleFunc("/account/api-keys", whoami)
+	return router
+}
+
+func TestApiKeyMiddleware_ResolvesKeyOnApiRoutes(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+	router := newApiKeyRouter(apiKeyServiceMock, authServiceMock)
+
+	apiKey, id, _, err := auth.GenerateApiKey()
+	require.NoError(t, err)
+	apiKeyServiceMock.On("Authenticate", apiKey).Return(&model.ApiKey{ID: id}, "jane", nil)
+
+	req := httptest.NewRequest("GET", "/videos/", nil)
+	req.Header.Set("Authorization", apiKey)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "jane", rr.Body.String())
+	apiKeyServiceMock.AssertNumberOfCalls(t, "Authenticate", 1)
+	authServiceMock.AssertNotCalled(t, "ValidateJwtToken", apiKey)
+}
+
+func TestApiKeyMiddleware_RefusesKeyOnAccountRoutes(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	router := newApiKeyRouter(apiKeyServiceMock, new(AuthService))
+
+	apiKey, _, _, err := auth.GenerateApiKey()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/account/api-keys", nil)
+	req.Header.Set("Authorization", apiKey)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeForbidden)
+	apiKeyServiceMock.AssertNotCalled(t, "Authenticate", apiKey)
+}
+
+func TestApiKeyMiddleware_InvalidKeyIsUnauthorized(t *testing.T) {
+	// Setup
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	authServiceMock := new(AuthService)
+	router := newApiKeyRouter(apiKeyServiceMock, authServiceMock)
+
+	apiKey, _, _, err := auth.GenerateApiKey()
+	require.NoError(t, err)
+	apiKeyServiceMock.On("Authenticate", apiKey).Return(nil, "", service.ErrApiKeyInvalid)
+	authServiceMock.On("ValidateJwtToken", apiKey).Return(false, "")
+
+	req := httptest.NewRequest("GET", "/videos/", nil)
+	req.Header.Set("Authorization", apiKey)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
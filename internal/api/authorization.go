@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+// authorizeAdmin fails unless the token belongs to an enabled administrator.
+// The role is read from the user rather than the token, so a demoted or
+// disabled admin loses access right away.
+func authorizeAdmin(r *http.Request, authService auth.AuthService, userService ports.UserService) error {
+	username, ok := authenticate(r, authService, r.Header.Get("Authorization"))
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	user, err := userService.Find(r.Context(), username)
+	if errors.Is(err, repository.UserNotFoundError) {
+		return ErrUnauthorized
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
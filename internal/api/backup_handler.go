@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type BackupHandler struct {
+	backupService ports.BackupService
+	userService   ports.UserService
+	authService   auth.AuthService
+}
+
+func NewBackupHandler(backupService ports.BackupService, userService ports.UserService, authService auth.AuthService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+		userService:   userService,
+		authService:   authService,
+	}
+}
+
+func (h *BackupHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	if err := authorizeAdmin(r, h.authService, h.userService); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	backup, err := h.backupService.Create(r.Context())
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newBackupDto(backup))
+}
+
+func (h *BackupHandler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	if err := authorizeAdmin(r, h.authService, h.userService); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	backups, err := h.backupService.List(r.Context())
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*BackupDto{}
+	for _, backup := range backups {
+		dtos = append(dtos, newBackupDto(backup))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
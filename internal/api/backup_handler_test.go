@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestBackupHandler_CreateHandler(t *testing.T) {
+	// Setup
+	backupServiceMock := new(BackupServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewBackupHandler(backupServiceMock, &mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	createdAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "admin-user")
+	backupServiceMock.On("Create").Return(&model.Backup{
+		Name: "videos_20240102T150405Z.db.gz", Path: "/var/lib/videos/backups/videos_20240102T150405Z.db.gz",
+		Size: 2048, Compressed: true, CreatedAt: createdAt,
+	}, nil)
+
+	req, err := http.NewRequest("POST", "/admin/backups", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response BackupDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, BackupDto{Name: "videos_20240102T150405Z.db.gz", Size: 2048, Compressed: true, CreatedAt: createdAt}, response)
+	assert.NotContains(t, rr.Body.String(), "/var/lib/videos")
+	backupServiceMock.AssertExpectations(t)
+}
+
+func TestBackupHandler_ListHandler(t *testing.T) {
+	// Setup
+	backupServiceMock := new(BackupServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewBackupHandler(backupServiceMock, &mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "admin-user")
+	backupServiceMock.On("List").Return([]*model.Backup{
+		{Name: "videos_20240102T150405Z.db.gz"},
+		{Name: "videos_20240101T150405Z.db.gz"},
+	}, nil)
+
+	req, err := http.NewRequest("GET", "/admin/backups", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ListHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []BackupDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, "videos_20240102T150405Z.db.gz", response[0].Name)
+}
+
+func TestBackupHandler_CreateHandler_NotAdmin(t *testing.T) {
+	// Setup
+	backupServiceMock := new(BackupServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewBackupHandler(backupServiceMock, &mockUserService{}, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	req, err := http.NewRequest("POST", "/admin/backups", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	backupServiceMock.AssertNotCalled(t, "Create")
+}
+
+func TestBackupHandler_ListHandler_Unauthorized(t *testing.T) {
+	// Setup
+	backupServiceMock := new(BackupServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewBackupHandler(backupServiceMock, &mockUserService{}, authServiceMock)
+
+	authServiceMock.On("ValidateJwtToken", "").Return(false, "")
+
+	req, err := http.NewRequest("GET", "/admin/backups", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ListHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	backupServiceMock.AssertNotCalled(t, "List")
+}
+
+type BackupServiceMock struct {
+	mock.Mock
+}
+
+func (s *BackupServiceMock) Create(ctx context.Context) (*model.Backup, error) {
+	args := s.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Backup), args.Error(1)
+}
+
+func (s *BackupServiceMock) List(ctx context.Context) ([]*model.Backup, error) {
+	args := s.Called()
+	return args.Get(0).([]*model.Backup), args.Error(1)
+}
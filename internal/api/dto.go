@@ -1,6 +1,12 @@
 package api
 
-import "github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
 
 type UserDto struct {
 	Email    string `json:"email"`
@@ -11,3 +17,289 @@ type VideoDto struct {
 	model.Video
 	Annotaions []*model.Annotation
 }
+
+// VideoPatchDto carries a partial update; nil fields are left unchanged.
+type VideoPatchDto struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Link        *string `json:"link"`
+}
+
+func (d *VideoPatchDto) applyTo(video *model.Video) {
+	if d.Title != nil {
+		video.Title = *d.Title
+	}
+	if d.Description != nil {
+		video.Description = *d.Description
+	}
+	if d.Link != nil {
+		video.Link = *d.Link
+	}
+}
+
+type WebhookDto struct {
+	URL             string   `json:"url"`
+	Secret          string   `json:"secret,omitempty"`
+	EventTypes      []string `json:"event_types"`
+	AnnotationTypes []string `json:"annotation_types"`
+}
+
+// WebhookSubscriptionDto only carries the secret in the response to the
+// request that created the subscription.
+type WebhookSubscriptionDto struct {
+	ID              int       `json:"id"`
+	URL             string    `json:"url"`
+	Secret          string    `json:"secret,omitempty"`
+	EventTypes      []string  `json:"event_types"`
+	AnnotationTypes []string  `json:"annotation_types"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryDto struct {
+	ID            int                          `json:"id"`
+	EventID       string                       `json:"event_id"`
+	EventType     string                       `json:"event_type"`
+	Status        string                       `json:"status"`
+	Attempts      []*WebhookDeliveryAttemptDto `json:"attempts"`
+	NextAttemptAt *time.Time                   `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	DeliveredAt   *time.Time                   `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveryAttemptDto struct {
+	Attempt      int       `json:"attempt"`
+	ResponseCode int       `json:"response_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+func newWebhookSubscriptionDto(subscription *model.WebhookSubscription, withSecret bool) *WebhookSubscriptionDto {
+	dto := &WebhookSubscriptionDto{
+		ID:              subscription.ID,
+		URL:             subscription.URL,
+		EventTypes:      nonNil(subscription.EventTypes),
+		AnnotationTypes: nonNil(subscription.AnnotationTypes),
+		CreatedAt:       subscription.CreatedAt,
+	}
+	if withSecret {
+		dto.Secret = subscription.Secret
+	}
+	return dto
+}
+
+func newWebhookDeliveryDto(delivery *model.WebhookDelivery, attempts []*model.WebhookDeliveryAttempt) *WebhookDeliveryDto {
+	dto := &WebhookDeliveryDto{
+		ID:          delivery.ID,
+		EventID:     delivery.EventID,
+		EventType:   delivery.EventType,
+		Status:      delivery.Status,
+		Attempts:    []*WebhookDeliveryAttemptDto{},
+		CreatedAt:   delivery.CreatedAt,
+		DeliveredAt: delivery.DeliveredAt,
+	}
+	if delivery.Status == model.DeliveryPending {
+		dto.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	for _, attempt := range attempts {
+		dto.Attempts = append(dto.Attempts, &WebhookDeliveryAttemptDto{
+			Attempt:      attempt.Attempt,
+			ResponseCode: attempt.ResponseCode,
+			Error:        attempt.Error,
+			DurationMs:   attempt.Duration.Milliseconds(),
+			AttemptedAt:  attempt.AttemptedAt,
+		})
+	}
+	return dto
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// WorkspaceDto carries the role of the logged in user in the workspace.
+type WorkspaceDto struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newWorkspaceDto(workspace *model.Workspace) *WorkspaceDto {
+	return &WorkspaceDto{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      workspace.Role,
+		CreatedAt: workspace.CreatedAt,
+	}
+}
+
+type WorkspaceMemberDto struct {
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type WorkspaceNameDto struct {
+	Name string `json:"name"`
+}
+
+type InvitationDto struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type JoinDto struct {
+	Token string `json:"token"`
+}
+
+type RoleDto struct {
+	Role string `json:"role"`
+}
+
+type BackupDto struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	Compressed bool      `json:"compressed"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newBackupDto(backup *model.Backup) *BackupDto {
+	return &BackupDto{
+		Name:       backup.Name,
+		Size:       backup.Size,
+		Compressed: backup.Compressed,
+		CreatedAt:  backup.CreatedAt,
+	}
+}
+
+// MfaChallengeDto answers a login that still needs a second factor, the
+// challenge token goes to /login/mfa along with a code.
+type MfaChallengeDto struct {
+	MfaRequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type MfaLoginDto struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type MfaCodeDto struct {
+	Code string `json:"code"`
+}
+
+// MfaEnrollmentDto carries the QR code as a data URI of a PNG.
+type MfaEnrollmentDto struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"`
+}
+
+func newMfaEnrollmentDto(enrollment *model.MfaEnrollment) *MfaEnrollmentDto {
+	return &MfaEnrollmentDto{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+type RecoveryCodesDto struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type VerifyEmailDto struct {
+	Token string `json:"token"`
+}
+
+type EmailDto struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetDto struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ProfileDto is the account of the logged in user.
+type ProfileDto struct {
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	Role          string    `json:"role"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newProfileDto(user *model.User) *ProfileDto {
+	return &ProfileDto{
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+type UsernameDto struct {
+	Username string `json:"username"`
+}
+
+type PasswordChangeDto struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+type EmailChangeDto struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// AccountDeletionDto tells what happens to the videos of the account,
+// Videos is "transfer", to the user TransferTo, or "delete".
+type AccountDeletionDto struct {
+	Password   string `json:"password"`
+	Videos     string `json:"videos"`
+	TransferTo string `json:"transfer_to,omitempty"`
+}
+
+type HealthDto struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type JwksDto struct {
+	Keys []auth.JSONWebKey `json:"keys"`
+}
+
+type VersionDto struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+}
+
+type ApiKeyNameDto struct {
+	Name string `json:"name"`
+}
+
+// ApiKeyDto only carries the key in the response to the request that
+// created it.
+type ApiKeyDto struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newApiKeyDto(key *model.ApiKey, apiKey string) *ApiKeyDto {
+	return &ApiKeyDto{
+		ID:        key.ID,
+		Name:      key.Name,
+		Key:       apiKey,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+var (
+	ErrIfMatchMissing = fmt.Errorf("If-Match header is required")
+	ErrIfMatchInvalid = fmt.Errorf("If-Match header is invalid")
+)
+
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch holds the entity tags of an If-Match header, either * or a list of
+// versions such as "1", "2".
+type ifMatch struct {
+	any      bool
+	versions []int
+}
+
+// parseIfMatch reads the If-Match header. If-Match compares strongly and the
+// ETags are strong, so a weak validator (W/"3") never matches; a header with
+// nothing but weak ones fails like a stale version.
+func parseIfMatch(r *http.Request) (*ifMatch, error) {
+	value := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if value == "" {
+		return nil, ErrIfMatchMissing
+	}
+	if value == "*" {
+		return &ifMatch{any: true}, nil
+	}
+
+	match := &ifMatch{}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			return nil, ErrIfMatchInvalid
+		}
+		version, err := strconv.Atoi(unquoted)
+		if err != nil || version < 1 {
+			return nil, ErrIfMatchInvalid
+		}
+		match.versions = append(match.versions, version)
+	}
+
+	if len(match.versions) == 0 {
+		return nil, ports.ErrVersionConflict
+	}
+	return match, nil
+}
+
+// single returns the version of a header naming exactly one, the write checks
+// it without loading the current version first.
+func (m *ifMatch) single() (int, bool) {
+	if m.any || len(m.versions) != 1 {
+		return 0, false
+	}
+	return m.versions[0], true
+}
+
+// version is current when * or one of the tags matches it, otherwise the first
+// tag, which the write then rejects as a stale version.
+func (m *ifMatch) version(current int) int {
+	if m.any || slices.Contains(m.versions, current) {
+		return current
+	}
+	return m.versions[0]
+}
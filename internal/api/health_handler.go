@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	readiness *health.Readiness
+}
+
+func NewHealthHandler(readiness *health.Readiness) *HealthHandler {
+	return &HealthHandler{readiness: readiness}
+}
+
+// LivenessHandler only tells the process still answers, a failing dependency
+// must not get it restarted.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithHealth(w, http.StatusOK, &HealthDto{Status: "ok"})
+}
+
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	result := h.readiness.Check(ctx)
+	if !result.Ready {
+		respondWithHealth(w, http.StatusServiceUnavailable, &HealthDto{Status: "unavailable", Checks: result.Checks})
+		return
+	}
+	respondWithHealth(w, http.StatusOK, &HealthDto{Status: "ready", Checks: result.Checks})
+}
+
+func (h *HealthHandler) VersionHandler(w http.ResponseWriter, r *http.Request) {
+	build := health.BuildInfo()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&VersionDto{Version: build.Version, Commit: build.Commit, GoVersion: build.GoVersion})
+}
+
+func respondWithHealth(w http.ResponseWriter, status int, dto *HealthDto) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func TestHealthHandler_LivenessHandler(t *testing.T) {
+	// Setup
+	readiness := health.NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return errors.New("database is locked") })
+	handler := NewHealthHandler(readiness)
+
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LivenessHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestHealthHandler_ReadinessHandler_Ready(t *testing.T) {
+	// Setup
+	readiness := health.NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return nil })
+	handler := NewHealthHandler(readiness)
+
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ReadinessHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ready","checks":{"database":"ok"}}`, rr.Body.String())
+}
+
+func TestHealthHandler_ReadinessHandler_FailingCheck(t *testing.T) {
+	// Setup
+	readiness := health.NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return nil })
+	readiness.Add("webhook_worker", func(ctx context.Context) error { return errors.New("not running") })
+	handler := NewHealthHandler(readiness)
+
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ReadinessHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"database":"ok","webhook_worker":"not running"}}`, rr.Body.String())
+}
+
+func TestHealthHandler_ReadinessHandler_Draining(t *testing.T) {
+	// Setup
+	readiness := health.NewReadiness()
+	handler := NewHealthHandler(readiness)
+	readiness.Drain()
+
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ReadinessHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"shutdown":"draining"}}`, rr.Body.String())
+}
+
+func TestHealthHandler_VersionHandler(t *testing.T) {
+	// Setup
+	defer func(version, commit string) { health.Version, health.Commit = version, commit }(health.Version, health.Commit)
+	health.Version, health.Commit = "1.4.0", "abc123"
+	handler := NewHealthHandler(health.NewReadiness())
+
+	req, err := http.NewRequest("GET", "/version", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.VersionHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	version := &VersionDto{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), version))
+	assert.Equal(t, "1.4.0", version.Version)
+	assert.Equal(t, "abc123", version.Commit)
+	assert.NotEmpty(t, version.GoVersion)
+}
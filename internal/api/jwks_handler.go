@@ -0,0 +1,25 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type JwksHandler struct {
+	authService auth.AuthService
+}
+
+func NewJwksHandler(authService auth.AuthService) *JwksHandler {
+	return &JwksHandler{authService: authService}
+}
+
+// KeysHandler publishes the public keys tokens are verified with, so other
+// services can verify them without holding a secret. Verifiers may cache the
+// set for five minutes.
+func (h *JwksHandler) KeysHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(&JwksDto{Keys: h.authService.JSONWebKeys()})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func TestJwksHandler_KeysHandler(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewJwksHandler(authServiceMock)
+	authServiceMock.On("JSONWebKeys").Return([]auth.JSONWebKey{{KeyType: "OKP", ID: "key-1", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.KeysHandler(rr, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","kid":"key-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, rr.Body.String())
+}
+
+func TestJwksHandler_KeysHandler_SharedSecret(t *testing.T) {
+	// Setup
+	handler := NewJwksHandler(auth.NewAuthService("secret-key"))
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.KeysHandler(rr, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"keys":[]}`, rr.Body.String())
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+// RequestIDMiddleware keeps the X-Request-ID sent by the caller, or creates
+// one, echoes it in the response and logs every line of the request with it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := logging.RequestID(r.Header.Get(logging.RequestIDHeader))
+		w.Header().Set(logging.RequestIDHeader, requestID)
+
+		logger := slog.Default().With("request_id", requestID)
+		if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+			logger = logger.With("trace_id", span.TraceID().String())
+		}
+		next.ServeHTTP(w, r.WithContext(logging.NewContext(r.Context(), logger)))
+	})
+}
+
+// AccessLogMiddleware logs every request once it is answered.
+func AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+
+		logging.FromContext(r.Context()).Info("request",
+			"method", r.Method,
+			"route", routeTemplate(r),
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
+
+// authenticate returns the user of the API key ApiKeyMiddleware resolved, or
+// validates the token and, when it is valid, adds its username to the logs of
+// the request.
+func authenticate(r *http.Request, authService auth.AuthService, tokenString string) (string, bool) {
+	if key, ok := apiKeyFrom(r.Context()); ok {
+		return key.username, true
+	}
+
+	ok, username := authService.ValidateJwtToken(tokenString)
+	if ok {
+		logging.With(r.Context(), "username", username)
+	}
+	return username, ok
+}
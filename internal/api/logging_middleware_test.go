@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+func TestRequestIDMiddleware_EchoesRequestID(t *testing.T) {
+	// Setup
+	logs := captureLogs(t)
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	router := newContractRouter(t, videoServiceMock, authServiceMock)
+
+	authServiceMock.On("ValidateJwtToken", "test-token").Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 41).Return(nil, nil, service.ErrVideoNotFound)
+
+	req := httptest.NewRequest("GET", "/videos/41/", nil)
+	req.Header.Set("Authorization", "test-token")
+	req.Header.Set(logging.RequestIDHeader, "req-41")
+	rr := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rr, req)
+
+	// Verify
+	assert.Equal(t, "req-41", rr.Header().Get(logging.RequestIDHeader))
+	assert.Contains(t, logs.String(), `level=INFO msg=request request_id=req-41 username=test-user method=GET route=/videos/{id}/ path=/videos/41/ status=404`)
+}
+
+func TestRequestIDMiddleware_GeneratesRequestID(t *testing.T) {
+	// Setup
+	logs := captureLogs(t)
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	rr := httptest.NewRecorder()
+
+	// Execute
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+
+	// Verify
+	requestID := rr.Header().Get(logging.RequestIDHeader)
+	assert.Len(t, requestID, 26)
+	assert.Contains(t, logs.String(), "request_id="+requestID+" method=GET route=/healthz")
+}
+
+// captureLogs sends the default logger to a buffer for the length of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buffer bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buffer, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buffer
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+)
+
+func TestMetricsMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	router := newContractRouter(t, videoServiceMock, authServiceMock)
+
+	authServiceMock.On("ValidateJwtToken", "test-token").Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 41).Return(nil, nil, service.ErrVideoNotFound)
+	videoServiceMock.On("Find", "test-user", 42).Return(nil, nil, service.ErrVideoNotFound)
+
+	notFound := metrics.HttpRequests.WithLabelValues("GET", "/videos/{id}/", "404")
+	before := testutil.ToFloat64(notFound)
+
+	// Execute
+	for _, path := range []string{"/videos/41/", "/videos/42/"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "test-token")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Verify
+	assert.Equal(t, before+2, testutil.ToFloat64(notFound))
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.NotContains(t, rr.Body.String(), `route="/videos/41/"`)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	// Setup
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+	// Execute
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	// Verify
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `http_requests_total{method="GET",route="/healthz",status="200"}`)
+	assert.Contains(t, rr.Body.String(), "http_request_duration_seconds_bucket")
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type MfaHandler struct {
+	mfaService  ports.MfaService
+	authService auth.AuthService
+}
+
+func NewMfaHandler(mfaService ports.MfaService, authService auth.AuthService) *MfaHandler {
+	return &MfaHandler{
+		mfaService:  mfaService,
+		authService: authService,
+	}
+}
+
+// LoginHandler finishes a login that answered with a challenge.
+func (h *MfaHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	loginDto := &MfaLoginDto{}
+	if err := json.NewDecoder(r.Body).Decode(loginDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	token, err := h.mfaService.Login(ctx, loginDto.ChallengeToken, loginDto.Code)
+	if err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	respondWithToken(w, token)
+}
+
+func (h *MfaHandler) EnrollHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(r.Context(), username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newMfaEnrollmentDto(enrollment))
+}
+
+func (h *MfaHandler) ConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	username, codeDto, ok := h.authenticateWithCode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(r.Context(), username, codeDto.Code)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithRecoveryCodes(w, codes)
+}
+
+func (h *MfaHandler) DisableHandler(w http.ResponseWriter, r *http.Request) {
+	username, codeDto, ok := h.authenticateWithCode(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.mfaService.Disable(ctx, username, codeDto.Code); err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MfaHandler) RecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	username, codeDto, ok := h.authenticateWithCode(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	codes, err := h.mfaService.RegenerateRecoveryCodes(ctx, username, codeDto.Code)
+	if err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	respondWithRecoveryCodes(w, codes)
+}
+
+// authenticateWithCode reads the code of a request of a logged in user, it
+// answers the request itself when that fails.
+func (h *MfaHandler) authenticateWithCode(w http.ResponseWriter, r *http.Request) (string, *MfaCodeDto, bool) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return "", nil, false
+	}
+
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return "", nil, false
+	}
+
+	codeDto := &MfaCodeDto{}
+	if err := json.NewDecoder(r.Body).Decode(codeDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return "", nil, false
+	}
+	return username, codeDto, true
+}
+
+func respondWithRecoveryCodes(w http.ResponseWriter, codes []string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&RecoveryCodesDto{RecoveryCodes: codes})
+}
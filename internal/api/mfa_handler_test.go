@@ -0,0 +1,287 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func mfaRequest(t *testing.T, path string, token string, body any) *http.Request {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest("POST", path, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "192.0.2.1:54321"
+	if token != "" {
+		req.Header.Add("Authorization", token)
+	}
+	return req
+}
+
+func TestMfaHandler_LoginHandler(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	handler := NewMfaHandler(mfaServiceMock, new(AuthService))
+
+	mfaServiceMock.On("Login", "challenge", "123456", "192.0.2.1").Return("jwt", nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LoginHandler(rr, mfaRequest(t, "/login/mfa", "", &MfaLoginDto{ChallengeToken: "challenge", Code: "123456"}))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"token": "jwt"}`, rr.Body.String())
+	mfaServiceMock.AssertExpectations(t)
+}
+
+func TestMfaHandler_LoginHandler_WrongCode(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	handler := NewMfaHandler(mfaServiceMock, new(AuthService))
+
+	mfaServiceMock.On("Login", "challenge", "000000", "192.0.2.1").Return("", service.InvalidMfaCodeError)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LoginHandler(rr, mfaRequest(t, "/login/mfa", "", &MfaLoginDto{ChallengeToken: "challenge", Code: "000000"}))
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeInvalidCredentials)
+}
+
+func TestMfaHandler_LoginHandler_Throttled(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	handler := NewMfaHandler(mfaServiceMock, new(AuthService))
+
+	mfaServiceMock.On("Login", "challenge", "000000", "192.0.2.1").Return("", &service.LoginThrottledError{RetryAfter: 30 * time.Second})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LoginHandler(rr, mfaRequest(t, "/login/mfa", "", &MfaLoginDto{ChallengeToken: "challenge", Code: "000000"}))
+
+	// Verify
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+}
+
+func TestMfaHandler_EnrollHandler(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Enroll", "test-user").Return(&model.MfaEnrollment{
+		Secret: "JBSWY3DPEHPK3PXP",
+		URI:    "otpauth://totp/videos-api:test-user?secret=JBSWY3DPEHPK3PXP",
+		QRCode: []byte("png"),
+	}, nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EnrollHandler(rr, mfaRequest(t, "/account/mfa", token, nil))
+
+	// Verify
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response MfaEnrollmentDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, MfaEnrollmentDto{
+		Secret:     "JBSWY3DPEHPK3PXP",
+		OtpauthURI: "otpauth://totp/videos-api:test-user?secret=JBSWY3DPEHPK3PXP",
+		QRCode:     "data:image/png;base64,cG5n",
+	}, response)
+}
+
+func TestMfaHandler_EnrollHandler_Unauthorized(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	authServiceMock.On("ValidateJwtToken", "").Return(false, "")
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EnrollHandler(rr, mfaRequest(t, "/account/mfa", "", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mfaServiceMock.AssertNotCalled(t, "Enroll", mock.Anything)
+}
+
+func TestMfaHandler_EnrollHandler_DisabledUser(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Enroll", "test-user").Return(nil, service.ErrUserDisabled)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EnrollHandler(rr, mfaRequest(t, "/account/mfa", token, nil))
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeForbidden)
+}
+
+func TestMfaHandler_ConfirmHandler(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Confirm", "test-user", "123456").Return([]string{"k3x7q-m2p9a", "b4n6r-t8w2c"}, nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ConfirmHandler(rr, mfaRequest(t, "/account/mfa/confirm", token, &MfaCodeDto{Code: "123456"}))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"recovery_codes": ["k3x7q-m2p9a", "b4n6r-t8w2c"]}`, rr.Body.String())
+}
+
+func TestMfaHandler_ConfirmHandler_WrongCode(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Confirm", "test-user", "000000").Return(nil, validation.ErrMfaCodeIsInvalid)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ConfirmHandler(rr, mfaRequest(t, "/account/mfa/confirm", token, &MfaCodeDto{Code: "000000"}))
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"code"`)
+}
+
+func TestMfaHandler_DisableHandler(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Disable", "test-user", "123456").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DisableHandler(rr, mfaRequest(t, "/account/mfa/disable", token, &MfaCodeDto{Code: "123456"}))
+
+	// Verify
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mfaServiceMock.AssertExpectations(t)
+}
+
+func TestMfaHandler_DisableHandler_NotEnrolled(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("Disable", "test-user", "123456").Return(service.ErrMfaNotEnrolled)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DisableHandler(rr, mfaRequest(t, "/account/mfa/disable", token, &MfaCodeDto{Code: "123456"}))
+
+	// Verify
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestMfaHandler_RecoveryCodesHandler(t *testing.T) {
+	// Setup
+	mfaServiceMock := new(MfaServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewMfaHandler(mfaServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	mfaServiceMock.On("RegenerateRecoveryCodes", "test-user", "k3x7q-m2p9a").Return([]string{"b4n6r-t8w2c"}, nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RecoveryCodesHandler(rr, mfaRequest(t, "/account/mfa/recovery-codes", token, &MfaCodeDto{Code: "k3x7q-m2p9a"}))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"recovery_codes": ["b4n6r-t8w2c"]}`, rr.Body.String())
+}
+
+type MfaServiceMock struct {
+	mock.Mock
+}
+
+func (s *MfaServiceMock) Enroll(ctx context.Context, username string) (*model.MfaEnrollment, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MfaEnrollment), args.Error(1)
+}
+
+func (s *MfaServiceMock) Confirm(ctx context.Context, username string, code string) ([]string, error) {
+	args := s.Called(username, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (s *MfaServiceMock) Disable(ctx context.Context, username string, code string) error {
+	args := s.Called(username, code)
+	return args.Error(0)
+}
+
+func (s *MfaServiceMock) RegenerateRecoveryCodes(ctx context.Context, username string, code string) ([]string, error) {
+	args := s.Called(username, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (s *MfaServiceMock) Reset(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+// Login also expects the client address, which the handler passes along.
+func (s *MfaServiceMock) Login(ctx context.Context, challenge string, code string) (string, error) {
+	args := s.Called(challenge, code, auth.ClientAddress(ctx))
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Schema is the subset of JSON Schema 2020-12 the API document relies on.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 SchemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"-"`
+	Items                *Schema            `json:"items"`
+	AllOf                []*Schema          `json:"allOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+	Enum                 []any              `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+	MinLength            *int               `json:"minLength"`
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+
+	// additionalProperties may also hold a schema, only the boolean form
+	// restricts validation here.
+	fields := struct {
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var allowed bool
+	if json.Unmarshal(fields.AdditionalProperties, &allowed) == nil {
+		s.AdditionalProperties = &allowed
+	}
+	return nil
+}
+
+// SchemaType accepts both "type": "string" and "type": ["string", "null"].
+type SchemaType []string
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func (t SchemaType) allows(name string) bool {
+	for _, candidate := range t {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation describes where a value breaks the document and why.
+type Violation struct {
+	Location string
+	Message  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Location, v.Message)
+}
+
+// Validate checks a decoded JSON value against a schema of the document.
+func (d *Document) Validate(schema *Schema, value any, location string) []Violation {
+	schema = d.resolveSchema(schema)
+	if schema == nil {
+		return nil
+	}
+
+	violations := []Violation{}
+	for _, part := range schema.AllOf {
+		violations = append(violations, d.Validate(part, value, location)...)
+	}
+	if len(schema.OneOf) > 0 {
+		violations = append(violations, d.validateOneOf(schema.OneOf, value, location)...)
+	}
+
+	if len(schema.Type) > 0 && !schema.Type.allows(typeOf(value)) {
+		if !(typeOf(value) == "integer" && schema.Type.allows("number")) {
+			return append(violations, Violation{location, fmt.Sprintf("must be of type %v", []string(schema.Type))})
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		violations = append(violations, Violation{location, fmt.Sprintf("must be one of %v", schema.Enum)})
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if schema.MinLength != nil && len(typed) < *schema.MinLength {
+			violations = append(violations, Violation{location, fmt.Sprintf("must be at least %d characters long", *schema.MinLength)})
+		}
+	case float64:
+		if schema.Minimum != nil && typed < *schema.Minimum {
+			violations = append(violations, Violation{location, fmt.Sprintf("must be greater than or equal to %v", *schema.Minimum)})
+		}
+	case map[string]any:
+		violations = append(violations, d.validateObject(schema, typed, location)...)
+	case []any:
+		for i, item := range typed {
+			violations = append(violations, d.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", location, i))...)
+		}
+	}
+
+	return violations
+}
+
+// validateOneOf reports the violations of the closest alternative when none
+// matches.
+func (d *Document) validateOneOf(alternatives []*Schema, value any, location string) []Violation {
+	var closest []Violation
+	matches := 0
+	for _, alternative := range alternatives {
+		violations := d.Validate(alternative, value, location)
+		if len(violations) == 0 {
+			matches++
+		} else if closest == nil || len(violations) < len(closest) {
+			closest = violations
+		}
+	}
+
+	switch {
+	case matches == 1:
+		return nil
+	case matches > 1:
+		return []Violation{{location, "must match exactly one schema"}}
+	}
+	return closest
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]any, location string) []Violation {
+	violations := []Violation{}
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, Violation{join(location, name), "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				violations = append(violations, Violation{join(location, name), "is not allowed"})
+			}
+			continue
+		}
+		violations = append(violations, d.Validate(property, object[name], join(location, name))...)
+	}
+	return violations
+}
+
+func typeOf(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return "unknown"
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, candidate := range enum {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(location, name string) string {
+	if location == "" {
+		return name
+	}
+	return location + "." + name
+}
@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Validate_HappyPath(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	value := decode(t, `{"Title": "test", "Duration": 100, "Annotaions": [{"Note": "note", "StartTime": 1}]}`)
+
+	// test
+	violations := document.Validate(&Schema{Ref: "#/components/schemas/VideoDto"}, value, "body")
+
+	// assert
+	require.Empty(t, violations)
+}
+
+func TestDocument_Validate_NullableArray(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	value := decode(t, `{"Title": "test", "Annotaions": null}`)
+
+	// test
+	violations := document.Validate(&Schema{Ref: "#/components/schemas/VideoDto"}, value, "body")
+
+	// assert
+	require.Empty(t, violations)
+}
+
+func TestDocument_Validate_UnhappyPath_WrongTypes(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	value := decode(t, `{"Title": 1, "Duration": 1.5, "Annotaions": [{"Note": false}]}`)
+
+	// test
+	violations := document.Validate(&Schema{Ref: "#/components/schemas/VideoDto"}, value, "body")
+
+	// assert
+	require.ElementsMatch(t, []Violation{
+		{"body.Title", "must be of type [string]"},
+		{"body.Duration", "must be of type [integer]"},
+		{"body.Annotaions[0].Note", "must be of type [string]"},
+	}, violations)
+}
+
+func TestDocument_Validate_UnhappyPath_RequiredAndAdditional(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+
+	// test
+	missing := document.Validate(&Schema{Ref: "#/components/schemas/UserDto"}, decode(t, `{"email": "a@b.com"}`), "body")
+	additional := document.Validate(&Schema{Ref: "#/components/schemas/VideoPatch"}, decode(t, `{"duration": 1}`), "body")
+
+	// assert
+	require.Equal(t, []Violation{{"body.password", "is required"}}, missing)
+	require.Equal(t, []Violation{{"body.duration", "is not allowed"}}, additional)
+}
+
+func TestDocument_Validate_OneOf(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	schema := &Schema{OneOf: []*Schema{
+		{Ref: "#/components/schemas/Token"},
+		{Ref: "#/components/schemas/MfaChallenge"},
+	}}
+
+	// test
+	token := document.Validate(schema, decode(t, `{"token": "abc"}`), "body")
+	challenge := document.Validate(schema, decode(t, `{"mfa_required": true, "challenge_token": "abc"}`), "body")
+	neither := document.Validate(schema, decode(t, `{"token": 1}`), "body")
+
+	// assert
+	require.Empty(t, token)
+	require.Empty(t, challenge)
+	require.Equal(t, []Violation{{"body.token", "must be of type [string]"}}, neither)
+}
+
+func TestDocument_Validate_UnhappyPath_Enum(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	value := decode(t, `{"field": "title", "from": 1, "to": 2}`)
+
+	// test
+	violations := document.Validate(&Schema{Ref: "#/components/schemas/AnnotationFieldChange"}, value, "body")
+
+	// assert
+	require.Len(t, violations, 1)
+	require.Equal(t, "body.field", violations[0].Location)
+}
+
+func loadDocument(t *testing.T) *Document {
+	document, err := Load()
+	require.NoError(t, err)
+	return document
+}
+
+func decode(t *testing.T, raw string) any {
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(raw), &value))
+	return value
+}
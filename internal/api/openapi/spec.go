@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+var ErrOperationNotFound = fmt.Errorf("operation not found in the OpenAPI document")
+
+// Spec returns the raw OpenAPI document served at /openapi.json.
+func Spec() []byte {
+	return spec
+}
+
+// DocsPage returns the HTML page rendering the OpenAPI document.
+func DocsPage() []byte {
+	return docsPage
+}
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+	Responses  map[string]*Response  `json:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter
+	Operations map[string]*Operation
+}
+
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	p.Operations = map[string]*Operation{}
+	for key, raw := range fields {
+		if key == "parameters" {
+			if err := json.Unmarshal(raw, &p.Parameters); err != nil {
+				return err
+			}
+			continue
+		}
+		if !isMethod(key) {
+			continue
+		}
+		operation := &Operation{}
+		if err := json.Unmarshal(raw, operation); err != nil {
+			return err
+		}
+		p.Operations[strings.ToUpper(key)] = operation
+	}
+	return nil
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string                `json:"$ref"`
+	Content map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Load parses the embedded OpenAPI document.
+func Load() (*Document, error) {
+	document := &Document{}
+	if err := json.Unmarshal(spec, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Operation finds the operation registered for a path template such as
+// "/videos/{id}/" and returns it with the path level parameters merged in.
+func (d *Document) Operation(pathTemplate, method string) (*Operation, []*Parameter, error) {
+	item, ok := d.Paths[pathTemplate]
+	if !ok {
+		return nil, nil, ErrOperationNotFound
+	}
+	operation, ok := item.Operations[strings.ToUpper(method)]
+	if !ok {
+		return nil, nil, ErrOperationNotFound
+	}
+
+	parameters := []*Parameter{}
+	for _, parameter := range append(append([]*Parameter{}, item.Parameters...), operation.Parameters...) {
+		parameters = append(parameters, d.resolveParameter(parameter))
+	}
+	return operation, parameters, nil
+}
+
+// Routes lists every "METHOD path" pair described by the document.
+func (d *Document) Routes() []string {
+	routes := []string{}
+	for path, item := range d.Paths {
+		for method := range item.Operations {
+			routes = append(routes, method+" "+path)
+		}
+	}
+	return routes
+}
+
+func (d *Document) resolveParameter(parameter *Parameter) *Parameter {
+	if parameter.Ref == "" {
+		return parameter
+	}
+	return d.Components.Parameters[refName(parameter.Ref)]
+}
+
+func (d *Document) resolveResponse(response *Response) *Response {
+	if response == nil || response.Ref == "" {
+		return response
+	}
+	return d.Components.Responses[refName(response.Ref)]
+}
+
+func (d *Document) resolveSchema(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = d.Components.Schemas[refName(schema.Ref)]
+	}
+	return schema
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func isMethod(key string) bool {
+	switch strings.ToUpper(key) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+		http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
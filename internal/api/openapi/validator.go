@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ValidateRequest checks the parameters and body of a request against the
+// operation registered for pathTemplate. The body is restored afterwards so
+// handlers can still decode it.
+func (d *Document) ValidateRequest(r *http.Request, pathTemplate string, pathParams map[string]string) ([]Violation, error) {
+	operation, parameters, err := d.Operation(pathTemplate, r.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []Violation{}
+	for _, parameter := range parameters {
+		var raw string
+		var present bool
+		switch parameter.In {
+		case "path":
+			raw, present = pathParams[parameter.Name]
+		case "query":
+			present = r.URL.Query().Has(parameter.Name)
+			raw = r.URL.Query().Get(parameter.Name)
+		case "header":
+			raw = r.Header.Get(parameter.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		location := parameter.In + "." + parameter.Name
+		if !present {
+			if parameter.Required {
+				violations = append(violations, Violation{location, "is required"})
+			}
+			continue
+		}
+		violations = append(violations, d.validateParameter(parameter, raw, location)...)
+	}
+
+	if operation.RequestBody == nil {
+		return violations, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			violations = append(violations, Violation{"body", "is required"})
+		}
+		return violations, nil
+	}
+
+	media, ok := operation.RequestBody.Content[mediaType(r.Header.Get("Content-Type"), "application/json")]
+	if !ok {
+		return append(violations, Violation{"body", "unsupported content type"}), nil
+	}
+	return append(violations, d.validateJSON(media.Schema, body, "body")...), nil
+}
+
+// ValidateResponse checks that a status is documented for the operation and
+// that a JSON body matches the documented schema.
+func (d *Document) ValidateResponse(pathTemplate, method string, status int, header http.Header, body []byte) ([]Violation, error) {
+	operation, _, err := d.Operation(pathTemplate, method)
+	if err != nil {
+		return nil, err
+	}
+
+	response := d.resolveResponse(findResponse(operation.Responses, status))
+	if response == nil {
+		return []Violation{{"status", fmt.Sprintf("%d is not documented", status)}}, nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	contentType := mediaType(header.Get("Content-Type"), "")
+	media, ok := response.Content[contentType]
+	if !ok {
+		return []Violation{{"body", fmt.Sprintf("content type %q is not documented for status %d", contentType, status)}}, nil
+	}
+	if !strings.HasSuffix(contentType, "json") {
+		return nil, nil
+	}
+	return d.validateJSON(media.Schema, body, "body"), nil
+}
+
+func (d *Document) validateJSON(schema *Schema, body []byte, location string) []Violation {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []Violation{{location, "is not valid JSON"}}
+	}
+	return d.Validate(schema, value, location)
+}
+
+// validateParameter converts the raw string into the type the parameter
+// schema expects before validating it.
+func (d *Document) validateParameter(parameter *Parameter, raw, location string) []Violation {
+	schema := d.resolveSchema(parameter.Schema)
+	if schema == nil {
+		return nil
+	}
+
+	var value any = raw
+	switch {
+	case schema.Type.allows("integer"):
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return []Violation{{location, "must be an integer"}}
+		}
+		value = float64(parsed)
+	case schema.Type.allows("number"):
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []Violation{{location, "must be a number"}}
+		}
+		value = parsed
+	case schema.Type.allows("boolean"):
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []Violation{{location, "must be a boolean"}}
+		}
+		value = parsed
+	}
+	return d.Validate(schema, value, location)
+}
+
+func findResponse(responses map[string]*Response, status int) *Response {
+	if response, ok := responses[strconv.Itoa(status)]; ok {
+		return response
+	}
+	if response, ok := responses[fmt.Sprintf("%dXX", status/100)]; ok {
+		return response
+	}
+	return responses["default"]
+}
+
+func mediaType(contentType, fallback string) string {
+	if contentType == "" {
+		return fallback
+	}
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
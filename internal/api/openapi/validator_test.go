@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_ValidateRequest_HappyPath(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	body := `{"title": "new title"}`
+	req := httptest.NewRequest("PATCH", "/videos/1/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token")
+	req.Header.Set("If-Match", `"1"`)
+
+	// test
+	violations, err := document.ValidateRequest(req, "/videos/{id}/", map[string]string{"id": "1"})
+
+	// assert
+	require.NoError(t, err)
+	require.Empty(t, violations)
+
+	restored, _ := io.ReadAll(req.Body)
+	require.Equal(t, body, string(restored))
+}
+
+func TestDocument_ValidateRequest_UnhappyPath_Parameters(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	req := httptest.NewRequest("GET", "/annotations/abc/revisions/diff?from=1", nil)
+	req.Header.Set("Authorization", "token")
+
+	// test
+	violations, err := document.ValidateRequest(req, "/annotations/{id}/revisions/diff", map[string]string{"id": "abc"})
+
+	// assert
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Violation{
+		{"path.id", "must be an integer"},
+		{"query.to", "is required"},
+	}, violations)
+}
+
+func TestDocument_ValidateRequest_UnhappyPath_MissingBody(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	req := httptest.NewRequest("POST", "/login", nil)
+
+	// test
+	violations, err := document.ValidateRequest(req, "/login", nil)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []Violation{{"body", "is required"}}, violations)
+}
+
+func TestDocument_ValidateRequest_UnhappyPath_UnknownOperation(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	req := httptest.NewRequest("GET", "/nowhere", nil)
+
+	// test
+	_, err := document.ValidateRequest(req, "/nowhere", nil)
+
+	// assert
+	require.ErrorIs(t, err, ErrOperationNotFound)
+}
+
+func TestDocument_ValidateResponse_HappyPath(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	header := http.Header{"Content-Type": []string{"application/problem+json"}}
+	body := []byte(`{"type": "/problems/not-found", "title": "Resource not found", "status": 404}`)
+
+	// test
+	violations, err := document.ValidateResponse("/videos/{id}/", "GET", http.StatusNotFound, header, body)
+
+	// assert
+	require.NoError(t, err)
+	require.Empty(t, violations)
+}
+
+func TestDocument_ValidateResponse_UnhappyPath_BodyMismatch(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	body := []byte(`{"tokens": "abc"}`)
+
+	// test
+	violations, err := document.ValidateResponse("/login", "POST", http.StatusOK, header, body)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []Violation{{"body.token", "is required"}}, violations)
+}
+
+func TestDocument_ValidateResponse_UnhappyPath_UndocumentedContentType(t *testing.T) {
+	// fixture
+	document := loadDocument(t)
+	header := http.Header{"Content-Type": []string{"text/plain"}}
+
+	// test
+	violations, err := document.ValidateResponse("/login", "POST", http.StatusOK, header, []byte("token"))
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+}
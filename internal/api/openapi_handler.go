@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/api/openapi"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+func OpenAPISpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.Spec())
+}
+
+// docsPolicy keeps the page from loading anything but the document itself.
+const docsPolicy = "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'"
+
+func OpenAPIDocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsPolicy)
+	w.Write(openapi.DocsPage())
+}
+
+// ContractValidationMiddleware rejects requests that do not match the OpenAPI
+// document and logs responses that drift from it.
+func ContractValidationMiddleware(document *openapi.Document) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			pathTemplate, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			violations, err := document.ValidateRequest(r, pathTemplate, mux.Vars(r))
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("contract validation skipped", "method", r.Method, "route", pathTemplate, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(violations) > 0 {
+				respondWithError(w, r, violationErrors(violations))
+				return
+			}
+
+			if streamingRoutes[pathTemplate] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			violations, err = document.ValidateResponse(pathTemplate, r.Method, recorder.status, w.Header(), recorder.body.Bytes())
+			if err == nil && len(violations) > 0 {
+				logging.FromContext(r.Context()).Warn("response does not match the OpenAPI document", "method", r.Method, "route", pathTemplate, "violations", violations)
+			}
+			recorder.flush()
+		})
+	}
+}
+
+func violationErrors(violations []openapi.Violation) validation.Errors {
+	errs := validation.Errors{}
+	for _, violation := range violations {
+		errs = append(errs, &validation.FieldError{Field: violation.Location, Err: errors.New(violation.Message)})
+	}
+	return errs
+}
+
+// responseRecorder buffers a response so it can be validated before it is
+// sent to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) flush() {
+	r.ResponseWriter.WriteHeader(r.status)
+	r.ResponseWriter.Write(r.body.Bytes())
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/api/openapi"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func TestOpenAPI_SpecMatchesRouter(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	document, err := openapi.Load()
+	require.NoError(t, err)
+
+	routes := []string{}
+	err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+template)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// assert
+	require.ElementsMatch(t, document.Routes(), routes)
+}
+
+func TestOpenAPISpecHandler(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &spec))
+	require.Equal(t, "3.1.0", spec["openapi"])
+}
+
+func TestOpenAPIDocsHandler(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	req := httptest.NewRequest("GET", "/docs", nil)
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), "/openapi.json")
+	require.NotContains(t, rr.Body.String(), "<script src=")
+	require.Contains(t, rr.Header().Get("Content-Security-Policy"), "default-src 'none'")
+}
+
+func TestContractValidationMiddleware_RejectsInvalidRequest(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": 1}`))
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	problem := &Problem{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), problem))
+	require.Equal(t, ProblemTypeValidation, problem.Type)
+	require.ElementsMatch(t, []*ProblemField{
+		{Field: "body.email", Detail: "must be of type [string]"},
+		{Field: "body.password", Detail: "is required"},
+	}, problem.Errors)
+}
+
+func TestContractValidationMiddleware_PassesValidRequest(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "a@b.com", "password": "secret"}`))
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), "token")
+}
+
+func TestOpenAPI_GetVideoResponseMatchesSpec(t *testing.T) {
+	// fixture
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	router := newContractRouter(t, videoServiceMock, authServiceMock)
+	document, err := openapi.Load()
+	require.NoError(t, err)
+
+	video := &model.Video{ID: 1, UserID: 1, Title: "title", Description: "description",
+		Link: "https://example.com/test.mp4", Duration: time.Minute, CreatedAt: time.Now(), Version: 1}
+	annotations := []*model.Annotation{{ID: 1, VideoID: 1, UserID: 1, StartTime: 1, EndTime: 2, Type: "ad", Note: "note", Version: 1}}
+
+	authServiceMock.On("ValidateJwtToken", "test-token").Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(video, annotations, nil)
+
+	req := httptest.NewRequest("GET", "/videos/1/", nil)
+	req.Header.Set("Authorization", "test-token")
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	violations, err := document.ValidateResponse("/videos/{id}/", "GET", rr.Code, rr.Header(), rr.Body.Bytes())
+	require.NoError(t, err)
+	require.Empty(t, violations)
+}
+
+func TestOpenAPI_ProblemResponseMatchesSpec(t *testing.T) {
+	// fixture
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+	document, err := openapi.Load()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "non-existing-user@example.com", "password": "secret"}`))
+	rr := httptest.NewRecorder()
+
+	// test
+	router.ServeHTTP(rr, req)
+
+	// assert
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	violations, err := document.ValidateResponse("/login", "POST", rr.Code, rr.Header(), rr.Body.Bytes())
+	require.NoError(t, err)
+	require.Empty(t, violations)
+}
+
+func newContractRouter(t *testing.T, videoService *VideoServiceMock, authService *AuthService) *mux.Router {
+	settings := &config.Settings{OpenAPIValidation: true}
+	router, err := NewRouter(settings, authService, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), new(AccountServiceMock), new(SsoServiceMock), videoService, new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), new(ApiKeyServiceMock), new(BackupServiceMock), stream.NewHub(), health.NewReadiness())
+	require.NoError(t, err)
+	return router
+}
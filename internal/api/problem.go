@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+const problemContentType = "application/problem+json"
+
+// Stable problem type URIs, clients should switch on these rather than on titles.
+const (
+	ProblemTypeValidation           = "/problems/validation-error"
+	ProblemTypeInvalidPayload       = "/problems/invalid-payload"
+	ProblemTypeUnauthorized         = "/problems/unauthorized"
+	ProblemTypeForbidden            = "/problems/forbidden"
+	ProblemTypeInvalidCredentials   = "/problems/invalid-credentials"
+	ProblemTypeNotFound             = "/problems/not-found"
+	ProblemTypeMethodNotAllowed     = "/problems/method-not-allowed"
+	ProblemTypePreconditionFailed   = "/problems/precondition-failed"
+	ProblemTypePreconditionRequired = "/problems/precondition-required"
+	ProblemTypeTooManyRequests      = "/problems/too-many-requests"
+	ProblemTypeConflict             = "/problems/conflict"
+	ProblemTypeEmailNotVerified     = "/problems/email-not-verified"
+	ProblemTypeInvalidLink          = "/problems/invalid-link"
+	ProblemTypeInternal             = "/problems/internal-error"
+)
+
+var (
+	ErrMethodNotAllowed = fmt.Errorf("method not allowed")
+	ErrUnauthorized     = fmt.Errorf("unauthorized")
+	ErrForbidden        = fmt.Errorf("forbidden")
+	ErrInvalidPayload   = fmt.Errorf("invalid request payload")
+	ErrTooManyRequests  = fmt.Errorf("too many requests")
+)
+
+// Problem is an RFC 7807 problem details document.
+type Problem struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Errors   []*ProblemField `json:"errors,omitempty"`
+}
+
+type ProblemField struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+var notFoundErrors = []error{
+	service.ErrVideoNotFound,
+	service.ErrAnnotationsNotFound,
+	service.ErrAnnotationNotFound,
+	service.ErrRevisionNotFound,
+	service.ErrWebhookNotFound,
+	service.ErrApiKeyNotFound,
+	service.ErrWorkspaceNotFound,
+	service.ErrSsoNotConfigured,
+	repository.UserNotFoundError,
+	repository.VideoNotFoundError,
+	repository.ErrAnnotationNotFound,
+	repository.ErrMemberNotFound,
+}
+
+// problemFor maps domain and transport errors onto problem details.
+// Unknown errors become a generic 500 so internals are never leaked.
+func problemFor(err error) *Problem {
+	if errs, ok := validation.AsErrors(err); ok {
+		return validationProblem(errs)
+	}
+	if validation.IsValidationError(err) {
+		return validationProblem(validation.Errors{{Field: validation.FieldOf(err), Err: err}})
+	}
+
+	for _, notFound := range notFoundErrors {
+		if errors.Is(err, notFound) {
+			return &Problem{Type: ProblemTypeNotFound, Title: "Resource not found", Status: http.StatusNotFound, Detail: err.Error()}
+		}
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidPayload), errors.Is(err, ErrIfMatchInvalid):
+		return &Problem{Type: ProblemTypeInvalidPayload, Title: "Invalid request payload", Status: http.StatusBadRequest, Detail: err.Error()}
+	case errors.Is(err, ErrUnauthorized):
+		return &Problem{Type: ProblemTypeUnauthorized, Title: "Unauthorized", Status: http.StatusUnauthorized}
+	case errors.Is(err, ErrForbidden):
+		return &Problem{Type: ProblemTypeForbidden, Title: "Forbidden", Status: http.StatusForbidden}
+	case errors.Is(err, service.UserOrPasswordNotFoundError):
+		return &Problem{Type: ProblemTypeInvalidCredentials, Title: "Invalid username or password", Status: http.StatusUnauthorized}
+	case errors.Is(err, service.InvalidMfaCodeError):
+		return &Problem{Type: ProblemTypeInvalidCredentials, Title: "Invalid two-factor code", Status: http.StatusUnauthorized}
+	case errors.Is(err, service.ErrMfaChallengeInvalid):
+		return &Problem{Type: ProblemTypeUnauthorized, Title: "Unauthorized", Status: http.StatusUnauthorized, Detail: err.Error()}
+	case errors.Is(err, service.ErrMfaNotEnrolled), errors.Is(err, service.ErrMfaAlreadyEnabled):
+		return &Problem{Type: ProblemTypeConflict, Title: "Conflict", Status: http.StatusConflict, Detail: err.Error()}
+	case errors.Is(err, service.ErrEmailNotVerified):
+		return &Problem{Type: ProblemTypeEmailNotVerified, Title: "Email not verified", Status: http.StatusForbidden,
+			Detail: "verify the email address of the account before changing anything"}
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		return &Problem{Type: ProblemTypeConflict, Title: "Conflict", Status: http.StatusConflict, Detail: err.Error()}
+	case errors.Is(err, service.ErrUserTokenInvalid):
+		return &Problem{Type: ProblemTypeInvalidLink, Title: "Invalid link", Status: http.StatusBadRequest, Detail: err.Error()}
+	case errors.Is(err, repository.UsernameTakenError), errors.Is(err, repository.EmailTakenError), errors.Is(err, service.ErrNoPassword):
+		return &Problem{Type: ProblemTypeConflict, Title: "Conflict", Status: http.StatusConflict, Detail: err.Error()}
+	case errors.Is(err, service.ErrWrongPassword):
+		return &Problem{Type: ProblemTypeInvalidCredentials, Title: "Wrong password", Status: http.StatusForbidden, Detail: err.Error()}
+	case errors.Is(err, service.ErrSsoLoginInvalid):
+		return &Problem{Type: ProblemTypeInvalidLink, Title: "Invalid link", Status: http.StatusBadRequest, Detail: err.Error()}
+	case errors.Is(err, service.ErrSsoRejected):
+		return &Problem{Type: ProblemTypeUnauthorized, Title: "Unauthorized", Status: http.StatusUnauthorized, Detail: err.Error()}
+	case errors.Is(err, service.ErrSsoNoRole), errors.Is(err, service.ErrNotAllowed), errors.Is(err, service.ErrUserDisabled),
+		errors.Is(err, service.ErrReadOnlyRole):
+		return &Problem{Type: ProblemTypeForbidden, Title: "Forbidden", Status: http.StatusForbidden, Detail: err.Error()}
+	case errors.Is(err, service.ErrLastAdmin):
+		return &Problem{Type: ProblemTypeConflict, Title: "Conflict", Status: http.StatusConflict, Detail: err.Error()}
+	case errors.Is(err, ErrMethodNotAllowed):
+		return &Problem{Type: ProblemTypeMethodNotAllowed, Title: "Method not allowed", Status: http.StatusMethodNotAllowed}
+	case errors.Is(err, ports.ErrVersionConflict):
+		return &Problem{Type: ProblemTypePreconditionFailed, Title: "Version mismatch", Status: http.StatusPreconditionFailed,
+			Detail: "the resource was modified since it was last read"}
+	case errors.Is(err, ErrTooManyRequests):
+		return &Problem{Type: ProblemTypeTooManyRequests, Title: "Too many requests", Status: http.StatusTooManyRequests,
+			Detail: "the rate limit of the route was reached, retry after the Retry-After delay"}
+	case errors.Is(err, service.ErrLoginThrottled):
+		return &Problem{Type: ProblemTypeTooManyRequests, Title: "Too many requests", Status: http.StatusTooManyRequests,
+			Detail: "too many failed logins, retry after the Retry-After delay"}
+	case errors.Is(err, ErrIfMatchMissing):
+		return &Problem{Type: ProblemTypePreconditionRequired, Title: "Precondition required", Status: http.StatusPreconditionRequired, Detail: err.Error()}
+	}
+
+	return &Problem{Type: ProblemTypeInternal, Title: "Request failed", Status: http.StatusInternalServerError}
+}
+
+func validationProblem(errs validation.Errors) *Problem {
+	problem := &Problem{
+		Type:   ProblemTypeValidation,
+		Title:  "Validation failed",
+		Status: http.StatusBadRequest,
+		Detail: errs.Error(),
+	}
+	for _, fieldErr := range errs {
+		problem.Errors = append(problem.Errors, &ProblemField{Field: fieldErr.Field, Detail: fieldErr.Err.Error()})
+	}
+	return problem
+}
+
+func respondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := problemFor(err)
+	if problem.Status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+	problem.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondWithError_ValidationErrors(t *testing.T) {
+	// fixture
+	err := validation.Errors{
+		{Field: "title", Err: validation.ErrTitleIsInvalid},
+		{Field: "annotations[0].note", Err: validation.ErrNoteIsInvalid},
+	}
+	req := httptest.NewRequest("POST", "/videos/", nil)
+	rr := httptest.NewRecorder()
+
+	// test
+	respondWithError(rr, req, err)
+
+	// assertion
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+	problem := &Problem{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), problem))
+	require.Equal(t, ProblemTypeValidation, problem.Type)
+	require.Equal(t, http.StatusBadRequest, problem.Status)
+	require.Equal(t, "/videos/", problem.Instance)
+	require.Equal(t, []*ProblemField{
+		{Field: "title", Detail: "title is invalid"},
+		{Field: "annotations[0].note", Detail: "note is invalid"},
+	}, problem.Errors)
+}
+
+func TestProblemFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		problem    string
+	}{
+		{"single validation error", validation.ErrEmailIsInvalid, http.StatusBadRequest, ProblemTypeValidation},
+		{"video not found", service.ErrVideoNotFound, http.StatusNotFound, ProblemTypeNotFound},
+		{"user not found", repository.UserNotFoundError, http.StatusNotFound, ProblemTypeNotFound},
+		{"forbidden", ErrForbidden, http.StatusForbidden, ProblemTypeForbidden},
+		{"invalid credentials", service.UserOrPasswordNotFoundError, http.StatusUnauthorized, ProblemTypeInvalidCredentials},
+		{"version conflict", ports.ErrVersionConflict, http.StatusPreconditionFailed, ProblemTypePreconditionFailed},
+		{"missing if-match", ErrIfMatchMissing, http.StatusPreconditionRequired, ProblemTypePreconditionRequired},
+		{"rate limited", ErrTooManyRequests, http.StatusTooManyRequests, ProblemTypeTooManyRequests},
+		{"login throttled", &service.LoginThrottledError{RetryAfter: time.Minute}, http.StatusTooManyRequests, ProblemTypeTooManyRequests},
+		{"invalid two-factor code", service.InvalidMfaCodeError, http.StatusUnauthorized, ProblemTypeInvalidCredentials},
+		{"two-factor already enabled", service.ErrMfaAlreadyEnabled, http.StatusConflict, ProblemTypeConflict},
+		{"username taken", repository.UsernameTakenError, http.StatusConflict, ProblemTypeConflict},
+		{"email taken", repository.EmailTakenError, http.StatusConflict, ProblemTypeConflict},
+		{"wrong current password", service.ErrWrongPassword, http.StatusForbidden, ProblemTypeInvalidCredentials},
+		{"not allowed in the workspace", service.ErrNotAllowed, http.StatusForbidden, ProblemTypeForbidden},
+		{"workspace not found", service.ErrWorkspaceNotFound, http.StatusNotFound, ProblemTypeNotFound},
+		{"last admin of the workspace", service.ErrLastAdmin, http.StatusConflict, ProblemTypeConflict},
+		{"unknown error", errors.New("disk on fire"), http.StatusInternalServerError, ProblemTypeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			problem := problemFor(tt.err)
+
+			// assertion
+			require.Equal(t, tt.statusCode, problem.Status)
+			require.Equal(t, tt.problem, problem.Type)
+		})
+	}
+}
+
+func TestProblemFor_SingleValidationErrorHasField(t *testing.T) {
+	// test
+	problem := problemFor(validation.ErrEmailIsInvalid)
+
+	// assertion
+	require.Equal(t, []*ProblemField{{Field: "email", Detail: "email is invalid"}}, problem.Errors)
+}
+
+func TestProblemFor_UnknownErrorHidesDetail(t *testing.T) {
+	// test
+	problem := problemFor(errors.New("disk on fire"))
+
+	// assertion
+	require.Empty(t, problem.Detail)
+}
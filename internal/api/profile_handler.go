@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type ProfileHandler struct {
+	profileService ports.ProfileService
+	authService    auth.AuthService
+}
+
+func NewProfileHandler(profileService ports.ProfileService, authService auth.AuthService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		authService:    authService,
+	}
+}
+
+func (h *ProfileHandler) GetHandler(w http.ResponseWriter, r *http.Request) {
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	user, err := h.profileService.Find(r.Context(), username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newProfileDto(user))
+}
+
+// PatchHandler changes the username and answers with a new token, the
+// current one carries the previous username.
+func (h *ProfileHandler) PatchHandler(w http.ResponseWriter, r *http.Request) {
+	usernameDto := &UsernameDto{}
+	username, ok := h.authenticateWith(w, r, usernameDto)
+	if !ok {
+		return
+	}
+
+	token, err := h.profileService.Rename(r.Context(), username, usernameDto.Username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithToken(w, token)
+}
+
+func (h *ProfileHandler) PasswordHandler(w http.ResponseWriter, r *http.Request) {
+	passwordDto := &PasswordChangeDto{}
+	username, ok := h.authenticateWith(w, r, passwordDto)
+	if !ok {
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.profileService.ChangePassword(ctx, username, passwordDto.CurrentPassword, passwordDto.Password); err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EmailHandler answers once the verification link is on its way to the new
+// email.
+func (h *ProfileHandler) EmailHandler(w http.ResponseWriter, r *http.Request) {
+	emailDto := &EmailChangeDto{}
+	username, ok := h.authenticateWith(w, r, emailDto)
+	if !ok {
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	if err := h.profileService.ChangeEmail(ctx, username, emailDto.Password, emailDto.Email); err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *ProfileHandler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	deletionDto := &AccountDeletionDto{}
+	username, ok := h.authenticateWith(w, r, deletionDto)
+	if !ok {
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	err := h.profileService.Delete(ctx, username, deletionDto.Password, deletionDto.Videos, deletionDto.TransferTo)
+	if err != nil {
+		respondWithLoginError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticateWith reads the body of a request of a logged in user into dto,
+// it answers the request itself when that fails.
+func (h *ProfileHandler) authenticateWith(w http.ResponseWriter, r *http.Request, dto any) (string, bool) {
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return "", false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return "", false
+	}
+	return username, true
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func profileRequest(t *testing.T, method string, path string, body any) *http.Request {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.RemoteAddr = "192.0.2.1:54321"
+	req.Header.Add("Authorization", "test-token")
+	return req
+}
+
+func newProfileHandler() (*ProfileHandler, *ProfileServiceMock) {
+	profileServiceMock := new(ProfileServiceMock)
+	authServiceMock := new(AuthService)
+	authServiceMock.On("ValidateJwtToken", "test-token").Return(true, "test-user")
+	return NewProfileHandler(profileServiceMock, authServiceMock), profileServiceMock
+}
+
+func TestProfileHandler_GetHandler(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	profileServiceMock.On("Find", "test-user").Return(&model.User{
+		ID: 1, Username: "test-user", Password: "hash", Email: "test@example.com", EmailVerified: true, Role: model.RoleEditor, CreatedAt: createdAt,
+	}, nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.GetHandler(rr, profileRequest(t, "GET", "/account", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"username":"test-user","email":"test@example.com","email_verified":true,"role":"editor","created_at":"2026-01-02T03:04:05Z"}`, rr.Body.String())
+}
+
+func TestProfileHandler_GetHandler_Unauthorized(t *testing.T) {
+	// Setup
+	profileServiceMock := new(ProfileServiceMock)
+	authServiceMock := new(AuthService)
+	handler := NewProfileHandler(profileServiceMock, authServiceMock)
+	authServiceMock.On("ValidateJwtToken", "").Return(false, "")
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.GetHandler(rr, httptest.NewRequest("GET", "/account", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	profileServiceMock.AssertNotCalled(t, "Find", mock.Anything)
+}
+
+func TestProfileHandler_GetHandler_DisabledUser(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("Find", "test-user").Return(nil, service.ErrUserDisabled)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.GetHandler(rr, profileRequest(t, "GET", "/account", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeForbidden)
+}
+
+func TestProfileHandler_PatchHandler(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("Rename", "test-user", "new-name").Return("new-token", nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.PatchHandler(rr, profileRequest(t, "PATCH", "/account", &UsernameDto{Username: "new-name"}))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"token":"new-token"}`, rr.Body.String())
+}
+
+func TestProfileHandler_PatchHandler_Taken(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("Rename", "test-user", "other-user").Return("", repository.UsernameTakenError)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.PatchHandler(rr, profileRequest(t, "PATCH", "/account", &UsernameDto{Username: "other-user"}))
+
+	// Verify
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeConflict)
+}
+
+func TestProfileHandler_PasswordHandler_WrongPassword(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("ChangePassword", "test-user", "wrong", "new-password").Return(service.ErrWrongPassword)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.PasswordHandler(rr, profileRequest(t, "POST", "/account/password",
+		&PasswordChangeDto{CurrentPassword: "wrong", Password: "new-password"}))
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeInvalidCredentials)
+}
+
+func TestProfileHandler_EmailHandler(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("ChangeEmail", "test-user", "password123", "new@example.com").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EmailHandler(rr, profileRequest(t, "POST", "/account/email", &EmailChangeDto{Email: "new@example.com", Password: "password123"}))
+
+	// Verify
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	profileServiceMock.AssertExpectations(t)
+}
+
+func TestProfileHandler_DeleteHandler(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("Delete", "test-user", "password123", model.VideosTransfer, "other-user", "192.0.2.1").Return(nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, profileRequest(t, "POST", "/account/delete",
+		&AccountDeletionDto{Password: "password123", Videos: model.VideosTransfer, TransferTo: "other-user"}))
+
+	// Verify
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	profileServiceMock.AssertExpectations(t)
+}
+
+func TestProfileHandler_DeleteHandler_InvalidTransfer(t *testing.T) {
+	// Setup
+	handler, profileServiceMock := newProfileHandler()
+	profileServiceMock.On("Delete", "test-user", "password123", model.VideosTransfer, "", "192.0.2.1").Return(validation.ErrTransferToIsInvalid)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, profileRequest(t, "POST", "/account/delete",
+		&AccountDeletionDto{Password: "password123", Videos: model.VideosTransfer}))
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"transfer_to"`)
+}
+
+type ProfileServiceMock struct {
+	mock.Mock
+}
+
+func (s *ProfileServiceMock) Find(ctx context.Context, username string) (*model.User, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (s *ProfileServiceMock) Rename(ctx context.Context, username string, newUsername string) (string, error) {
+	args := s.Called(username, newUsername)
+	return args.String(0), args.Error(1)
+}
+
+func (s *ProfileServiceMock) ChangePassword(ctx context.Context, username string, current string, password string) error {
+	args := s.Called(username, current, password)
+	return args.Error(0)
+}
+
+func (s *ProfileServiceMock) ChangeEmail(ctx context.Context, username string, password string, email string) error {
+	args := s.Called(username, password, email)
+	return args.Error(0)
+}
+
+func (s *ProfileServiceMock) Delete(ctx context.Context, username string, password string, videos string, transferTo string) error {
+	args := s.Called(username, password, videos, transferTo, auth.ClientAddress(ctx))
+	return args.Error(0)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/ratelimit"
+)
+
+// unlimitedRoutes answer probes and scrapers, which come from a few
+// addresses and must not be turned away.
+var unlimitedRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/version": true,
+	"/metrics": true,
+}
+
+// RateLimitMiddleware limits the requests of every client on every route,
+// answering with the RateLimit-* headers and, once the limit is reached, a
+// 429 with Retry-After. Requests go through when the store fails, a broken
+// limiter must not take the API down.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, authService auth.AuthService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			template := routeTemplate(r)
+			route := r.Method + " " + template
+			if unlimitedRoutes[template] || limiter.Limit(route).IsZero() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Take(r.Context(), route, clientKey(r, authService))
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("rate limit skipped", "route", route, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit.Burst))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ratelimit.Seconds(result.Reset)))
+			header.Set("RateLimit-Policy", result.Limit.Policy())
+
+			if !result.Allowed {
+				metrics.RateLimited.WithLabelValues(r.Method, template).Inc()
+				header.Set("Retry-After", strconv.Itoa(max(1, ratelimit.Seconds(result.RetryAfter))))
+				respondWithError(w, r, ErrTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey names the API key of a request authenticated with one, so every
+// key gets a limit of its own, then the user of a valid token, so a user
+// shares a limit across addresses, and the remote address otherwise.
+func clientKey(r *http.Request, authService auth.AuthService) string {
+	if key, ok := apiKeyFrom(r.Context()); ok {
+		return "key:" + key.id
+	}
+	if token := r.Header.Get("Authorization"); token != "" {
+		if ok, username := authService.ValidateJwtToken(token); ok {
+			return "user:" + username
+		}
+	}
+	return "ip:" + remoteAddress(r)
+}
+
+// remoteAddress is the host the request came from, without its port.
+func remoteAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func newRateLimitedRouter(t *testing.T, videoService *VideoServiceMock, authService *AuthService, apiKeyService *ApiKeyServiceMock) *mux.Router {
+	settings := &config.Settings{OpenAPIValidation: true, RateLimitDefault: "1/m", RateLimitRoutes: "POST /login=2/m"}
+	router, err := NewRouter(settings, authService, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), new(AccountServiceMock), new(SsoServiceMock), videoService, new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), apiKeyService, new(BackupServiceMock), stream.NewHub(), health.NewReadiness())
+	require.NoError(t, err)
+	return router
+}
+
+func login(router http.Handler, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/login", bytes.NewReader([]byte(`{"email":"test-user","password":"password"}`)))
+	req.RemoteAddr = remoteAddr
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimitMiddleware_LimitsByAddress(t *testing.T) {
+	// Setup
+	router := newRateLimitedRouter(t, new(VideoServiceMock), new(AuthService), new(ApiKeyServiceMock))
+
+	// Execute
+	first := login(router, "192.0.2.1:1234")
+	second := login(router, "192.0.2.1:5678")
+	limited := login(router, "192.0.2.1:1234")
+	otherAddress := login(router, "192.0.2.2:1234")
+
+	// Verify
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", first.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "0", second.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "application/problem+json", limited.Header().Get("Content-Type"))
+	assert.Equal(t, "30", limited.Header().Get("Retry-After"))
+	assert.Contains(t, limited.Body.String(), ProblemTypeTooManyRequests)
+
+	assert.Equal(t, http.StatusOK, otherAddress.Code)
+}
+
+func TestRateLimitMiddleware_LimitsByUser(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	router := newRateLimitedRouter(t, videoServiceMock, authServiceMock, new(ApiKeyServiceMock))
+
+	authServiceMock.On("ValidateJwtToken", "jane-token").Return(true, "jane")
+	authServiceMock.On("ValidateJwtToken", "john-token").Return(true, "john")
+	videoServiceMock.On("Find", "jane", 1).Return(nil, nil, service.ErrVideoNotFound)
+	videoServiceMock.On("Find", "john", 1).Return(nil, nil, service.ErrVideoNotFound)
+
+	get := func(token string) int {
+		req := httptest.NewRequest("GET", "/videos/1/", nil)
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Execute
+	first := get("jane-token")
+	limited := get("jane-token")
+	otherUser := get("john-token")
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, first)
+	assert.Equal(t, http.StatusTooManyRequests, limited)
+	assert.Equal(t, http.StatusNotFound, otherUser)
+}
+
+func TestRateLimitMiddleware_LimitsByApiKey(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	apiKeyServiceMock := new(ApiKeyServiceMock)
+	router := newRateLimitedRouter(t, videoServiceMock, authServiceMock, apiKeyServiceMock)
+
+	ciKey, ciId, _, err := auth.GenerateApiKey()
+	require.NoError(t, err)
+	backupKey, backupId, _, err := auth.GenerateApiKey()
+	require.NoError(t, err)
+	apiKeyServiceMock.On("Authenticate", ciKey).Return(&model.ApiKey{ID: ciId}, "jane", nil)
+	apiKeyServiceMock.On("Authenticate", backupKey).Return(&model.ApiKey{ID: backupId}, "jane", nil)
+	authServiceMock.On("ValidateJwtToken", "jane-token").Return(true, "jane")
+	videoServiceMock.On("Find", "jane", 1).Return(nil, nil, service.ErrVideoNotFound)
+
+	get := func(token string) int {
+		req := httptest.NewRequest("GET", "/videos/1/", nil)
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Execute
+	first := get(ciKey)
+	limited := get(ciKey)
+	otherKey := get(backupKey)
+	session := get("jane-token")
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, first)
+	assert.Equal(t, http.StatusTooManyRequests, limited)
+	assert.Equal(t, http.StatusNotFound, otherKey)
+	assert.Equal(t, http.StatusNotFound, session)
+	authServiceMock.AssertNotCalled(t, "ValidateJwtToken", ciKey)
+}
+
+func TestRateLimitMiddleware_ProbesAreNotLimited(t *testing.T) {
+	// Setup
+	router := newRateLimitedRouter(t, new(VideoServiceMock), new(AuthService), new(ApiKeyServiceMock))
+
+	// Execute
+	codes := []int{}
+	for range 3 {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+		codes = append(codes, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+
+	// Verify
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, codes)
+}
@@ -2,28 +2,181 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/api/openapi"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/graphqlapi"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/ratelimit"
 )
 
-func StartHttpServer(
+// NewHttpServer serves the router with the configured timeouts and header
+// limit. Shutting it down also ends the open event streams, which would
+// otherwise hold the server until the shutdown deadline.
+func NewHttpServer(
+	settings *config.Settings,
 	authService auth.AuthService,
 	userService ports.UserService,
-	videoService ports.VideoService) {
+	profileService ports.ProfileService,
+	mfaService ports.MfaService,
+	accountService ports.AccountService,
+	ssoService ports.SsoService,
+	videoService ports.VideoService,
+	annotationService ports.AnnotationService,
+	webhookService ports.WebhookService,
+	workspaceService ports.WorkspaceService,
+	apiKeyService ports.ApiKeyService,
+	backupService ports.BackupService,
+	hub *stream.Hub,
+	readiness *health.Readiness) (*http.Server, error) {
+	router, err := NewRouter(settings, authService, userService, profileService, mfaService, accountService, ssoService, videoService, annotationService, webhookService, workspaceService, apiKeyService, backupService, hub, readiness)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:              settings.HttpAddress,
+		Handler:           router,
+		ReadTimeout:       settings.HttpReadTimeout,
+		ReadHeaderTimeout: settings.HttpReadHeaderTimeout,
+		WriteTimeout:      settings.HttpWriteTimeout,
+		IdleTimeout:       settings.HttpIdleTimeout,
+		MaxHeaderBytes:    settings.HttpMaxHeaderBytes,
+	}
+	server.RegisterOnShutdown(hub.Close)
+	return server, nil
+}
+
+func NewRouter(
+	settings *config.Settings,
+	authService auth.AuthService,
+	userService ports.UserService,
+	profileService ports.ProfileService,
+	mfaService ports.MfaService,
+	accountService ports.AccountService,
+	ssoService ports.SsoService,
+	videoService ports.VideoService,
+	annotationService ports.AnnotationService,
+	webhookService ports.WebhookService,
+	workspaceService ports.WorkspaceService,
+	apiKeyService ports.ApiKeyService,
+	backupService ports.BackupService,
+	hub *stream.Hub,
+	readiness *health.Readiness) (*mux.Router, error) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), settings)
+	if err != nil {
+		return nil, err
+	}
+
 	router := mux.NewRouter()
+	router.Use(TracingMiddleware, RequestIDMiddleware, AccessLogMiddleware, MetricsMiddleware, ApiKeyMiddleware(apiKeyService), RateLimitMiddleware(limiter, authService))
+
+	if settings.OpenAPIValidation {
+		document, err := openapi.Load()
+		if err != nil {
+			return nil, err
+		}
+		router.Use(ContractValidationMiddleware(document))
+	}
+	router.Use(WriteAuthorizationMiddleware(accountService, authService))
+
+	healthHandler := NewHealthHandler(readiness)
+	router.HandleFunc("/healthz", healthHandler.LivenessHandler).Methods("GET")
+	router.HandleFunc("/readyz", healthHandler.ReadinessHandler).Methods("GET")
+	router.HandleFunc("/version", healthHandler.VersionHandler).Methods("GET")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	jwksHandler := NewJwksHandler(authService)
+	router.HandleFunc("/.well-known/jwks.json", jwksHandler.KeysHandler).Methods("GET")
+
+	router.HandleFunc("/openapi.json", OpenAPISpecHandler).Methods("GET")
+	router.HandleFunc("/docs", OpenAPIDocsHandler).Methods("GET")
+
 	userHandler := NewUserHandler(userService)
 
 	router.HandleFunc("/signup", userHandler.SignupHandler).Methods("POST")
 	router.HandleFunc("/login", userHandler.LoginHandler).Methods("POST")
 
+	profileHandler := NewProfileHandler(profileService, authService)
+	router.HandleFunc("/account", profileHandler.GetHandler).Methods("GET")
+	router.HandleFunc("/account", profileHandler.PatchHandler).Methods("PATCH")
+	router.HandleFunc("/account/password", profileHandler.PasswordHandler).Methods("POST")
+	router.HandleFunc("/account/email", profileHandler.EmailHandler).Methods("POST")
+	router.HandleFunc("/account/delete", profileHandler.DeleteHandler).Methods("POST")
+
+	mfaHandler := NewMfaHandler(mfaService, authService)
+	router.HandleFunc("/login/mfa", mfaHandler.LoginHandler).Methods("POST")
+	router.HandleFunc("/account/mfa", mfaHandler.EnrollHandler).Methods("POST")
+	router.HandleFunc("/account/mfa/confirm", mfaHandler.ConfirmHandler).Methods("POST")
+	router.HandleFunc("/account/mfa/disable", mfaHandler.DisableHandler).Methods("POST")
+	router.HandleFunc("/account/mfa/recovery-codes", mfaHandler.RecoveryCodesHandler).Methods("POST")
+
+	ssoHandler := NewSsoHandler(ssoService, strings.HasPrefix(settings.OidcRedirectURL, "https://"))
+	router.HandleFunc("/login/oidc", ssoHandler.LoginHandler).Methods("GET")
+	router.HandleFunc("/login/oidc/callback", ssoHandler.CallbackHandler).Methods("GET")
+
+	accountHandler := NewAccountHandler(accountService, authService)
+	router.HandleFunc("/verify-email/request", accountHandler.RequestVerificationHandler).Methods("POST")
+	router.HandleFunc("/verify-email", accountHandler.VerifyEmailHandler).Methods("POST")
+	router.HandleFunc("/reset-password/request", accountHandler.RequestPasswordResetHandler).Methods("POST")
+	router.HandleFunc("/reset-password", accountHandler.ResetPasswordHandler).Methods("POST")
+
+	apiKeyHandler := NewApiKeyHandler(apiKeyService, authService)
+	router.HandleFunc("/account/api-keys", apiKeyHandler.CreateHandler).Methods("POST")
+	router.HandleFunc("/account/api-keys", apiKeyHandler.ListHandler).Methods("GET")
+	router.HandleFunc("/account/api-keys/{id}", apiKeyHandler.DeleteHandler).Methods("DELETE")
+
 	videorHandler := NewVideoHandler(videoService, authService)
 	router.HandleFunc("/videos/", videorHandler.CreateHandler).Methods("POST")
 	router.HandleFunc("/videos/{id}/", videorHandler.UpdateHandler).Methods("PUT")
+	router.HandleFunc("/videos/{id}/", videorHandler.PatchHandler).Methods("PATCH")
 	router.HandleFunc("/videos/{id}/", videorHandler.GetHandler).Methods("GET")
 	router.HandleFunc("/videos/{id}/", videorHandler.DeleteHandler).Methods("DELETE")
 
-	http.ListenAndServe(":8080", nil)
+	streamHandler := NewStreamHandler(videoService, authService, hub)
+	router.HandleFunc("/videos/{id}/events", streamHandler.EventsHandler).Methods("GET")
+	router.HandleFunc("/videos/{id}/ws", streamHandler.WebSocketHandler).Methods("GET")
+
+	annotationHandler := NewAnnotationHandler(annotationService, authService)
+	router.HandleFunc("/annotations/{id}/revisions", annotationHandler.RevisionsHandler).Methods("GET")
+	router.HandleFunc("/annotations/{id}/revisions/diff", annotationHandler.DiffHandler).Methods("GET")
+	router.HandleFunc("/annotations/{id}/revisions/{revision}/revert", annotationHandler.RevertHandler).Methods("POST")
+
+	webhookHandler := NewWebhookHandler(webhookService, authService)
+	router.HandleFunc("/webhooks/", webhookHandler.CreateHandler).Methods("POST")
+	router.HandleFunc("/webhooks/", webhookHandler.ListHandler).Methods("GET")
+	router.HandleFunc("/webhooks/{id}/", webhookHandler.DeleteHandler).Methods("DELETE")
+	router.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.DeliveriesHandler).Methods("GET")
+
+	workspaceHandler := NewWorkspaceHandler(workspaceService, videoService, authService)
+	router.HandleFunc("/workspaces/", workspaceHandler.CreateHandler).Methods("POST")
+	router.HandleFunc("/workspaces/", workspaceHandler.ListHandler).Methods("GET")
+	router.HandleFunc("/workspaces/join", workspaceHandler.JoinHandler).Methods("POST")
+	router.HandleFunc("/workspaces/{id}/members", workspaceHandler.MembersHandler).Methods("GET")
+	router.HandleFunc("/workspaces/{id}/members/{username}", workspaceHandler.ChangeRoleHandler).Methods("PUT")
+	router.HandleFunc("/workspaces/{id}/members/{username}", workspaceHandler.RemoveMemberHandler).Methods("DELETE")
+	router.HandleFunc("/workspaces/{id}/invitations", workspaceHandler.InviteHandler).Methods("POST")
+	router.HandleFunc("/workspaces/{id}/videos", workspaceHandler.VideosHandler).Methods("GET")
+
+	backupHandler := NewBackupHandler(backupService, userService, authService)
+	router.HandleFunc("/admin/backups", backupHandler.CreateHandler).Methods("POST")
+	router.HandleFunc("/admin/backups", backupHandler.ListHandler).Methods("GET")
+
+	adminUserHandler := NewAdminUserHandler(userService, authService)
+	router.HandleFunc("/admin/users/{username}/unlock", adminUserHandler.UnlockHandler).Methods("POST")
+
+	graphqlHandler, err := graphqlapi.NewHandler(authService, userService, accountService, videoService)
+	if err != nil {
+		return nil, err
+	}
+	router.Handle("/graphql", graphqlHandler).Methods("POST")
+
+	return router, nil
 }
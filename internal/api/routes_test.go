@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func TestNewHttpServer_AppliesSettings(t *testing.T) {
+	// Setup
+	settings := &config.Settings{
+		HttpAddress:           ":8081",
+		HttpReadTimeout:       time.Second,
+		HttpReadHeaderTimeout: 2 * time.Second,
+		HttpWriteTimeout:      3 * time.Second,
+		HttpIdleTimeout:       4 * time.Second,
+		HttpMaxHeaderBytes:    4096,
+	}
+
+	// Execute
+	server, err := NewHttpServer(settings, new(AuthService), &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), new(AccountServiceMock), new(SsoServiceMock), new(VideoServiceMock),
+		new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), new(ApiKeyServiceMock), new(BackupServiceMock), stream.NewHub(), health.NewReadiness())
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, ":8081", server.Addr)
+	assert.Equal(t, time.Second, server.ReadTimeout)
+	assert.Equal(t, 2*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, server.WriteTimeout)
+	assert.Equal(t, 4*time.Second, server.IdleTimeout)
+	assert.Equal(t, 4096, server.MaxHeaderBytes)
+	assert.NotNil(t, server.Handler)
+}
+
+func TestNewHttpServer_StreamsOutliveWriteTimeoutAndEndOnShutdown(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	hub := stream.NewHub()
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, []*model.Annotation{}, nil)
+
+	settings := &config.Settings{HttpWriteTimeout: 100 * time.Millisecond}
+	server, err := NewHttpServer(settings, authServiceMock, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), new(AccountServiceMock), new(SsoServiceMock), videoServiceMock,
+		new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), new(ApiKeyServiceMock), new(BackupServiceMock), hub, health.NewReadiness())
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	req, err := http.NewRequest("GET", "http://"+listener.Addr().String()+"/videos/1/events", nil)
+	require.NoError(t, err)
+	req.Header.Add("Authorization", token)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	reader := bufio.NewReader(res.Body)
+
+	// Execute
+	time.Sleep(3 * settings.HttpWriteTimeout)
+	publishAnnotation(t, hub, model.EventAnnotationCreated, "late")
+	event := readServerSentEvent(t, reader)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdownErr := server.Shutdown(ctx)
+
+	// Verify
+	assert.Equal(t, "event: annotation.created", event[1])
+	assert.NoError(t, shutdownErr)
+	_, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+}
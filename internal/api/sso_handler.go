@@ -0,0 +1,87 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+const ssoStateCookie = "oidc_state"
+
+type SsoHandler struct {
+	ssoService ports.SsoService
+	// secureCookie is set when the callback is served over https
+	secureCookie bool
+}
+
+func NewSsoHandler(ssoService ports.SsoService, secureCookie bool) *SsoHandler {
+	return &SsoHandler{
+		ssoService:   ssoService,
+		secureCookie: secureCookie,
+	}
+}
+
+// LoginHandler sends the browser to the identity provider. The state is also
+// kept in a cookie so the callback only finishes logins this browser started.
+func (h *SsoHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	state, url, err := h.ssoService.Begin(r.Context())
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	h.setStateCookie(w, state, int(service.SsoLoginTTL.Seconds()))
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+func (h *SsoHandler) CallbackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	h.setStateCookie(w, "", -1)
+	if reason := query.Get("error"); reason != "" {
+		logging.FromContext(r.Context()).Info("single sign-on refused by the identity provider", "reason", reason, "description", query.Get("error_description"))
+		respondWithError(w, r, service.ErrSsoRejected)
+		return
+	}
+
+	state := query.Get("state")
+	cookie, err := r.Cookie(ssoStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		respondWithError(w, r, service.ErrSsoLoginInvalid)
+		return
+	}
+
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
+	token, err := h.ssoService.Finish(ctx, state, query.Get("code"))
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	respondWithToken(w, token)
+}
+
+func (h *SsoHandler) setStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    state,
+		Path:     "/login/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+)
+
+func callbackRequest(query string, state string) *http.Request {
+	req := httptest.NewRequest("GET", "/login/oidc/callback?"+query, nil)
+	req.RemoteAddr = "192.0.2.1:54321"
+	if state != "" {
+		req.AddCookie(&http.Cookie{Name: ssoStateCookie, Value: state})
+	}
+	return req
+}
+
+func TestSsoHandler_LoginHandler(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, true)
+	ssoServiceMock.On("Begin").Return("state-1", "https://idp.example.com/auth?state=state-1", nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LoginHandler(rr, httptest.NewRequest("GET", "/login/oidc", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "https://idp.example.com/auth?state=state-1", rr.Header().Get("Location"))
+	cookie := rr.Result().Cookies()[0]
+	assert.Equal(t, ssoStateCookie, cookie.Name)
+	assert.Equal(t, "state-1", cookie.Value)
+	assert.Equal(t, 600, cookie.MaxAge)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+}
+
+func TestSsoHandler_LoginHandler_NotConfigured(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, false)
+	ssoServiceMock.On("Begin").Return("", "", service.ErrSsoNotConfigured)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.LoginHandler(rr, httptest.NewRequest("GET", "/login/oidc", nil))
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeNotFound)
+}
+
+func TestSsoHandler_CallbackHandler(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, false)
+	ssoServiceMock.On("Finish", "state-1", "code-1").Return("test-token", nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CallbackHandler(rr, callbackRequest("code=code-1&state=state-1", "state-1"))
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"token":"test-token"}`, rr.Body.String())
+	assert.Equal(t, -1, rr.Result().Cookies()[0].MaxAge)
+}
+
+func TestSsoHandler_CallbackHandler_StateOfOtherBrowser(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, false)
+
+	// Execute
+	missing := httptest.NewRecorder()
+	handler.CallbackHandler(missing, callbackRequest("code=code-1&state=state-1", ""))
+	other := httptest.NewRecorder()
+	handler.CallbackHandler(other, callbackRequest("code=code-1&state=state-1", "state-2"))
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, missing.Code)
+	assert.Equal(t, http.StatusBadRequest, other.Code)
+	assert.Contains(t, other.Body.String(), ProblemTypeInvalidLink)
+	ssoServiceMock.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything)
+}
+
+func TestSsoHandler_CallbackHandler_ProviderError(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, false)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CallbackHandler(rr, callbackRequest("error=access_denied&state=state-1", "state-1"))
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	ssoServiceMock.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything)
+}
+
+func TestSsoHandler_CallbackHandler_NoRole(t *testing.T) {
+	// Setup
+	ssoServiceMock := new(SsoServiceMock)
+	handler := NewSsoHandler(ssoServiceMock, false)
+	ssoServiceMock.On("Finish", "state-1", "code-1").Return("", service.ErrSsoNoRole)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CallbackHandler(rr, callbackRequest("code=code-1&state=state-1", "state-1"))
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeForbidden)
+}
+
+type SsoServiceMock struct {
+	mock.Mock
+}
+
+func (s *SsoServiceMock) Begin(ctx context.Context) (string, string, error) {
+	args := s.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (s *SsoServiceMock) Finish(ctx context.Context, state string, code string) (string, error) {
+	args := s.Called(state, code)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+const (
+	streamHeartbeat    = 15 * time.Second
+	websocketWriteWait = 10 * time.Second
+)
+
+// streamingRoutes hold the connection open, their responses are never
+// buffered for contract validation.
+var streamingRoutes = map[string]bool{
+	"/videos/{id}/events": true,
+	"/videos/{id}/ws":     true,
+}
+
+// StreamHandler pushes the annotation events of a video to connected
+// clients, as Server-Sent Events or over a WebSocket.
+type StreamHandler struct {
+	videoService ports.VideoService
+	authService  auth.AuthService
+	hub          *stream.Hub
+	upgrader     websocket.Upgrader
+	heartbeat    time.Duration
+}
+
+// follower is a subscription with the user and the video it was authorized
+// for, so access can be checked again while the stream is open.
+type follower struct {
+	*stream.Subscription
+	backlog  []*stream.Message
+	username string
+	videoId  int
+}
+
+type streamMessageDto struct {
+	ID   uint64          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func NewStreamHandler(videoService ports.VideoService, authService auth.AuthService, hub *stream.Hub) *StreamHandler {
+	return &StreamHandler{
+		videoService: videoService,
+		authService:  authService,
+		hub:          hub,
+		heartbeat:    streamHeartbeat,
+	}
+}
+
+func (h *StreamHandler) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, r, fmt.Errorf("streaming is not supported by the connection"))
+		return
+	}
+
+	sub, ok := h.subscribe(w, r, r.Header.Get("Last-Event-ID"))
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	// The stream outlives the server write timeout, which is meant for
+	// regular requests.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, message := range sub.backlog {
+		writeServerSentEvent(w, message)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case message, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			writeServerSentEvent(w, message)
+		case <-heartbeat.C:
+			if !h.allowed(r, sub) {
+				return
+			}
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+func (h *StreamHandler) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.subscribe(w, r, "")
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered the request
+		return
+	}
+	defer conn.Close()
+
+	// Clients do not send anything, reading only notices when they leave.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, message := range sub.backlog {
+		if err := writeWebSocketMessage(conn, message); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case message, ok := <-sub.C:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+					time.Now().Add(websocketWriteWait))
+				return
+			}
+			if err := writeWebSocketMessage(conn, message); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if !h.allowed(r, sub) {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "access revoked"),
+					time.Now().Add(websocketWriteWait))
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe authenticates the request and starts following the video.
+// Browsers cannot set headers on EventSource or WebSocket requests, so the
+// last event id is also accepted as a query parameter.
+func (h *StreamHandler) subscribe(w http.ResponseWriter, r *http.Request, lastEventID string) (*follower, bool) {
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, streamToken(r)); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return nil, false
+	}
+
+	videoId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return nil, false
+	}
+
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var lastID uint64
+	resume := lastEventID != ""
+	if resume {
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			respondWithError(w, r, ErrInvalidPayload)
+			return nil, false
+		}
+	}
+
+	video, _, err := h.videoService.Find(r.Context(), username, videoId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return nil, false
+	}
+
+	sub, backlog := h.hub.Subscribe(videoId, video.WorkspaceID, lastID, resume)
+	return &follower{Subscription: sub, backlog: backlog, username: username, videoId: videoId}, true
+}
+
+// allowed checks on every heartbeat that the user can still see the video,
+// the stream ends once they left its workspace or were disabled.
+func (h *StreamHandler) allowed(r *http.Request, sub *follower) bool {
+	_, _, err := h.videoService.Find(r.Context(), sub.username, sub.videoId)
+	return err == nil
+}
+
+// streamToken reads the token from the Authorization header. EventSource and
+// WebSocket requests cannot set headers in browsers, only those may pass it
+// in the access_token query parameter, where proxies in front of the server
+// may log it.
+func streamToken(r *http.Request) string {
+	if tokenString := r.Header.Get("Authorization"); tokenString != "" {
+		return tokenString
+	}
+	if websocket.IsWebSocketUpgrade(r) || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return r.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+func writeServerSentEvent(w http.ResponseWriter, message *stream.Message) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Type, message.Data)
+}
+
+func writeWebSocketMessage(conn *websocket.Conn, message *stream.Message) error {
+	conn.SetWriteDeadline(time.Now().Add(websocketWriteWait))
+	return conn.WriteJSON(&streamMessageDto{ID: message.ID, Type: message.Type, Data: message.Data})
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func TestStreamHandler_EventsHandler_ResumesAndStreams(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	hub := stream.NewHub()
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, []*model.Annotation{}, nil)
+
+	publishAnnotation(t, hub, model.EventAnnotationCreated, "first")
+	publishAnnotation(t, hub, model.EventAnnotationUpdated, "second")
+
+	server := newStreamServer(t, videoServiceMock, authServiceMock, hub)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/videos/1/events", nil)
+	require.NoError(t, err)
+	req.Header.Add("Authorization", token)
+	req.Header.Add("Last-Event-ID", "1")
+
+	// Execute
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	reader := bufio.NewReader(res.Body)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	event := readServerSentEvent(t, reader)
+	assert.Equal(t, "id: 2", event[0])
+	assert.Equal(t, "event: annotation.updated", event[1])
+	assert.Contains(t, event[2], `"note":"second"`)
+
+	publishAnnotation(t, hub, model.EventAnnotationDeleted, "third")
+	event = readServerSentEvent(t, reader)
+	assert.Equal(t, "id: 3", event[0])
+	assert.Equal(t, "event: annotation.deleted", event[1])
+}
+
+func TestStreamHandler_EventsHandler_Unauthorized(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewStreamHandler(new(VideoServiceMock), authServiceMock, stream.NewHub())
+
+	authServiceMock.On("ValidateJwtToken", "").Return(false, "")
+
+	req, err := http.NewRequest("GET", "/videos/1/events", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EventsHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestStreamHandler_EventsHandler_VideoNotFound(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
+
+	server := newStreamServer(t, videoServiceMock, authServiceMock, stream.NewHub())
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/videos/1/events?access_token="+token, nil)
+	require.NoError(t, err)
+	req.Header.Add("Accept", "text/event-stream")
+
+	// Execute
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestStreamHandler_EventsHandler_AccessTokenOnlyForStreams(t *testing.T) {
+	// Setup
+	authServiceMock := new(AuthService)
+	handler := NewStreamHandler(new(VideoServiceMock), authServiceMock, stream.NewHub())
+
+	authServiceMock.On("ValidateJwtToken", "").Return(false, "")
+
+	req, err := http.NewRequest("GET", "/videos/1/events?access_token=test-token", nil)
+	require.NoError(t, err)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.EventsHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	authServiceMock.AssertNotCalled(t, "ValidateJwtToken", "test-token")
+}
+
+func TestStreamHandler_EventsHandler_EndsWhenAccessIsLost(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	hub := stream.NewHub()
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, []*model.Annotation{}, nil).Once()
+	videoServiceMock.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
+
+	handler := NewStreamHandler(videoServiceMock, authServiceMock, hub)
+	handler.heartbeat = 10 * time.Millisecond
+
+	req, err := http.NewRequest("GET", "/videos/1/events", nil)
+	require.NoError(t, err)
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.EventsHandler(rr, req)
+	}()
+
+	// Verify
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream was not ended")
+	}
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), ": ping")
+}
+
+func TestStreamHandler_WebSocketHandler(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	hub := stream.NewHub()
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, []*model.Annotation{}, nil)
+
+	publishAnnotation(t, hub, model.EventAnnotationCreated, "first")
+
+	server := newStreamServer(t, videoServiceMock, authServiceMock, hub)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/videos/1/ws?access_token=" + token + "&last_event_id=0"
+
+	// Execute
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// Verify
+	message := &streamMessageDto{}
+	require.NoError(t, conn.ReadJSON(message))
+	assert.Equal(t, uint64(1), message.ID)
+	assert.Equal(t, model.EventAnnotationCreated, message.Type)
+	assert.Contains(t, string(message.Data), `"note":"first"`)
+
+	publishAnnotation(t, hub, model.EventAnnotationUpdated, "second")
+	require.NoError(t, conn.ReadJSON(message))
+	assert.Equal(t, uint64(2), message.ID)
+	assert.Equal(t, model.EventAnnotationUpdated, message.Type)
+}
+
+// newStreamServer serves the full router with contract validation enabled,
+// streams must not be held back by the response buffering.
+func newStreamServer(t *testing.T, videoService *VideoServiceMock, authService *AuthService, hub *stream.Hub) *httptest.Server {
+	settings := &config.Settings{OpenAPIValidation: true}
+	router, err := NewRouter(settings, authService, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), new(AccountServiceMock), new(SsoServiceMock), videoService, new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), new(ApiKeyServiceMock), new(BackupServiceMock), hub, health.NewReadiness())
+	require.NoError(t, err)
+	return httptest.NewServer(router)
+}
+
+func publishAnnotation(t *testing.T, hub *stream.Hub, eventType, note string) {
+	event := &model.Event{Type: eventType, VideoID: 1, Annotation: &model.Annotation{ID: 1, VideoID: 1, Type: "note", Note: note}}
+	require.NoError(t, hub.Publish(context.Background(), event))
+}
+
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) []string {
+	lines := []string{}
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
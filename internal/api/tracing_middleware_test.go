@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+)
+
+func TestTracingMiddleware_ContinuesInboundTrace(t *testing.T) {
+	// Setup
+	spans := recordSpans()
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+	router := newContractRouter(t, videoServiceMock, authServiceMock)
+
+	authServiceMock.On("ValidateJwtToken", "test-token").Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 41).Return(nil, nil, service.ErrVideoNotFound)
+
+	req := httptest.NewRequest("GET", "/videos/41/", nil)
+	req.Header.Set("Authorization", "test-token")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// Execute
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Verify
+	ended := spans()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "GET /videos/{id}/", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", 404))
+}
+
+func TestTracingMiddleware_StartsNewTrace(t *testing.T) {
+	// Setup
+	spans := recordSpans()
+	router := newContractRouter(t, new(VideoServiceMock), new(AuthService))
+
+	// Execute
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+	// Verify
+	ended := spans()
+	require.Len(t, ended, 1)
+	assert.Equal(t, "GET /healthz", ended[0].Name())
+	assert.False(t, ended[0].Parent().IsValid())
+}
+
+var (
+	spanRecorder = tracetest.NewSpanRecorder()
+	installOnce  sync.Once
+)
+
+// recordSpans returns the spans that ended after it was called.
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	installOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	start := len(spanRecorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return spanRecorder.Ended()[start:]
+	}
+}
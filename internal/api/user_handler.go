@@ -2,12 +2,14 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
-	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/ratelimit"
 )
 
 type UserHandler struct {
@@ -22,33 +24,21 @@ func NewUserHandler(us ports.UserService) *UserHandler {
 
 func (h *UserHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
 	userDto := &UserDto{}
 
 	if err := json.NewDecoder(r.Body).Decode(userDto); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 
 	var token string
 	var err error
-	if token, err = h.userService.Signup(userDto.Email, userDto.Password); err != nil {
-		statusCode := http.StatusInternalServerError
-		if err == service.UserOrPasswordNotFoundError {
-			statusCode = http.StatusUnauthorized
-		}
-
-		if _, ok := validation.UserValidationErrors[err]; ok {
-			statusCode = http.StatusBadRequest
-			msg := fmt.Sprintf("Singnup failed due %s", err.Error())
-			http.Error(w, msg, statusCode)
-			return
-		}
-
-		http.Error(w, "Signup failed", statusCode)
+	if token, err = h.userService.Signup(r.Context(), userDto.Email, userDto.Password); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
@@ -57,30 +47,42 @@ func (h *UserHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
 	userDto := &UserDto{}
 	if err := json.NewDecoder(r.Body).Decode(userDto); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 
+	ctx := auth.WithClientAddress(r.Context(), remoteAddress(r))
 	var token string
 	var err error
-	if token, err = h.userService.Login(userDto.Email, userDto.Password); err != nil {
-		statusCode := http.StatusInternalServerError
-		if err == service.UserOrPasswordNotFoundError {
-			statusCode = http.StatusUnauthorized
+	if token, err = h.userService.Login(ctx, userDto.Email, userDto.Password); err != nil {
+		var required *service.MfaRequiredError
+		if errors.As(err, &required) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&MfaChallengeDto{MfaRequired: true, ChallengeToken: required.Challenge})
+			return
 		}
-		http.Error(w, "Login failed", statusCode)
+		respondWithLoginError(w, r, err)
 		return
 	}
 
 	respondWithToken(w, token)
 }
 
+// respondWithLoginError tells throttled clients when to retry.
+func respondWithLoginError(w http.ResponseWriter, r *http.Request, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, ratelimit.Seconds(throttled.RetryAfter))))
+	}
+	respondWithError(w, r, err)
+}
+
 func respondWithToken(w http.ResponseWriter, token string) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -2,12 +2,16 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
 	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
 	"github.com/stretchr/testify/require"
 )
@@ -199,9 +203,51 @@ func TestUserHandler_LoginHandler_UnhappyPath_InternalServerError(t *testing.T)
 	require.Equal(t, http.StatusUnauthorized, respWriter.Code)
 }
 
+func TestUserHandler_LoginHandler_UnhappyPath_Throttled(t *testing.T) {
+	// fixture
+	handler := NewUserHandler(&mockUserService{})
+
+	payload := map[string]string{
+		"email":    "locked-user@example.com",
+		"password": "password",
+	}
+	body, _ := json.Marshal(payload)
+
+	req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
+	respWriter := httptest.NewRecorder()
+
+	// test
+	handler.LoginHandler(respWriter, req)
+
+	// assertion
+	require.Equal(t, http.StatusTooManyRequests, respWriter.Code)
+	require.Equal(t, "90", respWriter.Header().Get("Retry-After"))
+}
+
+func TestUserHandler_LoginHandler_MfaRequired(t *testing.T) {
+	// fixture
+	handler := NewUserHandler(&mockUserService{})
+
+	payload := map[string]string{
+		"email":    "mfa-user@example.com",
+		"password": "password",
+	}
+	body, _ := json.Marshal(payload)
+
+	req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
+	respWriter := httptest.NewRecorder()
+
+	// test
+	handler.LoginHandler(respWriter, req)
+
+	// assertion
+	require.Equal(t, http.StatusOK, respWriter.Code)
+	require.JSONEq(t, `{"mfa_required": true, "challenge_token": "challenge"}`, respWriter.Body.String())
+}
+
 type mockUserService struct{}
 
-func (s *mockUserService) Signup(email, password string) (string, error) {
+func (s *mockUserService) Signup(ctx context.Context, email, password string) (string, error) {
 	if email == "existing-user@example.com" {
 		return "", service.UserOrPasswordNotFoundError
 	}
@@ -211,10 +257,50 @@ func (s *mockUserService) Signup(email, password string) (string, error) {
 	return "token", nil
 }
 
-func (s *mockUserService) Login(email, password string) (string, error) {
+func (s *mockUserService) Login(ctx context.Context, email, password string) (string, error) {
+	if email == "locked-user@example.com" {
+		return "", &service.LoginThrottledError{RetryAfter: 90 * time.Second}
+	}
+	if email == "mfa-user@example.com" {
+		return "", &service.MfaRequiredError{Challenge: "challenge"}
+	}
 	if email == "non-existing-user@example.com" || password == "invalid-password" {
 		return "", service.UserOrPasswordNotFoundError
 	}
 
 	return "token", nil
 }
+
+func (s *mockUserService) Find(ctx context.Context, username string) (*model.User, error) {
+	if username == "admin-user" {
+		return &model.User{Username: username, Role: model.RoleAdmin}, nil
+	}
+	return &model.User{Username: username, Role: model.RoleEditor}, nil
+}
+
+func (s *mockUserService) FindMany(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	return map[int]*model.User{}, nil
+}
+
+func (s *mockUserService) Create(ctx context.Context, email, password, role string) (*model.User, error) {
+	return &model.User{Email: email, Role: role}, nil
+}
+
+func (s *mockUserService) Disable(ctx context.Context, username string) error {
+	return nil
+}
+
+func (s *mockUserService) Unlock(ctx context.Context, username string) error {
+	if username == "unknown-user" {
+		return repository.UserNotFoundError
+	}
+	return nil
+}
+
+func (s *mockUserService) SetRole(ctx context.Context, username, role string) error {
+	return nil
+}
+
+func (s *mockUserService) ResetPassword(ctx context.Context, username, password string) error {
+	return nil
+}
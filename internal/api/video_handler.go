@@ -2,14 +2,11 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
-	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
-	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
 	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
 )
 
@@ -27,7 +24,7 @@ func NewVideoHandler(service ports.VideoService, authService auth.AuthService) *
 
 func (h *VideoHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
@@ -35,36 +32,20 @@ func (h *VideoHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	var username string
 	var ok bool
-	if ok, username = h.authService.ValidateJwtToken(tokenString); !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
 		return
 	}
 
 	videoDto := &VideoDto{}
 
 	if err := json.NewDecoder(r.Body).Decode(videoDto); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 
-	if err := h.videoService.Create(username, &videoDto.Video, videoDto.Annotaions); err != nil {
-		statusCode := http.StatusInternalServerError
-
-		if _, ok := validation.VideoValidationErrors[err]; !ok {
-			statusCode = http.StatusBadRequest
-			msg := fmt.Sprintf("Request failed due %s", err.Error())
-			http.Error(w, msg, statusCode)
-			return
-		}
-
-		if _, ok := validation.AnnotationValidationErrors[err]; !ok {
-			statusCode = http.StatusBadRequest
-			msg := fmt.Sprintf("Request failed due %s", err.Error())
-			http.Error(w, msg, statusCode)
-			return
-		}
-
-		http.Error(w, "Request failed", statusCode)
+	if err := h.videoService.Create(r.Context(), username, &videoDto.Video, videoDto.Annotaions); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
@@ -73,91 +54,134 @@ func (h *VideoHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 func (h *VideoHandler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
 	tokenString := r.Header.Get("Authorization")
-	if ok, _ := h.authService.ValidateJwtToken(tokenString); !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
 		return
 	}
 
 	vars := mux.Vars(r)
 	videoIdStr, ok := vars["id"]
 	if !ok {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 	videoId, err := strconv.ParseInt(videoIdStr, 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	version, err := h.expectedVersion(r, username, int(videoId))
+	if err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
 	videoDto := &VideoDto{}
 
 	if err := json.NewDecoder(r.Body).Decode(videoDto); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
+	videoDto.Video.Version = version
 
-	if err := h.videoService.Update(int(videoId), &videoDto.Video, videoDto.Annotaions); err != nil {
-		statusCode := http.StatusInternalServerError
+	if err := h.videoService.Update(r.Context(), username, int(videoId), &videoDto.Video, videoDto.Annotaions); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
 
-		if _, ok := validation.VideoValidationErrors[err]; ok {
-			statusCode = http.StatusBadRequest
-			msg := fmt.Sprintf("Request failed due %s", err.Error())
-			http.Error(w, msg, statusCode)
-			return
-		}
+	setETag(w, version+1)
+	w.WriteHeader(http.StatusOK)
 
-		if _, ok := validation.AnnotationValidationErrors[err]; ok {
-			statusCode = http.StatusBadRequest
-			msg := fmt.Sprintf("Request failed due %s", err.Error())
-			http.Error(w, msg, statusCode)
-			return
-		}
+}
 
-		http.Error(w, "Request failed", statusCode)
+func (h *VideoHandler) PatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	tokenString := r.Header.Get("Authorization")
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	videoId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
 
+	match, err := parseIfMatch(r)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	patchDto := &VideoPatchDto{}
+	if err := json.NewDecoder(r.Body).Decode(patchDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	video, _, err := h.videoService.Find(r.Context(), username, videoId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	version := match.version(video.Version)
+	patchDto.applyTo(video)
+	video.Version = version
+
+	if err := h.videoService.Update(r.Context(), username, videoId, video, nil); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	setETag(w, version+1)
+	w.WriteHeader(http.StatusOK)
 }
 
 func (h *VideoHandler) GetHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
 	tokenString := r.Header.Get("Authorization")
-	if ok, _ := h.authService.ValidateJwtToken(tokenString); !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
 		return
 	}
 
 	vars := mux.Vars(r)
 	videoIdStr, ok := vars["id"]
 	if !ok {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 	videoId, err := strconv.Atoi(videoIdStr)
 	if err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 
-	video, annotations, err := h.videoService.Find(videoId)
+	video, annotations, err := h.videoService.Find(r.Context(), username, videoId)
 	if err != nil {
-		if err == service.ErrVideoNotFound || err == service.ErrAnnotationsNotFound {
-			http.Error(w, "Video not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, "Request failed", http.StatusInternalServerError)
+		respondWithError(w, r, err)
 		return
 	}
 
@@ -166,6 +190,7 @@ func (h *VideoHandler) GetHandler(w http.ResponseWriter, r *http.Request) {
 		Annotaions: annotations,
 	}
 
+	setETag(w, video.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(videoDto)
 
@@ -173,33 +198,59 @@ func (h *VideoHandler) GetHandler(w http.ResponseWriter, r *http.Request) {
 
 func (h *VideoHandler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondWithError(w, r, ErrMethodNotAllowed)
 		return
 	}
 
 	tokenString := r.Header.Get("Authorization")
-	if ok, _ := h.authService.ValidateJwtToken(tokenString); !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
 		return
 	}
 
 	vars := mux.Vars(r)
 	videoIdStr, ok := vars["id"]
 	if !ok {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 	videoId, err := strconv.ParseInt(videoIdStr, 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		respondWithError(w, r, ErrInvalidPayload)
 		return
 	}
 
-	if err := h.videoService.Remove(int(videoId)); err != nil {
-		http.Error(w, "Request failed", http.StatusInternalServerError)
+	version, err := h.expectedVersion(r, username, int(videoId))
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	if err := h.videoService.Remove(r.Context(), username, int(videoId), version); err != nil {
+		respondWithError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusAccepted)
 
 }
+
+// expectedVersion reads the If-Match version, the stored one for * or for a
+// list naming it.
+func (h *VideoHandler) expectedVersion(r *http.Request, username string, videoId int) (int, error) {
+	match, err := parseIfMatch(r)
+	if err != nil {
+		return 0, err
+	}
+	if version, ok := match.single(); ok {
+		return version, nil
+	}
+
+	video, _, err := h.videoService.Find(r.Context(), username, videoId)
+	if err != nil {
+		return 0, err
+	}
+	return match.version(video.Version), nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -90,6 +93,7 @@ func TestVideoHandler_UpdateHandler(t *testing.T) {
 		Title:       "Test Video",
 		Description: "Test Description",
 		Link:        "https://example.com/test.mp4",
+		Version:     1,
 	}
 
 	annotations := []*model.Annotation{
@@ -129,9 +133,10 @@ func TestVideoHandler_UpdateHandler(t *testing.T) {
 	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
 
 	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"1"`)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 
-	videoServiceMock.On("Update", 1, &video, annotations).Return(nil)
+	videoServiceMock.On("Update", "test-user", 1, &video, annotations).Return(nil)
 
 	// Execute
 	rr := httptest.NewRecorder()
@@ -139,6 +144,7 @@ func TestVideoHandler_UpdateHandler(t *testing.T) {
 
 	// Verify
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
 	videoServiceMock.AssertExpectations(t)
 	authServiceMock.AssertExpectations(t)
 }
@@ -158,6 +164,7 @@ func TestVideoHandler_GetHandler(t *testing.T) {
 		UserID:      1,
 		Duration:    100,
 		CreatedAt:   time.Now(),
+		Version:     3,
 	}
 
 	annotations := []*model.Annotation{
@@ -181,7 +188,7 @@ func TestVideoHandler_GetHandler(t *testing.T) {
 		},
 	}
 
-	videoServiceMock.On("Find", 1).Return(&video, annotations, nil)
+	videoServiceMock.On("Find", "test-user", 1).Return(&video, annotations, nil)
 
 	req, err := http.NewRequest("GET", "/videos/1", nil)
 	if err != nil {
@@ -202,6 +209,7 @@ func TestVideoHandler_GetHandler(t *testing.T) {
 
 	// Verify
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
 	videoServiceMock.AssertExpectations(t)
 	authServiceMock.AssertExpectations(t)
 }
@@ -221,9 +229,10 @@ func TestVideoHandler_DeleteHandler(t *testing.T) {
 	token := "test-token"
 	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
 
-	videoServiceMock.On("Remove", 1).Return(nil)
+	videoServiceMock.On("Remove", "test-user", 1, 1).Return(nil)
 
 	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"1"`)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 
 	// Execute
@@ -281,6 +290,7 @@ func TestVideoHandler_UpdateHandler_InvalidRequestPayload(t *testing.T) {
 	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
 
 	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"1"`)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 
 	// Execute
@@ -356,7 +366,7 @@ func TestVideoHandler_GetHandler_VideoNotFound(t *testing.T) {
 
 	handler := NewVideoHandler(videoServiceMock, authServiceMock)
 
-	videoServiceMock.On("Find", 1).Return(nil, nil, service.ErrVideoNotFound)
+	videoServiceMock.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
 
 	req, err := http.NewRequest("GET", "/videos/1", nil)
 	if err != nil {
@@ -396,7 +406,7 @@ func TestVideoHandler_GetHandler_AnnotationsNotFound(t *testing.T) {
 		CreatedAt:   time.Now(),
 	}
 
-	videoServiceMock.On("Find", 1).Return(&video, nil, service.ErrAnnotationsNotFound)
+	videoServiceMock.On("Find", "test-user", 1).Return(&video, nil, service.ErrAnnotationsNotFound)
 
 	req, err := http.NewRequest("GET", "/videos/1", nil)
 	if err != nil {
@@ -447,16 +457,279 @@ func TestVideoHandler_DeleteHandler_InvalidVideoId(t *testing.T) {
 	authServiceMock.AssertExpectations(t)
 }
 
+func TestVideoHandler_UpdateHandler_MissingIfMatch(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("PUT", "/videos/1", bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.UpdateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_UpdateHandler_VersionMismatch(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	video := model.Video{
+		Title:       "Test Video",
+		Description: "Test Description",
+		Link:        "https://example.com/test.mp4",
+		Version:     1,
+	}
+	videoJson, _ := json.Marshal(VideoDto{Video: video})
+
+	req, err := http.NewRequest("PUT", "/videos/1", bytes.NewBuffer(videoJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Update", "test-user", 1, &video, []*model.Annotation(nil)).Return(ports.ErrVersionConflict)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"1"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.UpdateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_PatchHandler(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	createdAt := time.Now()
+	stored := &model.Video{
+		ID:          1,
+		Title:       "Test Video",
+		Description: "Test Description",
+		Link:        "https://example.com/test.mp4",
+		UserID:      1,
+		Duration:    100,
+		CreatedAt:   createdAt,
+		Version:     2,
+	}
+	patched := *stored
+	patched.Title = "New Title"
+
+	req, err := http.NewRequest("PATCH", "/videos/1", bytes.NewBufferString(`{"title":"New Title"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(stored, []*model.Annotation{}, nil)
+	videoServiceMock.On("Update", "test-user", 1, &patched, []*model.Annotation(nil)).Return(nil)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"2"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.PatchHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_DeleteHandler_VersionMismatch(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("DELETE", "/videos/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Remove", "test-user", 1, 4).Return(ports.ErrVersionConflict)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"4"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_DeleteHandler_WeakETag(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("DELETE", "/videos/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `W/"4"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	videoServiceMock.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVideoHandler_DeleteHandler_AnyVersion(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("DELETE", "/videos/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1, Version: 7}, []*model.Annotation{}, nil)
+	videoServiceMock.On("Remove", "test-user", 1, 7).Return(nil)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", "*")
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_DeleteHandler_VersionList(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("DELETE", "/videos/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1, Version: 7}, []*model.Annotation{}, nil)
+	videoServiceMock.On("Remove", "test-user", 1, 7).Return(nil)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"6", W/"8", "7"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestVideoHandler_DeleteHandler_VersionListWithoutCurrent(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewVideoHandler(videoServiceMock, authServiceMock)
+
+	req, err := http.NewRequest("DELETE", "/videos/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1, Version: 7}, []*model.Annotation{}, nil)
+	videoServiceMock.On("Remove", "test-user", 1, 5).Return(ports.ErrVersionConflict)
+
+	req.Header.Add("Authorization", token)
+	req.Header.Add("If-Match", `"5", W/"7", "6"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
 type VideoServiceMock struct {
 	mock.Mock
 }
 
-func (s *VideoServiceMock) Create(username string, video *model.Video, annotations []*model.Annotation) error {
+func (s *VideoServiceMock) Create(ctx context.Context, username string, video *model.Video, annotations []*model.Annotation) error {
 	args := s.Called(username, video, annotations)
 	return args.Error(0)
 }
-func (s *VideoServiceMock) Find(id int) (*model.Video, []*model.Annotation, error) {
-	args := s.Called(id)
+func (s *VideoServiceMock) Find(ctx context.Context, username string, id int) (*model.Video, []*model.Annotation, error) {
+	args := s.Called(username, id)
 	get0 := args.Get(0)
 	get1 := args.Get(1)
 	if get0 == nil || get1 == nil {
@@ -466,12 +739,36 @@ func (s *VideoServiceMock) Find(id int) (*model.Video, []*model.Annotation, erro
 	a := get1.([]*model.Annotation)
 	return v, a, args.Error(2)
 }
-func (s *VideoServiceMock) Update(id int, video *model.Video, annotations []*model.Annotation) error {
-	args := s.Called(id, video, annotations)
+func (s *VideoServiceMock) FindMany(ctx context.Context, username string, ids []int) (map[int]*model.Video, error) {
+	args := s.Called(username, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) FindAnnotations(ctx context.Context, username string, videoIds []int) (map[int][]*model.Annotation, error) {
+	args := s.Called(username, videoIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]*model.Annotation), args.Error(1)
+}
+
+func (s *VideoServiceMock) List(ctx context.Context, username string, workspaceId int) ([]*model.Video, error) {
+	args := s.Called(username, workspaceId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) Update(ctx context.Context, username string, id int, video *model.Video, annotations []*model.Annotation) error {
+	args := s.Called(username, id, video, annotations)
 	return args.Error(0)
 }
-func (s *VideoServiceMock) Remove(id int) error {
-	args := s.Called(id)
+func (s *VideoServiceMock) Remove(ctx context.Context, username string, id int, version int) error {
+	args := s.Called(username, id, version)
 	return args.Error(0)
 }
 
@@ -488,3 +785,28 @@ func (s *AuthService) ValidateJwtToken(tokenString string) (bool, string) {
 	args := s.Called(tokenString)
 	return args.Bool(0), args.String(1)
 }
+
+func (s *AuthService) GenerateMfaChallenge(username string) (string, error) {
+	args := s.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthService) ValidateMfaChallenge(tokenString string) (bool, string) {
+	args := s.Called(tokenString)
+	return args.Bool(0), args.String(1)
+}
+
+func (s *AuthService) GenerateActionToken(username string, purpose string, id string, ttl time.Duration) (string, error) {
+	args := s.Called(username, purpose, id, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthService) ValidateActionToken(tokenString string, purpose string) (bool, string, string) {
+	args := s.Called(tokenString, purpose)
+	return args.Bool(0), args.String(1), args.String(2)
+}
+
+func (s *AuthService) JSONWebKeys() []auth.JSONWebKey {
+	args := s.Called()
+	return args.Get(0).([]auth.JSONWebKey)
+}
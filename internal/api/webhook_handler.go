@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type WebhookHandler struct {
+	webhookService ports.WebhookService
+	authService    auth.AuthService
+}
+
+func NewWebhookHandler(service ports.WebhookService, authService auth.AuthService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: service,
+		authService:    authService,
+	}
+}
+
+func (h *WebhookHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	webhookDto := &WebhookDto{}
+	if err := json.NewDecoder(r.Body).Decode(webhookDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	subscription := &model.WebhookSubscription{
+		URL:             webhookDto.URL,
+		Secret:          webhookDto.Secret,
+		EventTypes:      webhookDto.EventTypes,
+		AnnotationTypes: webhookDto.AnnotationTypes,
+	}
+	if err := h.webhookService.Subscribe(r.Context(), username, subscription); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newWebhookSubscriptionDto(subscription, true))
+}
+
+func (h *WebhookHandler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	subscriptions, err := h.webhookService.Subscriptions(r.Context(), username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*WebhookSubscriptionDto{}
+	for _, subscription := range subscriptions {
+		dtos = append(dtos, newWebhookSubscriptionDto(subscription, false))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (h *WebhookHandler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	if err := h.webhookService.Unsubscribe(r.Context(), username, webhookId); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) DeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	deliveries, attempts, err := h.webhookService.Deliveries(r.Context(), username, webhookId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*WebhookDeliveryDto{}
+	for _, delivery := range deliveries {
+		dtos = append(dtos, newWebhookDeliveryDto(delivery, attempts[delivery.ID]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+func TestWebhookHandler_CreateHandler(t *testing.T) {
+	// Setup
+	webhookServiceMock := new(WebhookServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWebhookHandler(webhookServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	webhookServiceMock.On("Subscribe", "test-user", mock.AnythingOfType("*model.WebhookSubscription")).
+		Run(func(args mock.Arguments) {
+			subscription := args.Get(1).(*model.WebhookSubscription)
+			subscription.ID = 1
+			subscription.Secret = "generated-secret-value"
+		}).
+		Return(nil)
+
+	body := []byte(`{"url":"https://example.com/hook","event_types":["video.created"]}`)
+	req, err := http.NewRequest("POST", "/webhooks/", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response WebhookSubscriptionDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.ID)
+	assert.Equal(t, "generated-secret-value", response.Secret)
+	assert.Equal(t, []string{model.EventVideoCreated}, response.EventTypes)
+	assert.Equal(t, []string{}, response.AnnotationTypes)
+	webhookServiceMock.AssertExpectations(t)
+	authServiceMock.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateHandler_ValidationFailed(t *testing.T) {
+	// Setup
+	webhookServiceMock := new(WebhookServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWebhookHandler(webhookServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	webhookServiceMock.On("Subscribe", "test-user", mock.Anything).
+		Return(validation.Errors{{Field: "url", Err: validation.ErrWebhookURLIsInvalid}})
+
+	req, err := http.NewRequest("POST", "/webhooks/", bytes.NewReader([]byte(`{"url":"nope"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, "url", problem.Errors[0].Field)
+}
+
+func TestWebhookHandler_ListHandler_HidesSecret(t *testing.T) {
+	// Setup
+	webhookServiceMock := new(WebhookServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWebhookHandler(webhookServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	webhookServiceMock.On("Subscriptions", "test-user").Return([]*model.WebhookSubscription{
+		{ID: 1, URL: "https://example.com/hook", Secret: "very-secret-value"},
+	}, nil)
+
+	req, err := http.NewRequest("GET", "/webhooks/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.ListHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "very-secret-value")
+	webhookServiceMock.AssertExpectations(t)
+}
+
+func TestWebhookHandler_DeleteHandler_NotFound(t *testing.T) {
+	// Setup
+	webhookServiceMock := new(WebhookServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWebhookHandler(webhookServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	webhookServiceMock.On("Unsubscribe", "test-user", 1).Return(service.ErrWebhookNotFound)
+
+	req, err := http.NewRequest("DELETE", "/webhooks/1/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeleteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	webhookServiceMock.AssertExpectations(t)
+}
+
+func TestWebhookHandler_DeliveriesHandler(t *testing.T) {
+	// Setup
+	webhookServiceMock := new(WebhookServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWebhookHandler(webhookServiceMock, authServiceMock)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	deliveries := []*model.WebhookDelivery{
+		{ID: 2, EventID: "evt-2", EventType: model.EventVideoUpdated, Status: model.DeliveryPending, Attempts: 1, NextAttemptAt: now},
+		{ID: 1, EventID: "evt-1", EventType: model.EventVideoCreated, Status: model.DeliverySucceeded, Attempts: 1, DeliveredAt: &now},
+	}
+	attempts := map[int][]*model.WebhookDeliveryAttempt{
+		2: {{DeliveryID: 2, Attempt: 1, ResponseCode: 503, Error: "unexpected status 503", Duration: 20 * time.Millisecond, AttemptedAt: now}},
+		1: {{DeliveryID: 1, Attempt: 1, ResponseCode: 204, Duration: 5 * time.Millisecond, AttemptedAt: now}},
+	}
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	webhookServiceMock.On("Deliveries", "test-user", 3).Return(deliveries, attempts, nil)
+
+	req, err := http.NewRequest("GET", "/webhooks/3/deliveries", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.DeliveriesHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []*WebhookDeliveryDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, &now, response[0].NextAttemptAt)
+	assert.Equal(t, int64(20), response[0].Attempts[0].DurationMs)
+	assert.Nil(t, response[1].NextAttemptAt)
+	assert.Equal(t, 204, response[1].Attempts[0].ResponseCode)
+	webhookServiceMock.AssertExpectations(t)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func (s *WebhookServiceMock) Publish(ctx context.Context, event *model.Event) error {
+	args := s.Called(event)
+	return args.Error(0)
+}
+
+func (s *WebhookServiceMock) Subscribe(ctx context.Context, username string, subscription *model.WebhookSubscription) error {
+	args := s.Called(username, subscription)
+	return args.Error(0)
+}
+
+func (s *WebhookServiceMock) Subscriptions(ctx context.Context, username string) ([]*model.WebhookSubscription, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.WebhookSubscription), args.Error(1)
+}
+
+func (s *WebhookServiceMock) Unsubscribe(ctx context.Context, username string, id int) error {
+	args := s.Called(username, id)
+	return args.Error(0)
+}
+
+func (s *WebhookServiceMock) Deliveries(ctx context.Context, username string, id int) ([]*model.WebhookDelivery, map[int][]*model.WebhookDeliveryAttempt, error) {
+	args := s.Called(username, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*model.WebhookDelivery), args.Get(1).(map[int][]*model.WebhookDeliveryAttempt), args.Error(2)
+}
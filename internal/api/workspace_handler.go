@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type WorkspaceHandler struct {
+	workspaceService ports.WorkspaceService
+	videoService     ports.VideoService
+	authService      auth.AuthService
+}
+
+func NewWorkspaceHandler(service ports.WorkspaceService, videoService ports.VideoService, authService auth.AuthService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: service,
+		videoService:     videoService,
+		authService:      authService,
+	}
+}
+
+func (h *WorkspaceHandler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	nameDto := &WorkspaceNameDto{}
+	username, ok := h.authenticateWith(w, r, nameDto)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspaceService.Create(r.Context(), username, nameDto.Name)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newWorkspaceDto(workspace))
+}
+
+func (h *WorkspaceHandler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if username, ok = authenticate(r, h.authService, tokenString); !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return
+	}
+
+	workspaces, err := h.workspaceService.List(r.Context(), username)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*WorkspaceDto{}
+	for _, workspace := range workspaces {
+		dtos = append(dtos, newWorkspaceDto(workspace))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (h *WorkspaceHandler) MembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, workspaceId, ok := h.authenticateFor(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.Members(r.Context(), username, workspaceId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	dtos := []*WorkspaceMemberDto{}
+	for _, member := range members {
+		dtos = append(dtos, &WorkspaceMemberDto{Username: member.Username, Role: member.Role, JoinedAt: member.CreatedAt})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (h *WorkspaceHandler) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, workspaceId, ok := h.authenticateFor(w, r)
+	if !ok {
+		return
+	}
+
+	roleDto := &RoleDto{}
+	if err := json.NewDecoder(r.Body).Decode(roleDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	err := h.workspaceService.ChangeRole(r.Context(), username, workspaceId, mux.Vars(r)["username"], roleDto.Role)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WorkspaceHandler) RemoveMemberHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, workspaceId, ok := h.authenticateFor(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(r.Context(), username, workspaceId, mux.Vars(r)["username"]); err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WorkspaceHandler) InviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, workspaceId, ok := h.authenticateFor(w, r)
+	if !ok {
+		return
+	}
+
+	invitationDto := &InvitationDto{}
+	if err := json.NewDecoder(r.Body).Decode(invitationDto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return
+	}
+
+	err := h.workspaceService.Invite(r.Context(), username, workspaceId, invitationDto.Email, invitationDto.Role)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *WorkspaceHandler) JoinHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	joinDto := &JoinDto{}
+	username, ok := h.authenticateWith(w, r, joinDto)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspaceService.Join(r.Context(), username, joinDto.Token)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newWorkspaceDto(workspace))
+}
+
+func (h *WorkspaceHandler) VideosHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	username, workspaceId, ok := h.authenticateFor(w, r)
+	if !ok {
+		return
+	}
+
+	videos, err := h.videoService.List(r.Context(), username, workspaceId)
+	if err != nil {
+		respondWithError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(videos)
+}
+
+// authenticateWith reads the body of a request of a logged in user into dto,
+// it answers the request itself when that fails.
+func (h *WorkspaceHandler) authenticateWith(w http.ResponseWriter, r *http.Request, dto any) (string, bool) {
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return "", false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return "", false
+	}
+	return username, true
+}
+
+// authenticateFor reads the workspace id of a request of a logged in user,
+// it answers the request itself when that fails.
+func (h *WorkspaceHandler) authenticateFor(w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	username, ok := authenticate(r, h.authService, r.Header.Get("Authorization"))
+	if !ok {
+		respondWithError(w, r, ErrUnauthorized)
+		return "", 0, false
+	}
+
+	workspaceId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, r, ErrInvalidPayload)
+		return "", 0, false
+	}
+	return username, workspaceId, true
+}
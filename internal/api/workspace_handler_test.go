@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+func TestWorkspaceHandler_CreateHandler(t *testing.T) {
+	// Setup
+	workspaceServiceMock := new(WorkspaceServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWorkspaceHandler(workspaceServiceMock, new(VideoServiceMock), authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	workspaceServiceMock.On("Create", "test-user", "Annotators").
+		Return(&model.Workspace{ID: 2, Name: "Annotators", Role: model.RoleAdmin}, nil)
+
+	req, err := http.NewRequest("POST", "/workspaces/", bytes.NewReader([]byte(`{"name":"Annotators"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.CreateHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response WorkspaceDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.ID)
+	assert.Equal(t, model.RoleAdmin, response.Role)
+	workspaceServiceMock.AssertExpectations(t)
+}
+
+func TestWorkspaceHandler_InviteHandler_NotAllowed(t *testing.T) {
+	// Setup
+	workspaceServiceMock := new(WorkspaceServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWorkspaceHandler(workspaceServiceMock, new(VideoServiceMock), authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	workspaceServiceMock.On("Invite", "test-user", 1, "jane@example.com", model.RoleEditor).Return(service.ErrNotAllowed)
+
+	body := []byte(`{"email":"jane@example.com","role":"editor"}`)
+	req, err := http.NewRequest("POST", "/workspaces/1/invitations", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.InviteHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ProblemTypeForbidden)
+	workspaceServiceMock.AssertExpectations(t)
+}
+
+func TestWorkspaceHandler_RemoveMemberHandler_LastAdmin(t *testing.T) {
+	// Setup
+	workspaceServiceMock := new(WorkspaceServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWorkspaceHandler(workspaceServiceMock, new(VideoServiceMock), authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	workspaceServiceMock.On("RemoveMember", "test-user", 1, "test-user").Return(service.ErrLastAdmin)
+
+	req, err := http.NewRequest("DELETE", "/workspaces/1/members/test-user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "username": "test-user"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.RemoveMemberHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	workspaceServiceMock.AssertExpectations(t)
+}
+
+func TestWorkspaceHandler_VideosHandler_NotFound(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	authServiceMock := new(AuthService)
+
+	handler := NewWorkspaceHandler(new(WorkspaceServiceMock), videoServiceMock, authServiceMock)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	videoServiceMock.On("List", "test-user", 3).Return(nil, service.ErrWorkspaceNotFound)
+
+	req, err := http.NewRequest("GET", "/workspaces/3/videos", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("Authorization", token)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+
+	// Execute
+	rr := httptest.NewRecorder()
+	handler.VideosHandler(rr, req)
+
+	// Verify
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	videoServiceMock.AssertExpectations(t)
+}
+
+type WorkspaceServiceMock struct {
+	mock.Mock
+}
+
+func (s *WorkspaceServiceMock) Create(ctx context.Context, username string, name string) (*model.Workspace, error) {
+	args := s.Called(username, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workspace), args.Error(1)
+}
+
+func (s *WorkspaceServiceMock) List(ctx context.Context, username string) ([]*model.Workspace, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Workspace), args.Error(1)
+}
+
+func (s *WorkspaceServiceMock) Members(ctx context.Context, username string, workspaceId int) ([]*model.WorkspaceMember, error) {
+	args := s.Called(username, workspaceId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.WorkspaceMember), args.Error(1)
+}
+
+func (s *WorkspaceServiceMock) Invite(ctx context.Context, username string, workspaceId int, email string, role string) error {
+	args := s.Called(username, workspaceId, email, role)
+	return args.Error(0)
+}
+
+func (s *WorkspaceServiceMock) Join(ctx context.Context, username string, token string) (*model.Workspace, error) {
+	args := s.Called(username, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workspace), args.Error(1)
+}
+
+func (s *WorkspaceServiceMock) ChangeRole(ctx context.Context, username string, workspaceId int, member string, role string) error {
+	args := s.Called(username, workspaceId, member, role)
+	return args.Error(0)
+}
+
+func (s *WorkspaceServiceMock) RemoveMember(ctx context.Context, username string, workspaceId int, member string) error {
+	args := s.Called(username, workspaceId, member)
+	return args.Error(0)
+}
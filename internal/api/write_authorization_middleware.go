@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+// accountRoutes stay writable for viewers and users who did not verify their
+// email, they are how an account gets verified and secured and how a viewer
+// joins a workspace. GraphQL checks its mutations itself, its queries are
+// POSTs too.
+var accountRoutes = map[string]bool{
+	"/signup":                 true,
+	"/account":                true,
+	"/login":                  true,
+	"/login/mfa":              true,
+	"/verify-email":           true,
+	"/verify-email/request":   true,
+	"/reset-password":         true,
+	"/reset-password/request": true,
+	"/workspaces/join":        true,
+	"/graphql":                true,
+}
+
+// WriteAuthorizationMiddleware refuses the writes of viewers and, when
+// verified emails are required, of users whose email is not verified.
+// Anonymous requests go through, the handlers answer them.
+func WriteAuthorizationMiddleware(accountService ports.AccountService, authService auth.AuthService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			template := routeTemplate(r)
+			if isSafeMethod(r.Method) || accountRoutes[template] || strings.HasPrefix(template, "/account/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			username, ok := authenticate(r, authService, r.Header.Get("Authorization"))
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := accountService.AuthorizeWrite(r.Context(), username)
+			if err != nil && !errors.Is(err, repository.UserNotFoundError) {
+				respondWithError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
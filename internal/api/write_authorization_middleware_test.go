@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/stream"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+func TestWriteAuthorizationMiddleware_EmailNotVerified(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	accountServiceMock := new(AccountServiceMock)
+	authServiceMock := new(AuthService)
+	settings := &config.Settings{RequireVerifiedEmail: true}
+	router, err := NewRouter(settings, authServiceMock, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), accountServiceMock, new(SsoServiceMock), videoServiceMock,
+		new(AnnotationServiceMock), new(WebhookServiceMock), new(WorkspaceServiceMock), new(ApiKeyServiceMock), new(BackupServiceMock), stream.NewHub(), health.NewReadiness())
+	require.NoError(t, err)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	accountServiceMock.On("AuthorizeWrite", "test-user").Return(service.ErrEmailNotVerified)
+	accountServiceMock.On("SendVerification", "test-user").Return(nil)
+	videoServiceMock.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, []*model.Annotation{}, nil)
+
+	// Execute
+	serve := func(method string, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	deleted := serve("DELETE", "/videos/1/")
+	read := serve("GET", "/videos/1/")
+	requested := serve("POST", "/verify-email/request")
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, deleted.Code)
+	assert.Contains(t, deleted.Body.String(), ProblemTypeEmailNotVerified)
+	videoServiceMock.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusOK, read.Code)
+	assert.Equal(t, http.StatusAccepted, requested.Code)
+	accountServiceMock.AssertNumberOfCalls(t, "AuthorizeWrite", 1)
+}
+
+func TestWriteAuthorizationMiddleware_Viewer(t *testing.T) {
+	// Setup
+	videoServiceMock := new(VideoServiceMock)
+	accountServiceMock := new(AccountServiceMock)
+	workspaceServiceMock := new(WorkspaceServiceMock)
+	authServiceMock := new(AuthService)
+	router, err := NewRouter(&config.Settings{}, authServiceMock, &mockUserService{}, new(ProfileServiceMock), new(MfaServiceMock), accountServiceMock, new(SsoServiceMock), videoServiceMock,
+		new(AnnotationServiceMock), new(WebhookServiceMock), workspaceServiceMock, new(ApiKeyServiceMock), new(BackupServiceMock), stream.NewHub(), health.NewReadiness())
+	require.NoError(t, err)
+
+	token := "test-token"
+	authServiceMock.On("ValidateJwtToken", token).Return(true, "test-user")
+	accountServiceMock.On("AuthorizeWrite", "test-user").Return(service.ErrReadOnlyRole)
+	workspaceServiceMock.On("Join", "test-user", "invitation-token").Return(&model.Workspace{ID: 2, Name: "Team"}, nil)
+
+	// Execute
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	created := serve("POST", "/videos/", `{"title": "Title", "link": "https://example.com/video", "duration_seconds": 60}`)
+	joined := serve("POST", "/workspaces/join", `{"token": "invitation-token"}`)
+
+	// Verify
+	assert.Equal(t, http.StatusForbidden, created.Code)
+	assert.Contains(t, created.Body.String(), service.ErrReadOnlyRole.Error())
+	videoServiceMock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusOK, joined.Code)
+	accountServiceMock.AssertNumberOfCalls(t, "AuthorizeWrite", 1)
+}
@@ -10,4 +10,5 @@ type Annotation struct {
 	EndTime   time.Duration `db:"end_time"`
 	Type      string        `db:"type"`
 	Note      string        `db:"note"`
+	Version   int           `db:"version"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type AnnotationRevision struct {
+	ID           int           `db:"id" json:"id"`
+	AnnotationID int           `db:"annotation_id" json:"annotation_id"`
+	Revision     int           `db:"revision" json:"revision"`
+	StartTime    time.Duration `db:"start_time" json:"start_time"`
+	EndTime      time.Duration `db:"end_time" json:"end_time"`
+	Type         string        `db:"type" json:"type"`
+	Note         string        `db:"note" json:"note"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+}
+
+type AnnotationFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
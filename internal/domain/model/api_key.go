@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ApiKey authenticates scripts as its user on the API routes until it
+// expires or is revoked. Hash is the hash of the secret part of the key,
+// which is only shown once.
+type ApiKey struct {
+	ID        string    `db:"id"`
+	UserID    int       `db:"user_id"`
+	Name      string    `db:"name"`
+	Hash      string    `db:"key_hash"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	AuditLoginFailed   = "login.failed"
+	AuditLoginRejected = "login.rejected"
+	AuditLoginLocked   = "login.locked"
+	AuditLoginUnlocked = "login.unlocked"
+	AuditLoginSso      = "login.sso"
+
+	AuditUserProvisioned = "user.provisioned"
+	AuditUserRenamed     = "user.renamed"
+	AuditUserDeleted     = "user.deleted"
+
+	AuditMfaEnabled                  = "mfa.enabled"
+	AuditMfaDisabled                 = "mfa.disabled"
+	AuditMfaReset                    = "mfa.reset"
+	AuditMfaRecoveryCodesRegenerated = "mfa.recovery_codes_regenerated"
+	AuditMfaRecoveryCodeUsed         = "mfa.recovery_code_used"
+
+	AuditEmailVerified          = "email.verified"
+	AuditPasswordResetRequested = "password.reset_requested"
+	AuditPasswordReset          = "password.reset"
+	AuditPasswordChanged        = "password.changed"
+	AuditEmailChanged           = "email.changed"
+
+	AuditWorkspaceCreated     = "workspace.created"
+	AuditWorkspaceInvited     = "workspace.invited"
+	AuditWorkspaceJoined      = "workspace.joined"
+	AuditWorkspaceRoleChanged = "workspace.role_changed"
+	AuditWorkspaceLeft        = "workspace.left"
+)
+
+// AuditEvent records a security relevant action, Username is the one given
+// and may not exist.
+type AuditEvent struct {
+	ID         int       `db:"id"`
+	Type       string    `db:"type"`
+	Username   string    `db:"username"`
+	Address    string    `db:"address"`
+	Detail     string    `db:"detail"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Backup is a copy of the database, Path is where it is stored.
+type Backup struct {
+	Name       string
+	Path       string
+	Size       int64
+	Compressed bool
+	CreatedAt  time.Time
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	EventVideoCreated      = "video.created"
+	EventVideoUpdated      = "video.updated"
+	EventVideoDeleted      = "video.deleted"
+	EventAnnotationCreated = "annotation.created"
+	EventAnnotationUpdated = "annotation.updated"
+	EventAnnotationDeleted = "annotation.deleted"
+)
+
+var EventTypes = []string{
+	EventVideoCreated,
+	EventVideoUpdated,
+	EventVideoDeleted,
+	EventAnnotationCreated,
+	EventAnnotationUpdated,
+	EventAnnotationDeleted,
+}
+
+// Event describes a change to a video or one of its annotations. Annotation
+// is only set for annotation events. Only members of WorkspaceID receive it.
+type Event struct {
+	ID          string
+	Type        string
+	WorkspaceID int
+	VideoID     int
+	Video       *Video
+	Annotation  *Annotation
+	OccurredAt  time.Time
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// LoginAttempts counts the failed logins of a username or of an address,
+// told apart by Key, since the last successful one.
+type LoginAttempts struct {
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// Mail is a plain text email to a single recipient.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Mfa is the TOTP second factor of a user. It stays disabled until the
+// first code proves the authenticator app holds the secret.
+type Mfa struct {
+	UserID  int    `db:"user_id"`
+	Secret  string `db:"secret"`
+	Enabled bool   `db:"enabled"`
+	// LastStep is the time step of the last accepted code, a code is only
+	// accepted once.
+	LastStep  int64     `db:"last_step"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// MfaEnrollment holds what an authenticator app needs, URI is the otpauth
+// URI that QRCode, a PNG, encodes.
+type MfaEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
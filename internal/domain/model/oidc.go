@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// OidcLogin is a login sent to the identity provider and waiting for its
+// callback, State names it in the redirects.
+type OidcLogin struct {
+	State        string    `db:"state"`
+	CodeVerifier string    `db:"code_verifier"`
+	Nonce        string    `db:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+// ExternalIdentity is the user an identity provider vouched for. Groups
+// holds the values of the claim roles are mapped from.
+type ExternalIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Groups            []string
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// OutboxEntry is an event stored with the change that raised it, it stays in
+// the outbox until every subscriber received it.
+type OutboxEntry struct {
+	ID            int       `db:"id"`
+	Event         *Event    `db:"payload"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
@@ -1,11 +1,37 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// What happens to the videos of a deleted account.
+const (
+	VideosTransfer = "transfer"
+	VideosDelete   = "delete"
+)
+
+// Roles lists the roles a user can have, from the least to the most allowed.
+var Roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+// RoleAllows reports whether role grants at least what required does.
+func RoleAllows(role string, required string) bool {
+	return slices.Contains(Roles, role) && slices.Index(Roles, role) >= slices.Index(Roles, required)
+}
 
 type User struct {
-	ID        int       `db:"id"`
-	Username  string    `db:"username"`
-	Password  string    `db:"password"`
-	Email     string    `db:"email"`
-	CreatedAt time.Time `db:"created_at"`
+	ID            int       `db:"id"`
+	Username      string    `db:"username"`
+	Password      string    `db:"password"`
+	Email         string    `db:"email"`
+	EmailVerified bool      `db:"email_verified"`
+	Role          string    `db:"role"`
+	Disabled      bool      `db:"disabled"`
+	CreatedAt     time.Time `db:"created_at"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const (
+	TokenEmailVerification = "email_verification"
+	TokenPasswordReset     = "password_reset"
+	// TokenWorkspaceInvitation tokens carry the invited email rather than a
+	// username.
+	TokenWorkspaceInvitation = "workspace_invitation"
+)
+
+// UserToken tracks a token mailed to a user, ID is carried by the signed
+// token so it can be used once.
+type UserToken struct {
+	ID        string     `db:"id"`
+	UserID    int        `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
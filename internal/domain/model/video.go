@@ -5,9 +5,11 @@ import "time"
 type Video struct {
 	ID          int           `db:"id"`
 	UserID      int           `db:"user_id"`
+	WorkspaceID int           `db:"workspace_id"`
 	Title       string        `db:"title"`
 	Description string        `db:"description"`
 	Link        string        `db:"link"`
 	Duration    time.Duration `db:"duration"`
 	CreatedAt   time.Time     `db:"created_at"`
+	Version     int           `db:"version"`
 }
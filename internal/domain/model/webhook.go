@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookSubscription receives the events of the listed types, an empty list
+// matches every type. AnnotationTypes only narrows annotation events.
+type WebhookSubscription struct {
+	ID              int       `db:"id"`
+	UserID          int       `db:"user_id"`
+	URL             string    `db:"url"`
+	Secret          string    `db:"secret"`
+	EventTypes      []string  `db:"event_types"`
+	AnnotationTypes []string  `db:"annotation_types"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int        `db:"id"`
+	SubscriptionID int        `db:"subscription_id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID           int           `db:"id"`
+	DeliveryID   int           `db:"delivery_id"`
+	Attempt      int           `db:"attempt"`
+	ResponseCode int           `db:"response_code"`
+	Error        string        `db:"error"`
+	Duration     time.Duration `db:"duration"`
+	AttemptedAt  time.Time     `db:"attempted_at"`
+}
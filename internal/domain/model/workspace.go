@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Workspace shares a library of videos between its members. Role is the role
+// of the member the workspace was looked up for.
+type Workspace struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Role      string    `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// WorkspaceMember gives a user one of the Roles in a workspace.
+type WorkspaceMember struct {
+	WorkspaceID int       `db:"workspace_id"`
+	UserID      int       `db:"user_id"`
+	Username    string    `db:"username"`
+	Role        string    `db:"role"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// WorkspaceInvitation is mailed to Email, ID is carried by the signed token
+// so it can be accepted once.
+type WorkspaceInvitation struct {
+	ID          string     `db:"id"`
+	WorkspaceID int        `db:"workspace_id"`
+	Email       string     `db:"email"`
+	Role        string     `db:"role"`
+	InvitedBy   int        `db:"invited_by"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+	AcceptedAt  *time.Time `db:"accepted_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
@@ -0,0 +1,17 @@
+package ports
+
+import "context"
+
+type AccountService interface {
+	// SendVerification mails the user a link to verify the email address.
+	SendVerification(ctx context.Context, username string) error
+	VerifyEmail(ctx context.Context, token string) error
+	// RequestPasswordReset mails a reset link when a user has the email, it
+	// answers the same when none has.
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	// AuthorizeWrite fails when the user is disabled or a viewer, or when
+	// verified emails are required to change data and the user has not
+	// verified theirs.
+	AuthorizeWrite(ctx context.Context, username string) error
+}
@@ -1,12 +1,23 @@
 package ports
 
 import (
+	"context"
+
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 )
 
+// AnnotationRepository finds annotations of videos of the given workspaces
+// only.
 type AnnotationRepository interface {
-	Create(annotation *model.Annotation, videoId, userId int) error
-	FindVideoId(int) ([]*model.Annotation, error)
-	Update(int, *model.Annotation) error
-	Remove(int) error
+	Create(ctx context.Context, annotation *model.Annotation, videoId, userId int) error
+	FindById(ctx context.Context, workspaceIds []int, id int) (*model.Annotation, error)
+	FindVideoId(ctx context.Context, workspaceIds []int, videoId int) ([]*model.Annotation, error)
+	FindByVideoIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Annotation, error)
+	FindRevisions(ctx context.Context, workspaceIds []int, annotationId int) ([]*model.AnnotationRevision, error)
+	// Update only succeeds when annotation.Version matches the stored version.
+	// It returns the revision keeping the previous state, nil when none of the
+	// tracked fields changed.
+	Update(ctx context.Context, workspaceIds []int, id int, annotation *model.Annotation) (*model.AnnotationRevision, error)
+	Transfer(ctx context.Context, videoId int, userId int) error
+	Remove(ctx context.Context, workspaceIds []int, id int) error
 }
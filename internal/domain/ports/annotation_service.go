@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type AnnotationService interface {
+	Revisions(ctx context.Context, username string, annotationId int) ([]*model.AnnotationRevision, error)
+	Diff(ctx context.Context, username string, annotationId, from, to int) ([]*model.AnnotationFieldChange, error)
+	// Revert needs the editor role in the workspace of the annotation.
+	Revert(ctx context.Context, username string, annotationId, revision int) (*model.AnnotationRevision, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *model.ApiKey) error
+	Find(ctx context.Context, id string) (*model.ApiKey, error)
+	FindByUser(ctx context.Context, userId int) ([]*model.ApiKey, error)
+	// Remove only removes keys owned by userId.
+	Remove(ctx context.Context, id string, userId int) error
+}
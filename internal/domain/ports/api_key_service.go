@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type ApiKeyService interface {
+	// Create returns the key itself along with what is stored of it, the key
+	// cannot be read back later.
+	Create(ctx context.Context, username string, name string) (*model.ApiKey, string, error)
+	Keys(ctx context.Context, username string) ([]*model.ApiKey, error)
+	Revoke(ctx context.Context, username string, id string) error
+	// Authenticate returns the stored key and the current username of its
+	// user, unknown, expired and revoked keys are refused.
+	Authenticate(ctx context.Context, apiKey string) (*model.ApiKey, string, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type AuditRepository interface {
+	Append(ctx context.Context, event *model.AuditEvent) error
+}
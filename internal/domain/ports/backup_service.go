@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type BackupService interface {
+	Create(ctx context.Context) (*model.Backup, error)
+	// List returns the kept backups, newest first.
+	List(ctx context.Context) ([]*model.Backup, error)
+}
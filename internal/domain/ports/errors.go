@@ -0,0 +1,7 @@
+package ports
+
+import "fmt"
+
+// ErrVersionConflict is returned by repositories when a compare-and-swap
+// fails because the stored version no longer matches the expected one.
+var ErrVersionConflict = fmt.Errorf("version conflict")
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *model.Event) error
+}
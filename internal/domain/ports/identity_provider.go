@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// IdentityProvider signs users in with the authorization code flow.
+type IdentityProvider interface {
+	// AuthCodeURL is where the user logs in, the provider redirects back
+	// with a code and state.
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	// Exchange redeems the code and returns the identity of its ID token.
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*model.ExternalIdentity, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type LoginAttemptRepository interface {
+	// Find returns nil when key has no failure recorded.
+	Find(ctx context.Context, key string) (*model.LoginAttempts, error)
+	// AddFailure counts a failure at now, starting over when the previous
+	// one happened before since, and returns the updated attempts.
+	AddFailure(ctx context.Context, key string, now time.Time, since time.Time) (*model.LoginAttempts, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
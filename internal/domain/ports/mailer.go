@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, mail *model.Mail) error
+}
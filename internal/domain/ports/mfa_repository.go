@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type MfaRepository interface {
+	// Find returns nil when the user has not enrolled.
+	Find(ctx context.Context, userID int) (*model.Mfa, error)
+	Save(ctx context.Context, mfa *model.Mfa) error
+	// UseStep records step as the last accepted one, it returns false when
+	// that step or a later one was already used.
+	UseStep(ctx context.Context, userID int, step int64) (bool, error)
+	// ReplaceRecoveryCodes drops the remaining recovery codes of the user and
+	// stores hashes instead.
+	ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string) error
+	// UseRecoveryCode removes a recovery code, it returns false when the user
+	// has no such code.
+	UseRecoveryCode(ctx context.Context, userID int, hash string) (bool, error)
+	// Delete removes the second factor and the recovery codes of the user.
+	Delete(ctx context.Context, userID int) error
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type MfaService interface {
+	// Enroll creates a new secret for the user, it is only used once Confirm
+	// received a code generated with it.
+	Enroll(ctx context.Context, username string) (*model.MfaEnrollment, error)
+	// Confirm enables the second factor and returns the recovery codes.
+	Confirm(ctx context.Context, username string, code string) ([]string, error)
+	// Disable and RegenerateRecoveryCodes take a current code or a recovery
+	// code.
+	Disable(ctx context.Context, username string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, username string, code string) ([]string, error)
+	// Reset removes the second factor of a user who lost it, an
+	// administration task.
+	Reset(ctx context.Context, username string) error
+	// Login exchanges the challenge token of a login and a code for a
+	// session token.
+	Login(ctx context.Context, challenge string, code string) (string, error)
+}
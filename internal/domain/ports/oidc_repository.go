@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type OidcRepository interface {
+	// SaveLogin stores a started login and drops the expired ones.
+	SaveLogin(ctx context.Context, login *model.OidcLogin) error
+	// TakeLogin removes the login and returns it, or nil when it is unknown
+	// or expired.
+	TakeLogin(ctx context.Context, state string, now time.Time) (*model.OidcLogin, error)
+	// FindUser returns the user linked to the identity or UserNotFoundError.
+	FindUser(ctx context.Context, issuer string, subject string) (*model.User, error)
+	// Link ties the identity to an existing user.
+	Link(ctx context.Context, issuer string, subject string, userID int) error
+	// Provision saves a new user linked to the identity.
+	Provision(ctx context.Context, user *model.User, issuer string, subject string) error
+}
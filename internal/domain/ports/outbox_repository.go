@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type OutboxRepository interface {
+	Append(ctx context.Context, event *model.Event) error
+	// FindDue returns due entries oldest first, leaving out every entry that
+	// follows a not yet due entry of the same video.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEntry, error)
+	// Reschedule stores the attempts, last error and next attempt of entry.
+	Reschedule(ctx context.Context, entry *model.OutboxEntry) error
+	Remove(ctx context.Context, id int) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// ProfileService lets users manage their own account.
+type ProfileService interface {
+	Find(ctx context.Context, username string) (*model.User, error)
+	// Rename returns a session token for the new username, the tokens of the
+	// previous one no longer find the user.
+	Rename(ctx context.Context, username string, newUsername string) (string, error)
+	// ChangePassword, ChangeEmail and Delete take the current password.
+	ChangePassword(ctx context.Context, username string, current string, password string) error
+	// ChangeEmail mails a link to verify the new email, which stays
+	// unverified until then.
+	ChangeEmail(ctx context.Context, username string, password string, email string) error
+	// Delete removes the account, videos tells whether its videos go to the
+	// user transferTo or are deleted with it.
+	Delete(ctx context.Context, username string, password string, videos string, transferTo string) error
+}
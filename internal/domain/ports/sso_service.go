@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+type SsoService interface {
+	// Begin starts a login and returns its state and the URL of the identity
+	// provider to send the user to.
+	Begin(ctx context.Context) (string, string, error)
+	// Finish completes the login named by state and returns a session token.
+	Finish(ctx context.Context, state string, code string) (string, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import "context"
+
+// Tx groups the repositories that take part in one database transaction.
+type Tx struct {
+	Users       UserRepository
+	Videos      VideoRepository
+	Annotations AnnotationRepository
+	Outbox      OutboxRepository
+}
+
+type Transactor interface {
+	// Transaction commits when fn succeeds and rolls everything back otherwise.
+	Transaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error
+}
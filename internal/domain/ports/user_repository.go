@@ -1,8 +1,22 @@
 package ports
 
-import "github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+import (
+	"context"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
 
 type UserRepository interface {
-	FindByUsername(username string) (*model.User, error)
-	Save(user *model.User) error
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByIds(ctx context.Context, ids []int) ([]*model.User, error)
+	Save(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) error
+	// UsernameTaken also counts the usernames other users retired until now.
+	UsernameTaken(ctx context.Context, username string, userId int, now time.Time) (bool, error)
+	// Rename and Delete keep the released username from other users until
+	// retiredUntil.
+	Rename(ctx context.Context, user *model.User, username string, retiredUntil time.Time) error
+	Delete(ctx context.Context, user *model.User, retiredUntil time.Time) error
 }
@@ -1,6 +1,26 @@
 package ports
 
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
 type UserService interface {
-	Login(username string, password string) (string, error)
-	Signup(email string, password string) (string, error)
+	Login(ctx context.Context, username string, password string) (string, error)
+	// Signup derives the username from the email, numbered when it is taken.
+	Signup(ctx context.Context, email string, password string) (string, error)
+	// Create, Disable, SetRole, ResetPassword and Unlock are administration
+	// tasks.
+	Create(ctx context.Context, email string, password string, role string) (*model.User, error)
+	Disable(ctx context.Context, username string) error
+	SetRole(ctx context.Context, username string, role string) error
+	ResetPassword(ctx context.Context, username string, password string) error
+	// Unlock lifts the lockout that follows repeated failed logins.
+	Unlock(ctx context.Context, username string) error
+	// Find returns the user a request acts for, it fails when the user is
+	// disabled.
+	Find(ctx context.Context, username string) (*model.User, error)
+	// FindMany returns the users keyed by id.
+	FindMany(ctx context.Context, ids []int) (map[int]*model.User, error)
 }
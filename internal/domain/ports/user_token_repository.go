@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type UserTokenRepository interface {
+	// Issue stores token and drops the unused tokens of the user with the
+	// same purpose, only the latest one mailed works.
+	Issue(ctx context.Context, token *model.UserToken) error
+	// Use marks the token used, it returns false when it does not exist, was
+	// used already or expired by now.
+	Use(ctx context.Context, userID int, id string, purpose string, now time.Time) (bool, error)
+}
@@ -1,10 +1,26 @@
 package ports
 
-import "github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+import (
+	"context"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// VideoRepository finds videos of the given workspaces only, so the videos
+// of one workspace never show up in another.
 type VideoRepository interface {
-	Create(video *model.Video, userId int) (int, error)
-	FindById(int) (*model.Video, error)
-	Update(int, *model.Video) error
-	Remove(int) error
+	// Create stores the video in video.WorkspaceID.
+	Create(ctx context.Context, video *model.Video, userId int) (int, error)
+	FindById(ctx context.Context, workspaceIds []int, id int) (*model.Video, error)
+	FindByIds(ctx context.Context, workspaceIds []int, ids []int) ([]*model.Video, error)
+	FindByWorkspaceId(ctx context.Context, workspaceId int) ([]*model.Video, error)
+	// FindAfter returns up to limit videos of workspaceIds with an id greater
+	// than afterId, ordered by id.
+	FindAfter(ctx context.Context, workspaceIds []int, afterId int, limit int) ([]*model.Video, error)
+	FindByUserId(ctx context.Context, workspaceIds []int, userId int) ([]*model.Video, error)
+	// Update only succeeds when video.Version matches the stored version.
+	Update(ctx context.Context, workspaceIds []int, id int, video *model.Video) error
+	// Transfer only succeeds when version matches the stored version.
+	Transfer(ctx context.Context, workspaceIds []int, id int, version int, userId int, workspaceId int) error
+	Remove(ctx context.Context, workspaceIds []int, id int, version int) error
 }
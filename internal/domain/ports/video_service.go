@@ -1,10 +1,24 @@
 package ports
 
-import "github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+import (
+	"context"
 
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// VideoService only finds the videos of the workspaces of username, and only
+// lets editors of a workspace change its videos.
 type VideoService interface {
-	Create(username string, video *model.Video, annotaions []*model.Annotation) error
-	Find(int) (*model.Video, []*model.Annotation, error)
-	Update(int, *model.Video, []*model.Annotation) error
-	Remove(int) error
+	// Create adds the video to video.WorkspaceID, or to the first workspace
+	// of username when it is not set.
+	Create(ctx context.Context, username string, video *model.Video, annotaions []*model.Annotation) error
+	Find(ctx context.Context, username string, videoId int) (*model.Video, []*model.Annotation, error)
+	// FindMany and FindAnnotations batch lookups for several videos, results
+	// are keyed by video id.
+	FindMany(ctx context.Context, username string, ids []int) (map[int]*model.Video, error)
+	FindAnnotations(ctx context.Context, username string, videoIds []int) (map[int][]*model.Annotation, error)
+	// List returns the video library of a workspace.
+	List(ctx context.Context, username string, workspaceId int) ([]*model.Video, error)
+	Update(ctx context.Context, username string, videoId int, video *model.Video, annotaions []*model.Annotation) error
+	Remove(ctx context.Context, username string, id int, version int) error
 }
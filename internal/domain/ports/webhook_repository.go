@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (int, error)
+	FindSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	FindSubscriptionsByUser(ctx context.Context, userId int) ([]*model.WebhookSubscription, error)
+	// FindSubscriptionsByWorkspace returns the subscriptions of the members
+	// of a workspace.
+	FindSubscriptionsByWorkspace(ctx context.Context, workspaceId int) ([]*model.WebhookSubscription, error)
+	// RemoveSubscription only removes subscriptions owned by userId.
+	RemoveSubscription(ctx context.Context, id, userId int) error
+
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, subscriptionId int, limit int) ([]*model.WebhookDelivery, error)
+	FindAttempts(ctx context.Context, deliveryIds []int) ([]*model.WebhookDeliveryAttempt, error)
+	// RecordAttempt stores the attempt and the new state of its delivery.
+	RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error
+}
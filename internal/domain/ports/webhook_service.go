@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type WebhookService interface {
+	EventPublisher
+	Subscribe(ctx context.Context, username string, subscription *model.WebhookSubscription) error
+	Subscriptions(ctx context.Context, username string) ([]*model.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, username string, id int) error
+	// Deliveries returns the latest deliveries of a subscription with their
+	// attempts keyed by delivery id.
+	Deliveries(ctx context.Context, username string, id int) ([]*model.WebhookDelivery, map[int][]*model.WebhookDeliveryAttempt, error)
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+type WorkspaceRepository interface {
+	// Create fills in the id of workspace and makes userId its admin.
+	Create(ctx context.Context, workspace *model.Workspace, userId int) error
+	// FindByUserId returns the workspaces of a member with its role in each,
+	// the first one it joined first.
+	FindByUserId(ctx context.Context, userId int) ([]*model.Workspace, error)
+	// FindIds returns the id of every workspace, for the administration
+	// commands that work across all of them.
+	FindIds(ctx context.Context) ([]int, error)
+	FindMembers(ctx context.Context, workspaceId int) ([]*model.WorkspaceMember, error)
+	FindMember(ctx context.Context, workspaceId int, userId int) (*model.WorkspaceMember, error)
+	// SaveMember adds the member or changes its role.
+	SaveMember(ctx context.Context, member *model.WorkspaceMember) error
+	RemoveMember(ctx context.Context, workspaceId int, userId int) error
+
+	CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error
+	FindInvitation(ctx context.Context, id string) (*model.WorkspaceInvitation, error)
+	// AcceptInvitation adds member and marks the invitation accepted, it
+	// returns false when the invitation was accepted already or expired by
+	// now.
+	AcceptInvitation(ctx context.Context, id string, member *model.WorkspaceMember, now time.Time) (bool, error)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// WorkspaceService manages workspaces and their members. Members see the
+// workspace, editors change its videos and admins manage its members.
+type WorkspaceService interface {
+	// Create makes username the admin of a new workspace.
+	Create(ctx context.Context, username string, name string) (*model.Workspace, error)
+	List(ctx context.Context, username string) ([]*model.Workspace, error)
+	Members(ctx context.Context, username string, workspaceId int) ([]*model.WorkspaceMember, error)
+	// Invite mails email a link to join the workspace with role.
+	Invite(ctx context.Context, username string, workspaceId int, email string, role string) error
+	// Join accepts the invitation carried by token, it must have been sent to
+	// the email of username.
+	Join(ctx context.Context, username string, token string) (*model.Workspace, error)
+	ChangeRole(ctx context.Context, username string, workspaceId int, member string, role string) error
+	// RemoveMember lets admins remove anyone and members leave, a workspace
+	// keeps at least one admin.
+	RemoveMember(ctx context.Context, username string, workspaceId int, member string) error
+}
@@ -15,6 +15,7 @@ var (
 	ErrAnnotationVideoIdIdIsInvalid = fmt.Errorf("video id is invalid")
 	ErrStartimeIsInvalid            = fmt.Errorf("startime is invalid")
 	ErrEndtimeIsInvalid             = fmt.Errorf("endtime is invalid")
+	ErrEndtimeIsAfterVideoEnd       = fmt.Errorf("endtime is after the end of the video")
 
 	AnnotationValidationErrors = map[error]bool{
 		ErrAnnotationIsNil:              true,
@@ -24,44 +25,51 @@ var (
 		ErrAnnotationVideoIdIdIsInvalid: true,
 		ErrStartimeIsInvalid:            true,
 		ErrEndtimeIsInvalid:             true,
+		ErrEndtimeIsAfterVideoEnd:       true,
 	}
 )
 
+// ValidateAnnotation returns the first failing rule, see AnnotationErrors for all of them.
 func ValidateAnnotation(annotation *model.Annotation, videoDuration time.Duration) error {
 	if annotation == nil {
 		return ErrAnnotationIsNil
 	}
+	return AnnotationErrors(annotation, videoDuration).first()
+}
+
+func AnnotationErrors(annotation *model.Annotation, videoDuration time.Duration) Errors {
+	errs := Errors{}
+	if annotation == nil {
+		return errs.add("", ErrAnnotationIsNil)
+	}
 	if annotation.Note == "" {
-		return ErrNoteIsInvalid
+		errs = errs.add(FieldOf(ErrNoteIsInvalid), ErrNoteIsInvalid)
 	}
 
 	if annotation.Type == "" {
-		return ErrTypeIsInvalid
+		errs = errs.add(FieldOf(ErrTypeIsInvalid), ErrTypeIsInvalid)
 	}
 
 	if annotation.UserID == 0 {
-		return ErrAnnotationUserIdIsInvalid
+		errs = errs.add(FieldOf(ErrAnnotationUserIdIsInvalid), ErrAnnotationUserIdIsInvalid)
 	}
 
 	if annotation.VideoID == 0 {
-		return ErrAnnotationVideoIdIdIsInvalid
-	}
-
-	if annotation.StartTime == time.Duration(0) {
-		return ErrStartimeIsInvalid
-	}
-
-	if annotation.EndTime == time.Duration(0) {
-		return ErrEndtimeIsInvalid
+		errs = errs.add(FieldOf(ErrAnnotationVideoIdIdIsInvalid), ErrAnnotationVideoIdIdIsInvalid)
 	}
 
-	if annotation.EndTime.Milliseconds() <= annotation.StartTime.Milliseconds() {
-		return ErrEndtimeIsInvalid
+	startInvalid := annotation.StartTime == time.Duration(0) ||
+		videoDuration.Milliseconds() < annotation.StartTime.Milliseconds()
+	if startInvalid {
+		errs = errs.add(FieldOf(ErrStartimeIsInvalid), ErrStartimeIsInvalid)
 	}
 
-	if videoDuration.Milliseconds() < annotation.EndTime.Milliseconds() {
-		return ErrStartimeIsInvalid
+	if annotation.EndTime == time.Duration(0) ||
+		annotation.EndTime.Milliseconds() <= annotation.StartTime.Milliseconds() {
+		errs = errs.add(FieldOf(ErrEndtimeIsInvalid), ErrEndtimeIsInvalid)
+	} else if !startInvalid && videoDuration.Milliseconds() < annotation.EndTime.Milliseconds() {
+		errs = errs.add(FieldOf(ErrEndtimeIsAfterVideoEnd), ErrEndtimeIsAfterVideoEnd)
 	}
 
-	return nil
+	return errs
 }
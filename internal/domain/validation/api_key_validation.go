@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrApiKeyNameIsInvalid = fmt.Errorf("api key name must be 1 to 64 characters long")
+
+	ApiKeyValidationErrors = map[error]bool{
+		ErrApiKeyNameIsInvalid: true,
+	}
+)
+
+const maxApiKeyNameLength = 64
+
+func ValidateApiKeyName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxApiKeyNameLength {
+		return ErrApiKeyNameIsInvalid
+	}
+	return nil
+}
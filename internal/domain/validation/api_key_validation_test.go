@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateApiKeyName(t *testing.T) {
+	// assert
+	require.NoError(t, ValidateApiKeyName("ci"))
+	require.NoError(t, ValidateApiKeyName(strings.Repeat("é", 64)))
+	require.ErrorIs(t, ValidateApiKeyName(""), ErrApiKeyNameIsInvalid)
+	require.ErrorIs(t, ValidateApiKeyName("   "), ErrApiKeyNameIsInvalid)
+	require.ErrorIs(t, ValidateApiKeyName(strings.Repeat("a", 65)), ErrApiKeyNameIsInvalid)
+	require.True(t, IsValidationError(ErrApiKeyNameIsInvalid))
+	require.Equal(t, "name", FieldOf(ErrApiKeyNameIsInvalid))
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError ties a validation error to the field that failed it.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collects every failing field of a validation run.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fieldErr := range e {
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
+
+// WithPrefix qualifies every field name, e.g. "annotations[0]." + "note".
+func (e Errors) WithPrefix(prefix string) Errors {
+	prefixed := make(Errors, 0, len(e))
+	for _, fieldErr := range e {
+		prefixed = append(prefixed, &FieldError{Field: prefix + fieldErr.Field, Err: fieldErr.Err})
+	}
+	return prefixed
+}
+
+// Err returns nil when no field failed, so callers can return it directly.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) first() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0].Err
+}
+
+func (e Errors) add(field string, err error) Errors {
+	return append(e, &FieldError{Field: field, Err: err})
+}
+
+// AsErrors reports whether err carries field level validation errors.
+func AsErrors(err error) (Errors, bool) {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs, true
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return Errors{fieldErr}, true
+	}
+	return nil, false
+}
+
+// IsValidationError reports whether err is one of the single field errors of
+// this package.
+func IsValidationError(err error) bool {
+	return ApiKeyValidationErrors[err] ||
+		VideoValidationErrors[err] ||
+		AnnotationValidationErrors[err] ||
+		UserValidationErrors[err] ||
+		WebhookValidationErrors[err] ||
+		MfaValidationErrors[err] ||
+		WorkspaceValidationErrors[err]
+}
+
+// FieldOf returns the field a single validation error refers to.
+func FieldOf(err error) string {
+	return fieldNames[err]
+}
+
+var fieldNames = map[error]string{
+	ErrApiKeyNameIsInvalid:          "name",
+	ErrTitleIsInvalid:               "title",
+	ErrDescriptionIsInvalid:         "description",
+	ErrLinkIsInvalid:                "link",
+	ErrVideoUserIdIsInvalid:         "user_id",
+	ErrDurationIsInvalid:            "duration",
+	ErrDurationIsReadOnly:           "duration",
+	ErrVideoCreatedAtIsInvalid:      "created_at",
+	ErrNoteIsInvalid:                "note",
+	ErrTypeIsInvalid:                "type",
+	ErrAnnotationUserIdIsInvalid:    "user_id",
+	ErrAnnotationVideoIdIdIsInvalid: "video_id",
+	ErrStartimeIsInvalid:            "start_time",
+	ErrEndtimeIsInvalid:             "end_time",
+	ErrEndtimeIsAfterVideoEnd:       "end_time",
+	ErrNameIsInvalid:                "username",
+	ErrEmailIsInvalid:               "email",
+	ErrPasswordIsInvalid:            "password",
+	ErrUserCreatedAtIsInvalid:       "created_at",
+	ErrRoleIsInvalid:                "role",
+	ErrVideosIsInvalid:              "videos",
+	ErrTransferToIsInvalid:          "transfer_to",
+	ErrWebhookURLIsInvalid:          "url",
+	ErrWebhookHostIsNotPublic:       "url",
+	ErrWebhookSecretIsTooShort:      "secret",
+	ErrMfaCodeIsInvalid:             "code",
+	ErrWorkspaceNameIsInvalid:       "name",
+	ErrWorkspaceIdIsInvalid:         "workspace_id",
+}
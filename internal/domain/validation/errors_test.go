@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoErrors_ReportsAllFields(t *testing.T) {
+	// fixture
+	video := &model.Video{
+		Description: "test description",
+		Link:        "https://example.com/test",
+		UserID:      1,
+	}
+
+	// test
+	errs := VideoErrors(video)
+
+	// assertions
+	require.Equal(t, Errors{
+		{Field: "title", Err: ErrTitleIsInvalid},
+		{Field: "duration", Err: ErrDurationIsInvalid},
+		{Field: "created_at", Err: ErrVideoCreatedAtIsInvalid},
+	}, errs)
+}
+
+func TestAnnotationErrors_ReportsAllFields(t *testing.T) {
+	// fixture
+	videoDuration := time.Duration(10) * time.Minute
+	annotation := &model.Annotation{
+		UserID:    1,
+		VideoID:   1,
+		StartTime: time.Duration(2) * time.Minute,
+		EndTime:   time.Duration(1) * time.Minute,
+	}
+
+	// test
+	errs := AnnotationErrors(annotation, videoDuration)
+
+	// assertions
+	require.Equal(t, Errors{
+		{Field: "note", Err: ErrNoteIsInvalid},
+		{Field: "type", Err: ErrTypeIsInvalid},
+		{Field: "end_time", Err: ErrEndtimeIsInvalid},
+	}, errs)
+}
+
+func TestAnnotationErrors_ReportsTimesOnTheirField(t *testing.T) {
+	videoDuration := time.Duration(10) * time.Minute
+	tests := []struct {
+		name      string
+		startTime time.Duration
+		endTime   time.Duration
+		expected  Errors
+	}{
+		{"start after the video", 11 * time.Minute, 12 * time.Minute, Errors{{Field: "start_time", Err: ErrStartimeIsInvalid}}},
+		{"end before start", 2 * time.Minute, 1 * time.Minute, Errors{{Field: "end_time", Err: ErrEndtimeIsInvalid}}},
+		{"end after the video", 9 * time.Minute, 11 * time.Minute, Errors{{Field: "end_time", Err: ErrEndtimeIsAfterVideoEnd}}},
+		{"end at the end of the video", 9 * time.Minute, 10 * time.Minute, Errors{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// fixture
+			annotation := &model.Annotation{
+				Note:      "test note",
+				Type:      "test type",
+				UserID:    1,
+				VideoID:   1,
+				StartTime: tt.startTime,
+				EndTime:   tt.endTime,
+			}
+
+			// test
+			errs := AnnotationErrors(annotation, videoDuration)
+
+			// assertions
+			require.Equal(t, tt.expected, errs)
+		})
+	}
+}
+
+func TestErrors_Err_EmptyIsNil(t *testing.T) {
+	// test
+	err := Errors{}.Err()
+
+	// assertions
+	require.NoError(t, err)
+}
+
+func TestErrors_WithPrefix(t *testing.T) {
+	// fixture
+	errs := Errors{{Field: "note", Err: ErrNoteIsInvalid}}
+
+	// test
+	prefixed := errs.WithPrefix("annotations[0].")
+
+	// assertions
+	require.Equal(t, "annotations[0].note", prefixed[0].Field)
+	require.Equal(t, "note", errs[0].Field)
+}
+
+func TestErrors_Is(t *testing.T) {
+	// fixture
+	err := Errors{
+		{Field: "title", Err: ErrTitleIsInvalid},
+		{Field: "link", Err: ErrLinkIsInvalid},
+	}.Err()
+
+	// assertions
+	require.ErrorIs(t, err, ErrTitleIsInvalid)
+	require.ErrorIs(t, err, ErrLinkIsInvalid)
+	require.EqualError(t, err, "title is invalid; link is invalid")
+}
+
+func TestAsErrors(t *testing.T) {
+	// fixture
+	errs := Errors{{Field: "title", Err: ErrTitleIsInvalid}}
+
+	// test
+	found, ok := AsErrors(fmt.Errorf("wrapped: %w", errs))
+	_, notFound := AsErrors(errors.New("other"))
+
+	// assertions
+	require.True(t, ok)
+	require.Equal(t, errs, found)
+	require.False(t, notFound)
+}
+
+func TestIsValidationError(t *testing.T) {
+	// assertions
+	require.True(t, IsValidationError(ErrTitleIsInvalid))
+	require.True(t, IsValidationError(ErrWebhookURLIsInvalid))
+	require.False(t, IsValidationError(errors.New("other")))
+}
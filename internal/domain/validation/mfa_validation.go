@@ -0,0 +1,11 @@
+package validation
+
+import "fmt"
+
+var (
+	ErrMfaCodeIsInvalid = fmt.Errorf("code is invalid")
+
+	MfaValidationErrors = map[error]bool{
+		ErrMfaCodeIsInvalid: true,
+	}
+)
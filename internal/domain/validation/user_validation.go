@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
 )
@@ -13,6 +14,9 @@ var (
 	ErrEmailIsInvalid         = fmt.Errorf("email is invalid")
 	ErrPasswordIsInvalid      = fmt.Errorf("password is invalid")
 	ErrUserCreatedAtIsInvalid = fmt.Errorf("created_at is invalid")
+	ErrRoleIsInvalid          = fmt.Errorf("role is invalid")
+	ErrVideosIsInvalid        = fmt.Errorf("videos is invalid")
+	ErrTransferToIsInvalid    = fmt.Errorf("transfer_to is invalid")
 
 	UserValidationErrors = map[error]bool{
 		ErrUserIsNil:              true,
@@ -20,29 +24,68 @@ var (
 		ErrEmailIsInvalid:         true,
 		ErrPasswordIsInvalid:      true,
 		ErrUserCreatedAtIsInvalid: true,
+		ErrRoleIsInvalid:          true,
+		ErrVideosIsInvalid:        true,
+		ErrTransferToIsInvalid:    true,
 	}
 
-	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	emailRegex    = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,31}$`)
 )
 
+// ValidateUser returns the first failing rule, see UserErrors for all of them.
 func ValidateUser(user *model.User) error {
 	if user == nil {
 		return ErrUserIsNil
 	}
+	return UserErrors(user).first()
+}
+
+func UserErrors(user *model.User) Errors {
+	errs := Errors{}
+	if user == nil {
+		return errs.add("", ErrUserIsNil)
+	}
 	if user.Username == "" {
-		return ErrNameIsInvalid
+		errs = errs.add(FieldOf(ErrNameIsInvalid), ErrNameIsInvalid)
 	}
 
 	if !emailRegex.MatchString(user.Email) {
-		return ErrEmailIsInvalid
+		errs = errs.add(FieldOf(ErrEmailIsInvalid), ErrEmailIsInvalid)
 	}
 
 	if user.Password == "" {
-		return ErrPasswordIsInvalid
+		errs = errs.add(FieldOf(ErrPasswordIsInvalid), ErrPasswordIsInvalid)
 	}
 
 	if user.CreatedAt.IsZero() {
-		return ErrUserCreatedAtIsInvalid
+		errs = errs.add(FieldOf(ErrUserCreatedAtIsInvalid), ErrUserCreatedAtIsInvalid)
+	}
+	if err := ValidateRole(user.Role); err != nil {
+		errs = errs.add(FieldOf(ErrRoleIsInvalid), ErrRoleIsInvalid)
+	}
+	return errs
+}
+
+// ValidateUsername checks a username users choose, the ones derived from
+// emails predate the rule.
+func ValidateUsername(username string) error {
+	if !usernameRegex.MatchString(username) {
+		return ErrNameIsInvalid
+	}
+	return nil
+}
+
+func ValidateEmail(email string) error {
+	if !emailRegex.MatchString(email) {
+		return ErrEmailIsInvalid
+	}
+	return nil
+}
+
+func ValidateRole(role string) error {
+	if !slices.Contains(model.Roles, role) {
+		return ErrRoleIsInvalid
 	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ func TestValidateUser_HappyPath(t *testing.T) {
 		Username:  "johndoe",
 		Email:     "johndoe@example.com",
 		Password:  "password123",
+		Role:      model.RoleEditor,
 		CreatedAt: time.Now(),
 	}
 
@@ -95,3 +97,29 @@ func TestValidateUser_InvalidCreatedAt(t *testing.T) {
 	// assert
 	require.EqualError(t, err, ErrUserCreatedAtIsInvalid.Error())
 }
+
+func TestValidateUser_InvalidRole(t *testing.T) {
+	//fixtures
+	user := &model.User{
+		Username:  "johndoe",
+		Email:     "johndoe@example.com",
+		Password:  "password123",
+		Role:      "owner",
+		CreatedAt: time.Now(),
+	}
+
+	// test
+	err := ValidateUser(user)
+
+	// assert
+	require.EqualError(t, err, ErrRoleIsInvalid.Error())
+}
+
+func TestValidateUsername(t *testing.T) {
+	// assert
+	require.NoError(t, ValidateUsername("john.doe-2"))
+	require.ErrorIs(t, ValidateUsername(""), ErrNameIsInvalid)
+	require.ErrorIs(t, ValidateUsername("john doe"), ErrNameIsInvalid)
+	require.ErrorIs(t, ValidateUsername(".hidden"), ErrNameIsInvalid)
+	require.ErrorIs(t, ValidateUsername(strings.Repeat("a", 33)), ErrNameIsInvalid)
+}
@@ -14,6 +14,7 @@ var (
 	ErrLinkIsInvalid           = fmt.Errorf("link is invalid")
 	ErrVideoUserIdIsInvalid    = fmt.Errorf("user id is invalid")
 	ErrDurationIsInvalid       = fmt.Errorf("duration is invalid")
+	ErrDurationIsReadOnly      = fmt.Errorf("duration cannot change once the video is created")
 	ErrVideoCreatedAtIsInvalid = fmt.Errorf("created_at is invalid")
 
 	VideoValidationErrors = map[error]bool{
@@ -23,36 +24,47 @@ var (
 		ErrLinkIsInvalid:           true,
 		ErrVideoUserIdIsInvalid:    true,
 		ErrDurationIsInvalid:       true,
+		ErrDurationIsReadOnly:      true,
 		ErrVideoCreatedAtIsInvalid: true,
 	}
 )
 
+// ValidateVideo returns the first failing rule, see VideoErrors for all of them.
 func ValidateVideo(video *model.Video) error {
 	if video == nil {
 		return ErrVideoIsNil
 	}
+	return VideoErrors(video).first()
+}
+
+func VideoErrors(video *model.Video) Errors {
+	errs := Errors{}
+	if video == nil {
+		return errs.add("", ErrVideoIsNil)
+	}
+
 	if video.Title == "" {
-		return ErrTitleIsInvalid
+		errs = errs.add(FieldOf(ErrTitleIsInvalid), ErrTitleIsInvalid)
 	}
 
 	if video.Description == "" {
-		return ErrDescriptionIsInvalid
+		errs = errs.add(FieldOf(ErrDescriptionIsInvalid), ErrDescriptionIsInvalid)
 	}
 
 	if video.Link == "" {
-		return ErrLinkIsInvalid
+		errs = errs.add(FieldOf(ErrLinkIsInvalid), ErrLinkIsInvalid)
 	}
 
 	if video.UserID == 0 {
-		return ErrVideoUserIdIsInvalid
+		errs = errs.add(FieldOf(ErrVideoUserIdIsInvalid), ErrVideoUserIdIsInvalid)
 	}
 
 	if video.Duration == time.Duration(0) {
-		return ErrDurationIsInvalid
+		errs = errs.add(FieldOf(ErrDurationIsInvalid), ErrDurationIsInvalid)
 	}
 
 	if video.CreatedAt.IsZero() {
-		return ErrVideoCreatedAtIsInvalid
+		errs = errs.add(FieldOf(ErrVideoCreatedAtIsInvalid), ErrVideoCreatedAtIsInvalid)
 	}
-	return nil
+	return errs
 }
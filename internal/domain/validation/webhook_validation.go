@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+var (
+	ErrWebhookIsNil            = fmt.Errorf("webhook subscription is nil")
+	ErrWebhookURLIsInvalid     = fmt.Errorf("url must be an absolute http or https url")
+	ErrWebhookHostIsNotPublic  = fmt.Errorf("url must point to a public host")
+	ErrWebhookEventIsInvalid   = fmt.Errorf("event type is unknown")
+	ErrWebhookSecretIsTooShort = fmt.Errorf("secret must be at least 16 characters long")
+
+	WebhookValidationErrors = map[error]bool{
+		ErrWebhookIsNil:            true,
+		ErrWebhookURLIsInvalid:     true,
+		ErrWebhookHostIsNotPublic:  true,
+		ErrWebhookEventIsInvalid:   true,
+		ErrWebhookSecretIsTooShort: true,
+	}
+)
+
+const minWebhookSecretLength = 16
+
+// sharedAddressSpace is the carrier-grade NAT range, IsPrivate leaves it out.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddress tells whether webhooks may be delivered to address. Loopback,
+// private, link-local (cloud metadata endpoints among them) and unspecified
+// addresses are refused.
+func PublicAddress(address netip.Addr) bool {
+	address = address.Unmap()
+	return address.IsValid() &&
+		!address.IsLoopback() &&
+		!address.IsPrivate() &&
+		!address.IsLinkLocalUnicast() &&
+		!address.IsLinkLocalMulticast() &&
+		!address.IsInterfaceLocalMulticast() &&
+		!address.IsMulticast() &&
+		!address.IsUnspecified() &&
+		!sharedAddressSpace.Contains(address)
+}
+
+func WebhookErrors(subscription *model.WebhookSubscription) Errors {
+	errs := Errors{}
+	if subscription == nil {
+		return errs.add("", ErrWebhookIsNil)
+	}
+
+	parsed, err := url.Parse(subscription.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		errs = errs.add(FieldOf(ErrWebhookURLIsInvalid), ErrWebhookURLIsInvalid)
+	} else if !publicHost(parsed.Hostname()) {
+		errs = errs.add(FieldOf(ErrWebhookHostIsNotPublic), ErrWebhookHostIsNotPublic)
+	}
+
+	for i, eventType := range subscription.EventTypes {
+		if !slices.Contains(model.EventTypes, eventType) {
+			errs = errs.add(fmt.Sprintf("event_types[%d]", i), ErrWebhookEventIsInvalid)
+		}
+	}
+
+	if len(subscription.Secret) < minWebhookSecretLength {
+		errs = errs.add(FieldOf(ErrWebhookSecretIsTooShort), ErrWebhookSecretIsTooShort)
+	}
+	return errs
+}
+
+// publicHost refuses the hosts that are known to be internal without a DNS
+// lookup, the delivery worker checks the addresses names resolve to.
+func publicHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if address, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(address)
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookErrors_HappyPath(t *testing.T) {
+	// fixture
+	subscription := &model.WebhookSubscription{
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{model.EventVideoCreated, model.EventAnnotationDeleted},
+	}
+
+	// test
+	errs := WebhookErrors(subscription)
+
+	// assertions
+	require.Empty(t, errs)
+}
+
+func TestWebhookErrors_UnhappyPath_SubscriptionIsNil(t *testing.T) {
+	// test
+	err := WebhookErrors(nil).Err()
+
+	// assertions
+	require.ErrorIs(t, err, ErrWebhookIsNil)
+}
+
+func TestWebhookErrors_UnhappyPath_ReportsEveryField(t *testing.T) {
+	// fixture
+	subscription := &model.WebhookSubscription{
+		URL:        "ftp://example.com/hook",
+		Secret:     "short",
+		EventTypes: []string{model.EventVideoCreated, "video.watched"},
+	}
+
+	// test
+	errs := WebhookErrors(subscription)
+
+	// assertions
+	require.Equal(t, Errors{
+		{Field: "url", Err: ErrWebhookURLIsInvalid},
+		{Field: "event_types[1]", Err: ErrWebhookEventIsInvalid},
+		{Field: "secret", Err: ErrWebhookSecretIsTooShort},
+	}, errs)
+}
+
+func TestWebhookErrors_UnhappyPath_InternalHost(t *testing.T) {
+	for _, url := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.8/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		t.Run(url, func(t *testing.T) {
+			// fixture
+			subscription := &model.WebhookSubscription{URL: url, Secret: "0123456789abcdef"}
+
+			// test
+			err := WebhookErrors(subscription).Err()
+
+			// assertions
+			require.ErrorIs(t, err, ErrWebhookHostIsNotPublic)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrWorkspaceNameIsInvalid = fmt.Errorf("workspace name must be 1 to 64 characters long")
+	ErrWorkspaceIdIsInvalid   = fmt.Errorf("workspace_id is not a workspace of yours")
+
+	WorkspaceValidationErrors = map[error]bool{
+		ErrWorkspaceNameIsInvalid: true,
+		ErrWorkspaceIdIsInvalid:   true,
+	}
+)
+
+const maxWorkspaceNameLength = 64
+
+func ValidateWorkspaceName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxWorkspaceNameLength {
+		return ErrWorkspaceNameIsInvalid
+	}
+	return nil
+}
+
+// InvitationErrors reports every failing field of an invitation at once.
+func InvitationErrors(email string, role string) Errors {
+	errs := Errors{}
+	if err := ValidateEmail(email); err != nil {
+		errs = errs.add(FieldOf(err), err)
+	}
+	if err := ValidateRole(role); err != nil {
+		errs = errs.add(FieldOf(err), err)
+	}
+	return errs
+}
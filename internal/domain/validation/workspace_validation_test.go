@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkspaceName(t *testing.T) {
+	// assert
+	require.NoError(t, ValidateWorkspaceName("Annotators"))
+	require.NoError(t, ValidateWorkspaceName(strings.Repeat("é", 64)))
+	require.ErrorIs(t, ValidateWorkspaceName(""), ErrWorkspaceNameIsInvalid)
+	require.ErrorIs(t, ValidateWorkspaceName("   "), ErrWorkspaceNameIsInvalid)
+	require.ErrorIs(t, ValidateWorkspaceName(strings.Repeat("a", 65)), ErrWorkspaceNameIsInvalid)
+	require.Equal(t, "name", FieldOf(ErrWorkspaceNameIsInvalid))
+}
+
+func TestInvitationErrors(t *testing.T) {
+	// test
+	errs := InvitationErrors("johndoe", "owner")
+
+	// assert
+	require.NoError(t, InvitationErrors("johndoe@example.com", "editor").Err())
+	require.Len(t, errs, 2)
+	require.Equal(t, "email", errs[0].Field)
+	require.Equal(t, "role", errs[1].Field)
+}
@@ -0,0 +1,77 @@
+package graphqlapi
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	maxDepth      = 8
+	maxComplexity = 1000
+	// defaultListSize is the number of items assumed for list fields whose
+	// size cannot be derived from their arguments.
+	defaultListSize = 10
+)
+
+// analyze returns the field nesting depth and the estimated cost of an
+// operation: every field costs one and the selections below a list field are
+// multiplied by its expected size. Documents that do not validate score zero,
+// the executor reports them.
+func analyze(schema *ast.Schema, query, operationName string, variables map[string]any) (int, int) {
+	document, errs := gqlparser.LoadQuery(schema, query)
+	if len(errs) > 0 {
+		return 0, 0
+	}
+
+	operation := document.Operations.ForName(operationName)
+	if operation == nil {
+		return 0, 0
+	}
+	return selectionDepth(operation.SelectionSet), selectionCost(operation.SelectionSet, variables)
+}
+
+func selectionDepth(selections ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selections {
+		nested := 0
+		switch typed := selection.(type) {
+		case *ast.Field:
+			nested = 1 + selectionDepth(typed.SelectionSet)
+		case *ast.InlineFragment:
+			nested = selectionDepth(typed.SelectionSet)
+		case *ast.FragmentSpread:
+			if typed.Definition != nil {
+				nested = selectionDepth(typed.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, nested)
+	}
+	return depth
+}
+
+func selectionCost(selections ast.SelectionSet, variables map[string]any) int {
+	cost := 0
+	for _, selection := range selections {
+		switch typed := selection.(type) {
+		case *ast.Field:
+			cost += 1 + listSize(typed, variables)*selectionCost(typed.SelectionSet, variables)
+		case *ast.InlineFragment:
+			cost += selectionCost(typed.SelectionSet, variables)
+		case *ast.FragmentSpread:
+			if typed.Definition != nil {
+				cost += selectionCost(typed.Definition.SelectionSet, variables)
+			}
+		}
+	}
+	return cost
+}
+
+func listSize(field *ast.Field, variables map[string]any) int {
+	if field.Definition == nil || field.Definition.Type.Elem == nil {
+		return 1
+	}
+	if ids, ok := field.ArgumentMap(variables)["ids"].([]any); ok {
+		return len(ids)
+	}
+	return defaultListSize
+}
@@ -0,0 +1,51 @@
+package graphqlapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestAnalyze_HappyPath_ListSizes(t *testing.T) {
+	// fixture
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: schemaSDL})
+	query := `query($ids: [ID!]!) { videos(ids: $ids) { title annotations { note } } }`
+
+	// test
+	depth, cost := analyze(schema, query, "", map[string]any{"ids": []any{"1", "2", "3"}})
+
+	// assert
+	// videos(1) + 3 * (title(1) + annotations(1) + 10 * note(1))
+	require.Equal(t, 3, depth)
+	require.Equal(t, 1+3*(1+1+defaultListSize), cost)
+}
+
+func TestAnalyze_HappyPath_Fragments(t *testing.T) {
+	// fixture
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: schemaSDL})
+	query := `
+		query Named { video(id: 1) { ...details } }
+		fragment details on Video { title owner { username } }
+	`
+
+	// test
+	depth, cost := analyze(schema, query, "Named", nil)
+
+	// assert
+	require.Equal(t, 3, depth)
+	require.Equal(t, 4, cost)
+}
+
+func TestAnalyze_UnhappyPath_InvalidDocumentScoresZero(t *testing.T) {
+	// fixture
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: schemaSDL})
+
+	// test
+	depth, cost := analyze(schema, `{ unknown }`, "", nil)
+
+	// assert
+	require.Zero(t, depth)
+	require.Zero(t, cost)
+}
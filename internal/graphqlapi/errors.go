@@ -0,0 +1,111 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var (
+	ErrInvalidID        = fmt.Errorf("id must be an integer")
+	ErrUnauthorized     = fmt.Errorf("unauthorized")
+	ErrMethodNotAllowed = fmt.Errorf("method not allowed")
+	ErrInvalidPayload   = fmt.Errorf("invalid request payload")
+	ErrQueryTooDeep     = fmt.Errorf("query exceeds the maximum depth")
+	ErrQueryTooComplex  = fmt.Errorf("query is too complex")
+	ErrVersionConflict  = fmt.Errorf("the resource was modified since it was last read")
+	ErrValidationFailed = fmt.Errorf("validation failed")
+	ErrInternal         = fmt.Errorf("request failed")
+)
+
+// Stable error codes returned in the extensions of each GraphQL error.
+const (
+	errorCodeValidation   = "VALIDATION_FAILED"
+	errorCodeNotFound     = "NOT_FOUND"
+	errorCodeConflict     = "VERSION_CONFLICT"
+	errorCodeUnauthorized = "UNAUTHORIZED"
+	errorCodeNotVerified  = "EMAIL_NOT_VERIFIED"
+	errorCodeForbidden    = "FORBIDDEN"
+	errorCodeTooDeep      = "QUERY_TOO_DEEP"
+	errorCodeTooComplex   = "QUERY_TOO_COMPLEX"
+	errorCodeBadRequest   = "BAD_REQUEST"
+	errorCodeInternal     = "INTERNAL"
+)
+
+var notFoundErrors = []error{
+	service.ErrVideoNotFound,
+	service.ErrAnnotationsNotFound,
+	service.ErrAnnotationNotFound,
+	service.ErrWorkspaceNotFound,
+	repository.UserNotFoundError,
+	repository.VideoNotFoundError,
+	repository.ErrAnnotationNotFound,
+}
+
+// codedError carries a stable code in the GraphQL error extensions, clients
+// should switch on it rather than on the message.
+type codedError struct {
+	err    error
+	code   string
+	fields []map[string]string
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func (e *codedError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["fields"] = e.fields
+	}
+	return extensions
+}
+
+// resolverError maps domain errors the same way the REST problem details do.
+// Unknown errors are logged and replaced so internals are never leaked.
+func resolverError(ctx context.Context, err error) error {
+	if errs, ok := validation.AsErrors(err); ok {
+		return validationError(errs)
+	}
+	if validation.IsValidationError(err) {
+		return validationError(validation.Errors{{Field: validation.FieldOf(err), Err: err}})
+	}
+
+	for _, notFound := range notFoundErrors {
+		if errors.Is(err, notFound) {
+			return &codedError{err: err, code: errorCodeNotFound}
+		}
+	}
+
+	if errors.Is(err, ports.ErrVersionConflict) {
+		return &codedError{err: ErrVersionConflict, code: errorCodeConflict}
+	}
+	if errors.Is(err, service.ErrEmailNotVerified) {
+		return &codedError{err: err, code: errorCodeNotVerified}
+	}
+	if errors.Is(err, service.ErrNotAllowed) || errors.Is(err, service.ErrUserDisabled) || errors.Is(err, service.ErrReadOnlyRole) {
+		return &codedError{err: err, code: errorCodeForbidden}
+	}
+
+	logging.FromContext(ctx).Error("graphql resolver failed", "error", err)
+	return &codedError{err: ErrInternal, code: errorCodeInternal}
+}
+
+func validationError(errs validation.Errors) error {
+	fields := []map[string]string{}
+	for _, fieldErr := range errs {
+		fields = append(fields, map[string]string{"field": fieldErr.Field, "detail": fieldErr.Err.Error()})
+	}
+	return &codedError{err: fmt.Errorf("%w: %s", ErrValidationFailed, errs.Error()), code: errorCodeValidation, fields: fields}
+}
@@ -0,0 +1,115 @@
+// Package graphqlapi serves a GraphQL endpoint over users, videos and
+// annotations backed by the same ports as the REST api package.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vektah/gqlparser/v2"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+type Handler struct {
+	schema       *graphql.Schema
+	analysis     *gqlast.Schema
+	authService  auth.AuthService
+	userService  ports.UserService
+	videoService ports.VideoService
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func NewHandler(authService auth.AuthService, userService ports.UserService, accountService ports.AccountService, videoService ports.VideoService) (*Handler, error) {
+	// MaxDepth still guards documents the analysis could not score.
+	schema, err := graphql.ParseSchema(schemaSDL, &rootResolver{accountService: accountService, videoService: videoService}, graphql.MaxDepth(maxDepth))
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := gqlparser.LoadSchema(&gqlast.Source{Name: "schema.graphql", Input: schemaSDL})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		schema:       schema,
+		analysis:     analysis,
+		authService:  authService,
+		userService:  userService,
+		videoService: videoService,
+	}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, http.StatusMethodNotAllowed, errorCodeBadRequest, ErrMethodNotAllowed)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+
+	var username string
+	var ok bool
+	if ok, username = h.authService.ValidateJwtToken(tokenString); !ok {
+		respondWithError(w, http.StatusUnauthorized, errorCodeUnauthorized, ErrUnauthorized)
+		return
+	}
+	logging.With(r.Context(), "username", username)
+
+	req := &request{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.Query == "" {
+		respondWithError(w, http.StatusBadRequest, errorCodeBadRequest, ErrInvalidPayload)
+		return
+	}
+
+	depth, cost := analyze(h.analysis, req.Query, req.OperationName, req.Variables)
+	if depth > maxDepth {
+		respondWithError(w, http.StatusBadRequest, errorCodeTooDeep, ErrQueryTooDeep)
+		return
+	}
+	if cost > maxComplexity {
+		respondWithError(w, http.StatusBadRequest, errorCodeTooComplex, ErrQueryTooComplex)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), usernameKey{}, username)
+	ctx = withLoaders(ctx, newLoaders(h.videoService, h.userService))
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+type usernameKey struct{}
+
+func usernameFrom(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey{}).(string)
+	return username
+}
+
+func respondWithError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]any{{
+			"message":    err.Error(),
+			"extensions": map[string]string{"code": code},
+		}},
+	})
+}
@@ -0,0 +1,527 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+const token = "test-token"
+
+type testHandler struct {
+	handler        *Handler
+	authService    *AuthServiceMock
+	userService    *UserServiceMock
+	accountService *AccountServiceMock
+	videoService   *VideoServiceMock
+}
+
+type response struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func beforeEach(t *testing.T) *testHandler {
+	th := &testHandler{
+		authService:    new(AuthServiceMock),
+		userService:    new(UserServiceMock),
+		accountService: new(AccountServiceMock),
+		videoService:   new(VideoServiceMock),
+	}
+
+	handler, err := NewHandler(th.authService, th.userService, th.accountService, th.videoService)
+	require.NoError(t, err)
+	th.handler = handler
+	return th
+}
+
+func (th *testHandler) do(t *testing.T, query string, variables map[string]any) (*httptest.ResponseRecorder, *response) {
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Authorization", token)
+
+	recorder := httptest.NewRecorder()
+	th.handler.ServeHTTP(recorder, req)
+
+	resp := &response{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), resp))
+	return recorder, resp
+}
+
+func (th *testHandler) assertExpectations(t *testing.T) {
+	th.authService.AssertExpectations(t)
+	th.userService.AssertExpectations(t)
+	th.accountService.AssertExpectations(t)
+	th.videoService.AssertExpectations(t)
+}
+
+func TestHandler_UnhappyPath_Unauthorized(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(false, "")
+
+	// test
+	recorder, resp := th.do(t, `{ video(id: 1) { title } }`, nil)
+
+	// assert
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, errorCodeUnauthorized, resp.Errors[0].Extensions["code"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_UnhappyPath_MethodNotAllowed(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	req, _ := http.NewRequest(http.MethodGet, "/graphql", nil)
+	recorder := httptest.NewRecorder()
+
+	// test
+	th.handler.ServeHTTP(recorder, req)
+
+	// assert
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestHandler_Videos_HappyPath_BatchesNestedLookups(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	videos := map[int]*model.Video{
+		1: {ID: 1, UserID: 10, Title: "First", Duration: time.Minute},
+		2: {ID: 2, UserID: 20, Title: "Second", Duration: time.Minute},
+		3: {ID: 3, UserID: 10, Title: "Third", Duration: time.Minute},
+	}
+	annotations := map[int][]*model.Annotation{
+		1: {{ID: 1, VideoID: 1, UserID: 10, StartTime: time.Second, EndTime: 2 * time.Second, Type: "ad", Note: "first"}},
+		3: {{ID: 2, VideoID: 3, UserID: 20, StartTime: time.Second, EndTime: 2 * time.Second, Type: "ad", Note: "second"}},
+	}
+	users := map[int]*model.User{
+		10: {ID: 10, Username: "johndoe"},
+		20: {ID: 20, Username: "janedoe"},
+	}
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.videoService.On("FindMany", "test-user", []int{1, 2, 3, 4}).Return(videos, nil).Once()
+	th.videoService.On("FindAnnotations", "test-user", []int{1, 2, 3}).Return(annotations, nil).Once()
+	th.userService.On("FindMany", []int{10, 20}).Return(users, nil).Once()
+
+	// test
+	recorder, resp := th.do(t, `{
+		videos(ids: [1, 2, 3, 4]) {
+			title
+			owner { username }
+			annotations { note }
+		}
+	}`, nil)
+
+	// assert
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, resp.Errors)
+
+	list := resp.Data["videos"].([]any)
+	require.Len(t, list, 3)
+	third := list[2].(map[string]any)
+	require.Equal(t, "Third", third["title"])
+	require.Equal(t, "johndoe", third["owner"].(map[string]any)["username"])
+	require.Len(t, third["annotations"], 1)
+	require.Empty(t, list[1].(map[string]any)["annotations"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_Video_HappyPath_FiltersAnnotations(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.videoService.On("FindMany", "test-user", []int{1}).Return(map[int]*model.Video{1: {ID: 1, Duration: time.Minute}}, nil)
+	th.videoService.On("FindAnnotations", "test-user", []int{1}).Return(map[int][]*model.Annotation{
+		1: {
+			{ID: 1, VideoID: 1, StartTime: 0, EndTime: 5 * time.Second, Type: "ad", Note: "early ad"},
+			{ID: 2, VideoID: 1, StartTime: 20 * time.Second, EndTime: 25 * time.Second, Type: "ad", Note: "late ad"},
+			{ID: 3, VideoID: 1, StartTime: 20 * time.Second, EndTime: 25 * time.Second, Type: "chapter", Note: "chapter"},
+		},
+	}, nil)
+
+	// test
+	_, resp := th.do(t, `{ video(id: "1") { annotations(type: "ad", from: 10, to: 30) { note startSeconds } } }`, nil)
+
+	// assert
+	require.Empty(t, resp.Errors)
+	filtered := resp.Data["video"].(map[string]any)["annotations"].([]any)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "late ad", filtered[0].(map[string]any)["note"])
+	require.Equal(t, float64(20), filtered[0].(map[string]any)["startSeconds"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_Video_HappyPath_NotFoundIsNull(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.videoService.On("FindMany", "test-user", []int{9}).Return(map[int]*model.Video{}, nil)
+
+	// test
+	_, resp := th.do(t, `{ video(id: 9) { title } }`, nil)
+
+	// assert
+	require.Empty(t, resp.Errors)
+	require.Nil(t, resp.Data["video"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_UnhappyPath_MaxDepth(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+
+	// test
+	recorder, resp := th.do(t, `{ video(id: 1) { annotations { video { annotations { video { annotations { video { annotations { note } } } } } } } } }`, nil)
+
+	// assert
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, errorCodeTooDeep, resp.Errors[0].Extensions["code"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_UnhappyPath_QueryTooComplex(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ids := make([]any, 50)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+
+	// test
+	recorder, resp := th.do(t, `query($ids: [ID!]!) { videos(ids: $ids) { annotations { note video { title } } } }`,
+		map[string]any{"ids": ids})
+
+	// assert
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, errorCodeTooComplex, resp.Errors[0].Extensions["code"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_CreateVideo_HappyPath(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	th.videoService.On("Create", "test-user", mock.MatchedBy(func(video *model.Video) bool {
+		return video.Title == "Title" && video.Duration == 90*time.Second && video.WorkspaceID == 7 && !video.CreatedAt.IsZero()
+	}), mock.MatchedBy(func(annotations []*model.Annotation) bool {
+		return len(annotations) == 1 && annotations[0].EndTime == 1500*time.Millisecond
+	})).Return(nil)
+
+	// test
+	_, resp := th.do(t, `mutation {
+		createVideo(
+			input: { title: "Title", description: "Description", link: "https://example.com/1.mp4", durationSeconds: 90, workspaceId: 7 },
+			annotations: [{ startSeconds: 1, endSeconds: 1.5, type: "ad", note: "note" }]
+		)
+	}`, nil)
+
+	// assert
+	require.Empty(t, resp.Errors)
+	require.Equal(t, true, resp.Data["createVideo"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_CreateVideo_UnhappyPath_ValidationFields(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	errs := validation.Errors{
+		{Field: "title", Err: validation.ErrTitleIsInvalid},
+		{Field: "link", Err: validation.ErrLinkIsInvalid},
+	}
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	th.videoService.On("Create", "test-user", mock.Anything, mock.Anything).Return(error(errs))
+
+	// test
+	_, resp := th.do(t, `mutation {
+		createVideo(input: { title: "", description: "", link: "", durationSeconds: 0 })
+	}`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeValidation, resp.Errors[0].Extensions["code"])
+	require.Len(t, resp.Errors[0].Extensions["fields"], 2)
+	th.assertExpectations(t)
+}
+
+func TestHandler_UpdateVideo_UnhappyPath_VersionConflict(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	th.videoService.On("Update", "test-user", 1, mock.MatchedBy(func(video *model.Video) bool {
+		return video.Version == 2
+	}), []*model.Annotation(nil)).Return(ports.ErrVersionConflict)
+
+	// test
+	_, resp := th.do(t, `mutation {
+		updateVideo(id: 1, version: 2, input: { title: "T", description: "D", link: "https://example.com/1.mp4" }) { version }
+	}`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeConflict, resp.Errors[0].Extensions["code"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_DeleteVideo_HappyPath(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	th.videoService.On("Remove", "test-user", 1, 3).Return(nil)
+
+	// test
+	_, resp := th.do(t, `mutation { deleteVideo(id: 1, version: 3) }`, nil)
+
+	// assert
+	require.Empty(t, resp.Errors)
+	require.Equal(t, true, resp.Data["deleteVideo"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_DeleteVideo_UnhappyPath_Forbidden(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	th.videoService.On("Remove", "test-user", 1, 3).Return(service.ErrNotAllowed)
+
+	// test
+	_, resp := th.do(t, `mutation { deleteVideo(id: 1, version: 3) }`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeForbidden, resp.Errors[0].Extensions["code"])
+	th.assertExpectations(t)
+}
+
+func TestHandler_DeleteVideo_UnhappyPath_EmailNotVerified(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(service.ErrEmailNotVerified)
+
+	// test
+	_, resp := th.do(t, `mutation { deleteVideo(id: 1, version: 3) }`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeNotVerified, resp.Errors[0].Extensions["code"])
+	th.videoService.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything, mock.Anything)
+	th.assertExpectations(t)
+}
+
+func TestHandler_UnhappyPath_InternalErrorIsHidden(t *testing.T) {
+	// fixture
+	th := beforeEach(t)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.videoService.On("FindMany", "test-user", []int{1}).Return(nil, fmt.Errorf("database is locked"))
+
+	// test
+	_, resp := th.do(t, `{ video(id: 1) { title } }`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeInternal, resp.Errors[0].Extensions["code"])
+	require.False(t, strings.Contains(resp.Errors[0].Message, "database"))
+	th.assertExpectations(t)
+}
+
+type AuthServiceMock struct {
+	mock.Mock
+}
+
+func (s *AuthServiceMock) GenerateJwtToken(username string) (string, error) {
+	args := s.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateJwtToken(tokenString string) (bool, string) {
+	args := s.Called(tokenString)
+	return args.Bool(0), args.String(1)
+}
+
+func (s *AuthServiceMock) GenerateMfaChallenge(username string) (string, error) {
+	args := s.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateMfaChallenge(tokenString string) (bool, string) {
+	args := s.Called(tokenString)
+	return args.Bool(0), args.String(1)
+}
+
+func (s *AuthServiceMock) GenerateActionToken(username string, purpose string, id string, ttl time.Duration) (string, error) {
+	args := s.Called(username, purpose, id, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateActionToken(tokenString string, purpose string) (bool, string, string) {
+	args := s.Called(tokenString, purpose)
+	return args.Bool(0), args.String(1), args.String(2)
+}
+
+func (s *AuthServiceMock) JSONWebKeys() []auth.JSONWebKey {
+	args := s.Called()
+	return args.Get(0).([]auth.JSONWebKey)
+}
+
+type UserServiceMock struct {
+	mock.Mock
+}
+
+func (s *UserServiceMock) Login(ctx context.Context, username string, password string) (string, error) {
+	args := s.Called(username, password)
+	return args.String(0), args.Error(1)
+}
+
+func (s *UserServiceMock) Signup(ctx context.Context, email string, password string) (string, error) {
+	args := s.Called(email, password)
+	return args.String(0), args.Error(1)
+}
+
+func (s *UserServiceMock) FindMany(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	args := s.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Create(ctx context.Context, email string, password string, role string) (*model.User, error) {
+	args := s.Called(email, password, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Find(ctx context.Context, username string) (*model.User, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Disable(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) Unlock(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) SetRole(ctx context.Context, username string, role string) error {
+	args := s.Called(username, role)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) ResetPassword(ctx context.Context, username string, password string) error {
+	args := s.Called(username, password)
+	return args.Error(0)
+}
+
+type AccountServiceMock struct {
+	mock.Mock
+}
+
+func (s *AccountServiceMock) SendVerification(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) VerifyEmail(ctx context.Context, token string) error {
+	args := s.Called(token)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) RequestPasswordReset(ctx context.Context, email string) error {
+	args := s.Called(email)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) ResetPassword(ctx context.Context, token string, password string) error {
+	args := s.Called(token, password)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) AuthorizeWrite(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+type VideoServiceMock struct {
+	mock.Mock
+}
+
+func (s *VideoServiceMock) Create(ctx context.Context, username string, video *model.Video, annotations []*model.Annotation) error {
+	args := s.Called(username, video, annotations)
+	return args.Error(0)
+}
+
+func (s *VideoServiceMock) Find(ctx context.Context, username string, id int) (*model.Video, []*model.Annotation, error) {
+	args := s.Called(username, id)
+	if args.Get(0) == nil || args.Get(1) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Video), args.Get(1).([]*model.Annotation), args.Error(2)
+}
+
+func (s *VideoServiceMock) FindMany(ctx context.Context, username string, ids []int) (map[int]*model.Video, error) {
+	args := s.Called(username, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) FindAnnotations(ctx context.Context, username string, videoIds []int) (map[int][]*model.Annotation, error) {
+	args := s.Called(username, videoIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]*model.Annotation), args.Error(1)
+}
+
+func (s *VideoServiceMock) List(ctx context.Context, username string, workspaceId int) ([]*model.Video, error) {
+	args := s.Called(username, workspaceId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) Update(ctx context.Context, username string, id int, video *model.Video, annotations []*model.Annotation) error {
+	args := s.Called(username, id, video, annotations)
+	return args.Error(0)
+}
+
+func (s *VideoServiceMock) Remove(ctx context.Context, username string, id int, version int) error {
+	args := s.Called(username, id, version)
+	return args.Error(0)
+}
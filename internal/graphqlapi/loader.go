@@ -0,0 +1,134 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+// loader batches lookups by key. Keys are primed as soon as a parent object
+// is known, the first Load then fetches every pending key in one call and
+// later loads are served from the per-request cache.
+type loader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(context.Context, []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	loaded  map[K]bool
+}
+
+func newLoader[K comparable, V any](fetch func(context.Context, []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		queued:  map[K]bool{},
+		results: map[K]V{},
+		loaded:  map[K]bool{},
+	}
+}
+
+func (l *loader[K, V]) Prime(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue(keys...)
+}
+
+// Load returns the value for key and whether it exists.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loaded[key] {
+		l.queue(key)
+		if err := l.flush(ctx); err != nil {
+			var zero V
+			return zero, false, err
+		}
+	}
+
+	value, ok := l.results[key]
+	return value, ok, nil
+}
+
+func (l *loader[K, V]) queue(keys ...K) {
+	for _, key := range keys {
+		if l.loaded[key] || l.queued[key] {
+			continue
+		}
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+}
+
+func (l *loader[K, V]) flush(ctx context.Context) error {
+	keys := l.pending
+	l.pending = nil
+	l.queued = map[K]bool{}
+
+	values, err := l.fetch(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		l.loaded[key] = true
+		if value, ok := values[key]; ok {
+			l.results[key] = value
+		}
+	}
+	return nil
+}
+
+type loaders struct {
+	videos      *loader[int, *model.Video]
+	annotations *loader[int, []*model.Annotation]
+	users       *loader[int, *model.User]
+}
+
+// newLoaders wires the loaders so that fetching videos primes their
+// annotations and owners, and fetching annotations primes their authors.
+func newLoaders(videoService ports.VideoService, userService ports.UserService) *loaders {
+	l := &loaders{}
+
+	l.users = newLoader(userService.FindMany)
+
+	l.annotations = newLoader(func(ctx context.Context, videoIds []int) (map[int][]*model.Annotation, error) {
+		annotations, err := videoService.FindAnnotations(ctx, usernameFrom(ctx), videoIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range annotations {
+			for _, annotation := range list {
+				l.users.Prime(annotation.UserID)
+			}
+		}
+		return annotations, nil
+	})
+
+	l.videos = newLoader(func(ctx context.Context, ids []int) (map[int]*model.Video, error) {
+		videos, err := videoService.FindMany(ctx, usernameFrom(ctx), ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if video, ok := videos[id]; ok {
+				l.annotations.Prime(video.ID)
+				l.users.Prime(video.UserID)
+			}
+		}
+		return videos, nil
+	})
+
+	return l
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,83 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Load_HappyPath_FetchesPrimedKeysOnce(t *testing.T) {
+	// fixture
+	calls := [][]int{}
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls = append(calls, keys)
+		values := map[int]string{}
+		for _, key := range keys {
+			values[key] = fmt.Sprint("value-", key)
+		}
+		return values, nil
+	})
+	l.Prime(1, 2, 3, 2)
+
+	// test
+	wg := sync.WaitGroup{}
+	for _, key := range []int{3, 1, 2} {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			value, ok, err := l.Load(context.Background(), key)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, fmt.Sprint("value-", key), value)
+		}(key)
+	}
+	wg.Wait()
+
+	// assert
+	require.Equal(t, [][]int{{1, 2, 3}}, calls)
+}
+
+func TestLoader_Load_HappyPath_MissingKey(t *testing.T) {
+	// fixture
+	calls := 0
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls++
+		return map[int]string{}, nil
+	})
+
+	// test
+	_, ok, err := l.Load(context.Background(), 1)
+	_, okAgain, errAgain := l.Load(context.Background(), 1)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, errAgain)
+	require.False(t, ok)
+	require.False(t, okAgain)
+	require.Equal(t, 1, calls)
+}
+
+func TestLoader_Load_UnhappyPath_ErrorsAreNotCached(t *testing.T) {
+	// fixture
+	fail := true
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		if fail {
+			return nil, fmt.Errorf("database error")
+		}
+		return map[int]string{1: "value"}, nil
+	})
+
+	// test
+	_, _, err := l.Load(context.Background(), 1)
+	fail = false
+	value, ok, errAgain := l.Load(context.Background(), 1)
+
+	// assert
+	require.Error(t, err)
+	require.NoError(t, errAgain)
+	require.True(t, ok)
+	require.Equal(t, "value", value)
+}
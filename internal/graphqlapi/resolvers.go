@@ -0,0 +1,368 @@
+package graphqlapi
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+)
+
+type rootResolver struct {
+	accountService ports.AccountService
+	videoService   ports.VideoService
+}
+
+func (r *rootResolver) Video(ctx context.Context, args struct{ ID graphql.ID }) (*videoResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	video, ok, err := loadersFrom(ctx).videos.Load(ctx, id)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &videoResolver{video: video}, nil
+}
+
+func (r *rootResolver) Videos(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*videoResolver, error) {
+	ids := make([]int, 0, len(args.IDs))
+	for _, rawId := range args.IDs {
+		id, err := parseID(rawId)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	videos := loadersFrom(ctx).videos
+	videos.Prime(ids...)
+
+	resolvers := []*videoResolver{}
+	for _, id := range ids {
+		video, ok, err := videos.Load(ctx, id)
+		if err != nil {
+			return nil, resolverError(ctx, err)
+		}
+		if ok {
+			resolvers = append(resolvers, &videoResolver{video: video})
+		}
+	}
+	return resolvers, nil
+}
+
+type videoInput struct {
+	Title           string
+	Description     string
+	Link            string
+	DurationSeconds *float64
+	WorkspaceID     *graphql.ID
+}
+
+type annotationInput struct {
+	ID           *graphql.ID
+	StartSeconds float64
+	EndSeconds   float64
+	Type         string
+	Note         string
+	Version      *int32
+}
+
+func (r *rootResolver) CreateVideo(ctx context.Context, args struct {
+	Input       videoInput
+	Annotations *[]annotationInput
+}) (bool, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return false, err
+	}
+
+	video, err := args.Input.toModel()
+	if err != nil {
+		return false, err
+	}
+	video.CreatedAt = time.Now()
+
+	annotations, err := toAnnotationModels(args.Annotations)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.videoService.Create(ctx, usernameFrom(ctx), video, annotations); err != nil {
+		return false, resolverError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r *rootResolver) UpdateVideo(ctx context.Context, args struct {
+	ID          graphql.ID
+	Version     int32
+	Input       videoInput
+	Annotations *[]annotationInput
+}) (*videoResolver, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+	video.Version = int(args.Version)
+
+	annotations, err := toAnnotationModels(args.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.videoService.Update(ctx, usernameFrom(ctx), id, video, annotations); err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	updated, _, err := r.videoService.Find(ctx, usernameFrom(ctx), id)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	return &videoResolver{video: updated}, nil
+}
+
+func (r *rootResolver) DeleteVideo(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version int32
+}) (bool, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return false, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.videoService.Remove(ctx, usernameFrom(ctx), id, int(args.Version)); err != nil {
+		return false, resolverError(ctx, err)
+	}
+	return true, nil
+}
+
+// authorizeWrite refuses the mutations of viewers and of users who must
+// verify their email first.
+func (r *rootResolver) authorizeWrite(ctx context.Context) error {
+	if err := r.accountService.AuthorizeWrite(ctx, usernameFrom(ctx)); err != nil {
+		return resolverError(ctx, err)
+	}
+	return nil
+}
+
+type userResolver struct {
+	user *model.User
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return formatID(r.user.ID)
+}
+
+func (r *userResolver) Username() string {
+	return r.user.Username
+}
+
+func (r *userResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.user.CreatedAt}
+}
+
+type videoResolver struct {
+	video *model.Video
+}
+
+func (r *videoResolver) ID() graphql.ID {
+	return formatID(r.video.ID)
+}
+
+func (r *videoResolver) Title() string {
+	return r.video.Title
+}
+
+func (r *videoResolver) Description() string {
+	return r.video.Description
+}
+
+func (r *videoResolver) Link() string {
+	return r.video.Link
+}
+
+func (r *videoResolver) DurationSeconds() float64 {
+	return r.video.Duration.Seconds()
+}
+
+func (r *videoResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.video.CreatedAt}
+}
+
+func (r *videoResolver) WorkspaceID() graphql.ID {
+	return formatID(r.video.WorkspaceID)
+}
+
+func (r *videoResolver) Version() int32 {
+	return int32(r.video.Version)
+}
+
+func (r *videoResolver) Owner(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.video.UserID)
+}
+
+func (r *videoResolver) Annotations(ctx context.Context, args struct {
+	Type *string
+	From *float64
+	To   *float64
+}) ([]*annotationResolver, error) {
+	annotations, _, err := loadersFrom(ctx).annotations.Load(ctx, r.video.ID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	resolvers := []*annotationResolver{}
+	for _, annotation := range annotations {
+		if args.Type != nil && annotation.Type != *args.Type {
+			continue
+		}
+		if args.From != nil && annotation.EndTime < seconds(*args.From) {
+			continue
+		}
+		if args.To != nil && annotation.StartTime > seconds(*args.To) {
+			continue
+		}
+		resolvers = append(resolvers, &annotationResolver{annotation: annotation})
+	}
+	return resolvers, nil
+}
+
+type annotationResolver struct {
+	annotation *model.Annotation
+}
+
+func (r *annotationResolver) ID() graphql.ID {
+	return formatID(r.annotation.ID)
+}
+
+func (r *annotationResolver) StartSeconds() float64 {
+	return r.annotation.StartTime.Seconds()
+}
+
+func (r *annotationResolver) EndSeconds() float64 {
+	return r.annotation.EndTime.Seconds()
+}
+
+func (r *annotationResolver) Type() string {
+	return r.annotation.Type
+}
+
+func (r *annotationResolver) Note() string {
+	return r.annotation.Note
+}
+
+func (r *annotationResolver) Version() int32 {
+	return int32(r.annotation.Version)
+}
+
+func (r *annotationResolver) Author(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.annotation.UserID)
+}
+
+func (r *annotationResolver) Video(ctx context.Context) (*videoResolver, error) {
+	video, ok, err := loadersFrom(ctx).videos.Load(ctx, r.annotation.VideoID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &videoResolver{video: video}, nil
+}
+
+func loadUser(ctx context.Context, id int) (*userResolver, error) {
+	user, ok, err := loadersFrom(ctx).users.Load(ctx, id)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &userResolver{user: user}, nil
+}
+
+// toModel leaves the workspace at 0 when none is given, new videos then go
+// to the default workspace of the user. Without a duration creating the
+// video fails validation.
+func (i videoInput) toModel() (*model.Video, error) {
+	video := &model.Video{
+		Title:       i.Title,
+		Description: i.Description,
+		Link:        i.Link,
+	}
+	if i.DurationSeconds != nil {
+		video.Duration = seconds(*i.DurationSeconds)
+	}
+	if i.WorkspaceID != nil {
+		workspaceId, err := parseID(*i.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		video.WorkspaceID = workspaceId
+	}
+	return video, nil
+}
+
+func toAnnotationModels(inputs *[]annotationInput) ([]*model.Annotation, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+
+	annotations := make([]*model.Annotation, 0, len(*inputs))
+	for _, input := range *inputs {
+		annotation := &model.Annotation{
+			StartTime: seconds(input.StartSeconds),
+			EndTime:   seconds(input.EndSeconds),
+			Type:      input.Type,
+			Note:      input.Note,
+		}
+		if input.ID != nil {
+			id, err := parseID(*input.ID)
+			if err != nil {
+				return nil, err
+			}
+			annotation.ID = id
+		}
+		if input.Version != nil {
+			annotation.Version = int(*input.Version)
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
+
+func parseID(id graphql.ID) (int, error) {
+	parsed, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, ErrInvalidID
+	}
+	return parsed, nil
+}
+
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
@@ -0,0 +1,99 @@
+package graphqlapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+)
+
+// withVideoService serves the schema with the video service over an in-memory
+// database, so the mutations go through its validation.
+func withVideoService(t *testing.T) (*testHandler, ports.UserRepository) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// every connection would open its own in-memory database
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+
+	_, err = db.NewMigrator(database).Up(context.Background())
+	require.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(database)
+	user := &model.User{Username: "test-user", Email: "test-user@example.com", Role: model.RoleEditor, CreatedAt: time.Now().UTC()}
+	require.NoError(t, userRepo.Save(context.Background(), user))
+
+	th := &testHandler{
+		authService:    new(AuthServiceMock),
+		userService:    new(UserServiceMock),
+		accountService: new(AccountServiceMock),
+	}
+	videoService := service.NewVideoService(repository.NewVideoRepository(database), repository.NewAnnotationRepository(database),
+		userRepo, repository.NewWorkspaceRepository(database), repository.NewTransactor(database))
+
+	th.handler, err = NewHandler(th.authService, th.userService, th.accountService, videoService)
+	require.NoError(t, err)
+	th.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	th.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	return th, userRepo
+}
+
+func TestResolvers_CreateAndUpdateVideo(t *testing.T) {
+	// fixture
+	th, _ := withVideoService(t)
+
+	// test
+	_, created := th.do(t, `mutation {
+		createVideo(
+			input: { title: "Title", description: "Description", link: "https://example.com/1.mp4", durationSeconds: 90 },
+			annotations: [{ startSeconds: 1, endSeconds: 1.5, type: "ad", note: "note" }]
+		)
+	}`, nil)
+	_, updated := th.do(t, `mutation {
+		updateVideo(
+			id: 1, version: 1,
+			input: { title: "New title", description: "Description", link: "https://example.com/1.mp4", durationSeconds: 90 },
+			annotations: [{ id: 1, version: 1, startSeconds: 2, endSeconds: 3, type: "ad", note: "moved" }]
+		) { title version workspaceId annotations { startSeconds note version } }
+	}`, nil)
+
+	// assert
+	require.Empty(t, created.Errors)
+	require.Equal(t, true, created.Data["createVideo"])
+	require.Empty(t, updated.Errors)
+	video := updated.Data["updateVideo"].(map[string]any)
+	require.Equal(t, "New title", video["title"])
+	require.Equal(t, 2.0, video["version"])
+	require.Equal(t, "1", video["workspaceId"])
+	require.Equal(t, []any{map[string]any{"startSeconds": 2.0, "note": "moved", "version": 2.0}}, video["annotations"])
+}
+
+func TestResolvers_DisabledUserIsRefused(t *testing.T) {
+	// fixture
+	th, userRepo := withVideoService(t)
+	_, created := th.do(t, `mutation {
+		createVideo(input: { title: "Title", description: "Description", link: "https://example.com/1.mp4", durationSeconds: 90 })
+	}`, nil)
+	require.Empty(t, created.Errors)
+
+	user, err := userRepo.FindByUsername(context.Background(), "test-user")
+	require.NoError(t, err)
+	user.Disabled = true
+	require.NoError(t, userRepo.Update(context.Background(), user))
+
+	// test
+	_, resp := th.do(t, `{ video(id: 1) { title } }`, nil)
+
+	// assert
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, errorCodeForbidden, resp.Errors[0].Extensions["code"])
+}
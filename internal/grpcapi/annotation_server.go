@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+)
+
+type annotationServer struct {
+	videospb.UnimplementedAnnotationServiceServer
+	videoService      ports.VideoService
+	annotationService ports.AnnotationService
+}
+
+func (s *annotationServer) ListAnnotations(req *videospb.ListAnnotationsRequest, stream grpc.ServerStreamingServer[videospb.Annotation]) error {
+	_, annotations, err := s.videoService.Find(stream.Context(), usernameFrom(stream.Context()), int(req.GetVideoId()))
+	if err != nil {
+		return statusFor(stream.Context(), err)
+	}
+
+	for _, annotation := range annotations {
+		if err := stream.Send(toAnnotationMessage(annotation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *annotationServer) ListRevisions(req *videospb.ListRevisionsRequest, stream grpc.ServerStreamingServer[videospb.AnnotationRevision]) error {
+	revisions, err := s.annotationService.Revisions(stream.Context(), usernameFrom(stream.Context()), int(req.GetAnnotationId()))
+	if err != nil {
+		return statusFor(stream.Context(), err)
+	}
+
+	for _, revision := range revisions {
+		if err := stream.Send(toRevisionMessage(revision)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *annotationServer) DiffRevisions(ctx context.Context, req *videospb.DiffRevisionsRequest) (*videospb.DiffRevisionsResponse, error) {
+	changes, err := s.annotationService.Diff(ctx, usernameFrom(ctx), int(req.GetAnnotationId()), int(req.GetFrom()), int(req.GetTo()))
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+
+	response := &videospb.DiffRevisionsResponse{}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, toFieldChangeMessage(change))
+	}
+	return response, nil
+}
+
+func (s *annotationServer) RevertAnnotation(ctx context.Context, req *videospb.RevertAnnotationRequest) (*videospb.AnnotationRevision, error) {
+	written, err := s.annotationService.Revert(ctx, usernameFrom(ctx), int(req.GetAnnotationId()), int(req.GetRevision()))
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return toRevisionMessage(written), nil
+}
@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+type authServer struct {
+	videospb.UnimplementedAuthServiceServer
+	userService ports.UserService
+}
+
+func (s *authServer) Signup(ctx context.Context, req *videospb.SignupRequest) (*videospb.TokenResponse, error) {
+	token, err := s.userService.Signup(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return &videospb.TokenResponse{Token: token}, nil
+}
+
+func (s *authServer) Login(ctx context.Context, req *videospb.LoginRequest) (*videospb.TokenResponse, error) {
+	token, err := s.userService.Login(auth.WithClientAddress(ctx, peerAddress(ctx)), req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return &videospb.TokenResponse{Token: token}, nil
+}
+
+// peerAddress is the host the call came from, without its port.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,98 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+)
+
+func toVideoMessage(video *model.Video) *videospb.Video {
+	return &videospb.Video{
+		Id:          int64(video.ID),
+		UserId:      int64(video.UserID),
+		Title:       video.Title,
+		Description: video.Description,
+		Link:        video.Link,
+		Duration:    durationpb.New(video.Duration),
+		CreatedAt:   timestamppb.New(video.CreatedAt),
+		Version:     int64(video.Version),
+	}
+}
+
+func toVideoModel(message *videospb.Video) *model.Video {
+	if message == nil {
+		return nil
+	}
+	video := &model.Video{
+		ID:          int(message.GetId()),
+		UserID:      int(message.GetUserId()),
+		Title:       message.GetTitle(),
+		Description: message.GetDescription(),
+		Link:        message.GetLink(),
+		Duration:    message.GetDuration().AsDuration(),
+		Version:     int(message.GetVersion()),
+	}
+	if message.CreatedAt != nil {
+		video.CreatedAt = message.CreatedAt.AsTime()
+	}
+	return video
+}
+
+func toAnnotationMessage(annotation *model.Annotation) *videospb.Annotation {
+	return &videospb.Annotation{
+		Id:        int64(annotation.ID),
+		VideoId:   int64(annotation.VideoID),
+		UserId:    int64(annotation.UserID),
+		StartTime: durationpb.New(annotation.StartTime),
+		EndTime:   durationpb.New(annotation.EndTime),
+		Type:      annotation.Type,
+		Note:      annotation.Note,
+		Version:   int64(annotation.Version),
+	}
+}
+
+func toAnnotationModels(messages []*videospb.Annotation) []*model.Annotation {
+	if len(messages) == 0 {
+		return nil
+	}
+	annotations := make([]*model.Annotation, 0, len(messages))
+	for _, message := range messages {
+		annotations = append(annotations, &model.Annotation{
+			ID:        int(message.GetId()),
+			VideoID:   int(message.GetVideoId()),
+			UserID:    int(message.GetUserId()),
+			StartTime: message.GetStartTime().AsDuration(),
+			EndTime:   message.GetEndTime().AsDuration(),
+			Type:      message.GetType(),
+			Note:      message.GetNote(),
+			Version:   int(message.GetVersion()),
+		})
+	}
+	return annotations
+}
+
+func toRevisionMessage(revision *model.AnnotationRevision) *videospb.AnnotationRevision {
+	return &videospb.AnnotationRevision{
+		AnnotationId: int64(revision.AnnotationID),
+		Revision:     int64(revision.Revision),
+		StartTime:    durationpb.New(revision.StartTime),
+		EndTime:      durationpb.New(revision.EndTime),
+		Type:         revision.Type,
+		Note:         revision.Note,
+		CreatedAt:    timestamppb.New(revision.CreatedAt),
+	}
+}
+
+// toFieldChangeMessage renders both sides as text, durations keep their
+// time.Duration notation such as "1m30s".
+func toFieldChangeMessage(change *model.AnnotationFieldChange) *videospb.FieldChange {
+	return &videospb.FieldChange{
+		Field: change.Field,
+		From:  fmt.Sprint(change.From),
+		To:    fmt.Sprint(change.To),
+	}
+}
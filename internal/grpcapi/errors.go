@@ -0,0 +1,114 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/repository"
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var notFoundErrors = []error{
+	service.ErrVideoNotFound,
+	service.ErrAnnotationsNotFound,
+	service.ErrAnnotationNotFound,
+	service.ErrRevisionNotFound,
+	service.ErrWorkspaceNotFound,
+	repository.UserNotFoundError,
+	repository.VideoNotFoundError,
+	repository.ErrAnnotationNotFound,
+}
+
+// statusFor maps domain errors onto gRPC statuses, mirroring the problem
+// details of the REST API. Unknown errors become Internal without details.
+func statusFor(ctx context.Context, err error) error {
+	if errs, ok := validation.AsErrors(err); ok {
+		return validationStatus(errs)
+	}
+	if validation.IsValidationError(err) {
+		return validationStatus(validation.Errors{{Field: validation.FieldOf(err), Err: err}})
+	}
+
+	for _, notFound := range notFoundErrors {
+		if errors.Is(err, notFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+	}
+
+	switch {
+	case errors.Is(err, service.UserOrPasswordNotFoundError):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrEmailNotVerified), errors.Is(err, service.ErrNotAllowed), errors.Is(err, service.ErrUserDisabled),
+		errors.Is(err, service.ErrReadOnlyRole):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ports.ErrVersionConflict):
+		return status.Error(codes.FailedPrecondition, "the resource was modified since it was last read")
+	case errors.Is(err, service.ErrLoginThrottled):
+		return throttledStatus(err)
+	case errors.Is(err, service.ErrMfaRequired):
+		return mfaRequiredStatus(err)
+	}
+
+	logging.FromContext(ctx).Error("rpc failed", "error", err)
+	return status.Error(codes.Internal, "request failed")
+}
+
+// throttledStatus tells when to retry a throttled login with RetryInfo.
+func throttledStatus(err error) error {
+	var throttled *service.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(throttled.RetryAfter.Round(time.Second))}
+	st, detailsErr := status.New(codes.ResourceExhausted, err.Error()).WithDetails(retryInfo)
+	if detailsErr != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return st.Err()
+}
+
+// mfaRequiredStatus hands the challenge over in an ErrorInfo, the login is
+// finished over HTTP at /login/mfa.
+func mfaRequiredStatus(err error) error {
+	var required *service.MfaRequiredError
+	if !errors.As(err, &required) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	errorInfo := &errdetails.ErrorInfo{
+		Reason:   "MFA_REQUIRED",
+		Domain:   "videos-api",
+		Metadata: map[string]string{"challenge_token": required.Challenge},
+	}
+	st, detailsErr := status.New(codes.Unauthenticated, err.Error()).WithDetails(errorInfo)
+	if detailsErr != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return st.Err()
+}
+
+func validationStatus(errs validation.Errors) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldErr := range errs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Err.Error(),
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, errs.Error()).WithDetails(badRequest)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, errs.Error())
+	}
+	return st.Err()
+}
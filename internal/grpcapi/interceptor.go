@@ -0,0 +1,104 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+// publicMethods can be called without a token, they are how one is obtained.
+var publicMethods = map[string]bool{
+	videospb.AuthService_Signup_FullMethodName: true,
+	videospb.AuthService_Login_FullMethodName:  true,
+}
+
+// writeMethods change data, viewers and users who must verify their email
+// first cannot call them.
+var writeMethods = map[string]bool{
+	videospb.VideoService_CreateVideo_FullMethodName:           true,
+	videospb.VideoService_UpdateVideo_FullMethodName:           true,
+	videospb.VideoService_DeleteVideo_FullMethodName:           true,
+	videospb.AnnotationService_RevertAnnotation_FullMethodName: true,
+}
+
+type usernameKey struct{}
+
+type authInterceptor struct {
+	authService    auth.AuthService
+	accountService ports.AccountService
+}
+
+func newAuthInterceptor(authService auth.AuthService, accountService ports.AccountService) *authInterceptor {
+	return &authInterceptor{authService: authService, accountService: accountService}
+}
+
+func (i *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	ctx, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if writeMethods[info.FullMethod] {
+		if err := i.accountService.AuthorizeWrite(ctx, usernameFrom(ctx)); err != nil {
+			return nil, statusFor(ctx, err)
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (i *authInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if publicMethods[info.FullMethod] {
+		return handler(srv, ss)
+	}
+
+	ctx, err := i.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate validates the "authorization" metadata, with or without a
+// "Bearer " prefix, and stores the username in the returned context.
+func (i *authInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata is required")
+	}
+
+	tokenString := strings.TrimPrefix(values[0], "Bearer ")
+	ok, username := i.authService.ValidateJwtToken(tokenString)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	logging.With(ctx, "username", username)
+	return context.WithValue(ctx, usernameKey{}, username), nil
+}
+
+func usernameFrom(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey{}).(string)
+	return username
+}
+
+// contextStream hands the handler a context the interceptors added to.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
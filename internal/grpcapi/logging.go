@@ -0,0 +1,54 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var requestIDKey = strings.ToLower(logging.RequestIDHeader)
+
+func loggingUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, requestID := startScope(ctx, info.FullMethod)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logRPC(ctx, start, err)
+	return resp, err
+}
+
+func loggingStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, requestID := startScope(ss.Context(), info.FullMethod)
+	ss.SetHeader(metadata.Pairs(requestIDKey, requestID))
+
+	start := time.Now()
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	logRPC(ctx, start, err)
+	return err
+}
+
+// startScope keeps the x-request-id metadata sent by the caller, or creates
+// one, and logs every line of the call with it.
+func startScope(ctx context.Context, fullMethod string) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := logging.RequestID(metadataCarrier(md).Get(requestIDKey))
+
+	logger := slog.Default().With("request_id", requestID, "rpc", strings.TrimPrefix(fullMethod, "/"))
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logger = logger.With("trace_id", span.TraceID().String())
+	}
+	return logging.NewContext(ctx, logger), requestID
+}
+
+func logRPC(ctx context.Context, start time.Time, err error) {
+	logging.FromContext(ctx).Info("rpc", "code", status.Code(err).String(), "duration", time.Since(start))
+}
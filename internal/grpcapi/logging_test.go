@@ -0,0 +1,38 @@
+package grpcapi
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+)
+
+func TestLoggingUnary_EchoesRequestID(t *testing.T) {
+	// fixture
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.videoService.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
+	client := videospb.NewVideoServiceClient(ts.conn)
+	ctx := metadata.AppendToOutgoingContext(authenticated(), "x-request-id", "req-1")
+	var header metadata.MD
+
+	// test
+	_, err := client.GetVideo(ctx, &videospb.GetVideoRequest{Id: 1}, grpc.Header(&header))
+
+	// assert
+	require.Error(t, err)
+	require.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+	require.Contains(t, logs.String(), "msg=rpc request_id=req-1 rpc=videos.v1.VideoService/GetVideo username=test-user code=NotFound")
+	ts.assertExpectations(t)
+}
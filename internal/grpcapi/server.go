@@ -0,0 +1,35 @@
+// Package grpcapi exposes the video, annotation and auth operations over gRPC
+// for internal services. It wraps the same ports as the REST api package.
+package grpcapi
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/juliocnsouzadev/go-videos-api/internal/grpcapi --go-grpc_out=. --go-grpc_opt=module=github.com/juliocnsouzadev/go-videos-api/internal/grpcapi videos/v1/videos.proto
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+func NewServer(
+	authService auth.AuthService,
+	userService ports.UserService,
+	accountService ports.AccountService,
+	videoService ports.VideoService,
+	annotationService ports.AnnotationService) *grpc.Server {
+	interceptor := newAuthInterceptor(authService, accountService)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tracingUnary, loggingUnary, interceptor.unary),
+		grpc.ChainStreamInterceptor(tracingStream, loggingStream, interceptor.stream),
+	)
+
+	videospb.RegisterAuthServiceServer(server, &authServer{userService: userService})
+	videospb.RegisterVideoServiceServer(server, &videoServer{videoService: videoService})
+	videospb.RegisterAnnotationServiceServer(server, &annotationServer{
+		videoService:      videoService,
+		annotationService: annotationService,
+	})
+
+	return server
+}
@@ -0,0 +1,608 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/auth"
+)
+
+const token = "test-token"
+
+type testServer struct {
+	conn              *grpc.ClientConn
+	authService       *AuthServiceMock
+	userService       *UserServiceMock
+	accountService    *AccountServiceMock
+	videoService      *VideoServiceMock
+	annotationService *AnnotationServiceMock
+}
+
+func beforeEach(t *testing.T) *testServer {
+	ts := &testServer{
+		authService:       new(AuthServiceMock),
+		userService:       new(UserServiceMock),
+		accountService:    new(AccountServiceMock),
+		videoService:      new(VideoServiceMock),
+		annotationService: new(AnnotationServiceMock),
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(ts.authService, ts.userService, ts.accountService, ts.videoService, ts.annotationService)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	ts.conn = conn
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+	return ts
+}
+
+func (ts *testServer) assertExpectations(t *testing.T) {
+	ts.authService.AssertExpectations(t)
+	ts.userService.AssertExpectations(t)
+	ts.accountService.AssertExpectations(t)
+	ts.videoService.AssertExpectations(t)
+	ts.annotationService.AssertExpectations(t)
+}
+
+func authenticated() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuthServer_Login_HappyPath_NoTokenRequired(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.userService.On("Login", "johndoe", "password").Return("jwt", nil)
+	client := videospb.NewAuthServiceClient(ts.conn)
+
+	// test
+	response, err := client.Login(context.Background(), &videospb.LoginRequest{Username: "johndoe", Password: "password"})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "jwt", response.GetToken())
+	ts.assertExpectations(t)
+}
+
+func TestAuthServer_Login_UnhappyPath_InvalidCredentials(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.userService.On("Login", "johndoe", "wrong").Return("", service.UserOrPasswordNotFoundError)
+	client := videospb.NewAuthServiceClient(ts.conn)
+
+	// test
+	_, err := client.Login(context.Background(), &videospb.LoginRequest{Username: "johndoe", Password: "wrong"})
+
+	// assert
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestAuthServer_Login_UnhappyPath_Throttled(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.userService.On("Login", "johndoe", "password").Return("", &service.LoginThrottledError{RetryAfter: 90 * time.Second})
+	client := videospb.NewAuthServiceClient(ts.conn)
+
+	// test
+	_, err := client.Login(context.Background(), &videospb.LoginRequest{Username: "johndoe", Password: "password"})
+
+	// assert
+	st := status.Convert(err)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo := st.Details()[0].(*errdetails.RetryInfo)
+	require.Equal(t, 90*time.Second, retryInfo.GetRetryDelay().AsDuration())
+	ts.assertExpectations(t)
+}
+
+func TestAuthServer_Login_UnhappyPath_MfaRequired(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.userService.On("Login", "johndoe", "password").Return("", &service.MfaRequiredError{Challenge: "challenge"})
+	client := videospb.NewAuthServiceClient(ts.conn)
+
+	// test
+	_, err := client.Login(context.Background(), &videospb.LoginRequest{Username: "johndoe", Password: "password"})
+
+	// assert
+	st := status.Convert(err)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+	require.Len(t, st.Details(), 1)
+	errorInfo := st.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, "MFA_REQUIRED", errorInfo.GetReason())
+	require.Equal(t, "challenge", errorInfo.GetMetadata()["challenge_token"])
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_GetVideo_UnhappyPath_MissingToken(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.GetVideo(context.Background(), &videospb.GetVideoRequest{Id: 1})
+
+	// assert
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_GetVideo_UnhappyPath_InvalidToken(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(false, "")
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.GetVideo(authenticated(), &videospb.GetVideoRequest{Id: 1})
+
+	// assert
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_GetVideo_HappyPath(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	video := &model.Video{ID: 1, Title: "Title", Duration: time.Minute, Version: 3}
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.videoService.On("Find", "test-user", 1).Return(video, []*model.Annotation{}, nil)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	response, err := client.GetVideo(authenticated(), &videospb.GetVideoRequest{Id: 1})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "Title", response.GetTitle())
+	require.Equal(t, time.Minute, response.GetDuration().AsDuration())
+	require.EqualValues(t, 3, response.GetVersion())
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_GetVideo_UnhappyPath_NotFound(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.videoService.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.GetVideo(authenticated(), &videospb.GetVideoRequest{Id: 1})
+
+	// assert
+	require.Equal(t, codes.NotFound, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_CreateVideo_HappyPath_UsesTokenUsername(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	ts.videoService.On("Create", "test-user", mock.MatchedBy(func(video *model.Video) bool {
+		return video.Title == "Title" && video.Duration == time.Minute
+	}), mock.MatchedBy(func(annotations []*model.Annotation) bool {
+		return len(annotations) == 1 && annotations[0].EndTime == 10*time.Second
+	})).Return(nil)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.CreateVideo(authenticated(), &videospb.CreateVideoRequest{
+		Video:       &videospb.Video{Title: "Title", Duration: durationpb.New(time.Minute)},
+		Annotations: []*videospb.Annotation{{EndTime: durationpb.New(10 * time.Second)}},
+	})
+
+	// assert
+	require.NoError(t, err)
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_CreateVideo_UnhappyPath_ValidationDetails(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	errs := validation.Errors{
+		{Field: "title", Err: validation.ErrTitleIsInvalid},
+		{Field: "link", Err: validation.ErrLinkIsInvalid},
+	}
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	ts.videoService.On("Create", "test-user", mock.Anything, mock.Anything).Return(error(errs))
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.CreateVideo(authenticated(), &videospb.CreateVideoRequest{Video: &videospb.Video{}})
+
+	// assert
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest := st.Details()[0].(*errdetails.BadRequest)
+	require.Len(t, badRequest.GetFieldViolations(), 2)
+	require.Equal(t, "title", badRequest.GetFieldViolations()[0].GetField())
+	require.Equal(t, "link", badRequest.GetFieldViolations()[1].GetField())
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_UpdateVideo_HappyPath(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	ts.videoService.On("Update", "test-user", 1, mock.MatchedBy(func(video *model.Video) bool {
+		return video.Version == 2
+	}), []*model.Annotation(nil)).Return(nil)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	response, err := client.UpdateVideo(authenticated(), &videospb.UpdateVideoRequest{Id: 1, Version: 2, Video: &videospb.Video{Title: "Title"}})
+
+	// assert
+	require.NoError(t, err)
+	require.EqualValues(t, 3, response.GetVersion())
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_UpdateVideo_UnhappyPath_VersionConflict(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	ts.videoService.On("Update", "test-user", 1, mock.Anything, mock.Anything).Return(ports.ErrVersionConflict)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.UpdateVideo(authenticated(), &videospb.UpdateVideoRequest{Id: 1, Version: 2, Video: &videospb.Video{}})
+
+	// assert
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_DeleteVideo_UnhappyPath_EmailNotVerified(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(service.ErrEmailNotVerified)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.DeleteVideo(authenticated(), &videospb.DeleteVideoRequest{Id: 1, Version: 1})
+
+	// assert
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	ts.videoService.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+	ts.assertExpectations(t)
+}
+
+func TestVideoServer_DeleteVideo_UnhappyPath_MissingVersion(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	client := videospb.NewVideoServiceClient(ts.conn)
+
+	// test
+	_, err := client.DeleteVideo(authenticated(), &videospb.DeleteVideoRequest{Id: 1})
+
+	// assert
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestAnnotationServer_ListAnnotations_HappyPath_StreamsEachAnnotation(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	annotations := []*model.Annotation{
+		{ID: 1, VideoID: 1, StartTime: time.Second, EndTime: 2 * time.Second, Note: "first"},
+		{ID: 2, VideoID: 1, StartTime: 3 * time.Second, EndTime: 4 * time.Second, Note: "second"},
+	}
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.videoService.On("Find", "test-user", 1).Return(&model.Video{ID: 1}, annotations, nil)
+	client := videospb.NewAnnotationServiceClient(ts.conn)
+
+	// test
+	stream, err := client.ListAnnotations(authenticated(), &videospb.ListAnnotationsRequest{VideoId: 1})
+	require.NoError(t, err)
+
+	received := []*videospb.Annotation{}
+	for {
+		annotation, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		received = append(received, annotation)
+	}
+
+	// assert
+	require.Len(t, received, 2)
+	require.Equal(t, "first", received[0].GetNote())
+	require.Equal(t, 3*time.Second, received[1].GetStartTime().AsDuration())
+	ts.assertExpectations(t)
+}
+
+func TestAnnotationServer_ListRevisions_UnhappyPath_MissingToken(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	client := videospb.NewAnnotationServiceClient(ts.conn)
+
+	// test
+	stream, err := client.ListRevisions(context.Background(), &videospb.ListRevisionsRequest{AnnotationId: 1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+
+	// assert
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+func TestAnnotationServer_DiffRevisions_HappyPath(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	changes := []*model.AnnotationFieldChange{{Field: "end_time", From: 2 * time.Second, To: 90 * time.Second}}
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.annotationService.On("Diff", "test-user", 1, 1, 2).Return(changes, nil)
+	client := videospb.NewAnnotationServiceClient(ts.conn)
+
+	// test
+	response, err := client.DiffRevisions(authenticated(), &videospb.DiffRevisionsRequest{AnnotationId: 1, From: 1, To: 2})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, response.GetChanges(), 1)
+	require.Equal(t, "2s", response.GetChanges()[0].GetFrom())
+	require.Equal(t, "1m30s", response.GetChanges()[0].GetTo())
+	ts.assertExpectations(t)
+}
+
+func TestAnnotationServer_RevertAnnotation_UnhappyPath_RevisionNotFound(t *testing.T) {
+	// fixture
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.accountService.On("AuthorizeWrite", "test-user").Return(nil)
+	ts.annotationService.On("Revert", "test-user", 1, 9).Return(nil, service.ErrRevisionNotFound)
+	client := videospb.NewAnnotationServiceClient(ts.conn)
+
+	// test
+	_, err := client.RevertAnnotation(authenticated(), &videospb.RevertAnnotationRequest{AnnotationId: 1, Revision: 9})
+
+	// assert
+	require.Equal(t, codes.NotFound, status.Code(err))
+	ts.assertExpectations(t)
+}
+
+type AuthServiceMock struct {
+	mock.Mock
+}
+
+func (s *AuthServiceMock) GenerateJwtToken(username string) (string, error) {
+	args := s.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateJwtToken(tokenString string) (bool, string) {
+	args := s.Called(tokenString)
+	return args.Bool(0), args.String(1)
+}
+
+func (s *AuthServiceMock) GenerateMfaChallenge(username string) (string, error) {
+	args := s.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateMfaChallenge(tokenString string) (bool, string) {
+	args := s.Called(tokenString)
+	return args.Bool(0), args.String(1)
+}
+
+func (s *AuthServiceMock) GenerateActionToken(username string, purpose string, id string, ttl time.Duration) (string, error) {
+	args := s.Called(username, purpose, id, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (s *AuthServiceMock) ValidateActionToken(tokenString string, purpose string) (bool, string, string) {
+	args := s.Called(tokenString, purpose)
+	return args.Bool(0), args.String(1), args.String(2)
+}
+
+func (s *AuthServiceMock) JSONWebKeys() []auth.JSONWebKey {
+	args := s.Called()
+	return args.Get(0).([]auth.JSONWebKey)
+}
+
+type UserServiceMock struct {
+	mock.Mock
+}
+
+func (s *UserServiceMock) Login(ctx context.Context, username string, password string) (string, error) {
+	args := s.Called(username, password)
+	return args.String(0), args.Error(1)
+}
+
+func (s *UserServiceMock) Signup(ctx context.Context, email string, password string) (string, error) {
+	args := s.Called(email, password)
+	return args.String(0), args.Error(1)
+}
+
+func (s *UserServiceMock) FindMany(ctx context.Context, ids []int) (map[int]*model.User, error) {
+	args := s.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Create(ctx context.Context, email string, password string, role string) (*model.User, error) {
+	args := s.Called(email, password, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Find(ctx context.Context, username string) (*model.User, error) {
+	args := s.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (s *UserServiceMock) Disable(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) Unlock(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) SetRole(ctx context.Context, username string, role string) error {
+	args := s.Called(username, role)
+	return args.Error(0)
+}
+
+func (s *UserServiceMock) ResetPassword(ctx context.Context, username string, password string) error {
+	args := s.Called(username, password)
+	return args.Error(0)
+}
+
+type AccountServiceMock struct {
+	mock.Mock
+}
+
+func (s *AccountServiceMock) SendVerification(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) VerifyEmail(ctx context.Context, token string) error {
+	args := s.Called(token)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) RequestPasswordReset(ctx context.Context, email string) error {
+	args := s.Called(email)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) ResetPassword(ctx context.Context, token string, password string) error {
+	args := s.Called(token, password)
+	return args.Error(0)
+}
+
+func (s *AccountServiceMock) AuthorizeWrite(ctx context.Context, username string) error {
+	args := s.Called(username)
+	return args.Error(0)
+}
+
+type VideoServiceMock struct {
+	mock.Mock
+}
+
+func (s *VideoServiceMock) Create(ctx context.Context, username string, video *model.Video, annotations []*model.Annotation) error {
+	args := s.Called(username, video, annotations)
+	return args.Error(0)
+}
+
+func (s *VideoServiceMock) Find(ctx context.Context, username string, id int) (*model.Video, []*model.Annotation, error) {
+	args := s.Called(username, id)
+	if args.Get(0) == nil || args.Get(1) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Video), args.Get(1).([]*model.Annotation), args.Error(2)
+}
+
+func (s *VideoServiceMock) FindMany(ctx context.Context, username string, ids []int) (map[int]*model.Video, error) {
+	args := s.Called(username, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) FindAnnotations(ctx context.Context, username string, videoIds []int) (map[int][]*model.Annotation, error) {
+	args := s.Called(username, videoIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]*model.Annotation), args.Error(1)
+}
+
+func (s *VideoServiceMock) List(ctx context.Context, username string, workspaceId int) ([]*model.Video, error) {
+	args := s.Called(username, workspaceId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Video), args.Error(1)
+}
+
+func (s *VideoServiceMock) Update(ctx context.Context, username string, id int, video *model.Video, annotations []*model.Annotation) error {
+	args := s.Called(username, id, video, annotations)
+	return args.Error(0)
+}
+
+func (s *VideoServiceMock) Remove(ctx context.Context, username string, id int, version int) error {
+	args := s.Called(username, id, version)
+	return args.Error(0)
+}
+
+type AnnotationServiceMock struct {
+	mock.Mock
+}
+
+func (s *AnnotationServiceMock) Revisions(ctx context.Context, username string, annotationId int) ([]*model.AnnotationRevision, error) {
+	args := s.Called(username, annotationId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AnnotationRevision), args.Error(1)
+}
+
+func (s *AnnotationServiceMock) Diff(ctx context.Context, username string, annotationId, from, to int) ([]*model.AnnotationFieldChange, error) {
+	args := s.Called(username, annotationId, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AnnotationFieldChange), args.Error(1)
+}
+
+func (s *AnnotationServiceMock) Revert(ctx context.Context, username string, annotationId, revision int) (*model.AnnotationRevision, error) {
+	args := s.Called(username, annotationId, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AnnotationRevision), args.Error(1)
+}
@@ -0,0 +1,62 @@
+package grpcapi
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/adapters/service"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+)
+
+func TestTracingUnary_ContinuesInboundTrace(t *testing.T) {
+	// fixture
+	spans := recordSpans()
+	ts := beforeEach(t)
+	ts.authService.On("ValidateJwtToken", token).Return(true, "test-user")
+	ts.videoService.On("Find", "test-user", 1).Return(nil, nil, service.ErrVideoNotFound)
+	client := videospb.NewVideoServiceClient(ts.conn)
+	ctx := metadata.AppendToOutgoingContext(authenticated(), "traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// test
+	_, err := client.GetVideo(ctx, &videospb.GetVideoRequest{Id: 1})
+
+	// assert
+	require.Error(t, err)
+	ended := spans()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	require.Equal(t, "videos.v1.VideoService/GetVideo", span.Name())
+	require.Equal(t, trace.SpanKindServer, span.SpanKind())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	require.Contains(t, span.Attributes(), attribute.Int("rpc.grpc.status_code", 5))
+	require.Equal(t, otelcodes.Error, span.Status().Code)
+	ts.assertExpectations(t)
+}
+
+var (
+	spanRecorder = tracetest.NewSpanRecorder()
+	installOnce  sync.Once
+)
+
+// recordSpans returns the spans that ended after it was called.
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	installOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	start := len(spanRecorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return spanRecorder.Ended()[start:]
+	}
+}
@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/grpcapi/videospb"
+)
+
+type videoServer struct {
+	videospb.UnimplementedVideoServiceServer
+	videoService ports.VideoService
+}
+
+func (s *videoServer) CreateVideo(ctx context.Context, req *videospb.CreateVideoRequest) (*videospb.CreateVideoResponse, error) {
+	video := toVideoModel(req.GetVideo())
+	annotations := toAnnotationModels(req.GetAnnotations())
+
+	if err := s.videoService.Create(ctx, usernameFrom(ctx), video, annotations); err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return &videospb.CreateVideoResponse{}, nil
+}
+
+func (s *videoServer) GetVideo(ctx context.Context, req *videospb.GetVideoRequest) (*videospb.Video, error) {
+	video, _, err := s.videoService.Find(ctx, usernameFrom(ctx), int(req.GetId()))
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return toVideoMessage(video), nil
+}
+
+func (s *videoServer) UpdateVideo(ctx context.Context, req *videospb.UpdateVideoRequest) (*videospb.UpdateVideoResponse, error) {
+	if req.GetVersion() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	video := toVideoModel(req.GetVideo())
+	if video != nil {
+		video.Version = int(req.GetVersion())
+	}
+
+	if err := s.videoService.Update(ctx, usernameFrom(ctx), int(req.GetId()), video, toAnnotationModels(req.GetAnnotations())); err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return &videospb.UpdateVideoResponse{Version: req.GetVersion() + 1}, nil
+}
+
+func (s *videoServer) DeleteVideo(ctx context.Context, req *videospb.DeleteVideoRequest) (*videospb.DeleteVideoResponse, error) {
+	if req.GetVersion() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	if err := s.videoService.Remove(ctx, usernameFrom(ctx), int(req.GetId()), int(req.GetVersion())); err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return &videospb.DeleteVideoResponse{}, nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ApiKeyPrefix starts every API key, so they are told apart from tokens.
+const ApiKeyPrefix = "vak_"
+
+const (
+	apiKeyIdBytes     = 8
+	apiKeySecretBytes = 32
+)
+
+// GenerateApiKey returns a new key, "vak_<id>_<secret>", along with its id
+// and the hash of its secret, which is all that needs storing.
+func GenerateApiKey() (string, string, string, error) {
+	random := make([]byte, apiKeyIdBytes+apiKeySecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", "", err
+	}
+	id := hex.EncodeToString(random[:apiKeyIdBytes])
+	secret := hex.EncodeToString(random[apiKeyIdBytes:])
+	return ApiKeyPrefix + id + "_" + secret, id, hashApiKeySecret(secret), nil
+}
+
+// ParseApiKey returns the id of a key and the hash of its secret, it does not
+// tell whether the key exists.
+func ParseApiKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, ApiKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 2*apiKeyIdBytes || len(secret) != 2*apiKeySecretBytes {
+		return "", "", false
+	}
+	return id, hashApiKeySecret(secret), true
+}
+
+func hashApiKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
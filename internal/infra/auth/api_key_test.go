@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateApiKey_ParsesBack(t *testing.T) {
+	// test
+	key, id, hash, err := GenerateApiKey()
+	parsedId, parsedHash, ok := ParseApiKey(key)
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, ApiKeyPrefix))
+	require.NotContains(t, key, hash)
+	require.True(t, ok)
+	require.Equal(t, id, parsedId)
+	require.Equal(t, hash, parsedHash)
+}
+
+func TestParseApiKey_UnhappyPath(t *testing.T) {
+	key, _, _, err := GenerateApiKey()
+	require.NoError(t, err)
+
+	for name, value := range map[string]string{
+		"jwt":         "eyJhbGciOiJIUzI1NiJ9.e30.signature",
+		"no prefix":   strings.TrimPrefix(key, ApiKeyPrefix),
+		"no secret":   key[:len(ApiKeyPrefix)+16],
+		"short":       key[:len(key)-1],
+		"empty":       "",
+		"prefix only": ApiKeyPrefix,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// test
+			_, _, ok := ParseApiKey(value)
+
+			// assert
+			require.False(t, ok)
+		})
+	}
+}
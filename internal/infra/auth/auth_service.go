@@ -1,61 +1,217 @@
 package auth
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+const (
+	// mfaChallengePurpose marks the tokens that only let the second step of
+	// a login through, they are no session tokens.
+	mfaChallengePurpose = "mfa_challenge"
+	mfaChallengeTTL     = 5 * time.Minute
+
+	defaultIssuer = "videos-api"
+
+	// SessionTTL is how long a session token works, tokens cannot be
+	// revoked before.
+	SessionTTL = 24 * time.Hour
+)
+
+var (
+	ErrInvalidPurpose = errors.New("action tokens need a purpose of their own")
+	ErrUnknownKey     = errors.New("token is not signed with a known key")
 )
 
 type Claims struct {
 	Username string `json:"username"`
-	jwt.StandardClaims
+	Purpose  string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
 }
 
 type AuthService interface {
 	GenerateJwtToken(username string) (string, error)
 	ValidateJwtToken(tokenString string) (bool, string)
+	// GenerateMfaChallenge issues a short-lived token telling that username
+	// passed the first step of a login.
+	GenerateMfaChallenge(username string) (string, error)
+	ValidateMfaChallenge(tokenString string) (bool, string)
+	// GenerateActionToken issues a token for a single action such as a
+	// password reset, id tells the issued tokens apart.
+	GenerateActionToken(username string, purpose string, id string, ttl time.Duration) (string, error)
+	// ValidateActionToken returns the username and id of a token issued for
+	// purpose.
+	ValidateActionToken(tokenString string, purpose string) (bool, string, string)
+	// JSONWebKeys returns the public keys tokens are verified with, none for
+	// a shared secret.
+	JSONWebKeys() []JSONWebKey
 }
 
 type authService struct {
-	jwtKey []byte
+	signing *key
+	// keys verify the tokens by their kid, the shared secret has none
+	keys     map[string]*key
+	issuer   string
+	audience string
+	parser   *jwt.Parser
+}
+
+// NewAuthService signs HS256 tokens with a shared secret.
+func NewAuthService(secret string) *authService {
+	return newAuthService(secretKey(secret), nil, defaultIssuer, defaultIssuer)
 }
 
-func NewAuthService(key string) *authService {
+// New signs with JWT_SIGNING_KEY, or JWT_KEY when it is not set, and also
+// accepts the tokens of JWT_KEY and JWT_VERIFICATION_KEYS so keys can be
+// rotated without logging everyone out.
+func New(settings *config.Settings) (*authService, error) {
+	verifying := []*key{}
+	if settings.JwtVerificationKeys != "" {
+		keys, err := parsePublicKeys(settings.JwtVerificationKeys)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEYS: %w", err)
+		}
+		verifying = keys
+	}
+
+	if settings.JwtSigningKey == "" {
+		return newAuthService(secretKey(settings.JwtKey), verifying, settings.JwtIssuer, settings.JwtAudience), nil
+	}
+	signing, err := parsePrivateKey(settings.JwtSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+	}
+	if settings.JwtKey != "" {
+		verifying = append(verifying, secretKey(settings.JwtKey))
+	}
+	return newAuthService(signing, verifying, settings.JwtIssuer, settings.JwtAudience), nil
+}
+
+func newAuthService(signing *key, verifying []*key, issuer string, audience string) *authService {
+	keys := map[string]*key{signing.id: signing}
+	methods := []string{signing.method.Alg()}
+	for _, k := range verifying {
+		if _, ok := keys[k.id]; !ok {
+			keys[k.id] = k
+			methods = append(methods, k.method.Alg())
+		}
+	}
+
 	return &authService{
-		jwtKey: []byte(key),
+		signing:  signing,
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(methods),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+		),
 	}
 }
 
 func (a *authService) GenerateJwtToken(username string) (string, error) {
-	// Set the JWT claims
-	expirationTime := time.Now().Add(24 * time.Hour)
+	return a.sign(username, "", "", SessionTTL)
+}
+
+func (a *authService) ValidateJwtToken(tokenString string) (bool, string) {
+	claims, ok := a.validate(tokenString, "")
+	return ok, claims.Username
+}
+
+func (a *authService) GenerateMfaChallenge(username string) (string, error) {
+	return a.sign(username, mfaChallengePurpose, "", mfaChallengeTTL)
+}
+
+func (a *authService) ValidateMfaChallenge(tokenString string) (bool, string) {
+	claims, ok := a.validate(tokenString, mfaChallengePurpose)
+	return ok, claims.Username
+}
+
+func (a *authService) GenerateActionToken(username string, purpose string, id string, ttl time.Duration) (string, error) {
+	if purpose == "" || purpose == mfaChallengePurpose {
+		return "", ErrInvalidPurpose
+	}
+	return a.sign(username, purpose, id, ttl)
+}
+
+func (a *authService) ValidateActionToken(tokenString string, purpose string) (bool, string, string) {
+	if purpose == "" || purpose == mfaChallengePurpose {
+		return false, "", ""
+	}
+	claims, ok := a.validate(tokenString, purpose)
+	return ok, claims.Username, claims.ID
+}
+
+func (a *authService) JSONWebKeys() []JSONWebKey {
+	jwks := []JSONWebKey{}
+	for _, k := range a.keys {
+		if k.id != "" {
+			jwks = append(jwks, k.jwk())
+		}
+	}
+	// the signing key first, then a stable order
+	slices.SortFunc(jwks, func(x, y JSONWebKey) int {
+		switch {
+		case x.ID == a.signing.id:
+			return -1
+		case y.ID == a.signing.id:
+			return 1
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+	return jwks
+}
+
+func (a *authService) sign(username string, purpose string, id string, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := &Claims{
 		Username: username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
+		Purpose:  purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    a.issuer,
+			Subject:   username,
+			Audience:  jwt.ClaimStrings{a.audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	// Generate the JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(a.jwtKey)
-	if err != nil {
-		return "", err
+	token := jwt.NewWithClaims(a.signing.method, claims)
+	if a.signing.id != "" {
+		token.Header["kid"] = a.signing.id
 	}
-
-	return tokenString, nil
+	return token.SignedString(a.signing.private)
 }
 
-func (a *authService) ValidateJwtToken(tokenString string) (bool, string) {
+// validate only accepts tokens issued for purpose, so a challenge does not
+// pass for a session and the other way round.
+func (a *authService) validate(tokenString string, purpose string) (*Claims, bool) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return a.jwtKey, nil
+	token, err := a.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		id, _ := token.Header["kid"].(string)
+		k, ok := a.keys[id]
+		// the key decides the algorithm, never the token
+		if !ok || token.Method.Alg() != k.method.Alg() {
+			return nil, ErrUnknownKey
+		}
+		return k.public, nil
 	})
 
-	if err != nil {
-		return false, ""
+	if err != nil || claims.Purpose != purpose || !token.Valid {
+		return &Claims{}, false
 	}
 
-	return token.Valid, claims.Username
+	return claims, true
 }
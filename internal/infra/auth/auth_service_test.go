@@ -1,11 +1,18 @@
 package auth
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
 )
 
 func TestAuthService_GenerateJwtToken_HappyPath(t *testing.T) {
@@ -71,18 +78,22 @@ func generateExpiredTestToken(t *testing.T, service AuthService) string {
 	// Set the expiration time to 1 second ago
 	expirationTime := time.Now().Add(-1 * time.Second)
 
-	claims := &Claims{
-		Username: "johndoe",
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return signTestToken(t, service, jwt.RegisteredClaims{
+		Issuer:    "videos-api",
+		Audience:  jwt.ClaimStrings{"videos-api"},
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+	})
+}
 
+// signTestToken signs claims for johndoe with the signing key of service.
+func signTestToken(t *testing.T, service AuthService, claims jwt.RegisteredClaims) string {
 	authServiceImpl := service.(*authService)
 
-	tokenString, err := token.SignedString(authServiceImpl.jwtKey)
+	token := jwt.NewWithClaims(authServiceImpl.signing.method, &Claims{Username: "johndoe", RegisteredClaims: claims})
+	if authServiceImpl.signing.id != "" {
+		token.Header["kid"] = authServiceImpl.signing.id
+	}
+	tokenString, err := token.SignedString(authServiceImpl.signing.private)
 	require.NoError(t, err)
 
 	return tokenString
@@ -98,3 +109,247 @@ func TestAuthService_ValidateJwtToken_UnhappyPath_InvalidKey(t *testing.T) {
 	valid, _ := authService2.ValidateJwtToken(token)
 	require.False(t, valid)
 }
+
+func TestAuthService_MfaChallenge_HappyPath(t *testing.T) {
+	// fixture
+	authService := NewAuthService("secret-key")
+
+	// test
+	challenge, err := authService.GenerateMfaChallenge("johndoe")
+	valid, username := authService.ValidateMfaChallenge(challenge)
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, "johndoe", username)
+}
+
+func TestAuthService_MfaChallenge_IsNoSessionToken(t *testing.T) {
+	// fixture
+	authService := NewAuthService("secret-key")
+	challenge, err := authService.GenerateMfaChallenge("johndoe")
+	require.NoError(t, err)
+	session := generateTestToken(t, authService)
+
+	// test
+	challengeAsSession, _ := authService.ValidateJwtToken(challenge)
+	sessionAsChallenge, _ := authService.ValidateMfaChallenge(session)
+
+	// assert
+	require.False(t, challengeAsSession)
+	require.False(t, sessionAsChallenge)
+}
+
+func TestAuthService_ActionToken_HappyPath(t *testing.T) {
+	// fixture
+	authService := NewAuthService("secret-key")
+
+	// test
+	token, err := authService.GenerateActionToken("johndoe", "password_reset", "token-id", time.Hour)
+	valid, username, id := authService.ValidateActionToken(token, "password_reset")
+
+	// assert
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, "johndoe", username)
+	require.Equal(t, "token-id", id)
+}
+
+func TestAuthService_ActionToken_OnlyPassesForItsPurpose(t *testing.T) {
+	// fixture
+	authService := NewAuthService("secret-key")
+	token, err := authService.GenerateActionToken("johndoe", "password_reset", "token-id", time.Hour)
+	require.NoError(t, err)
+	expired, err := authService.GenerateActionToken("johndoe", "password_reset", "token-id", -time.Minute)
+	require.NoError(t, err)
+
+	// test
+	asSession, _ := authService.ValidateJwtToken(token)
+	asVerification, _, _ := authService.ValidateActionToken(token, "email_verification")
+	sessionAsAction, _, _ := authService.ValidateActionToken(generateTestToken(t, authService), "")
+	expiredValid, _, _ := authService.ValidateActionToken(expired, "password_reset")
+	_, challengeErr := authService.GenerateActionToken("johndoe", "mfa_challenge", "token-id", time.Hour)
+
+	// assert
+	require.False(t, asSession)
+	require.False(t, asVerification)
+	require.False(t, sessionAsAction)
+	require.False(t, expiredValid)
+	require.ErrorIs(t, challengeErr, ErrInvalidPurpose)
+}
+
+func rsaKeyPEM(t *testing.T) (string, string) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return keyPEM(t, private, &private.PublicKey)
+}
+
+func ed25519KeyPEM(t *testing.T) (string, string) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return keyPEM(t, private, public)
+}
+
+// keyPEM returns the PEM of the private and of the public key.
+func keyPEM(t *testing.T, private any, public any) (string, string) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+}
+
+func newKeyedAuthService(t *testing.T, signingKey string, verificationKeys string) *authService {
+	authService, err := New(&config.Settings{
+		JwtSigningKey:       signingKey,
+		JwtVerificationKeys: verificationKeys,
+		JwtIssuer:           "videos-api",
+		JwtAudience:         "videos-api",
+	})
+	require.NoError(t, err)
+	return authService
+}
+
+func TestAuthService_AsymmetricKeys_HappyPath(t *testing.T) {
+	for name, keyPair := range map[string]func(t *testing.T) (string, string){"RS256": rsaKeyPEM, "EdDSA": ed25519KeyPEM} {
+		t.Run(name, func(t *testing.T) {
+			// fixture
+			signingKey, _ := keyPair(t)
+			authService := newKeyedAuthService(t, signingKey, "")
+
+			// test
+			token := generateTestToken(t, authService)
+			valid, username := authService.ValidateJwtToken(token)
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+
+			// assert
+			require.True(t, valid)
+			require.Equal(t, "johndoe", username)
+			require.NoError(t, err)
+			require.Equal(t, name, parsed.Header["alg"])
+			require.Equal(t, authService.signing.id, parsed.Header["kid"])
+			claims := parsed.Claims.(*Claims)
+			require.Equal(t, "videos-api", claims.Issuer)
+			require.Equal(t, jwt.ClaimStrings{"videos-api"}, claims.Audience)
+			require.Equal(t, "johndoe", claims.Subject)
+			require.NotNil(t, claims.NotBefore)
+		})
+	}
+}
+
+func TestAuthService_AsymmetricKeys_Rotation(t *testing.T) {
+	// fixture
+	oldKey, oldPublic := rsaKeyPEM(t)
+	newKey, _ := ed25519KeyPEM(t)
+	_, otherPublic := rsaKeyPEM(t)
+	before := newKeyedAuthService(t, oldKey, "")
+	oldToken := generateTestToken(t, before)
+	after := newKeyedAuthService(t, newKey, otherPublic+oldPublic)
+	afterRotation := newKeyedAuthService(t, newKey, "")
+
+	// test
+	oldValid, _ := after.ValidateJwtToken(oldToken)
+	newValid, _ := after.ValidateJwtToken(generateTestToken(t, after))
+	oldValidAfterRotation, _ := afterRotation.ValidateJwtToken(oldToken)
+	jwks := after.JSONWebKeys()
+
+	// assert
+	require.True(t, oldValid)
+	require.True(t, newValid)
+	require.False(t, oldValidAfterRotation)
+	require.Len(t, jwks, 3)
+	require.Equal(t, after.signing.id, jwks[0].ID)
+	require.Equal(t, "OKP", jwks[0].KeyType)
+	require.Equal(t, "EdDSA", jwks[0].Algorithm)
+	require.Contains(t, []string{jwks[1].ID, jwks[2].ID}, before.signing.id)
+	for _, jwk := range jwks[1:] {
+		require.Equal(t, "RSA", jwk.KeyType)
+		require.Equal(t, "AQAB", jwk.Exponent)
+		require.Equal(t, "sig", jwk.Use)
+	}
+}
+
+func TestAuthService_AsymmetricKeys_KeepsAcceptingTheSecret(t *testing.T) {
+	// fixture
+	signingKey, _ := rsaKeyPEM(t)
+	secretToken := generateTestToken(t, NewAuthService("secret-key"))
+	authService, err := New(&config.Settings{JwtKey: "secret-key", JwtSigningKey: signingKey, JwtIssuer: "videos-api", JwtAudience: "videos-api"})
+	require.NoError(t, err)
+
+	// test
+	valid, _ := authService.ValidateJwtToken(secretToken)
+
+	// assert
+	require.True(t, valid)
+	require.Len(t, authService.JSONWebKeys(), 1, "the secret is never published")
+	require.Empty(t, NewAuthService("secret-key").JSONWebKeys())
+}
+
+func TestAuthService_ValidateJwtToken_UnhappyPath_Claims(t *testing.T) {
+	// fixture
+	signingKey, _ := ed25519KeyPEM(t)
+	authService := newKeyedAuthService(t, signingKey, "")
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	tokens := map[string]string{
+		"other issuer":   signTestToken(t, authService, jwt.RegisteredClaims{Issuer: "other", Audience: jwt.ClaimStrings{"videos-api"}, ExpiresAt: expiresAt}),
+		"other audience": signTestToken(t, authService, jwt.RegisteredClaims{Issuer: "videos-api", Audience: jwt.ClaimStrings{"other"}, ExpiresAt: expiresAt}),
+		"not yet valid": signTestToken(t, authService, jwt.RegisteredClaims{Issuer: "videos-api", Audience: jwt.ClaimStrings{"videos-api"}, ExpiresAt: expiresAt,
+			NotBefore: jwt.NewNumericDate(time.Now().Add(time.Minute))}),
+		"no expiry": signTestToken(t, authService, jwt.RegisteredClaims{Issuer: "videos-api", Audience: jwt.ClaimStrings{"videos-api"}}),
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			// test
+			valid, _ := authService.ValidateJwtToken(token)
+
+			// assert
+			require.False(t, valid)
+		})
+	}
+}
+
+func TestAuthService_ValidateJwtToken_UnhappyPath_PublicKeyAsSecret(t *testing.T) {
+	// fixture
+	signingKey, publicKey := rsaKeyPEM(t)
+	authService := newKeyedAuthService(t, signingKey, "")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Username: "johndoe", RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    "videos-api",
+		Audience:  jwt.ClaimStrings{"videos-api"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}})
+	token.Header["kid"] = authService.signing.id
+	forged, err := token.SignedString([]byte(publicKey))
+	require.NoError(t, err)
+
+	// test
+	valid, _ := authService.ValidateJwtToken(forged)
+
+	// assert
+	require.False(t, valid)
+}
+
+func TestNew_UnhappyPath_InvalidKeys(t *testing.T) {
+	// fixture
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	weakPEM, _ := keyPEM(t, weakKey, &weakKey.PublicKey)
+	_, publicKey := ed25519KeyPEM(t)
+
+	for name, settings := range map[string]*config.Settings{
+		"not PEM":           {JwtSigningKey: "secret"},
+		"weak RSA key":      {JwtSigningKey: weakPEM},
+		"public as signing": {JwtSigningKey: publicKey},
+		"no public keys":    {JwtKey: "secret", JwtVerificationKeys: "public"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// test
+			_, err := New(settings)
+
+			// assert
+			require.Error(t, err)
+		})
+	}
+}
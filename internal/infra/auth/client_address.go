@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+type clientAddressKey struct{}
+
+// WithClientAddress tells the services which address a login comes from,
+// failed logins are counted per address too.
+func WithClientAddress(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, clientAddressKey{}, address)
+}
+
+// ClientAddress returns the address of the request, empty when the caller
+// is not a network client such as the command line.
+func ClientAddress(ctx context.Context) string {
+	address, _ := ctx.Value(clientAddressKey{}).(string)
+	return address
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSABits = 2048
+
+var ErrNoPEM = errors.New("no PEM block found")
+
+// JSONWebKey is the public part of a key as published in a JWK set (RFC 7517).
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// key verifies tokens, and signs them when it holds the private key. The id
+// of asymmetric keys is the thumbprint of the public key (RFC 7638) and goes
+// in the kid header, shared secrets have none.
+type key struct {
+	id      string
+	method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+func secretKey(secret string) *key {
+	return &key{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+}
+
+// parsePrivateKey reads a PKCS #8 or PKCS #1 private key.
+func parsePrivateKey(data string) (*key, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, ErrNoPEM
+	}
+
+	var private any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var public any
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		public = &private.PublicKey
+	case ed25519.PrivateKey:
+		public = private.Public()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", private)
+	}
+
+	k, err := publicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	k.private = private
+	return k, nil
+}
+
+// parsePublicKeys reads every PKIX or PKCS #1 public key of data.
+func parsePublicKeys(data string) ([]*key, error) {
+	keys := []*key{}
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		var public any
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			public, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := publicKey(public)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoPEM
+	}
+	return keys, nil
+}
+
+func publicKey(public any) (*key, error) {
+	k := &key{public: public}
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys need at least %d bits", minRSABits)
+		}
+		k.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+
+	jwk := k.jwk()
+	// the thumbprint hashes the required members in lexicographic order
+	var members any
+	if jwk.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.Exponent, jwk.KeyType, jwk.Modulus}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+	thumbprint, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(thumbprint)
+	k.id = base64.RawURLEncoding.EncodeToString(sum[:])
+	return k, nil
+}
+
+func (k *key) jwk() JSONWebKey {
+	jwk := JSONWebKey{ID: k.id, Use: "sig", Algorithm: k.method.Alg()}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
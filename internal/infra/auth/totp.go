@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpPeriod = 30
+	// totpSkew accepts the codes of the previous and the next step too, for
+	// clocks that drifted apart.
+	totpSkew = 1
+)
+
+var totpOptions = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// NewTotpKey generates a TOTP secret for account, issuer names the service
+// in authenticator apps.
+func NewTotpKey(issuer string, account string) (*otp.Key, error) {
+	return totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+}
+
+// MatchTotp returns the time step code was generated for when it is a
+// current code of secret.
+func MatchTotp(secret string, code string, now time.Time) (int64, bool) {
+	step := now.Unix() / totpPeriod
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix((step+offset)*totpPeriod, 0), totpOptions)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + offset, true
+		}
+	}
+	return 0, false
+}
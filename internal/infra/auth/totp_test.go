@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestMatchTotp_RfcVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		// test
+		step, ok := MatchTotp(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+
+		// assert
+		require.True(t, ok, tt.code)
+		require.Equal(t, tt.unix/30, step)
+	}
+}
+
+func TestMatchTotp_Skew(t *testing.T) {
+	// fixture
+	generatedAt := time.Unix(1234567890, 0)
+
+	// test
+	_, previousStep := MatchTotp(rfcSecret, "005924", generatedAt.Add(30*time.Second))
+	_, nextStep := MatchTotp(rfcSecret, "005924", generatedAt.Add(-30*time.Second))
+	_, tooLate := MatchTotp(rfcSecret, "005924", generatedAt.Add(90*time.Second))
+	_, wrongCode := MatchTotp(rfcSecret, "005925", generatedAt)
+
+	// assert
+	require.True(t, previousStep)
+	require.True(t, nextStep)
+	require.False(t, tooLate)
+	require.False(t, wrongCode)
+}
+
+func TestNewTotpKey(t *testing.T) {
+	// test
+	key, err := NewTotpKey("videos-api", "johndoe")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, "videos-api", key.Issuer())
+	require.Equal(t, "johndoe", key.AccountName())
+	require.Contains(t, key.URL(), "otpauth://totp/videos-api:johndoe?")
+}
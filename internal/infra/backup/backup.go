@@ -0,0 +1,278 @@
+// Package backup copies the SQLite database while it is in use, keeps the
+// latest copies and restores them.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/metrics"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var tracer = tracing.Tracer("internal/infra/backup")
+
+const (
+	timeLayout     = "20060102T150405Z"
+	extension      = ".db"
+	gzExtension    = ".db.gz"
+	tempInfix      = "_partial_"
+	defaultDir     = "backups"
+	dirMode        = 0o750
+	fileMode       = 0o600
+	copyBufferSize = 1 << 20
+)
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Manager writes the backups of a database to a directory, named after the
+// database and the time they were taken, e.g. videos_20240102T150405Z.db.gz.
+// Only the latest Retention backups are kept.
+type Manager struct {
+	db      *sql.DB
+	dir     string
+	base    string
+	now     func() time.Time
+	mutex   sync.Mutex
+	running atomic.Bool
+
+	Interval  time.Duration
+	Retention int
+	Compress  bool
+}
+
+// NewManager stores the backups in the backup directory of the settings, or
+// in a backups directory next to the database.
+func NewManager(database *sql.DB, settings *config.Settings) *Manager {
+	dir := settings.BackupDir
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(settings.DatabaseURL), defaultDir)
+	}
+	return &Manager{
+		db:        database,
+		dir:       dir,
+		base:      strings.TrimSuffix(filepath.Base(settings.DatabaseURL), filepath.Ext(settings.DatabaseURL)),
+		now:       time.Now,
+		Interval:  settings.BackupInterval,
+		Retention: settings.BackupRetention,
+		Compress:  settings.BackupCompress,
+	}
+}
+
+// Running reports whether Run is looping, it is always false when scheduled
+// backups are disabled.
+func (m *Manager) Running() bool {
+	return m.running.Load()
+}
+
+// Run takes a backup every Interval until the context is cancelled, it
+// returns right away when Interval is zero.
+func (m *Manager) Run(ctx context.Context) {
+	if m.Interval <= 0 {
+		return
+	}
+	m.running.Store(true)
+	defer m.running.Store(false)
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := m.Create(ctx); err != nil {
+			slog.Error("scheduled backup failed", "error", err)
+		}
+	}
+}
+
+// Create takes a backup and removes the ones beyond the retention. The copy
+// is written under a temporary name first, so a backup listed is complete.
+func (m *Manager) Create(ctx context.Context) (backup *model.Backup, err error) {
+	ctx, span := tracer.Start(ctx, "Manager.Create")
+	defer func() { tracing.End(span, err) }()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	defer func() {
+		if err != nil {
+			metrics.Backups.WithLabelValues(metrics.BackupFailed).Inc()
+		}
+	}()
+
+	if err := os.MkdirAll(m.dir, dirMode); err != nil {
+		return nil, err
+	}
+
+	createdAt := m.now().UTC().Truncate(time.Second)
+	name := m.base + "_" + createdAt.Format(timeLayout)
+	target := filepath.Join(m.dir, name+extension)
+	if m.Compress {
+		target = filepath.Join(m.dir, name+gzExtension)
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil, errors.New("backup " + filepath.Base(target) + " already exists")
+	}
+
+	partial := filepath.Join(m.dir, m.base+tempInfix+createdAt.Format(timeLayout)+extension)
+	defer os.Remove(partial)
+	if err := db.Backup(ctx, m.db, partial); err != nil {
+		return nil, fmt.Errorf("copying the database: %w", err)
+	}
+
+	if m.Compress {
+		compressed := partial + ".gz"
+		defer os.Remove(compressed)
+		if err := compress(partial, compressed); err != nil {
+			return nil, fmt.Errorf("compressing the backup: %w", err)
+		}
+		partial = compressed
+	}
+	if err := os.Rename(partial, target); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	backup = &model.Backup{
+		Name:       filepath.Base(target),
+		Path:       target,
+		Size:       info.Size(),
+		Compressed: m.Compress,
+		CreatedAt:  createdAt,
+	}
+	metrics.Backups.WithLabelValues(metrics.BackupSucceeded).Inc()
+	metrics.LastBackup.Set(float64(createdAt.Unix()))
+	logging.FromContext(ctx).Info("database backed up", "backup", backup.Name, "size", backup.Size)
+
+	if err := m.prune(ctx); err != nil {
+		logging.FromContext(ctx).Warn("removing old backups failed", "error", err)
+	}
+	return backup, nil
+}
+
+func (m *Manager) List(ctx context.Context) ([]*model.Backup, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*model.Backup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []*model.Backup{}
+	for _, entry := range entries {
+		backup, ok := m.parse(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backup.Size = info.Size()
+		backups = append(backups, backup)
+	}
+
+	slices.SortFunc(backups, func(a, b *model.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// Find returns the latest backup taken at or before at.
+func (m *Manager) Find(ctx context.Context, at time.Time) (*model.Backup, error) {
+	backups, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, backup := range backups {
+		if !backup.CreatedAt.After(at) {
+			return backup, nil
+		}
+	}
+	return nil, fmt.Errorf("%w at or before %s in %s", ErrBackupNotFound, at.UTC().Format(time.RFC3339), m.dir)
+}
+
+func (m *Manager) prune(ctx context.Context) error {
+	backups, err := m.List(ctx)
+	if err != nil || len(backups) <= m.Retention {
+		return err
+	}
+	for _, backup := range backups[m.Retention:] {
+		if err := os.Remove(backup.Path); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Info("removed old backup", "backup", backup.Name)
+	}
+	return nil
+}
+
+// parse reads the time a backup was taken from its name, other files in the
+// directory are not backups of this database.
+func (m *Manager) parse(name string) (*model.Backup, bool) {
+	stamp, ok := strings.CutPrefix(name, m.base+"_")
+	if !ok {
+		return nil, false
+	}
+
+	compressed := strings.HasSuffix(stamp, gzExtension)
+	stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, gzExtension), extension)
+	createdAt, err := time.Parse(timeLayout, stamp)
+	if err != nil || (!compressed && !strings.HasSuffix(name, extension)) {
+		return nil, false
+	}
+
+	return &model.Backup{
+		Name:       name,
+		Path:       filepath.Join(m.dir, name),
+		Compressed: compressed,
+		CreatedAt:  createdAt,
+	}, true
+}
+
+func compress(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writer := gzip.NewWriter(out)
+	if _, err := io.CopyBuffer(writer, in, make([]byte, copyBufferSize)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+)
+
+func migratedDatabase(t *testing.T) (*sql.DB, string) {
+	path := filepath.Join(t.TempDir(), "videos.db")
+	database, err := db.Connect(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	_, err = db.NewMigrator(database).Up(context.Background())
+	require.NoError(t, err)
+	return database, path
+}
+
+func insertUser(t *testing.T, database *sql.DB, username string) {
+	_, err := database.Exec("INSERT INTO users (username, password, email) VALUES (?, 'hash', ?)", username, username+"@example.com")
+	require.NoError(t, err)
+}
+
+func countUsers(t *testing.T, path string) int {
+	database, err := db.Connect(path)
+	require.NoError(t, err)
+	defer database.Close()
+
+	var count int
+	require.NoError(t, database.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+	return count
+}
+
+// newManager returns a manager whose clock advances a minute per backup.
+func newManager(database *sql.DB, path string, compress bool) *Manager {
+	manager := NewManager(database, &config.Settings{DatabaseURL: path, BackupRetention: 2, BackupCompress: compress})
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	manager.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return manager
+}
+
+func TestManager_CreateAndList(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		// fixture
+		database, path := migratedDatabase(t)
+		manager := newManager(database, path, compress)
+		insertUser(t, database, "johndoe")
+
+		// test
+		created := []string{}
+		for range 3 {
+			backup, err := manager.Create(context.Background())
+			require.NoError(t, err)
+			created = append(created, backup.Name)
+		}
+		backups, err := manager.List(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		require.Equal(t, created[2], backups[0].Name)
+		require.Equal(t, created[1], backups[1].Name)
+		require.Equal(t, compress, backups[0].Compressed)
+		require.Equal(t, time.Date(2024, 1, 2, 15, 7, 5, 0, time.UTC), backups[0].CreatedAt)
+		require.Positive(t, backups[0].Size)
+
+		entries, err := os.ReadDir(filepath.Join(filepath.Dir(path), "backups"))
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		if compress {
+			require.Equal(t, "videos_20240102T150705Z.db.gz", created[2])
+		} else {
+			require.Equal(t, "videos_20240102T150705Z.db", created[2])
+		}
+	}
+}
+
+func TestManager_Find(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	manager := newManager(database, path, true)
+	first, err := manager.Create(context.Background())
+	require.NoError(t, err)
+	second, err := manager.Create(context.Background())
+	require.NoError(t, err)
+
+	// test
+	between, betweenErr := manager.Find(context.Background(), second.CreatedAt.Add(-time.Second))
+	latest, latestErr := manager.Find(context.Background(), second.CreatedAt.Add(time.Hour))
+	_, beforeErr := manager.Find(context.Background(), first.CreatedAt.Add(-time.Second))
+
+	// assert
+	require.NoError(t, betweenErr)
+	require.Equal(t, first.Name, between.Name)
+	require.NoError(t, latestErr)
+	require.Equal(t, second.Name, latest.Name)
+	require.ErrorIs(t, beforeErr, ErrBackupNotFound)
+}
+
+func TestRestore(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	manager := newManager(database, path, true)
+	insertUser(t, database, "johndoe")
+	backup, err := manager.Create(context.Background())
+	require.NoError(t, err)
+	insertUser(t, database, "janedoe")
+	require.NoError(t, database.Close())
+
+	// test
+	previous, err := Restore(context.Background(), backup.Path, path)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 1, countUsers(t, path))
+	require.Equal(t, 2, countUsers(t, previous))
+	require.NoFileExists(t, filepath.Join(filepath.Dir(path), "videos_restoring.db"))
+}
+
+func TestRestore_VerifiesReadOnly(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	manager := newManager(database, path, false)
+	insertUser(t, database, "johndoe")
+	backup, err := manager.Create(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, database.Close())
+	backedUp, err := os.ReadFile(backup.Path)
+	require.NoError(t, err)
+
+	// test
+	_, err = Restore(context.Background(), backup.Path, path)
+
+	// assert
+	require.NoError(t, err)
+	restored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, backedUp, restored)
+}
+
+func TestRestore_UnmigratedBackup(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	require.NoError(t, database.Close())
+	unmigrated := filepath.Join(t.TempDir(), "unmigrated.db")
+	other, err := db.Connect(unmigrated)
+	require.NoError(t, err)
+	_, err = other.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	// test
+	previous, err := Restore(context.Background(), unmigrated, path)
+
+	// assert
+	require.ErrorContains(t, err, "no schema migration was applied")
+	require.Empty(t, previous)
+}
+
+func TestRestore_CorruptBackup(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	insertUser(t, database, "johndoe")
+	require.NoError(t, database.Close())
+	corrupt := filepath.Join(t.TempDir(), "corrupt.db")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not a database"), 0o600))
+
+	// test
+	previous, err := Restore(context.Background(), corrupt, path)
+
+	// assert
+	require.ErrorContains(t, err, "file is not a database")
+	require.Empty(t, previous)
+	require.Equal(t, 1, countUsers(t, path))
+}
+
+func TestRestore_NewerSchema(t *testing.T) {
+	// fixture
+	database, path := migratedDatabase(t)
+	manager := newManager(database, path, false)
+	_, err := database.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (1000, 'from the future', ?)", time.Now())
+	require.NoError(t, err)
+	backup, err := manager.Create(context.Background())
+	require.NoError(t, err)
+
+	// test
+	_, err = Restore(context.Background(), backup.Path, path)
+
+	// assert
+	require.ErrorContains(t, err, "schema version 1000 is newer than version")
+}
@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/db"
+)
+
+const (
+	restoringSuffix      = "_restoring" + extension
+	beforeRestoreInfix   = "_before_restore_"
+	integrityCheckPassed = "ok"
+)
+
+// sidecars are the files SQLite keeps next to a database in WAL mode, they
+// belong to the database they were written for.
+var sidecars = []string{"", "-wal", "-shm"}
+
+// Restore replaces the database at databasePath with the backup at path,
+// gzipped when its name ends with .gz. The backup is copied next to the
+// database and checked before anything is replaced: SQLite must find it
+// intact, and its schema must not be newer than the migrations of this
+// binary, older ones are migrated when the server starts.
+//
+// The replaced database is kept and its path returned, it is empty when there
+// was no database. The server must be stopped while restoring.
+func Restore(ctx context.Context, path string, databasePath string) (string, error) {
+	dir := filepath.Dir(databasePath)
+	base := strings.TrimSuffix(filepath.Base(databasePath), filepath.Ext(databasePath))
+
+	candidate := filepath.Join(dir, base+restoringSuffix)
+	if err := os.Remove(candidate); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	defer os.Remove(candidate)
+
+	if err := extract(path, candidate); err != nil {
+		return "", fmt.Errorf("copying the backup: %w", err)
+	}
+	if err := verify(ctx, candidate); err != nil {
+		return "", fmt.Errorf("backup %s: %w", path, err)
+	}
+
+	previous := ""
+	if _, err := os.Stat(databasePath); err == nil {
+		previous = filepath.Join(dir, base+beforeRestoreInfix+time.Now().UTC().Format(timeLayout)+extension)
+		for _, sidecar := range sidecars {
+			err := os.Rename(databasePath+sidecar, previous+sidecar)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("moving the database aside: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(candidate, databasePath); err != nil {
+		return previous, err
+	}
+	return previous, nil
+}
+
+// verify fails when the database at path is corrupt or its schema is unknown
+// to this binary. It opens the database read-only, so checking a backup never
+// changes it.
+func verify(ctx context.Context, path string) error {
+	database, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	var integrity string
+	if err := database.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&integrity); err != nil {
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+	if integrity != integrityCheckPassed {
+		return errors.New("integrity check failed: " + integrity)
+	}
+
+	var migrated int
+	query := `SELECT COUNT(1) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`
+	if err := database.QueryRowContext(ctx, query).Scan(&migrated); err != nil {
+		return err
+	}
+	var version int
+	if migrated > 0 {
+		query = `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+		if err := database.QueryRowContext(ctx, query).Scan(&version); err != nil {
+			return err
+		}
+	}
+
+	if version == 0 {
+		return errors.New("no schema migration was applied")
+	}
+	if latest := db.LatestVersion(); version > latest {
+		return fmt.Errorf("schema version %d is newer than version %d of this binary", version, latest)
+	}
+	return nil
+}
+
+func extract(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(source, ".gz") {
+		gzipReader, err := gzip.NewReader(in)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.CopyBuffer(out, reader, make([]byte, copyBufferSize)); err != nil {
+		return err
+	}
+	return out.Sync()
+}
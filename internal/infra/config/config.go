@@ -1,48 +1,310 @@
+// Package config loads the Settings from, by increasing precedence, their
+// defaults, a YAML or TOML file, environment variables and command line flags.
 package config
 
 import (
 	"errors"
-	"os"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Settings are described by their tags: `setting` holds the environment
+// variable name, followed by ",required" and ",secret" when they apply,
+// `default` the value used when no source sets it and `oneof` the accepted
+// values. Durations accept Go durations such as "30s" or "2m", zero disables
+// the timeout.
 type Settings struct {
-	DatabaseURL string
-	JwtKey      string
+	DatabaseURL       string `setting:"DATABASE_PATH,required" usage:"path of the SQLite database"`
+	JwtKey            string `setting:"JWT_KEY,secret" usage:"shared secret signing HS256 tokens, required unless JWT_SIGNING_KEY is set, then it only verifies the tokens it signed"`
+	OpenAPIValidation bool   `setting:"OPENAPI_VALIDATION" default:"false" usage:"reject requests that do not match the OpenAPI contract"`
+	GrpcAddress       string `setting:"GRPC_ADDRESS" default:":9090" usage:"address the gRPC server listens on"`
+
+	JwtSigningKey       string `setting:"JWT_SIGNING_KEY,secret" usage:"PEM RSA or Ed25519 private key signing RS256 or EdDSA tokens instead of JWT_KEY"`
+	JwtVerificationKeys string `setting:"JWT_VERIFICATION_KEYS" usage:"PEM public keys tokens are also accepted from, such as the previous signing key while keys are rotated"`
+	JwtIssuer           string `setting:"JWT_ISSUER" default:"videos-api" usage:"iss of the tokens, tokens of other issuers are refused"`
+	JwtAudience         string `setting:"JWT_AUDIENCE" default:"videos-api" usage:"aud of the tokens, tokens for other audiences are refused"`
+
+	HttpAddress           string        `setting:"HTTP_ADDRESS" default:":8080" usage:"address the HTTP server listens on"`
+	HttpReadTimeout       time.Duration `setting:"HTTP_READ_TIMEOUT" default:"15s" usage:"time to read a whole request"`
+	HttpReadHeaderTimeout time.Duration `setting:"HTTP_READ_HEADER_TIMEOUT" default:"5s" usage:"time to read the request headers"`
+	HttpWriteTimeout      time.Duration `setting:"HTTP_WRITE_TIMEOUT" default:"30s" usage:"time to write a response"`
+	HttpIdleTimeout       time.Duration `setting:"HTTP_IDLE_TIMEOUT" default:"60s" usage:"time a keep-alive connection waits for the next request"`
+	HttpMaxHeaderBytes    int           `setting:"HTTP_MAX_HEADER_BYTES" default:"1048576" usage:"maximum size of the request headers"`
+	ShutdownTimeout       time.Duration `setting:"SHUTDOWN_TIMEOUT" default:"20s" usage:"time in-flight requests and workers get to finish on shutdown"`
+	ShutdownDrainDelay    time.Duration `setting:"SHUTDOWN_DRAIN_DELAY" default:"0s" usage:"time the server reports not ready before it stops listening"`
+
+	TracingExporter     string `setting:"TRACING_EXPORTER" default:"none" oneof:"none stdout otlp" usage:"where spans are exported"`
+	TracingOtlpEndpoint string `setting:"TRACING_OTLP_ENDPOINT" usage:"OTLP/HTTP endpoint of the otlp exporter"`
+
+	ApiKeyTTL time.Duration `setting:"API_KEY_TTL" default:"2160h" usage:"time an API key works before it expires"`
+
+	RateLimitDefault string `setting:"RATE_LIMIT_DEFAULT" default:"300/m" usage:"requests a client can make to a route, as a count per s, m or h, none disables the limit"`
+	RateLimitRoutes  string `setting:"RATE_LIMIT_ROUTES" default:"POST /login=10/m,POST /login/mfa=10/m,POST /signup=10/m,POST /verify-email/request=5/m,POST /reset-password/request=5/m,POST /videos/=60/m" usage:"limits of single routes, as comma separated METHOD /route=10/m"`
+
+	LoginMaxFailures        int           `setting:"LOGIN_MAX_FAILURES" default:"5" usage:"failed logins of a username before it is locked out"`
+	LoginMaxAddressFailures int           `setting:"LOGIN_MAX_ADDRESS_FAILURES" default:"20" usage:"failed logins from an address before it is locked out"`
+	LoginFailureDelay       time.Duration `setting:"LOGIN_FAILURE_DELAY" default:"1s" usage:"wait after the first failed login of a username, doubled after each further failure"`
+	LoginLockout            time.Duration `setting:"LOGIN_LOCKOUT" default:"15m" usage:"time a username or address stays locked out, and failures are remembered"`
+	MfaIssuer               string        `setting:"MFA_ISSUER" default:"videos-api" usage:"name authenticator apps show next to the two-factor codes"`
+
+	Mailer       string `setting:"MAILER" default:"log" oneof:"log file smtp" usage:"how emails are sent, log only logs their recipient and subject and file keeps them for development"`
+	MailFrom     string `setting:"MAIL_FROM" default:"videos-api@localhost" usage:"sender address of the emails"`
+	MailDir      string `setting:"MAIL_DIR" default:"mail" usage:"directory the file mailer writes the emails to"`
+	SmtpAddress  string `setting:"SMTP_ADDRESS" default:"localhost:587" usage:"host:port of the SMTP server, STARTTLS is used when it offers it"`
+	SmtpUsername string `setting:"SMTP_USERNAME" usage:"username of the SMTP server, empty to send without authenticating"`
+	SmtpPassword string `setting:"SMTP_PASSWORD,secret" usage:"password of the SMTP server"`
+
+	AppURL                 string        `setting:"APP_URL" default:"http://localhost:8080" usage:"base URL of the links mailed to users, the app behind it posts their token back to the API"`
+	EmailVerificationTTL   time.Duration `setting:"EMAIL_VERIFICATION_TTL" default:"24h" usage:"time an email verification link stays valid"`
+	PasswordResetTTL       time.Duration `setting:"PASSWORD_RESET_TTL" default:"1h" usage:"time a password reset link stays valid"`
+	WorkspaceInvitationTTL time.Duration `setting:"WORKSPACE_INVITATION_TTL" default:"168h" usage:"time a workspace invitation link stays valid"`
+	RequireVerifiedEmail   bool          `setting:"REQUIRE_VERIFIED_EMAIL" default:"false" usage:"only let users with a verified email change data"`
+
+	OidcIssuer       string `setting:"OIDC_ISSUER" usage:"issuer URL of the OpenID Connect provider, empty disables single sign-on"`
+	OidcClientID     string `setting:"OIDC_CLIENT_ID" usage:"client id of the API at the OpenID Connect provider"`
+	OidcClientSecret string `setting:"OIDC_CLIENT_SECRET,secret" usage:"client secret of the API at the OpenID Connect provider"`
+	OidcRedirectURL  string `setting:"OIDC_REDIRECT_URL" default:"http://localhost:8080/login/oidc/callback" usage:"URL of /login/oidc/callback the provider redirects back to"`
+	OidcScopes       string `setting:"OIDC_SCOPES" default:"openid email profile" usage:"space separated scopes asked for"`
+	OidcRoleClaim    string `setting:"OIDC_ROLE_CLAIM" default:"groups" usage:"ID token claim, a string or a list, roles are mapped from"`
+	OidcRoleMapping  string `setting:"OIDC_ROLE_MAPPING" usage:"roles of the claim values, as comma separated value=role, the most allowed role matched wins"`
+	OidcDefaultRole  string `setting:"OIDC_DEFAULT_ROLE" default:"viewer" usage:"role of users no value maps a role to, none refuses them"`
+
+	BackupDir       string        `setting:"BACKUP_DIR" usage:"directory of the backups, by default the backups directory next to the database"`
+	BackupInterval  time.Duration `setting:"BACKUP_INTERVAL" default:"0s" usage:"time between scheduled backups, zero disables them"`
+	BackupRetention int           `setting:"BACKUP_RETENTION" default:"7" usage:"number of backups kept, older ones are removed"`
+	BackupCompress  bool          `setting:"BACKUP_COMPRESS" default:"true" usage:"gzip the backups"`
+
+	LogLevel  string `setting:"LOG_LEVEL" default:"info" oneof:"debug info warn error" usage:"minimum level of the logs"`
+	LogFormat string `setting:"LOG_FORMAT" default:"text" oneof:"text json" usage:"format of the log lines"`
+
+	// sources tells where each value came from, for Dump
+	sources map[string]string
 }
 
 const (
-	DATABASE_PATH = "DATABASE_PATH"
-	JWT_KEY       = "JWT_KEY"
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOtlp   = "otlp"
 )
 
-func Load() (*Settings, error) {
-	// Load configurations, e.g., from environment variables or a config file
-	var err error
-	var dbURL string
+// OidcRoleNone as OIDC_DEFAULT_ROLE refuses the users no value maps a role to.
+const OidcRoleNone = "none"
+
+const (
+	MailerLog  = "log"
+	MailerFile = "file"
+	MailerSmtp = "smtp"
+)
 
-	if dbURL, err = loadEnvVar(DATABASE_PATH); err != nil {
+const (
+	LogDebug = "debug"
+	LogInfo  = "info"
+	LogWarn  = "warn"
+	LogError = "error"
+
+	LogText = "text"
+	LogJson = "json"
+)
+
+const (
+	CONFIG_FILE = "CONFIG_FILE"
+
+	configFlag = "config"
+	redacted   = "[REDACTED]"
+)
+
+// Load registers a flag per setting, plus -config naming the settings file
+// (CONFIG_FILE in the environment), on flags, parses args with it and loads
+// the settings. Callers can register their own flags on flags beforehand.
+func Load(flags *flag.FlagSet, args []string) (*Settings, error) {
+	fields := settingFields()
+
+	configFile := flags.String(configFlag, "", "YAML or TOML settings `file`, "+CONFIG_FILE+" in the environment")
+	for _, field := range fields {
+		flags.Var(&flagValue{isBool: field.kind == reflect.Bool}, field.flagName(), field.usageText())
+	}
+	if err := flags.Parse(args); err != nil {
 		return nil, err
 	}
 
-	var jwtKey string
-	if jwtKey, err = loadEnvVar(JWT_KEY); err != nil {
+	values := make(map[string]source)
+
+	path := *configFile
+	if path == "" {
+		path = lookupEnv(CONFIG_FILE)
+	}
+	if path != "" {
+		if err := readFile(path, fields, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := readEnv(fields, values); err != nil {
 		return nil, err
 	}
 
-	settings := &Settings{
-		DatabaseURL: dbURL,
-		JwtKey:      jwtKey,
+	flags.Visit(func(f *flag.Flag) {
+		if value, ok := f.Value.(*flagValue); ok {
+			name := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+			values[name] = source{value: value.value, from: "flag -" + f.Name}
+		}
+	})
+
+	return build(fields, values)
+}
+
+// build sets every field from its value, or its default, and validates it.
+func build(fields []settingField, values map[string]source) (*Settings, error) {
+	settings := &Settings{sources: make(map[string]string, len(fields))}
+	target := reflect.ValueOf(settings).Elem()
+
+	for _, field := range fields {
+		value, ok := values[field.name]
+		if !ok || value.value == "" {
+			if field.required {
+				return nil, errors.New(field.name + " is not set")
+			}
+			value = source{value: field.fallback, from: "default"}
+		}
+		settings.sources[field.name] = value.from
+
+		if err := field.set(target.Field(field.index), value.value); err != nil {
+			return nil, fmt.Errorf("%s from %s %w", field.name, value.from, err)
+		}
+	}
+
+	// one of the two signs the tokens
+	if settings.JwtKey == "" && settings.JwtSigningKey == "" {
+		return nil, errors.New("JWT_KEY is not set")
 	}
 
 	return settings, nil
 }
 
-func loadEnvVar(key string) (string, error) {
-	value := os.Getenv(key)
-	if value == "" {
-		return "", errors.New(key + " environment variable is not set")
+// Dump writes every setting with where its value came from, secrets
+// redacted.
+func (s *Settings) Dump(w io.Writer) error {
+	target := reflect.ValueOf(s).Elem()
+	for _, field := range settingFields() {
+		value := fmt.Sprint(target.Field(field.index).Interface())
+		if field.secret && value != "" {
+			value = redacted
+		}
+
+		from := s.sources[field.name]
+		if from == "" {
+			from = "default"
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s # %s\n", field.name, value, from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type settingField struct {
+	index    int
+	kind     reflect.Kind
+	isTime   bool
+	name     string
+	required bool
+	secret   bool
+	fallback string
+	oneOf    []string
+	usage    string
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+func settingFields() []settingField {
+	settingsType := reflect.TypeFor[Settings]()
+	fields := make([]settingField, 0, settingsType.NumField())
+
+	for i := range settingsType.NumField() {
+		structField := settingsType.Field(i)
+		tag, ok := structField.Tag.Lookup("setting")
+		if !ok {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		field := settingField{
+			index:    i,
+			kind:     structField.Type.Kind(),
+			isTime:   structField.Type == durationType,
+			name:     name,
+			fallback: structField.Tag.Get("default"),
+			usage:    structField.Tag.Get("usage"),
+		}
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "required":
+				field.required = true
+			case "secret":
+				field.secret = true
+			}
+		}
+		if oneOf := structField.Tag.Get("oneof"); oneOf != "" {
+			field.oneOf = strings.Fields(oneOf)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func (f settingField) flagName() string {
+	return strings.ToLower(strings.ReplaceAll(f.name, "_", "-"))
+}
+
+func (f settingField) usageText() string {
+	usage := f.usage + ", " + f.name + " in the environment"
+	if f.oneOf != nil {
+		usage += ", one of " + strings.Join(f.oneOf, ", ")
+	}
+	if f.fallback != "" {
+		usage += " (default " + f.fallback + ")"
 	}
-	return strings.TrimSpace(value), nil
+	return usage
+}
+
+func (f settingField) set(target reflect.Value, value string) error {
+	switch {
+	case f.isTime:
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return errors.New("must be a non-negative duration")
+		}
+		target.SetInt(int64(duration))
 
+	case f.kind == reflect.Bool:
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		target.SetBool(enabled)
+
+	case f.kind == reflect.Int:
+		number, err := strconv.Atoi(value)
+		if err != nil || number <= 0 {
+			return errors.New("must be a positive integer")
+		}
+		target.SetInt(int64(number))
+
+	default:
+		if f.oneOf != nil {
+			value = strings.ToLower(value)
+			if !slices.Contains(f.oneOf, value) {
+				return errors.New("must be one of " + strings.Join(f.oneOf, ", "))
+			}
+		}
+		target.SetString(value)
+	}
+	return nil
 }
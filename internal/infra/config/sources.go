@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type source struct {
+	value string
+	from  string
+}
+
+// readFile reads the settings of a YAML or TOML file, chosen by extension.
+// Keys are the environment variable names in any case, and nested sections
+// join their keys with '_', so `http: {read_timeout: 5s}` sets
+// HTTP_READ_TIMEOUT. A list sets the comma separated value.
+func readFile(path string, fields []settingField, values map[string]source) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading settings file: %w", err)
+	}
+
+	document := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	default:
+		return errors.New("settings file " + path + " must be a .yaml, .yml or .toml file")
+	}
+	if err != nil {
+		return fmt.Errorf("parsing settings file %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.name] = true
+	}
+
+	flattened := map[string]string{}
+	if err := flatten("", document, flattened); err != nil {
+		return fmt.Errorf("parsing settings file %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(flattened))
+	for name := range flattened {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			return errors.New("unknown setting " + name + " in " + path)
+		}
+		values[name] = source{value: flattened[name], from: "file " + path}
+	}
+	return nil
+}
+
+// flatten names the values of document after their path. Objects only work
+// as sections, anywhere else they fail naming the setting.
+func flatten(prefix string, document map[string]any, flattened map[string]string) error {
+	for key, value := range document {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		if section, ok := value.(map[string]any); ok {
+			if err := flatten(name, section, flattened); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text, err := settingValue(name, value, true)
+		if err != nil {
+			return err
+		}
+		flattened[name] = text
+	}
+	return nil
+}
+
+// settingValue writes value the way the environment variable name would hold
+// it: lists are comma separated and numbers never use an exponent, so 2e6
+// reads as 2000000.
+func settingValue(name string, value any, listAllowed bool) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case []any:
+		if !listAllowed {
+			return "", errors.New("setting " + name + " nests a list in a list")
+		}
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			text, err := settingValue(name, item, false)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any, map[any]any, []map[string]any:
+		return "", errors.New("setting " + name + " holds an object, only sections may")
+	default:
+		return strings.TrimSpace(fmt.Sprint(value)), nil
+	}
+}
+
+// readEnv reads every setting from its environment variable, or from the
+// file named by the same variable suffixed with _FILE, as Docker secrets are.
+func readEnv(fields []settingField, values map[string]source) error {
+	for _, field := range fields {
+		value := lookupEnv(field.name)
+		path := lookupEnv(field.name + "_FILE")
+
+		switch {
+		case value != "" && path != "":
+			return errors.New(field.name + " and " + field.name + "_FILE are both set")
+
+		case path != "":
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s_FILE: %w", field.name, err)
+			}
+			values[field.name] = source{value: strings.TrimSpace(string(content)), from: "file " + path}
+
+		case value != "":
+			values[field.name] = source{value: value, from: "environment"}
+		}
+	}
+	return nil
+}
+
+func lookupEnv(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+// flagValue keeps flags unset until they are given, so they do not override
+// the file and the environment with their defaults.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value = strings.TrimSpace(value)
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
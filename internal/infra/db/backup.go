@@ -0,0 +1,18 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+)
+
+// Backup writes a consistent copy of the database to path while it is in
+// use. It does not overwrite an existing file.
+func Backup(ctx context.Context, db *sql.DB, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return errors.New("backup file " + path + " already exists")
+	}
+	_, err := db.ExecContext(ctx, `VACUUM INTO ?`, path)
+	return err
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	// fixtures
+	dbPath := TestDbPath
+	defer Cleanup(dbPath)
+
+	db := connectDb(dbPath, t)
+	defer db.Close()
+
+	require.NoError(t, NewTablesBuilder(db).WithUsersTable().Build())
+	_, err := db.Exec("INSERT INTO users (username, password, email) VALUES ('johndoe', 'hash', 'johndoe@example.com')")
+	require.NoError(t, err)
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+
+	// test
+	err = Backup(context.Background(), db, backupPath)
+	again := Backup(context.Background(), db, backupPath)
+
+	// assert
+	require.NoError(t, err)
+	require.EqualError(t, again, "backup file "+backupPath+" already exists")
+
+	backup := connectDb(backupPath, t)
+	defer backup.Close()
+	var count int
+	require.NoError(t, backup.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+	require.Equal(t, 1, count)
+}
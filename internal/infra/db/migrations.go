@@ -0,0 +1,526 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Migration changes the schema from Version-1 to Version, Down reverts it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration was applied, AppliedAt is nil
+// when it was not.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// migrations are only ever appended to, an applied migration must not change.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create tables",
+		// the schema databases had before migrations were tracked, its tables
+		// are only created if they do not exist
+		Up: `
+		CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL UNIQUE,
+			password TEXT NOT NULL,
+			email TEXT NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS videos (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT,
+			link TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE TABLE IF NOT EXISTS annotations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			video_id INTEGER NOT NULL,
+			start_time TEXT NOT NULL,
+			end_time TEXT NOT NULL,
+			note TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+		);
+		`,
+		Down: `
+		DROP TABLE IF EXISTS annotations;
+		DROP TABLE IF EXISTS videos;
+		DROP TABLE IF EXISTS users;
+		`,
+	},
+	{
+		Version: 2,
+		Name:    "add versions, annotation types, revisions, webhooks and outbox",
+		// annotations belong to the owner of their video and videos last as
+		// long as their longest annotation, so existing rows stay valid
+		Up: `
+		ALTER TABLE videos ADD COLUMN duration INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE videos ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE annotations ADD COLUMN user_id INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE annotations ADD COLUMN type TEXT NOT NULL DEFAULT 'note';
+		ALTER TABLE annotations ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+		UPDATE annotations SET user_id = (SELECT videos.user_id FROM videos WHERE videos.id = annotations.video_id);
+		UPDATE videos SET duration = COALESCE((SELECT MAX(CAST(annotations.end_time AS INTEGER)) FROM annotations
+			WHERE annotations.video_id = videos.id), 0);
+		CREATE TABLE annotation_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			annotation_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			start_time TEXT NOT NULL,
+			end_time TEXT NOT NULL,
+			type TEXT NOT NULL,
+			note TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (annotation_id, revision),
+			FOREIGN KEY (annotation_id) REFERENCES annotations(id) ON DELETE CASCADE
+		);
+		CREATE TABLE webhook_subscriptions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			event_types TEXT NOT NULL DEFAULT '',
+			annotation_types TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE TABLE webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			subscription_id INTEGER NOT NULL,
+			event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			payload BLOB NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			delivered_at TIMESTAMP,
+			FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE
+		);
+		CREATE INDEX webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+		CREATE TABLE webhook_delivery_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			delivery_id INTEGER NOT NULL,
+			attempt INTEGER NOT NULL,
+			response_code INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			duration INTEGER NOT NULL DEFAULT 0,
+			attempted_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+		);
+		CREATE TABLE outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			video_id INTEGER NOT NULL,
+			payload BLOB NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX outbox_video ON outbox (video_id, id);
+		`,
+		Down: `
+		DROP TABLE outbox;
+		DROP TABLE webhook_delivery_attempts;
+		DROP TABLE webhook_deliveries;
+		DROP TABLE webhook_subscriptions;
+		DROP TABLE annotation_revisions;
+		ALTER TABLE annotations DROP COLUMN version;
+		ALTER TABLE annotations DROP COLUMN type;
+		ALTER TABLE annotations DROP COLUMN user_id;
+		ALTER TABLE videos DROP COLUMN version;
+		ALTER TABLE videos DROP COLUMN duration;
+		`,
+	},
+	{
+		Version: 3,
+		Name:    "add user role and disabled",
+		Up: `
+		ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'editor';
+		ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
+		`,
+		Down: `
+		ALTER TABLE users DROP COLUMN disabled;
+		ALTER TABLE users DROP COLUMN role;
+		`,
+	},
+	{
+		Version: 4,
+		Name:    "add api keys",
+		// only the hash of a key is kept, the key is shown once
+		Up: `
+		CREATE TABLE api_keys (
+			id TEXT PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			key_hash TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_api_keys_user ON api_keys (user_id);
+		`,
+		Down: `
+		DROP TABLE api_keys;
+		`,
+	},
+	{
+		Version: 5,
+		Name:    "create login attempts and audit events",
+		Up: `
+		CREATE TABLE login_attempts (
+			key TEXT PRIMARY KEY,
+			failures INTEGER NOT NULL,
+			last_failure_at TIMESTAMP NOT NULL,
+			locked_until TIMESTAMP
+		);
+		CREATE TABLE audit_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			username TEXT NOT NULL,
+			address TEXT NOT NULL,
+			detail TEXT NOT NULL,
+			occurred_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX idx_audit_events_username ON audit_events (username, occurred_at);
+		`,
+		Down: `
+		DROP TABLE audit_events;
+		DROP TABLE login_attempts;
+		`,
+	},
+	{
+		Version: 6,
+		Name:    "create user mfa and recovery codes",
+		Up: `
+		CREATE TABLE user_mfa (
+			user_id INTEGER PRIMARY KEY,
+			secret TEXT NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 0,
+			last_step INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE TABLE mfa_recovery_codes (
+			user_id INTEGER NOT NULL,
+			code_hash TEXT NOT NULL,
+			PRIMARY KEY (user_id, code_hash),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		`,
+		Down: `
+		DROP TABLE mfa_recovery_codes;
+		DROP TABLE user_mfa;
+		`,
+	},
+	{
+		Version: 7,
+		Name:    "add email verification and user tokens",
+		// users created before verification existed are trusted
+		Up: `
+		ALTER TABLE users ADD COLUMN email_verified INTEGER NOT NULL DEFAULT 0;
+		UPDATE users SET email_verified = 1;
+		CREATE TABLE user_tokens (
+			id TEXT PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			purpose TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_user_tokens_user ON user_tokens (user_id, purpose);
+		`,
+		Down: `
+		DROP TABLE user_tokens;
+		ALTER TABLE users DROP COLUMN email_verified;
+		`,
+	},
+	{
+		Version: 8,
+		Name:    "add single sign-on identities",
+		Up: `
+		CREATE TABLE user_identities (
+			issuer TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			user_id INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (issuer, subject),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_user_identities_user ON user_identities (user_id);
+		CREATE TABLE oidc_logins (
+			state TEXT PRIMARY KEY,
+			code_verifier TEXT NOT NULL,
+			nonce TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+		`,
+		Down: `
+		DROP TABLE oidc_logins;
+		DROP TABLE user_identities;
+		`,
+	},
+	{
+		Version: 9,
+		Name:    "add retired usernames",
+		// a released username stays with its user until the session tokens
+		// issued to it expire
+		Up: `
+		CREATE TABLE retired_usernames (
+			username TEXT PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			retired_until TIMESTAMP NOT NULL
+		);
+		`,
+		Down: `
+		DROP TABLE retired_usernames;
+		`,
+	},
+	{
+		Version: 10,
+		Name:    "add workspaces",
+		// every user gets a personal workspace, with the id of the user, that
+		// holds the videos they own
+		Up: `
+		CREATE TABLE workspaces (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE TABLE workspace_members (
+			workspace_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (workspace_id, user_id),
+			FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_workspace_members_user ON workspace_members (user_id);
+		CREATE TABLE workspace_invitations (
+			id TEXT PRIMARY KEY,
+			workspace_id INTEGER NOT NULL,
+			email TEXT NOT NULL,
+			role TEXT NOT NULL,
+			invited_by INTEGER NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			accepted_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_workspace_invitations_workspace ON workspace_invitations (workspace_id);
+		INSERT INTO workspaces (id, name, created_at)
+			SELECT id, username, COALESCE(created_at, CURRENT_TIMESTAMP) FROM users;
+		INSERT INTO workspace_members (workspace_id, user_id, role, created_at)
+			SELECT id, id, 'admin', COALESCE(created_at, CURRENT_TIMESTAMP) FROM users;
+		ALTER TABLE videos ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 0;
+		UPDATE videos SET workspace_id = user_id;
+		CREATE INDEX idx_videos_workspace ON videos (workspace_id, id);
+		`,
+		Down: `
+		DROP INDEX idx_videos_workspace;
+		ALTER TABLE videos DROP COLUMN workspace_id;
+		DROP TABLE workspace_invitations;
+		DROP TABLE workspace_members;
+		DROP TABLE workspaces;
+		`,
+	},
+	{
+		Version: 11,
+		Name:    "drop webhook response bodies",
+		// failed attempts kept the start of the response body, subscribers
+		// read them back and must not see what internal hosts answered
+		Up: `
+		UPDATE webhook_delivery_attempts SET error = 'unexpected status ' || response_code
+			WHERE response_code <> 0 AND error LIKE 'unexpected status %';
+		`,
+		// the bodies are gone for good
+		Down: ``,
+	},
+}
+
+type migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) *migrator {
+	return &migrator{db: db, migrations: migrations}
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the applied ones.
+func (m *migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.Up,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			migration.Version, migration.Name, time.Now())
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, latest first, and returns
+// the reverted ones.
+func (m *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.run(ctx, migration.Down, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func (m *migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Verify fails when a migration is pending.
+func (m *migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			return fmt.Errorf("migration %d %s is pending", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// Current fails while the schema is not at the version of this binary, either
+// behind it or already migrated by a newer one.
+func (m *migrator) Current(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("schema version %d is not version %d of this binary", version, m.Latest())
+	}
+	return nil
+}
+
+// Version returns the latest applied migration, 0 when none is.
+func (m *migrator) Version(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for applied := range applied {
+		version = max(version, applied)
+	}
+	return version, nil
+}
+
+// Latest returns the version of the last migration this binary knows.
+func (m *migrator) Latest() int {
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// LatestVersion is Latest without a database, to check the schema of one that
+// must not be migrated.
+func LatestVersion() int {
+	return NewMigrator(nil).Latest()
+}
+
+func (m *migrator) run(ctx context.Context, script string, record string, args ...any) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	_, err := m.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	);
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Up(t *testing.T) {
+	// fixtures
+	dbPath := TestDbPath
+	defer Cleanup(dbPath)
+
+	db := connectDb(dbPath, t)
+	defer db.Close()
+
+	migrator := NewMigrator(db)
+
+	// test
+	applied, err := migrator.Up(context.Background())
+	again, againErr := migrator.Up(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrations))
+	require.NoError(t, againErr)
+	require.Empty(t, again)
+	require.Contains(t, getDbTableNames(db, t), "schema_migrations")
+	require.NoError(t, migrator.Verify(context.Background()))
+	require.NoError(t, migrator.Current(context.Background()))
+
+	_, err = db.Exec("INSERT INTO users (username, password, email) VALUES ('johndoe', 'hash', 'johndoe@example.com')")
+	require.NoError(t, err)
+	var role string
+	require.NoError(t, db.QueryRow("SELECT role FROM users WHERE username = 'johndoe'").Scan(&role))
+	require.Equal(t, "editor", role)
+}
+
+func TestMigrator_Up_ExistingTables(t *testing.T) {
+	// fixtures
+	dbPath := TestDbPath
+	defer Cleanup(dbPath)
+
+	db := connectDb(dbPath, t)
+	defer db.Close()
+
+	_, err := db.Exec(baselineSchema)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+	INSERT INTO users (username, password, email) VALUES ('johndoe', 'hash', 'johndoe@example.com');
+	INSERT INTO videos (user_id, title, description, link) VALUES (1, 'title', 'description', 'https://example.com');
+	INSERT INTO annotations (video_id, start_time, end_time, note) VALUES (1, 1000000000, 90000000000, 'note');
+	`)
+	require.NoError(t, err)
+
+	// test
+	_, err = NewMigrator(db).Up(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users WHERE role = 'editor'").Scan(&count))
+	require.Equal(t, 1, count)
+
+	var duration, videoVersion int64
+	require.NoError(t, db.QueryRow("SELECT duration, version FROM videos WHERE id = 1").Scan(&duration, &videoVersion))
+	require.Equal(t, int64(90000000000), duration)
+	require.Equal(t, int64(1), videoVersion)
+
+	var userId, annotationVersion int
+	var annotationType string
+	require.NoError(t, db.QueryRow("SELECT user_id, type, version FROM annotations WHERE id = 1").Scan(&userId, &annotationType, &annotationVersion))
+	require.Equal(t, 1, userId)
+	require.Equal(t, "note", annotationType)
+	require.Equal(t, 1, annotationVersion)
+}
+
+// baselineSchema is the schema the tables builder created before the
+// migrations were tracked.
+const baselineSchema = `
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	username TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	email TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS videos (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT,
+	link TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS annotations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	video_id INTEGER NOT NULL,
+	start_time TEXT NOT NULL,
+	end_time TEXT NOT NULL,
+	note TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+);
+`
+
+func TestMigrator_DownAndStatus(t *testing.T) {
+	// fixtures
+	dbPath := TestDbPath
+	defer Cleanup(dbPath)
+
+	db := connectDb(dbPath, t)
+	defer db.Close()
+
+	migrator := NewMigrator(db)
+	_, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	// test
+	reverted, err := migrator.Down(context.Background(), len(migrations)-1)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, reverted, len(migrations)-1)
+	require.Equal(t, len(migrations), reverted[0].Version)
+	require.Equal(t, 2, reverted[len(reverted)-1].Version)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, statuses[0].AppliedAt)
+	require.Nil(t, statuses[1].AppliedAt)
+	require.EqualError(t, migrator.Verify(context.Background()), "migration 2 add versions, annotation types, revisions, webhooks and outbox is pending")
+
+	version, err := migrator.Version(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	require.Equal(t, len(migrations), migrator.Latest())
+	require.EqualError(t, migrator.Current(context.Background()), fmt.Sprintf("schema version 1 is not version %d of this binary", len(migrations)))
+
+	_, err = db.Exec("SELECT version FROM videos")
+	require.ErrorContains(t, err, "no such column: version")
+}
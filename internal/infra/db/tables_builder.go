@@ -43,6 +43,8 @@ func (t *tablesBuilder) WithVideosTable() *tablesBuilder {
 		title TEXT NOT NULL,
 		description TEXT,
 		link TEXT NOT NULL,
+		duration INTEGER NOT NULL DEFAULT 0,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 	);
@@ -56,9 +58,12 @@ func (t *tablesBuilder) WithAnnotationsTable() *tablesBuilder {
 	CREATE TABLE IF NOT EXISTS annotations (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		video_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
 		start_time TEXT NOT NULL,
 		end_time TEXT NOT NULL,
+		type TEXT NOT NULL,
 		note TEXT,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
 	);
@@ -66,3 +71,96 @@ func (t *tablesBuilder) WithAnnotationsTable() *tablesBuilder {
 	t.statements = append(t.statements, createStatement)
 	return t
 }
+
+func (t *tablesBuilder) WithAnnotationRevisionsTable() *tablesBuilder {
+	createStatement := `
+	CREATE TABLE IF NOT EXISTS annotation_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		annotation_id INTEGER NOT NULL,
+		revision INTEGER NOT NULL,
+		start_time TEXT NOT NULL,
+		end_time TEXT NOT NULL,
+		type TEXT NOT NULL,
+		note TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (annotation_id, revision),
+		FOREIGN KEY (annotation_id) REFERENCES annotations(id) ON DELETE CASCADE
+	);
+	`
+	t.statements = append(t.statements, createStatement)
+	return t
+}
+
+func (t *tablesBuilder) WithWebhookSubscriptionsTable() *tablesBuilder {
+	createStatement := `
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		event_types TEXT NOT NULL DEFAULT '',
+		annotation_types TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+	`
+	t.statements = append(t.statements, createStatement)
+	return t
+}
+
+func (t *tablesBuilder) WithWebhookDeliveriesTable() *tablesBuilder {
+	createStatement := `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		subscription_id INTEGER NOT NULL,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload BLOB NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		delivered_at TIMESTAMP,
+		FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+	`
+	t.statements = append(t.statements, createStatement)
+	return t
+}
+
+func (t *tablesBuilder) WithWebhookDeliveryAttemptsTable() *tablesBuilder {
+	createStatement := `
+	CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		delivery_id INTEGER NOT NULL,
+		attempt INTEGER NOT NULL,
+		response_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration INTEGER NOT NULL DEFAULT 0,
+		attempted_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+	);
+	`
+	t.statements = append(t.statements, createStatement)
+	return t
+}
+
+func (t *tablesBuilder) WithOutboxTable() *tablesBuilder {
+	createStatement := `
+	CREATE TABLE IF NOT EXISTS outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		video_id INTEGER NOT NULL,
+		payload BLOB NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS outbox_video ON outbox (video_id, id);
+	`
+	t.statements = append(t.statements, createStatement)
+	return t
+}
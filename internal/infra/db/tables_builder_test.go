@@ -9,7 +9,8 @@ import (
 
 func TestTablesBuilder_Build(t *testing.T) {
 	// fixtures
-	expectedTableNames := []string{"sqlite_sequence", "users", "videos", "annotations"}
+	expectedTableNames := []string{"sqlite_sequence", "users", "videos", "annotations", "annotation_revisions",
+		"webhook_subscriptions", "webhook_deliveries", "webhook_delivery_attempts", "outbox"}
 
 	dbPath := TestDbPath
 	defer Cleanup(dbPath)
@@ -20,7 +21,8 @@ func TestTablesBuilder_Build(t *testing.T) {
 	builder := NewTablesBuilder(db)
 
 	// test
-	err := builder.WithUsersTable().WithVideosTable().WithAnnotationsTable().Build()
+	err := builder.WithUsersTable().WithVideosTable().WithAnnotationsTable().WithAnnotationRevisionsTable().
+		WithWebhookSubscriptionsTable().WithWebhookDeliveriesTable().WithWebhookDeliveryAttemptsTable().WithOutboxTable().Build()
 
 	// assert
 	require.NoError(t, err)
@@ -56,6 +58,56 @@ func TestTablesBuilder_WithAnnotationsTable(t *testing.T) {
 	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS annotations")
 }
 
+func TestTablesBuilder_WithAnnotationRevisionsTable(t *testing.T) {
+	// fixtures
+	builder := NewTablesBuilder(nil)
+	builder.WithAnnotationRevisionsTable()
+
+	// assert
+	require.Len(t, builder.statements, 1)
+	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS annotation_revisions")
+}
+
+func TestTablesBuilder_WithWebhookSubscriptionsTable(t *testing.T) {
+	// fixtures
+	builder := NewTablesBuilder(nil)
+	builder.WithWebhookSubscriptionsTable()
+
+	// assert
+	require.Len(t, builder.statements, 1)
+	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS webhook_subscriptions")
+}
+
+func TestTablesBuilder_WithWebhookDeliveriesTable(t *testing.T) {
+	// fixtures
+	builder := NewTablesBuilder(nil)
+	builder.WithWebhookDeliveriesTable()
+
+	// assert
+	require.Len(t, builder.statements, 1)
+	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS webhook_deliveries")
+}
+
+func TestTablesBuilder_WithWebhookDeliveryAttemptsTable(t *testing.T) {
+	// fixtures
+	builder := NewTablesBuilder(nil)
+	builder.WithWebhookDeliveryAttemptsTable()
+
+	// assert
+	require.Len(t, builder.statements, 1)
+	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS webhook_delivery_attempts")
+}
+
+func TestTablesBuilder_WithOutboxTable(t *testing.T) {
+	// fixtures
+	builder := NewTablesBuilder(nil)
+	builder.WithOutboxTable()
+
+	// assert
+	require.Len(t, builder.statements, 1)
+	require.Contains(t, builder.statements[0], "CREATE TABLE IF NOT EXISTS outbox")
+}
+
 func connectDb(dbPath string, t *testing.T) *sql.DB {
 	db, err := Connect(dbPath)
 	require.NoError(t, err)
@@ -0,0 +1,115 @@
+// Package health tells orchestrators whether the service is alive, ready to
+// take traffic and which build is running.
+package health
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Version and Commit are set at link time:
+//
+//	go build -ldflags "-X github.com/juliocnsouzadev/go-videos-api/internal/infra/health.Version=1.4.0 -X github.com/juliocnsouzadev/go-videos-api/internal/infra/health.Commit=$(git rev-parse HEAD)"
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Check reports why a dependency is not ready, nil means it is.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Readiness runs every registered check, the service is ready only when all
+// of them pass and it is not draining.
+type Readiness struct {
+	mu       sync.Mutex
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+type Result struct {
+	Ready  bool
+	Checks map[string]string
+}
+
+type Build struct {
+	Version   string
+	Commit    string
+	GoVersion string
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func (r *Readiness) Add(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, namedCheck{name, check})
+}
+
+// Drain makes the service report not ready from now on, so it is taken out
+// of the load balancer before it stops taking requests.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+func (r *Readiness) Check(ctx context.Context) *Result {
+	r.mu.Lock()
+	checks := append([]namedCheck{}, r.checks...)
+	r.mu.Unlock()
+
+	result := &Result{Ready: !r.draining.Load(), Checks: map[string]string{}}
+	if r.draining.Load() {
+		result.Checks["shutdown"] = "draining"
+	}
+
+	for _, check := range checks {
+		if err := check.check(ctx); err != nil {
+			result.Ready = false
+			result.Checks[check.name] = err.Error()
+			continue
+		}
+		result.Checks[check.name] = "ok"
+	}
+	return result
+}
+
+// Running turns a background loop's Running method into a check.
+func Running(running func() bool) Check {
+	return func(ctx context.Context) error {
+		if !running() {
+			return errors.New("not running")
+		}
+		return nil
+	}
+}
+
+// BuildInfo falls back to the commit recorded by the go tool when none was
+// set at link time.
+func BuildInfo() *Build {
+	build := &Build{Version: Version, Commit: Commit, GoVersion: runtime.Version()}
+	if build.Commit != "" {
+		return build
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				build.Commit = setting.Value
+			}
+		}
+	}
+	if build.Commit == "" {
+		build.Commit = "unknown"
+	}
+	return build
+}
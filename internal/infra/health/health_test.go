@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness_Check_HappyPath(t *testing.T) {
+	// fixture
+	readiness := NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return nil })
+
+	// test
+	result := readiness.Check(context.Background())
+
+	// assert
+	require.True(t, result.Ready)
+	require.Equal(t, map[string]string{"database": "ok"}, result.Checks)
+}
+
+func TestReadiness_Check_FailingCheck(t *testing.T) {
+	// fixture
+	readiness := NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return nil })
+	readiness.Add("outbox_dispatcher", func(ctx context.Context) error { return errors.New("not running") })
+
+	// test
+	result := readiness.Check(context.Background())
+
+	// assert
+	require.False(t, result.Ready)
+	require.Equal(t, "ok", result.Checks["database"])
+	require.Equal(t, "not running", result.Checks["outbox_dispatcher"])
+}
+
+func TestReadiness_Check_Draining(t *testing.T) {
+	// fixture
+	readiness := NewReadiness()
+	readiness.Add("database", func(ctx context.Context) error { return nil })
+
+	// test
+	readiness.Drain()
+	result := readiness.Check(context.Background())
+
+	// assert
+	require.False(t, result.Ready)
+	require.Equal(t, "draining", result.Checks["shutdown"])
+}
+
+func TestRunning(t *testing.T) {
+	// fixture
+	running := false
+	check := Running(func() bool { return running })
+
+	// test
+	stopped := check(context.Background())
+	running = true
+	started := check(context.Background())
+
+	// assert
+	require.EqualError(t, stopped, "not running")
+	require.NoError(t, started)
+}
+
+func TestBuildInfo(t *testing.T) {
+	// fixture
+	defer func(version, commit string) { Version, Commit = version, commit }(Version, Commit)
+	Version, Commit = "1.4.0", "abc123"
+
+	// test
+	build := BuildInfo()
+
+	// assert
+	require.Equal(t, "1.4.0", build.Version)
+	require.Equal(t, "abc123", build.Commit)
+	require.NotEmpty(t, build.GoVersion)
+}
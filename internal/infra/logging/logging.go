@@ -0,0 +1,93 @@
+// Package logging sets up the structured logger and carries a request scoped
+// logger in the context, so every log line of a request has its request id.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+// New builds the logger described by the settings.
+func New(w io.Writer, settings *config.Settings) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level(settings.LogLevel)}
+	if settings.LogFormat == config.LogJson {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+func level(name string) slog.Level {
+	switch name {
+	case config.LogDebug:
+		return slog.LevelDebug
+	case config.LogWarn:
+		return slog.LevelWarn
+	case config.LogError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// scope is stored by pointer, attributes added once the caller is known,
+// like its username, reach the loggers of the whole request, access log
+// included.
+type scope struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+type scopeKey struct{}
+
+// NewContext starts a scope logging with logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{logger: logger})
+}
+
+// FromContext returns the logger of the scope, the default logger outside of
+// one.
+func FromContext(ctx context.Context) *slog.Logger {
+	if scope, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		return scope.logger
+	}
+	return slog.Default()
+}
+
+// With adds attributes to every later log line of the scope, it does nothing
+// outside of one.
+func With(ctx context.Context, args ...any) {
+	if scope, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		scope.logger = scope.logger.With(args...)
+	}
+}
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID keeps the id sent by the caller when it is safe to log, up to
+// 128 letters, digits, '-', '_', '.' or ':', and generates one otherwise.
+func RequestID(sent string) string {
+	if sent == "" || len(sent) > maxRequestIDLength {
+		return rand.Text()
+	}
+	for _, c := range sent {
+		valid := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)
+		if !valid {
+			return rand.Text()
+		}
+	}
+	return sent
+}
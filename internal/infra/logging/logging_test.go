@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+func TestNew_Json(t *testing.T) {
+	// fixture
+	var buffer bytes.Buffer
+	logger := New(&buffer, &config.Settings{LogLevel: config.LogInfo, LogFormat: config.LogJson})
+
+	// test
+	logger.Debug("hidden")
+	logger.Info("shown", "username", "test-user")
+
+	// assert
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &line))
+	require.Equal(t, "shown", line["msg"])
+	require.Equal(t, "INFO", line["level"])
+	require.Equal(t, "test-user", line["username"])
+}
+
+func TestNew_TextAtWarnLevel(t *testing.T) {
+	// fixture
+	var buffer bytes.Buffer
+	logger := New(&buffer, &config.Settings{LogLevel: config.LogWarn, LogFormat: config.LogText})
+
+	// test
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	// assert
+	require.NotContains(t, buffer.String(), "hidden")
+	require.Contains(t, buffer.String(), "level=WARN msg=shown")
+}
+
+func TestFromContext_DefaultOutsideOfAScope(t *testing.T) {
+	// test
+	logger := FromContext(context.Background())
+
+	// assert
+	require.Same(t, slog.Default(), logger)
+}
+
+func TestWith_AddsToTheWholeScope(t *testing.T) {
+	// fixture
+	var buffer bytes.Buffer
+	ctx := NewContext(context.Background(), slog.New(slog.NewTextHandler(&buffer, nil)).With("request_id", "abc"))
+
+	// test
+	With(ctx, "username", "test-user")
+	FromContext(ctx).Info("request")
+
+	// assert
+	require.Contains(t, buffer.String(), "msg=request request_id=abc username=test-user")
+}
+
+func TestRequestID_KeepsValidId(t *testing.T) {
+	// test
+	id := RequestID("4bf92f35-77b3:4da6.a3ce_929d")
+
+	// assert
+	require.Equal(t, "4bf92f35-77b3:4da6.a3ce_929d", id)
+}
+
+func TestRequestID_GeneratesWhenMissingOrUnsafe(t *testing.T) {
+	for _, sent := range []string{"", "abc\ninjected=true", strings.Repeat("a", 129)} {
+		// test
+		id := RequestID(sent)
+
+		// assert
+		require.NotEqual(t, sent, id)
+		require.Len(t, id, 26)
+	}
+}
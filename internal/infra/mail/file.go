@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+// FileMailer writes every email to an .eml file of dir, which mail clients
+// open.
+type FileMailer struct {
+	from string
+	dir  string
+	now  func() time.Time
+}
+
+func NewFileMailer(from string, dir string) *FileMailer {
+	return &FileMailer{from: from, dir: dir, now: time.Now}
+}
+
+func (m *FileMailer) Send(ctx context.Context, message *model.Mail) error {
+	_, span := tracer.Start(ctx, "FileMailer.Send")
+	defer span.End()
+
+	now := m.now()
+	content, err := format(m.from, message, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(m.dir, now.UTC().Format("20060102T150405Z")+"-*.eml")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,80 @@
+// Package mail sends the emails of the service over SMTP, or keeps them in
+// the logs or in files during development.
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/ports"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+var tracer = tracing.Tracer("internal/infra/mail")
+
+var ErrInvalidAddress = errors.New("invalid email address")
+
+// New builds the mailer chosen by MAILER.
+func New(settings *config.Settings) (ports.Mailer, error) {
+	if _, err := mail.ParseAddress(settings.MailFrom); err != nil {
+		return nil, fmt.Errorf("MAIL_FROM %w", ErrInvalidAddress)
+	}
+
+	switch settings.Mailer {
+	case config.MailerFile:
+		return NewFileMailer(settings.MailFrom, settings.MailDir), nil
+	case config.MailerSmtp:
+		return NewSmtpMailer(settings.MailFrom, settings.SmtpAddress, settings.SmtpUsername, settings.SmtpPassword), nil
+	default:
+		return NewLogMailer(settings.MailFrom), nil
+	}
+}
+
+// format writes message as a plain text RFC 5322 message, the body quoted
+// printable. Addresses with line breaks are refused so they cannot add
+// headers.
+func format(from string, message *model.Mail, now time.Time) ([]byte, error) {
+	for _, address := range []string{from, message.To} {
+		if strings.ContainsAny(address, "\r\n") {
+			return nil, ErrInvalidAddress
+		}
+		if _, err := mail.ParseAddress(address); err != nil {
+			return nil, ErrInvalidAddress
+		}
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	_, domain, _ := strings.Cut(envelope(from), "@")
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "From: %s\r\n", from)
+	fmt.Fprintf(buffer, "To: %s\r\n", message.To)
+	fmt.Fprintf(buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(buffer, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(buffer, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(buffer)
+	if _, err := body.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
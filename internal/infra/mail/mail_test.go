@@ -0,0 +1,186 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/logging"
+)
+
+var testMail = &model.Mail{
+	To:      "jane@example.com",
+	Subject: "Réinitialiser",
+	Body:    "Open https://app.example.com/reset-password?token=abc\nto choose a new password.",
+}
+
+func TestFormat(t *testing.T) {
+	// fixture
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	// test
+	content, err := format("Videos <videos@example.com>", testMail, now)
+
+	// assert
+	require.NoError(t, err)
+	message, err := mail.ReadMessage(strings.NewReader(string(content)))
+	require.NoError(t, err)
+	require.Equal(t, "Videos <videos@example.com>", message.Header.Get("From"))
+	require.Equal(t, "jane@example.com", message.Header.Get("To"))
+	require.Equal(t, "=?utf-8?q?R=C3=A9initialiser?=", message.Header.Get("Subject"))
+	require.Equal(t, "Tue, 02 Jan 2024 15:04:05 +0000", message.Header.Get("Date"))
+	require.True(t, strings.HasSuffix(message.Header.Get("Message-ID"), "@example.com>"))
+	body, err := io.ReadAll(message.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "token=3Dabc\r\nto choose")
+}
+
+func TestFormat_UnhappyPath_HeaderInjection(t *testing.T) {
+	// test
+	_, err := format("videos@example.com", &model.Mail{To: "jane@example.com\r\nBcc: all@example.com"}, time.Now())
+
+	// assert
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	// fixture
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer("videos@example.com", dir)
+
+	// test
+	err := mailer.Send(context.Background(), testMail)
+
+	// assert
+	require.NoError(t, err)
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(content), "To: jane@example.com\r\n")
+}
+
+func TestSmtpMailer_Send(t *testing.T) {
+	// fixture
+	server := newSmtpServer(t)
+	mailer := NewSmtpMailer("videos@example.com", server.address, "videos", "secret")
+
+	// test
+	err := mailer.Send(context.Background(), testMail)
+
+	// assert
+	require.NoError(t, err)
+	commands := <-server.commands
+	require.Equal(t, []string{
+		"EHLO localhost",
+		"AUTH PLAIN AHZpZGVvcwBzZWNyZXQ=",
+		"MAIL FROM:<videos@example.com> BODY=8BITMIME",
+		"RCPT TO:<jane@example.com>",
+		"DATA",
+		"QUIT",
+	}, commands)
+	require.Contains(t, <-server.data, "Subject: =?utf-8?q?R=C3=A9initialiser?=\r\n")
+}
+
+func TestLogMailer_Send_LeavesOutTheBody(t *testing.T) {
+	// fixture
+	logs := &strings.Builder{}
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(logs, nil)))
+
+	// test
+	err := NewLogMailer("videos@example.com").Send(ctx, testMail)
+
+	// assert
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "to=jane@example.com")
+	require.NotContains(t, logs.String(), "token=abc")
+}
+
+func TestNew_UnhappyPath_InvalidSender(t *testing.T) {
+	// test
+	_, err := New(&config.Settings{Mailer: config.MailerLog, MailFrom: "videos"})
+
+	// assert
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+// smtpServer accepts a single email without TLS, which net/smtp allows for
+// credentials on localhost only.
+type smtpServer struct {
+	address  string
+	commands chan []string
+	data     chan string
+}
+
+func newSmtpServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &smtpServer{address: listener.Addr().String(), commands: make(chan []string, 1), data: make(chan string, 1)}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.serve(conn)
+	}()
+	return server
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	commands := []string{}
+	defer func() { s.commands <- commands }()
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		commands = append(commands, command)
+
+		switch verb, _, _ := strings.Cut(command, " "); verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250-8BITMIME")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 authenticated")
+		case "DATA":
+			reply("354 go ahead")
+			data := &strings.Builder{}
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data <- data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
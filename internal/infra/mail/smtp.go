@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// SmtpMailer hands every email to an SMTP server. It upgrades the
+// connection with STARTTLS when the server offers it, and only sends
+// credentials over TLS or to localhost.
+type SmtpMailer struct {
+	from     string
+	address  string
+	username string
+	password string
+	now      func() time.Time
+}
+
+func NewSmtpMailer(from string, address string, username string, password string) *SmtpMailer {
+	return &SmtpMailer{from: from, address: address, username: username, password: password, now: time.Now}
+}
+
+func (m *SmtpMailer) Send(ctx context.Context, message *model.Mail) error {
+	ctx, span := tracer.Start(ctx, "SmtpMailer.Send")
+	defer span.End()
+
+	content, err := format(m.from, message, m.now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", m.address)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(m.address)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(envelope(m.from)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(envelope(message.To)); err != nil {
+		return err
+	}
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(content); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// envelope returns the bare address of "Name <address>", format already
+// checked it parses.
+func envelope(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.Address
+}
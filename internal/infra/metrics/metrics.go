@@ -0,0 +1,114 @@
+// Package metrics exposes the service metrics in the Prometheus format.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+// Registry holds every metric of the service, it is not the global default
+// registry so tests and libraries cannot leak metrics into it.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HttpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HttpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	VideosCreated = factory.NewCounter(prometheus.CounterOpts{
+		Name: "videos_created_total",
+		Help: "Videos created.",
+	})
+
+	AnnotationsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Name: "annotations_created_total",
+		Help: "Annotations created, together with their video or on their own.",
+	})
+
+	ValidationFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "validation_failures_total",
+		Help: "Rejected fields by validation error.",
+	}, []string{"error"})
+
+	LoginFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_failures_total",
+		Help: "Failed logins by reason.",
+	}, []string{"reason"})
+
+	RateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_rate_limited_requests_total",
+		Help: "HTTP requests turned away by the rate limit, by method and route template.",
+	}, []string{"method", "route"})
+
+	Backups = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "backups_total",
+		Help: "Database backups by result.",
+	}, []string{"result"})
+
+	LastBackup = factory.NewGauge(prometheus.GaugeOpts{
+		Name: "backup_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful database backup.",
+	})
+)
+
+const (
+	LoginUnknownUser   = "unknown_user"
+	LoginWrongPassword = "wrong_password"
+	LoginDisabledUser  = "disabled_user"
+	LoginThrottled     = "throttled"
+	LoginWrongCode     = "wrong_code"
+	LoginSsoRejected   = "sso_rejected"
+	LoginNoRole        = "no_role"
+)
+
+const (
+	BackupSucceeded = "success"
+	BackupFailed    = "failure"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB exports the connection pool stats of db as go_sql_* metrics.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ValidationFailed counts every failing field of err, errors that are not
+// validation errors are ignored. Error messages are fixed strings, so they
+// are safe to use as label values.
+func ValidationFailed(err error) {
+	if errs, ok := validation.AsErrors(err); ok {
+		for _, fieldErr := range errs {
+			ValidationFailures.WithLabelValues(fieldErr.Err.Error()).Inc()
+		}
+		return
+	}
+	if validation.IsValidationError(err) {
+		ValidationFailures.WithLabelValues(err.Error()).Inc()
+	}
+}
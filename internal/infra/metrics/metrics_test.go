@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/validation"
+)
+
+func TestValidationFailed_CountsEveryField(t *testing.T) {
+	// fixture
+	titles := testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrTitleIsInvalid.Error()))
+	links := testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrLinkIsInvalid.Error()))
+	errs := validation.Errors{
+		{Field: "title", Err: validation.ErrTitleIsInvalid},
+		{Field: "annotations[0].title", Err: validation.ErrTitleIsInvalid},
+		{Field: "link", Err: validation.ErrLinkIsInvalid},
+	}
+
+	// test
+	ValidationFailed(fmt.Errorf("wrapped: %w", errs))
+
+	// assert
+	require.Equal(t, titles+2, testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrTitleIsInvalid.Error())))
+	require.Equal(t, links+1, testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrLinkIsInvalid.Error())))
+}
+
+func TestValidationFailed_SingleError(t *testing.T) {
+	// fixture
+	before := testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrEmailIsInvalid.Error()))
+
+	// test
+	ValidationFailed(validation.ErrEmailIsInvalid)
+
+	// assert
+	require.Equal(t, before+1, testutil.ToFloat64(ValidationFailures.WithLabelValues(validation.ErrEmailIsInvalid.Error())))
+}
+
+func TestValidationFailed_IgnoresOtherErrors(t *testing.T) {
+	// fixture
+	series := testutil.CollectAndCount(ValidationFailures)
+
+	// test
+	ValidationFailed(errors.New("database is locked"))
+
+	// assert
+	require.Equal(t, series, testutil.CollectAndCount(ValidationFailures))
+}
+
+func TestHandler_ExposesDBStats(t *testing.T) {
+	// fixture
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, RegisterDB(db, "test"))
+
+	// test
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, strings.Contains(rr.Body.String(), `go_sql_open_connections{db_name="test"}`))
+	require.True(t, strings.Contains(rr.Body.String(), "go_goroutines"))
+}
@@ -0,0 +1,169 @@
+// Package oidcmock runs an OpenID Connect provider for tests. It logs in
+// whoever asks as the user of its Claims, without a login page, and checks
+// the client and PKCE when the code is redeemed.
+package oidcmock
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+)
+
+const keyID = "oidcmock"
+
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu     sync.Mutex
+	claims map[string]any
+	grants map[string]*grant
+	key    *rsa.PrivateKey
+}
+
+// grant is an issued code with what redeeming it must match.
+type grant struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	claims        map[string]any
+}
+
+// NewServer starts a provider whose user has the subject "user-1", callers
+// must Close it.
+func NewServer(clientID string, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic("oidcmock: generating key: " + err.Error())
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		claims:       map[string]any{"sub": "user-1"},
+		grants:       map[string]*grant{},
+		key:          key,
+	}
+	discovery := &oidctest.Server{PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: keyID, Algorithm: gooidc.RS256}}}
+
+	mux := http.NewServeMux()
+	mux.Handle("/.well-known/openid-configuration", discovery)
+	mux.Handle("/keys", discovery)
+	mux.HandleFunc("/auth", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	discovery.SetIssuer(s.URL)
+	return s
+}
+
+// SetClaims sets the claims of the user who logs in next, besides the ones
+// every ID token has.
+func (s *Server) SetClaims(claims map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims = maps.Clone(claims)
+}
+
+// authorize approves the request right away and redirects back with a code.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.ClientID || query.Get("response_type") != "code" {
+		http.Error(w, "unknown client or response type", http.StatusBadRequest)
+		return
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirect.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+	s.mu.Lock()
+	s.grants[code] = &grant{
+		redirectURI:   redirect.String(),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		claims:        maps.Clone(s.claims),
+	}
+	s.mu.Unlock()
+
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token redeems a code once, answering an ID token signed with the key the
+// discovery document publishes.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	s.mu.Lock()
+	code := r.PostForm.Get("code")
+	grant, ok := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || grant.redirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != grant.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	claims := grant.claims
+	claims["iss"] = s.URL
+	claims["aud"] = s.ClientID
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	if grant.nonce != "" {
+		claims["nonce"] = grant.nonce
+	}
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(s.key, keyID, gooidc.RS256, string(rawClaims)),
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
@@ -0,0 +1,136 @@
+// Package oidc signs users in at an OpenID Connect provider with the
+// authorization code flow and PKCE.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/domain/model"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/tracing"
+)
+
+const requestTimeout = 30 * time.Second
+
+var (
+	ErrMissingIDToken = errors.New("token response has no id_token")
+	ErrNonceMismatch  = errors.New("id_token nonce does not match the login")
+)
+
+var tracer = tracing.Tracer("internal/infra/oidc")
+
+// Provider discovers the endpoints and keys of the issuer on first use, and
+// again after a failure, so the API starts while the issuer is down.
+type Provider struct {
+	issuer    string
+	config    oauth2.Config
+	roleClaim string
+	client    *http.Client
+
+	mu       sync.Mutex
+	verifier *gooidc.IDTokenVerifier
+}
+
+func New(settings *config.Settings) *Provider {
+	return &Provider{
+		issuer: settings.OidcIssuer,
+		config: oauth2.Config{
+			ClientID:     settings.OidcClientID,
+			ClientSecret: settings.OidcClientSecret,
+			RedirectURL:  settings.OidcRedirectURL,
+			Scopes:       strings.Fields(settings.OidcScopes),
+		},
+		roleClaim: settings.OidcRoleClaim,
+		client:    &http.Client{Timeout: requestTimeout, Transport: tracing.NewTransport(nil)},
+	}
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*model.ExternalIdentity, error) {
+	ctx, span := tracer.Start(ctx, "Provider.Exchange")
+	defer span.End()
+
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := config.Exchange(gooidc.ClientContext(ctx, p.client), code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrMissingIDToken
+	}
+
+	idToken, err := verifier.Verify(gooidc.ClientContext(ctx, p.client), rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	identity := &model.ExternalIdentity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Groups:  values(claims[p.roleClaim]),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+	return identity, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.verifier == nil {
+		provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, p.client), p.issuer)
+		if err != nil {
+			return oauth2.Config{}, nil, fmt.Errorf("discovering %s: %w", p.issuer, err)
+		}
+		p.config.Endpoint = provider.Endpoint()
+		p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.config.ClientID})
+	}
+	return p.config, p.verifier, nil
+}
+
+// values reads a claim holding a string or a list of strings.
+func values(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := []string{}
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return nil
+}
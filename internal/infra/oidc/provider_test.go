@@ -0,0 +1,121 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/oidc/oidcmock"
+)
+
+const redirectURL = "https://api.example.com/login/oidc/callback"
+
+func newProvider(t *testing.T) (*Provider, *oidcmock.Server) {
+	server := oidcmock.NewServer("videos-api", "client-secret")
+	t.Cleanup(server.Close)
+	return New(&config.Settings{
+		OidcIssuer:       server.URL,
+		OidcClientID:     "videos-api",
+		OidcClientSecret: "client-secret",
+		OidcRedirectURL:  redirectURL,
+		OidcScopes:       "openid email profile",
+		OidcRoleClaim:    "groups",
+	}), server
+}
+
+// authorize follows the authorization URL and returns the code of the
+// redirect back.
+func authorize(t *testing.T, authURL string, state string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, redirectURL, location.Scheme+"://"+location.Host+location.Path)
+	require.Equal(t, state, location.Query().Get("state"))
+	return location.Query().Get("code")
+}
+
+func TestProvider_HappyPath(t *testing.T) {
+	// fixture
+	provider, server := newProvider(t)
+	server.SetClaims(map[string]any{
+		"sub":                "user-42",
+		"email":              "jane@example.com",
+		"email_verified":     true,
+		"preferred_username": "jane",
+		"groups":             []string{"video-editors", "staff"},
+	})
+	verifier := oauth2.GenerateVerifier()
+
+	// test
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", verifier)
+	require.NoError(t, err)
+	identity, err := provider.Exchange(context.Background(), authorize(t, authURL, "state"), verifier, "nonce")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, server.URL, identity.Issuer)
+	require.Equal(t, "user-42", identity.Subject)
+	require.Equal(t, "jane@example.com", identity.Email)
+	require.True(t, identity.EmailVerified)
+	require.Equal(t, "jane", identity.PreferredUsername)
+	require.Equal(t, []string{"video-editors", "staff"}, identity.Groups)
+}
+
+func TestProvider_UnhappyPath_WrongVerifier(t *testing.T) {
+	// fixture
+	provider, _ := newProvider(t)
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", oauth2.GenerateVerifier())
+	require.NoError(t, err)
+
+	// test
+	identity, err := provider.Exchange(context.Background(), authorize(t, authURL, "state"), oauth2.GenerateVerifier(), "nonce")
+
+	// assert
+	require.Nil(t, identity)
+	require.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestProvider_UnhappyPath_NonceMismatch(t *testing.T) {
+	// fixture
+	provider, _ := newProvider(t)
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", verifier)
+	require.NoError(t, err)
+
+	// test
+	identity, err := provider.Exchange(context.Background(), authorize(t, authURL, "state"), verifier, "other-nonce")
+
+	// assert
+	require.Nil(t, identity)
+	require.ErrorIs(t, err, ErrNonceMismatch)
+}
+
+func TestProvider_DiscoveryIsRetried(t *testing.T) {
+	// fixture
+	provider, server := newProvider(t)
+	issuer := provider.issuer
+	provider.issuer = server.URL + "/unknown"
+	_, failed := provider.AuthCodeURL(context.Background(), "state", "nonce", oauth2.GenerateVerifier())
+	provider.issuer = issuer
+
+	// test
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", oauth2.GenerateVerifier())
+
+	// assert
+	require.Error(t, failed)
+	require.NoError(t, err)
+	require.Contains(t, authURL, server.URL+"/auth?")
+	require.Contains(t, authURL, "code_challenge_method=S256")
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	limit   Limit
+	updated time.Time
+}
+
+// refill adds the tokens earned since the bucket was last updated.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.rate())
+	b.updated = now
+}
+
+// memoryStore keeps the buckets of a single instance. Full buckets are
+// dropped now and then, a missing bucket is a full one.
+type memoryStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now.Sub(s.swept) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), limit: limit, updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	result := Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = fromSeconds((1 - b.tokens) / limit.rate())
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = fromSeconds((float64(limit.Burst) - b.tokens) / limit.rate())
+	return result, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
+
+func fromSeconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,150 @@
+// Package ratelimit limits requests per client and route with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+const unlimited = "none"
+
+var periods = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// Limit lets Burst requests through at once, its bucket refills over
+// Period. The zero Limit does not limit anything.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) IsZero() bool {
+	return l.Burst == 0
+}
+
+// rate is the number of tokens added to the bucket per second.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Policy describes the limit the way the RateLimit-Policy header does, e.g.
+// "10;w=60".
+func (l Limit) Policy() string {
+	return strconv.Itoa(l.Burst) + ";w=" + strconv.Itoa(int(l.Period.Seconds()))
+}
+
+// ParseLimit reads a count per unit such as "10/m", units are s, m and h.
+// An empty value or "none" is the zero Limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == unlimited {
+		return Limit{}, nil
+	}
+
+	count, unit, _ := strings.Cut(value, "/")
+	burst, err := strconv.Atoi(count)
+	period, ok := periods[unit]
+	if err != nil || burst <= 0 || !ok {
+		return Limit{}, errors.New("limit " + value + " must be a count per s, m or h such as 10/m")
+	}
+	return Limit{Burst: burst, Period: period}, nil
+}
+
+// ParseRoutes reads comma separated route limits such as
+// "POST /login=10/m,GET /videos/{id}/=none", routes are the method and the
+// route template.
+func ParseRoutes(value string) (map[string]Limit, error) {
+	routes := map[string]Limit{}
+	for entry := range strings.SplitSeq(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		method, template, hasTemplate := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasTemplate || !strings.HasPrefix(template, "/") {
+			return nil, errors.New("route limit " + strings.TrimSpace(entry) + " must look like METHOD /route=10/m")
+		}
+
+		parsed, err := ParseLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+		routes[strings.ToUpper(method)+" "+template] = parsed
+	}
+	return routes, nil
+}
+
+// Result tells whether a request was let through and how much of its limit
+// is left.
+type Result struct {
+	Limit     Limit
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again, RetryAfter the time
+	// until the next request is let through.
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Seconds rounds a duration of a Result up to whole seconds, as headers
+// carry them.
+func Seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// Store keeps the buckets, an implementation shared by the instances of the
+// service limits them together.
+type Store interface {
+	// Take removes a token from the bucket of key, refilled according to
+	// limit since it was last used.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Limiter applies the limit of each route, or the default one, to every
+// client separately.
+type Limiter struct {
+	store    Store
+	fallback Limit
+	routes   map[string]Limit
+	now      func() time.Time
+}
+
+func NewLimiter(store Store, settings *config.Settings) (*Limiter, error) {
+	fallback, err := ParseLimit(settings.RateLimitDefault)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	routes, err := ParseRoutes(settings.RateLimitRoutes)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+	}
+	return &Limiter{store: store, fallback: fallback, routes: routes, now: time.Now}, nil
+}
+
+// Limit returns the limit of a route, "GET /videos/{id}/" for instance.
+func (l *Limiter) Limit(route string) Limit {
+	if limit, ok := l.routes[route]; ok {
+		return limit
+	}
+	return l.fallback
+}
+
+// Take counts a request of client on route, requests on unlimited routes are
+// always let through.
+func (l *Limiter) Take(ctx context.Context, route string, client string) (Result, error) {
+	limit := l.Limit(route)
+	if limit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, route+" "+client, limit, l.now())
+}
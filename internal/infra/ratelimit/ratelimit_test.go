@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+func TestParseLimit(t *testing.T) {
+	for value, expected := range map[string]Limit{
+		"10/s":  {Burst: 10, Period: time.Second},
+		"5/m":   {Burst: 5, Period: time.Minute},
+		"100/h": {Burst: 100, Period: time.Hour},
+		"none":  {},
+		"":      {},
+	} {
+		t.Run(value, func(t *testing.T) {
+			// test
+			limit, err := ParseLimit(value)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, expected, limit)
+		})
+	}
+}
+
+func TestParseLimit_Invalid(t *testing.T) {
+	for _, value := range []string{"10", "10/d", "0/m", "ten/m"} {
+		t.Run(value, func(t *testing.T) {
+			// test
+			_, err := ParseLimit(value)
+
+			// assert
+			require.EqualError(t, err, "limit "+value+" must be a count per s, m or h such as 10/m")
+		})
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	// test
+	routes, err := ParseRoutes("post /login=10/m, GET /videos/{id}/=none,")
+	_, invalidErr := ParseRoutes("/login=10/m")
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, map[string]Limit{
+		"POST /login":       {Burst: 10, Period: time.Minute},
+		"GET /videos/{id}/": {},
+	}, routes)
+	require.EqualError(t, invalidErr, "route limit /login=10/m must look like METHOD /route=10/m")
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	// fixture
+	store := NewMemoryStore()
+	limit := Limit{Burst: 2, Period: time.Minute}
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	take := func(at time.Time) Result {
+		result, err := store.Take(context.Background(), "client", limit, at)
+		require.NoError(t, err)
+		return result
+	}
+
+	// test
+	first := take(now)
+	second := take(now)
+	limited := take(now.Add(10 * time.Second))
+	refilled := take(now.Add(30 * time.Second))
+
+	// assert
+	require.Equal(t, Result{Limit: limit, Allowed: true, Remaining: 1, Reset: 30 * time.Second}, first)
+	require.Equal(t, Result{Limit: limit, Allowed: true, Remaining: 0, Reset: time.Minute}, second)
+	require.False(t, limited.Allowed)
+	require.Equal(t, 20*time.Second, limited.RetryAfter.Round(time.Millisecond))
+	require.True(t, refilled.Allowed)
+	require.Equal(t, 0, refilled.Remaining)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	// fixture
+	store := NewMemoryStore()
+	limit := Limit{Burst: 1, Period: time.Second}
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	_, err := store.Take(context.Background(), "idle", limit, now)
+	require.NoError(t, err)
+
+	// test
+	_, err = store.Take(context.Background(), "busy", limit, now.Add(sweepInterval))
+
+	// assert
+	require.NoError(t, err)
+	require.NotContains(t, store.buckets, "idle")
+	require.Contains(t, store.buckets, "busy")
+}
+
+func TestLimiter_RouteLimitOverridesDefault(t *testing.T) {
+	// fixture
+	limiter, err := NewLimiter(NewMemoryStore(), &config.Settings{RateLimitDefault: "1/m", RateLimitRoutes: "GET /healthz=none"})
+	require.NoError(t, err)
+
+	// test
+	first, _ := limiter.Take(context.Background(), "POST /videos/", "ip:192.0.2.1")
+	second, _ := limiter.Take(context.Background(), "POST /videos/", "ip:192.0.2.1")
+	otherRoute, _ := limiter.Take(context.Background(), "GET /webhooks/", "ip:192.0.2.1")
+	unlimited, _ := limiter.Take(context.Background(), "GET /healthz", "ip:192.0.2.1")
+
+	// assert
+	require.True(t, first.Allowed)
+	require.False(t, second.Allowed)
+	require.True(t, otherRoute.Allowed)
+	require.True(t, unlimited.Allowed)
+	require.True(t, limiter.Limit("GET /healthz").IsZero())
+}
+
+func TestNewLimiter_InvalidSettings(t *testing.T) {
+	// test
+	_, err := NewLimiter(NewMemoryStore(), &config.Settings{RateLimitDefault: "fast"})
+
+	// assert
+	require.EqualError(t, err, "RATE_LIMIT_DEFAULT: limit fast must be a count per s, m or h such as 10/m")
+}
@@ -0,0 +1,126 @@
+// Package tracing wires OpenTelemetry: the exporter picked in the settings,
+// W3C trace context propagation and the helpers the adapters share.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/health"
+)
+
+const (
+	ServiceName = "videos-api"
+
+	instrumentation = "github.com/juliocnsouzadev/go-videos-api/"
+)
+
+// Tracer returns the tracer of a component, named after its package path
+// relative to the module, e.g. "internal/adapters/repository".
+func Tracer(component string) trace.Tracer {
+	return otel.Tracer(instrumentation + component)
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter
+// is "none", a tracer provider exporting every span. The returned function
+// flushes the pending spans and must be called on shutdown.
+func Setup(ctx context.Context, settings *config.Settings) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch settings.TracingExporter {
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case config.TracingOtlp:
+		options := []otlptracehttp.Option{}
+		if settings.TracingOtlpEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(settings.TracingOtlpEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", ServiceName),
+			attribute.String("service.version", health.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Transport runs every outbound request in a client span and sends the span
+// along in the traceparent header, so the receiving service joins the trace.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, span := Tracer("internal/infra/tracing").Start(request.Context(), request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", request.Method),
+			attribute.String("server.address", request.URL.Hostname()),
+			attribute.String("url.full", request.URL.Redacted()),
+		),
+	)
+	defer span.End()
+
+	request = request.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := t.Base.RoundTrip(request)
+	if err != nil {
+		RecordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, response.Status)
+	}
+	return response, nil
+}
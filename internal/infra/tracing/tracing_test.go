@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/juliocnsouzadev/go-videos-api/internal/infra/config"
+)
+
+func TestSetup_None(t *testing.T) {
+	// fixture
+	settings := &config.Settings{TracingExporter: config.TracingNone}
+
+	// test
+	shutdown, err := Setup(context.Background(), settings)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	// fixture
+	settings := &config.Settings{TracingExporter: config.TracingStdout}
+
+	// test
+	shutdown, err := Setup(context.Background(), settings)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	// fixture
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := provider.Tracer("test").Start(context.Background(), "failing")
+
+	// test
+	End(span, errors.New("database error"))
+
+	// assert
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, "database error", spans[0].Status().Description)
+}
+
+func TestTransport_InjectsTraceparent(t *testing.T) {
+	// fixture
+	spans := recordSpans()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	// test
+	response, err := client.Do(request)
+	parent.End()
+
+	// assert
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusAccepted, response.StatusCode)
+
+	ended := spans()
+	require.Len(t, ended, 2)
+	post := ended[0]
+	require.Equal(t, "POST", post.Name())
+	require.Equal(t, trace.SpanKindClient, post.SpanKind())
+	require.Equal(t, parent.SpanContext().SpanID(), post.Parent().SpanID())
+	require.Equal(t, "00-"+post.SpanContext().TraceID().String()+"-"+post.SpanContext().SpanID().String()+"-01", traceparent)
+}
+
+var (
+	recorder    = tracetest.NewSpanRecorder()
+	installOnce sync.Once
+)
+
+// recordSpans installs a recording tracer provider, once since tracers that
+// were created before only follow the first one, and returns the spans that
+// ended since the call.
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	installOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+	start := len(recorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return recorder.Ended()[start:]
+	}
+}